@@ -64,6 +64,90 @@ func main() {
 			},
 		},
 		Action: run,
+		Commands: []*cli.Command{
+			{
+				Name:  "validate",
+				Usage: "validate a config file without starting anything",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "format",
+						Value: "text",
+						Usage: "output format to use",
+						Validator: func(format string) error {
+							formats := []string{"text", "json"}
+							if !slices.Contains(formats, format) {
+								return fmt.Errorf("unknown output format: %s", format)
+							}
+							return nil
+						},
+					},
+				},
+				Action: validate,
+			},
+			{
+				Name:  "schema",
+				Usage: "write the config, routes, modules and processors JSON schemas to disk",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "output",
+						Value: "./schema",
+						Usage: "directory to write schema files to",
+					},
+				},
+				Action: writeSchemasAction,
+			},
+			{
+				Name:      "test",
+				Usage:     "run route test files against the config using recording stand-ins for modules",
+				ArgsUsage: "<test file>...",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "format",
+						Value: "text",
+						Usage: "output format to use",
+						Validator: func(format string) error {
+							formats := []string{"text", "json", "junit"}
+							if !slices.Contains(formats, format) {
+								return fmt.Errorf("unknown output format: %s", format)
+							}
+							return nil
+						},
+					},
+					&cli.StringFlag{
+						Name:  "output",
+						Usage: "file to write results to instead of stdout",
+					},
+				},
+				Action: testRoutes,
+			},
+			{
+				Name:      "replay",
+				Usage:     "feed a recording of module inputs into the router",
+				ArgsUsage: "<recording file>",
+				Flags: []cli.Flag{
+					&cli.Float64Flag{
+						Name:  "speed",
+						Value: 1,
+						Usage: "playback speed, 1 is real time and 0 is as fast as possible",
+						Validator: func(speed float64) error {
+							if speed < 0 {
+								return fmt.Errorf("speed cannot be negative")
+							}
+							return nil
+						},
+					},
+					&cli.BoolFlag{
+						Name:  "loop",
+						Usage: "start the recording over when it ends",
+					},
+					&cli.StringFlag{
+						Name:  "source",
+						Usage: "source id to use for every input instead of the recorded one",
+					},
+				},
+				Action: replay,
+			},
+		},
 	}
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
@@ -141,12 +225,7 @@ func writeConfig(configPath string, newConfig config.Config) error {
 	return nil
 }
 
-func run(ctx context.Context, c *cli.Command) error {
-	configPath := c.String("config")
-	if configPath == "" {
-		return errors.New("config path cannot be empty")
-	}
-
+func setupLogging(c *cli.Command) {
 	var logLevel slog.Level
 
 	logLevelFromFlag := c.String("log-level")
@@ -184,6 +263,15 @@ func run(ctx context.Context, c *cli.Command) error {
 	}
 
 	slog.SetDefault(slog.New(logHandler))
+}
+
+func run(ctx context.Context, c *cli.Command) error {
+	configPath := c.String("config")
+	if configPath == "" {
+		return errors.New("config path cannot be empty")
+	}
+
+	setupLogging(c)
 
 	showbridgeApp := &showbridgeApp{
 		ctx:        ctx,
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jwetzell/showbridge-go"
+	"github.com/jwetzell/showbridge-go/internal/recording"
+	"github.com/urfave/cli/v3"
+)
+
+func replay(ctx context.Context, c *cli.Command) error {
+	configPath := c.String("config")
+	if configPath == "" {
+		return errors.New("config path cannot be empty")
+	}
+
+	recordingPath := c.Args().First()
+	if recordingPath == "" {
+		return errors.New("recording file is required")
+	}
+
+	setupLogging(c)
+	logger := slog.Default().With("component", "cmd")
+
+	frames, err := recording.Load(recordingPath)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := readConfig(configPath)
+	if err != nil {
+		return err
+	}
+	//NOTE(jwetzell): don't record the replay on top of the original recording
+	cfg.Recording = nil
+
+	router, moduleErrors, routeErrors := showbridge.NewRouter(cfg)
+	if moduleErrors != nil || routeErrors != nil {
+		writeValidationText(c.Root().ErrWriter, configPath, validationResult{ModuleErrors: moduleErrors, RouteErrors: routeErrors})
+		return fmt.Errorf("errors initializing modules or routes")
+	}
+
+	router.Start(context.Background())
+	defer router.Stop()
+
+	logger.Info("replaying recording", "path", recordingPath, "frames", len(frames))
+	err = recording.Replay(ctx, frames, router.HandleInput, recording.ReplayOptions{
+		Speed:  c.Float64("speed"),
+		Loop:   c.Bool("loop"),
+		Source: c.String("source"),
+	})
+	if err != nil {
+		return err
+	}
+	logger.Info("replay finished")
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jwetzell/showbridge-go/internal/schema"
+	"github.com/urfave/cli/v3"
+)
+
+func writeSchemas(outputDir string) ([]string, error) {
+	err := os.MkdirAll(outputDir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := []struct {
+		fileName string
+		schema   any
+	}{
+		{fileName: "config.schema.json", schema: schema.ConfigSchema},
+		{fileName: "routes.schema.json", schema: schema.RoutesConfigSchema},
+		{fileName: "modules.schema.json", schema: schema.GetModulesSchema()},
+		{fileName: "processors.schema.json", schema: schema.GetProcessorsSchema()},
+	}
+
+	paths := []string{}
+	for _, s := range schemas {
+		schemaJSON, err := json.MarshalIndent(s.schema, "", "  ")
+		if err != nil {
+			return paths, fmt.Errorf("failed to encode %s: %w", s.fileName, err)
+		}
+
+		schemaPath := filepath.Join(outputDir, s.fileName)
+		err = os.WriteFile(schemaPath, append(schemaJSON, '\n'), 0644)
+		if err != nil {
+			return paths, fmt.Errorf("failed to write %s: %w", schemaPath, err)
+		}
+		paths = append(paths, schemaPath)
+	}
+	return paths, nil
+}
+
+func writeSchemasAction(ctx context.Context, c *cli.Command) error {
+	paths, err := writeSchemas(c.String("output"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		fmt.Fprintln(c.Root().Writer, path)
+	}
+	return nil
+}
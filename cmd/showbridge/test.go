@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jwetzell/showbridge-go/internal/routetest"
+	"github.com/jwetzell/showbridge-go/showbridgetest"
+	"github.com/urfave/cli/v3"
+)
+
+func testRoutes(ctx context.Context, c *cli.Command) error {
+	configPath := c.String("config")
+	if configPath == "" {
+		return errors.New("config path cannot be empty")
+	}
+
+	testPaths := c.Args().Slice()
+	if len(testPaths) == 0 {
+		return errors.New("at least one test file is required")
+	}
+
+	setupLogging(c)
+
+	result := validateConfigFile(configPath)
+	if !result.Valid {
+		writeValidationText(c.Root().ErrWriter, configPath, result)
+		return cli.Exit("", 1)
+	}
+
+	cfg, err := readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	suiteResults, err := showbridgetest.RunFiles(cfg, testPaths...)
+	if err != nil {
+		return err
+	}
+
+	var output io.Writer = c.Root().Writer
+	outputPath := c.String("output")
+	if outputPath != "" {
+		outputFile, err := os.Create(outputPath)
+		if err != nil {
+			return err
+		}
+		defer outputFile.Close()
+		output = outputFile
+	}
+
+	switch c.String("format") {
+	case "json":
+		err = routetest.WriteJSON(output, suiteResults)
+	case "junit":
+		err = routetest.WriteJUnit(output, suiteResults)
+	default:
+		err = routetest.WriteText(output, suiteResults)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write test results: %w", err)
+	}
+
+	for _, suiteResult := range suiteResults {
+		if suiteResult.Failed() > 0 {
+			return cli.Exit("", 1)
+		}
+	}
+	return nil
+}
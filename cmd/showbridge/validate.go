@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jwetzell/showbridge-go"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/urfave/cli/v3"
+)
+
+type validationResult struct {
+	Valid        bool                 `json:"valid"`
+	Error        string               `json:"error,omitempty"`
+	ModuleErrors []config.ModuleError `json:"moduleErrors,omitempty"`
+	RouteErrors  []config.RouteError  `json:"routeErrors,omitempty"`
+}
+
+func validateConfigFile(configPath string) validationResult {
+	cfg, err := readConfig(configPath)
+	if err != nil {
+		return validationResult{
+			Valid: false,
+			Error: err.Error(),
+		}
+	}
+
+	moduleErrors, routeErrors := showbridge.ValidateConfig(cfg)
+
+	return validationResult{
+		Valid:        len(moduleErrors) == 0 && len(routeErrors) == 0,
+		ModuleErrors: moduleErrors,
+		RouteErrors:  routeErrors,
+	}
+}
+
+func writeValidationText(w io.Writer, configPath string, result validationResult) {
+	if result.Valid {
+		fmt.Fprintf(w, "%s: config is valid\n", configPath)
+		return
+	}
+
+	if result.Error != "" {
+		fmt.Fprintf(w, "%s: %s\n", configPath, result.Error)
+	}
+
+	for _, moduleError := range result.ModuleErrors {
+		fmt.Fprintf(w, "%s: modules[%d] (%s): %s\n", configPath, moduleError.Index, moduleError.Config.Id, moduleError.Error)
+	}
+
+	for _, routeError := range result.RouteErrors {
+		fmt.Fprintf(w, "%s: routes[%d] (%s): %s\n", configPath, routeError.Index, routeError.Config.Id, routeError.Error)
+	}
+}
+
+func validate(ctx context.Context, c *cli.Command) error {
+	configPath := c.String("config")
+	if configPath == "" {
+		return errors.New("config path cannot be empty")
+	}
+
+	result := validateConfigFile(configPath)
+
+	switch c.String("format") {
+	case "json":
+		resultJSON, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.Root().Writer, string(resultJSON))
+	default:
+		writeValidationText(c.Root().Writer, configPath, result)
+	}
+
+	if !result.Valid {
+		return cli.Exit("", 1)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/jwetzell/showbridge-go/internal/common"
 	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
 	"github.com/jwetzell/showbridge-go/internal/route"
 )
 
@@ -30,6 +31,14 @@ func (r *Router) UpdateConfig(newConfig config.Config, triggerChangeChan bool) (
 		r.runningConfig.Api = newConfig.Api
 	}
 
+	if !reflect.DeepEqual(oldConfig.EventLog, newConfig.EventLog) {
+		r.logger.Info("applying new event log config")
+		r.eventLogMu.Lock()
+		r.eventLog.Close()
+		r.eventLog = r.newEventLog(newConfig.EventLog)
+		r.eventLogMu.Unlock()
+	}
+
 	// TODO(jwetzell): handle config update errors better
 	for _, moduleInstance := range r.ModuleInstances {
 		moduleInstance.Stop()
@@ -37,8 +46,22 @@ func (r *Router) UpdateConfig(newConfig config.Config, triggerChangeChan bool) (
 	r.logger.Debug("waiting for modules to exit")
 	r.moduleWait.Wait()
 
+	if !reflect.DeepEqual(oldConfig.Recording, newConfig.Recording) {
+		r.logger.Info("applying new recording config")
+		if r.recorder != nil {
+			r.recorder.Close()
+		}
+		r.recorder = r.newRecorder(newConfig.Recording)
+	}
+
 	r.ModuleInstances = make(map[string]common.Module)
+	r.moduleIds = make(map[string]bool)
 	r.RouteInstances = []*route.Route{}
+	//NOTE(jwetzell): processors from the old routes must not keep sharing state with the new ones
+	r.processorGroups = processor.NewGroups()
+	r.moduleStatusesMu.Lock()
+	r.moduleStatuses = make(map[string]common.ModuleStatus)
+	r.moduleStatusesMu.Unlock()
 
 	var moduleErrors []config.ModuleError
 
@@ -83,3 +106,51 @@ func (r *Router) UpdateConfig(newConfig config.Config, triggerChangeChan bool) (
 
 	return moduleErrors, routeErrors, nil
 }
+
+func (r *Router) ValidateConfig(newConfig config.Config) ([]config.ModuleError, []config.RouteError) {
+	return ValidateConfig(newConfig)
+}
+
+// ValidateConfig constructs every module and route in the config without starting them or touching a running router
+func ValidateConfig(cfg config.Config) ([]config.ModuleError, []config.RouteError) {
+	var moduleErrors []config.ModuleError
+	moduleIds := make(map[string]bool, len(cfg.Modules))
+
+	for moduleIndex, moduleDecl := range cfg.Modules {
+		_, err := newModule(moduleDecl, moduleIds)
+		if err != nil {
+			if moduleErrors == nil {
+				moduleErrors = []config.ModuleError{}
+			}
+			moduleErrors = append(moduleErrors, config.ModuleError{
+				Index:  moduleIndex,
+				Config: moduleDecl,
+				Error:  err.Error(),
+			})
+			continue
+		}
+		moduleIds[moduleDecl.Id] = true
+	}
+
+	var routeErrors []config.RouteError
+	processorGroups := processor.NewGroups()
+	for routeIndex, routeDecl := range cfg.Routes {
+		routeInstance, err := route.NewRoute(routeDecl)
+		if err == nil {
+			err = routeInstance.JoinGroups(processorGroups)
+		}
+		if err != nil {
+			if routeErrors == nil {
+				routeErrors = []config.RouteError{}
+			}
+			routeErrors = append(routeErrors, config.RouteError{
+				Index:  routeIndex,
+				Config: routeDecl,
+				Error:  err.Error(),
+			})
+			continue
+		}
+	}
+
+	return moduleErrors, routeErrors
+}
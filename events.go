@@ -1,9 +1,15 @@
 package showbridge
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/jwetzell/showbridge-go/internal/codec"
 	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/eventlog"
 )
 
 func (r *Router) HandleEvent(event common.Event, sender common.EventDestination) {
@@ -12,11 +18,57 @@ func (r *Router) HandleEvent(event common.Event, sender common.EventDestination)
 		r.unicastEvent(common.Event{Type: "pong", Data: map[string]any{
 			"timestamp": time.Now().UnixMilli(),
 		}}, sender)
+	case "inject":
+		r.handleInjectEvent(event, sender)
 	default:
 		r.logger.Warn("unknown event type", "eventType", event.Type)
 	}
 }
 
+func (r *Router) handleInjectEvent(event common.Event, sender common.EventDestination) {
+	eventData, ok := event.Data.(map[string]any)
+	if !ok {
+		r.unicastEvent(common.Event{Type: "inject", Error: "inject event data must be an object"}, sender)
+		return
+	}
+
+	source, ok := eventData["source"].(string)
+	if !ok || source == "" {
+		r.unicastEvent(common.Event{Type: "inject", Error: "inject event source must be a non-empty string"}, sender)
+		return
+	}
+
+	encoding, _ := eventData["encoding"].(string)
+	payload, err := codec.DecodePayload(encoding, eventData["payload"])
+	if err != nil {
+		r.unicastEvent(common.Event{Type: "inject", Data: map[string]any{"source": source}, Error: err.Error()}, sender)
+		return
+	}
+
+	ctx := r.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	aRouteFound, routeIOErrors := r.HandleInput(ctx, source, payload)
+
+	result := common.Event{
+		Type: "inject",
+		Data: map[string]any{
+			"source":     source,
+			"routeFound": aRouteFound,
+		},
+	}
+	if routeIOErrors != nil {
+		errorMessages := []string{}
+		for _, routeIOError := range routeIOErrors {
+			errorMessages = append(errorMessages, fmt.Sprintf("route[%d]: %s", routeIOError.Index, routeIOError.ProcessError))
+		}
+		result.Error = strings.Join(errorMessages, "; ")
+	}
+	r.unicastEvent(result, sender)
+}
+
 func (r *Router) AddEventDestination(dest common.EventDestination) {
 	r.eventDestinationsMu.Lock()
 	defer r.eventDestinationsMu.Unlock()
@@ -42,6 +94,10 @@ func (r *Router) unicastEvent(event common.Event, dest common.EventDestination)
 }
 
 func (r *Router) broadcastEvent(event common.Event) {
+	r.eventLogMu.RLock()
+	r.eventLog.Append(event)
+	r.eventLogMu.RUnlock()
+
 	r.eventDestinationsMu.Lock()
 	defer r.eventDestinationsMu.Unlock()
 	for _, dest := range r.eventDestinations {
@@ -51,3 +107,29 @@ func (r *Router) broadcastEvent(event common.Event) {
 		}
 	}
 }
+
+const defaultEventLogSize = 1000
+
+func (r *Router) newEventLog(eventLogConfig config.EventLogConfig) *eventlog.EventLog {
+	if eventLogConfig.Size <= 0 {
+		eventLogConfig.Size = defaultEventLogSize
+	}
+	eventLog, err := eventlog.New(eventLogConfig)
+	if err != nil {
+		r.logger.Error("unable to create event log, falling back to memory only", "error", err)
+		eventLog, _ = eventlog.New(config.EventLogConfig{Size: eventLogConfig.Size})
+	}
+	return eventLog
+}
+
+func (r *Router) QueryEvents(query common.EventQuery) ([]common.EventRecord, error) {
+	r.eventLogMu.RLock()
+	defer r.eventLogMu.RUnlock()
+	return r.eventLog.Query(query)
+}
+
+func (r *Router) SubscribeEvents() (<-chan common.EventRecord, func()) {
+	r.eventLogMu.RLock()
+	defer r.eventLogMu.RUnlock()
+	return r.eventLog.Subscribe()
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"sync"
 	"time"
@@ -16,20 +18,26 @@ import (
 	"github.com/jwetzell/showbridge-go/internal/schema"
 )
 
+type Router interface {
+	config.Configurable
+	common.EventRouter
+	common.EventHistoryRouter
+	common.ModuleStatusRouter
+}
+
 type ApiServer struct {
-	config             config.ApiConfig
-	serverMu           sync.Mutex
-	server             *http.Server
-	logger             *slog.Logger
-	configurableRouter config.Configurable
-	eventRouter        common.EventRouter
+	config       config.ApiConfig
+	serverMu     sync.Mutex
+	server       *http.Server
+	serverCancel context.CancelFunc
+	logger       *slog.Logger
+	router       Router
 }
 
-func NewApiServer(configurableRouter config.Configurable, eventRouter common.EventRouter) *ApiServer {
+func NewApiServer(router Router) *ApiServer {
 	return &ApiServer{
-		configurableRouter: configurableRouter,
-		eventRouter:        eventRouter,
-		logger:             slog.Default().With("component", "api"),
+		router: router,
+		logger: slog.Default().With("component", "api"),
 	}
 }
 
@@ -44,6 +52,11 @@ func (as *ApiServer) Start(config config.ApiConfig) {
 	mux.HandleFunc("/ws", as.handleWebsocket)
 	mux.HandleFunc("/health", as.handleHealthHTTP)
 	mux.HandleFunc("/api/v1/config", as.handleConfigHTTP)
+	mux.HandleFunc("/api/v1/config/validate", as.handleConfigValidateHTTP)
+	mux.HandleFunc("/api/v1/modules", as.handleModulesHTTP)
+	mux.HandleFunc("/api/v1/events", as.handleEventsHTTP)
+	mux.Handle("/ui/", http.StripPrefix("/ui/", http.FileServerFS(uiFS)))
+	mux.Handle("/{$}", http.RedirectHandler("/ui/", http.StatusFound))
 	mux.HandleFunc("/schema/config.schema.json", handleConfigSchema)
 	mux.HandleFunc("/schema/routes.schema.json", handleRoutesSchema)
 	mux.HandleFunc("/schema/modules.schema.json", handleModulesSchema)
@@ -51,10 +64,16 @@ func (as *ApiServer) Start(config config.ApiConfig) {
 
 	as.serverMu.Lock()
 	defer as.serverMu.Unlock()
+	//NOTE(jwetzell): canceled on stop so long-lived event streams don't hold up shutdown
+	serverContext, serverCancel := context.WithCancel(context.Background())
+	as.serverCancel = serverCancel
 	as.server = &http.Server{
 		Addr:              fmt.Sprintf(":%d", as.config.Port),
 		ReadHeaderTimeout: 5 * time.Second,
 		Handler:           mux,
+		BaseContext: func(net.Listener) context.Context {
+			return serverContext
+		},
 	}
 
 	go func() {
@@ -72,6 +91,7 @@ func (as *ApiServer) Stop() {
 	as.serverMu.Lock()
 	defer as.serverMu.Unlock()
 	if as.server != nil {
+		as.serverCancel()
 		apiShutdownCtx, apiShutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer apiShutdownCancel()
 		err := as.server.Shutdown(apiShutdownCtx)
@@ -103,7 +123,7 @@ func (as *ApiServer) handleConfigHTTP(w http.ResponseWriter, req *http.Request)
 
 	switch req.Method {
 	case http.MethodGet:
-		configJSON, err := json.Marshal(as.configurableRouter.GetRunningConfig())
+		configJSON, err := json.Marshal(as.router.GetRunningConfig())
 		if err != nil {
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
 			return
@@ -113,46 +133,18 @@ func (as *ApiServer) handleConfigHTTP(w http.ResponseWriter, req *http.Request)
 		w.Header().Set("Content-Type", "application/json")
 		w.Write(configJSON)
 	case http.MethodPut:
-		//TODO(jwetzell): again way too much marshaling
 		cfgBytes, err := io.ReadAll(req.Body)
 		if err != nil {
 			http.Error(w, "Bad request", http.StatusBadRequest)
 			return
 		}
-		cfgMap := make(map[string]any)
-		err = json.Unmarshal(cfgBytes, &cfgMap)
-		if err != nil {
-			http.Error(w, "Bad request", http.StatusBadRequest)
-			return
-		}
-
-		err = schema.ApplyDefaults(&cfgMap)
-
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-
-		err = schema.ValidateConfig(cfgMap)
 
+		newConfig, err := parseConfigJSON(cfgBytes)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-
-		validCfgBytes, err := json.Marshal(cfgMap)
-		if err != nil {
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
-			return
-		}
-
-		var newConfig config.Config
-		err = json.Unmarshal(validCfgBytes, &newConfig)
-		if err != nil {
-			http.Error(w, "Bad request", http.StatusBadRequest)
-			return
-		}
-		moduleErrors, routeErrors, err := as.configurableRouter.UpdateConfig(newConfig, true)
+		moduleErrors, routeErrors, err := as.router.UpdateConfig(newConfig, true)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusConflict)
 			return
@@ -189,6 +181,110 @@ func (as *ApiServer) handleConfigHTTP(w http.ResponseWriter, req *http.Request)
 	}
 }
 
+func parseConfigJSON(cfgBytes []byte) (config.Config, error) {
+	//TODO(jwetzell): again way too much marshaling
+	cfgMap := make(map[string]any)
+	err := json.Unmarshal(cfgBytes, &cfgMap)
+	if err != nil {
+		return config.Config{}, errors.New("Bad request")
+	}
+
+	err = schema.ApplyDefaults(&cfgMap)
+	if err != nil {
+		return config.Config{}, err
+	}
+
+	err = schema.ValidateConfig(cfgMap)
+	if err != nil {
+		return config.Config{}, err
+	}
+
+	validCfgBytes, err := json.Marshal(cfgMap)
+	if err != nil {
+		return config.Config{}, err
+	}
+
+	var newConfig config.Config
+	err = json.Unmarshal(validCfgBytes, &newConfig)
+	if err != nil {
+		return config.Config{}, errors.New("Bad request")
+	}
+	return newConfig, nil
+}
+
+type configValidationResponse struct {
+	Valid        bool                 `json:"valid"`
+	Error        string               `json:"error,omitempty"`
+	ModuleErrors []config.ModuleError `json:"moduleErrors,omitempty"`
+	RouteErrors  []config.RouteError  `json:"routeErrors,omitempty"`
+}
+
+func (as *ApiServer) handleConfigValidateHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodPost:
+		cfgBytes, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		response := configValidationResponse{Valid: true}
+
+		newConfig, err := parseConfigJSON(cfgBytes)
+		if err != nil {
+			response.Valid = false
+			response.Error = err.Error()
+		} else {
+			response.ModuleErrors, response.RouteErrors = as.router.ValidateConfig(newConfig)
+			if len(response.ModuleErrors) > 0 || len(response.RouteErrors) > 0 {
+				response.Valid = false
+			}
+		}
+
+		responseJSON, err := json.Marshal(response)
+		if err != nil {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		if !response.Valid {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		w.Write(responseJSON)
+	case http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (as *ApiServer) handleModulesHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		modulesJSON, err := json.Marshal(as.router.GetModuleStatuses())
+		if err != nil {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(modulesJSON)
+	case http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
 func handleConfigSchema(w http.ResponseWriter, req *http.Request) {
 	switch req.Method {
 	case http.MethodGet:
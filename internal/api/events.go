@@ -0,0 +1,229 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/eventlog"
+)
+
+const (
+	defaultEventQueryLimit = 100
+	maxEventQueryLimit     = 10000
+)
+
+type eventsResponse struct {
+	Events []common.EventRecord `json:"events"`
+	Cursor uint64               `json:"cursor"`
+}
+
+func parseEventTime(value string) (time.Time, error) {
+	milliseconds, err := strconv.ParseInt(value, 10, 64)
+	if err == nil {
+		return time.UnixMilli(milliseconds), nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+func parseEventQuery(values url.Values) (common.EventQuery, error) {
+	query := common.EventQuery{
+		Limit: defaultEventQueryLimit,
+	}
+
+	after := values.Get("after")
+	if after != "" {
+		afterId, err := strconv.ParseUint(after, 10, 64)
+		if err != nil {
+			return query, fmt.Errorf("after must be an event id: %w", err)
+		}
+		query.After = afterId
+	}
+
+	since := values.Get("since")
+	if since != "" {
+		sinceTime, err := parseEventTime(since)
+		if err != nil {
+			return query, fmt.Errorf("since must be unix milliseconds or RFC3339: %w", err)
+		}
+		query.Since = sinceTime
+	}
+
+	until := values.Get("until")
+	if until != "" {
+		untilTime, err := parseEventTime(until)
+		if err != nil {
+			return query, fmt.Errorf("until must be unix milliseconds or RFC3339: %w", err)
+		}
+		query.Until = untilTime
+	}
+
+	for _, eventTypes := range values["type"] {
+		for eventType := range strings.SplitSeq(eventTypes, ",") {
+			if eventType != "" {
+				query.Types = append(query.Types, eventType)
+			}
+		}
+	}
+
+	query.Module = values.Get("module")
+	query.Route = values.Get("route")
+
+	errorOnly := values.Get("errorOnly")
+	if errorOnly != "" {
+		errorOnlyBool, err := strconv.ParseBool(errorOnly)
+		if err != nil {
+			return query, fmt.Errorf("errorOnly must be a boolean: %w", err)
+		}
+		query.ErrorOnly = errorOnlyBool
+	}
+
+	limit := values.Get("limit")
+	if limit != "" {
+		limitInt, err := strconv.Atoi(limit)
+		if err != nil || limitInt < 1 {
+			return query, fmt.Errorf("limit must be a positive integer")
+		}
+		query.Limit = min(limitInt, maxEventQueryLimit)
+	}
+
+	return query, nil
+}
+
+func (as *ApiServer) handleEventsHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		query, err := parseEventQuery(req.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		stream, _ := strconv.ParseBool(req.URL.Query().Get("stream"))
+		if stream {
+			as.streamEvents(w, req, query)
+			return
+		}
+
+		records, err := as.router.QueryEvents(query)
+		if err != nil {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		response := eventsResponse{
+			Events: records,
+			Cursor: query.After,
+		}
+		if len(records) > 0 {
+			response.Cursor = records[len(records)-1].Id
+		}
+
+		responseJSON, err := json.Marshal(response)
+		if err != nil {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(responseJSON)
+	case http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Last-Event-ID")
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func writeEventRecord(w http.ResponseWriter, record common.EventRecord) error {
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", record.Id, recordJSON)
+	return err
+}
+
+// streamEvents sends matching events as server-sent events, starting with any already recorded after the cursor
+func (as *ApiServer) streamEvents(w http.ResponseWriter, req *http.Request, query common.EventQuery) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	lastEventId := req.Header.Get("Last-Event-ID")
+	if lastEventId != "" {
+		afterId, err := strconv.ParseUint(lastEventId, 10, 64)
+		if err != nil {
+			http.Error(w, "Last-Event-ID must be an event id", http.StatusBadRequest)
+			return
+		}
+		query.After = afterId
+	}
+
+	//NOTE(jwetzell): subscribe before reading the backlog so nothing falls in between
+	records, unsubscribe := as.router.SubscribeEvents()
+	defer unsubscribe()
+
+	//NOTE(jwetzell): without a cursor or start time only new events are streamed
+	var backlog []common.EventRecord
+	if query.After > 0 || !query.Since.IsZero() {
+		backlogQuery := query
+		backlogQuery.Limit = maxEventQueryLimit
+		var err error
+		backlog, err = as.router.QueryEvents(backlogQuery)
+		if err != nil {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	lastSentId := query.After
+	for _, record := range backlog {
+		err := writeEventRecord(w, record)
+		if err != nil {
+			return
+		}
+		lastSentId = record.Id
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case record, ok := <-records:
+			if !ok {
+				return
+			}
+			if record.Id <= lastSentId {
+				continue
+			}
+			liveQuery := query
+			liveQuery.After = lastSentId
+			if !eventlog.Matches(record, liveQuery) {
+				continue
+			}
+			err := writeEventRecord(w, record)
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+			lastSentId = record.Id
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed ui
+var uiFiles embed.FS
+
+var uiFS = mustSubFS(uiFiles, "ui")
+
+func mustSubFS(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,649 @@
+"use strict";
+
+const state = {
+  config: { api: {}, modules: [], routes: [] },
+  moduleSchemas: {},
+  processorSchemas: {},
+  moduleStatuses: {},
+  events: [],
+  eventsPaused: false,
+  rawMode: false,
+  socket: undefined,
+};
+
+const maxEvents = 1000;
+
+function $(id) {
+  return document.getElementById(id);
+}
+
+function el(tag, attrs, ...children) {
+  const element = document.createElement(tag);
+  for (const [key, value] of Object.entries(attrs || {})) {
+    if (key.startsWith("on")) {
+      element.addEventListener(key.substring(2), value);
+    } else if (key === "className") {
+      element.className = value;
+    } else if (value !== undefined && value !== null && value !== false) {
+      element.setAttribute(key, value === true ? "" : value);
+    }
+  }
+  for (const child of children) {
+    if (child === undefined || child === null) {
+      continue;
+    }
+    element.append(child instanceof Node ? child : document.createTextNode(String(child)));
+  }
+  return element;
+}
+
+async function fetchJSON(url, options) {
+  const response = await fetch(url, options);
+  const text = await response.text();
+  let body = text;
+  try {
+    body = text ? JSON.parse(text) : undefined;
+  } catch (error) {
+    // NOTE: plain text error bodies are returned as-is
+  }
+  return { ok: response.ok, status: response.status, body };
+}
+
+// schemas
+
+function schemasById(schema) {
+  const schemas = {};
+  for (const definition of schema?.items?.oneOf || []) {
+    schemas[definition.$id] = definition;
+  }
+  return schemas;
+}
+
+async function loadSchemas() {
+  const [modules, processors] = await Promise.all([
+    fetchJSON("/schema/modules.schema.json"),
+    fetchJSON("/schema/processors.schema.json"),
+  ]);
+  state.moduleSchemas = schemasById(modules.body);
+  state.processorSchemas = schemasById(processors.body);
+}
+
+// params forms
+
+function paramsFieldInput(propertySchema, value, onChange) {
+  if (Array.isArray(propertySchema.enum)) {
+    const select = el(
+      "select",
+      { onchange: (event) => onChange(JSON.parse(event.target.value)) },
+      el("option", { value: "null" }, ""),
+      ...propertySchema.enum.map((option) =>
+        el("option", { value: JSON.stringify(option), selected: option === value }, option),
+      ),
+    );
+    return select;
+  }
+
+  switch (propertySchema.type) {
+    case "boolean":
+      return el("input", {
+        type: "checkbox",
+        checked: value === true,
+        onchange: (event) => onChange(event.target.checked),
+      });
+    case "integer":
+    case "number":
+      return el("input", {
+        type: "number",
+        value: value ?? "",
+        min: propertySchema.minimum,
+        max: propertySchema.maximum,
+        step: propertySchema.type === "integer" ? 1 : "any",
+        onchange: (event) => onChange(event.target.value === "" ? null : Number(event.target.value)),
+      });
+    case "string":
+      return el("input", {
+        type: "text",
+        value: value ?? "",
+        onchange: (event) => onChange(event.target.value === "" ? null : event.target.value),
+      });
+    case "array":
+      if (propertySchema.items?.type === "string") {
+        return el(
+          "textarea",
+          {
+            placeholder: "one item per line",
+            onchange: (event) => {
+              const lines = event.target.value.split("\n").filter((line) => line !== "");
+              onChange(lines.length > 0 ? lines : null);
+            },
+          },
+          (value || []).join("\n"),
+        );
+      }
+    // falls through to JSON editing for anything more complex
+    default:
+      return el(
+        "textarea",
+        {
+          placeholder: "JSON",
+          onchange: (event) => {
+            if (event.target.value.trim() === "") {
+              onChange(null);
+              return;
+            }
+            try {
+              onChange(JSON.parse(event.target.value));
+              event.target.setCustomValidity("");
+            } catch (error) {
+              event.target.setCustomValidity(error.message);
+              event.target.reportValidity();
+            }
+          },
+        },
+        value === undefined ? "" : JSON.stringify(value, null, 2),
+      );
+  }
+}
+
+function paramsForm(definitionSchema, target) {
+  const paramsSchema = definitionSchema?.properties?.params;
+  const container = el("div", { className: "params" });
+  if (!paramsSchema || !paramsSchema.properties) {
+    return container;
+  }
+  const required = paramsSchema.required || [];
+  for (const [key, propertySchema] of Object.entries(paramsSchema.properties)) {
+    const onChange = (newValue) => {
+      if (newValue === null || newValue === undefined) {
+        if (target.params) {
+          delete target.params[key];
+        }
+      } else {
+        target.params = target.params || {};
+        target.params[key] = newValue;
+      }
+    };
+    const title = (propertySchema.title || key) + (required.includes(key) ? " *" : "");
+    container.append(
+      el(
+        "div",
+        { className: "field" },
+        el("span", { title: key }, title),
+        paramsFieldInput(propertySchema, target.params?.[key], onChange),
+        propertySchema.description ? el("span", { className: "description" }, propertySchema.description) : undefined,
+      ),
+    );
+  }
+  return container;
+}
+
+function typeSelect(schemas, selected, onChange) {
+  const types = Object.keys(schemas).sort();
+  if (selected && !types.includes(selected)) {
+    types.unshift(selected);
+  }
+  return el(
+    "select",
+    { onchange: (event) => onChange(event.target.value) },
+    el("option", { value: "" }, "select type"),
+    ...types.map((type) => el("option", { value: type, selected: type === selected }, type)),
+  );
+}
+
+function moveItem(list, index, offset) {
+  const newIndex = index + offset;
+  if (newIndex < 0 || newIndex >= list.length) {
+    return;
+  }
+  const [item] = list.splice(index, 1);
+  list.splice(newIndex, 0, item);
+}
+
+// config view
+
+function renderApiConfig() {
+  const api = state.config.api || (state.config.api = {});
+  $("config-api").replaceChildren(
+    el(
+      "div",
+      { className: "field" },
+      el("span", {}, "Enabled"),
+      el("input", { type: "checkbox", checked: api.enabled === true, onchange: (event) => (api.enabled = event.target.checked) }),
+    ),
+    el(
+      "div",
+      { className: "field" },
+      el("span", {}, "Port"),
+      el("input", { type: "number", value: api.port ?? 8080, onchange: (event) => (api.port = Number(event.target.value)) }),
+    ),
+  );
+}
+
+function renderModules() {
+  const modules = state.config.modules || (state.config.modules = []);
+  $("config-modules").replaceChildren(
+    ...modules.map((moduleConfig, index) =>
+      el(
+        "div",
+        { className: "card" },
+        el(
+          "div",
+          { className: "card-header" },
+          el("input", {
+            type: "text",
+            placeholder: "id",
+            value: moduleConfig.id || "",
+            onchange: (event) => {
+              moduleConfig.id = event.target.value;
+              renderModuleIds();
+            },
+          }),
+          typeSelect(state.moduleSchemas, moduleConfig.type, (type) => {
+            moduleConfig.type = type;
+            delete moduleConfig.params;
+            renderModules();
+          }),
+          el("span", { className: "description" }, state.moduleSchemas[moduleConfig.type]?.title),
+          el("span", { className: "spacer" }),
+          el("button", { className: "danger", onclick: () => { modules.splice(index, 1); renderConfig(); } }, "Remove"),
+        ),
+        paramsForm(state.moduleSchemas[moduleConfig.type], moduleConfig),
+      ),
+    ),
+  );
+}
+
+function renderProcessors(routeConfig) {
+  const processors = routeConfig.processors || (routeConfig.processors = []);
+  return el(
+    "div",
+    { className: "processors" },
+    ...processors.map((processorConfig, index) =>
+      el(
+        "div",
+        { className: "card" },
+        el(
+          "div",
+          { className: "card-header" },
+          el("span", {}, `${index}`),
+          el("input", {
+            type: "text",
+            placeholder: "id",
+            value: processorConfig.id || "",
+            onchange: (event) => (processorConfig.id = event.target.value),
+          }),
+          typeSelect(state.processorSchemas, processorConfig.type, (type) => {
+            processorConfig.type = type;
+            delete processorConfig.params;
+            renderRoutes();
+          }),
+          el("span", { className: "description" }, state.processorSchemas[processorConfig.type]?.title),
+          el("span", { className: "spacer" }),
+          el("button", { onclick: () => { moveItem(processors, index, -1); renderRoutes(); } }, "Up"),
+          el("button", { onclick: () => { moveItem(processors, index, 1); renderRoutes(); } }, "Down"),
+          el("button", { className: "danger", onclick: () => { processors.splice(index, 1); renderRoutes(); } }, "Remove"),
+        ),
+        paramsForm(state.processorSchemas[processorConfig.type], processorConfig),
+      ),
+    ),
+    el("button", { onclick: () => { processors.push({ id: "", type: "" }); renderRoutes(); } }, "Add Processor"),
+  );
+}
+
+function renderRoutes() {
+  const routes = state.config.routes || (state.config.routes = []);
+  $("config-routes").replaceChildren(
+    ...routes.map((routeConfig, index) =>
+      el(
+        "div",
+        { className: "card" },
+        el(
+          "div",
+          { className: "card-header" },
+          el("input", {
+            type: "text",
+            placeholder: "id",
+            value: routeConfig.id || "",
+            onchange: (event) => (routeConfig.id = event.target.value),
+          }),
+          el("input", {
+            type: "text",
+            placeholder: "input",
+            list: "module-ids",
+            value: routeConfig.input || "",
+            onchange: (event) => (routeConfig.input = event.target.value),
+          }),
+          el("span", { className: "spacer" }),
+          el("button", { onclick: () => { moveItem(routes, index, -1); renderRoutes(); } }, "Up"),
+          el("button", { onclick: () => { moveItem(routes, index, 1); renderRoutes(); } }, "Down"),
+          el("button", { className: "danger", onclick: () => { routes.splice(index, 1); renderRoutes(); } }, "Remove"),
+        ),
+        renderProcessors(routeConfig),
+      ),
+    ),
+  );
+}
+
+function renderModuleIds() {
+  const ids = new Set([
+    ...(state.config.modules || []).map((moduleConfig) => moduleConfig.id),
+    ...Object.keys(state.moduleStatuses),
+  ]);
+  $("module-ids").replaceChildren(...[...ids].filter(Boolean).map((id) => el("option", { value: id })));
+}
+
+function renderConfig() {
+  renderApiConfig();
+  renderModules();
+  renderRoutes();
+  renderModuleIds();
+  $("config-raw").value = JSON.stringify(state.config, null, 2);
+}
+
+function currentConfig() {
+  if (state.rawMode) {
+    state.config = JSON.parse($("config-raw").value);
+  }
+  return state.config;
+}
+
+function showConfigResult(ok, message) {
+  $("config-result").replaceChildren(el("div", { className: `result ${ok ? "good" : "bad"}` }, message));
+}
+
+function describeConfigErrors(body) {
+  if (typeof body === "string") {
+    return body;
+  }
+  const lines = [];
+  if (body?.error) {
+    lines.push(body.error);
+  }
+  for (const moduleError of body?.moduleErrors || []) {
+    lines.push(`module[${moduleError.index}] ${moduleError.config?.id || ""}: ${moduleError.error}`);
+  }
+  for (const routeError of body?.routeErrors || []) {
+    lines.push(`route[${routeError.index}] ${routeError.config?.id || ""}: ${routeError.error}`);
+  }
+  return lines.join("\n") || JSON.stringify(body);
+}
+
+async function loadConfig() {
+  const response = await fetchJSON("/api/v1/config");
+  if (!response.ok) {
+    showConfigResult(false, `failed to load config: ${describeConfigErrors(response.body)}`);
+    return;
+  }
+  state.config = response.body;
+  renderConfig();
+}
+
+async function validateConfig() {
+  let cfg;
+  try {
+    cfg = currentConfig();
+  } catch (error) {
+    showConfigResult(false, `invalid JSON: ${error.message}`);
+    return false;
+  }
+  const response = await fetchJSON("/api/v1/config/validate", {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(cfg),
+  });
+  if (response.ok && response.body?.valid) {
+    showConfigResult(true, "config is valid");
+    return true;
+  }
+  showConfigResult(false, describeConfigErrors(response.body));
+  return false;
+}
+
+async function applyConfig() {
+  const valid = await validateConfig();
+  if (!valid) {
+    return;
+  }
+  const response = await fetchJSON("/api/v1/config", {
+    method: "PUT",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(state.config),
+  });
+  if (!response.ok) {
+    showConfigResult(false, describeConfigErrors(response.body));
+    return;
+  }
+  showConfigResult(true, "config applied");
+  await loadConfig();
+  await loadModuleStatuses();
+}
+
+function toggleRawMode() {
+  if (state.rawMode) {
+    try {
+      state.config = JSON.parse($("config-raw").value);
+    } catch (error) {
+      showConfigResult(false, `invalid JSON: ${error.message}`);
+      return;
+    }
+  }
+  state.rawMode = !state.rawMode;
+  renderConfig();
+  $("config-raw").classList.toggle("hidden", !state.rawMode);
+  $("config-form").classList.toggle("hidden", state.rawMode);
+  $("config-raw-toggle").textContent = state.rawMode ? "Edit Form" : "Edit JSON";
+}
+
+// status view
+
+function renderModuleStatuses() {
+  const statuses = Object.values(state.moduleStatuses).sort((a, b) => a.id.localeCompare(b.id));
+  $("module-status").replaceChildren(
+    ...statuses.map((moduleStatus) =>
+      el(
+        "tr",
+        {},
+        el("td", {}, moduleStatus.id),
+        el("td", {}, moduleStatus.type),
+        el("td", { className: `status-${moduleStatus.status}` }, moduleStatus.status),
+        el("td", {}, moduleStatus.error || ""),
+      ),
+    ),
+  );
+  renderModuleIds();
+}
+
+async function loadModuleStatuses() {
+  const response = await fetchJSON("/api/v1/modules");
+  if (!response.ok) {
+    return;
+  }
+  state.moduleStatuses = {};
+  for (const moduleStatus of response.body || []) {
+    state.moduleStatuses[moduleStatus.id] = moduleStatus;
+  }
+  renderModuleStatuses();
+}
+
+// events view
+
+function eventMatchesFilter(event) {
+  if ($("event-errors-only").checked && !event.error) {
+    return false;
+  }
+  const filter = $("event-filter").value.trim().toLowerCase();
+  if (filter === "") {
+    return true;
+  }
+  return JSON.stringify(event).toLowerCase().includes(filter);
+}
+
+function eventListItem(entry) {
+  const { received, event } = entry;
+  return el(
+    "li",
+    { className: event.error ? "error" : "" },
+    `${received.toLocaleTimeString()} ${event.type} ${event.data === undefined ? "" : JSON.stringify(event.data)}${event.error ? ` error: ${event.error}` : ""}`,
+  );
+}
+
+function renderEvents() {
+  $("event-log").replaceChildren(
+    ...state.events
+      .filter((entry) => eventMatchesFilter(entry.event))
+      .reverse()
+      .map(eventListItem),
+  );
+}
+
+function recordEvent(event) {
+  const entry = { received: new Date(), event };
+  state.events.push(entry);
+  if (state.events.length > maxEvents) {
+    state.events.shift();
+  }
+  if (!state.eventsPaused && eventMatchesFilter(event)) {
+    $("event-log").prepend(eventListItem(entry));
+    while ($("event-log").childElementCount > maxEvents) {
+      $("event-log").lastChild.remove();
+    }
+  }
+}
+
+// inject view
+
+function showInjectResult(event) {
+  const data = event.data || {};
+  let message = `${new Date().toLocaleTimeString()} ${data.source || ""}`;
+  if (event.error) {
+    message += ` error: ${event.error}`;
+  } else {
+    message += data.routeFound ? " routed" : " no matching routes";
+  }
+  $("inject-results").prepend(el("li", { className: event.error ? "error" : "" }, message));
+}
+
+function sendInject(submitEvent) {
+  submitEvent.preventDefault();
+  const encoding = $("inject-encoding").value;
+  let payload = $("inject-payload").value;
+  if (encoding === "json" || encoding === "osc") {
+    try {
+      payload = JSON.parse(payload);
+    } catch (error) {
+      showInjectResult({ error: `invalid JSON: ${error.message}` });
+      return;
+    }
+  }
+  sendEvent({
+    type: "inject",
+    data: {
+      source: $("inject-source").value,
+      encoding,
+      payload,
+    },
+  });
+}
+
+// websocket
+
+function sendEvent(event) {
+  if (!state.socket || state.socket.readyState !== WebSocket.OPEN) {
+    showInjectResult({ error: "not connected" });
+    return;
+  }
+  state.socket.send(JSON.stringify(event));
+}
+
+function setConnected(connected) {
+  const badge = $("connection");
+  badge.textContent = connected ? "connected" : "disconnected";
+  badge.className = `badge ${connected ? "good" : "bad"}`;
+}
+
+function connect() {
+  const protocol = window.location.protocol === "https:" ? "wss:" : "ws:";
+  const socket = new WebSocket(`${protocol}//${window.location.host}/ws`);
+  state.socket = socket;
+
+  socket.addEventListener("open", () => {
+    setConnected(true);
+    loadModuleStatuses();
+  });
+
+  socket.addEventListener("close", () => {
+    setConnected(false);
+    setTimeout(connect, 2000);
+  });
+
+  socket.addEventListener("message", (message) => {
+    let event;
+    try {
+      event = JSON.parse(message.data);
+    } catch (error) {
+      return;
+    }
+    switch (event.type) {
+      case "module":
+        state.moduleStatuses[event.data.id] = { ...event.data, error: event.error };
+        renderModuleStatuses();
+        break;
+      case "inject":
+        showInjectResult(event);
+        return;
+    }
+    recordEvent(event);
+  });
+}
+
+// setup
+
+function showView(view) {
+  for (const tab of document.querySelectorAll(".tab")) {
+    tab.classList.toggle("active", tab.dataset.view === view);
+  }
+  for (const section of document.querySelectorAll(".view")) {
+    section.classList.toggle("active", section.id === `view-${view}`);
+  }
+}
+
+function setup() {
+  for (const tab of document.querySelectorAll(".tab")) {
+    tab.addEventListener("click", () => showView(tab.dataset.view));
+  }
+
+  $("event-filter").addEventListener("input", renderEvents);
+  $("event-errors-only").addEventListener("change", renderEvents);
+  $("event-pause").addEventListener("click", (event) => {
+    state.eventsPaused = !state.eventsPaused;
+    event.target.textContent = state.eventsPaused ? "Resume" : "Pause";
+    if (!state.eventsPaused) {
+      renderEvents();
+    }
+  });
+  $("event-clear").addEventListener("click", () => {
+    state.events = [];
+    renderEvents();
+  });
+
+  $("config-reload").addEventListener("click", loadConfig);
+  $("config-raw-toggle").addEventListener("click", toggleRawMode);
+  $("config-validate").addEventListener("click", validateConfig);
+  $("config-apply").addEventListener("click", applyConfig);
+  $("config-add-module").addEventListener("click", () => {
+    state.config.modules.push({ id: "", type: "" });
+    renderModules();
+  });
+  $("config-add-route").addEventListener("click", () => {
+    state.config.routes.push({ id: "", input: "", processors: [] });
+    renderRoutes();
+  });
+
+  $("inject-form").addEventListener("submit", sendInject);
+
+  loadSchemas().then(loadConfig);
+  loadModuleStatuses();
+  connect();
+}
+
+setup();
@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"sync"
 
 	"github.com/gorilla/websocket"
 	"github.com/jwetzell/showbridge-go/internal/common"
@@ -15,7 +16,8 @@ var upgrader = websocket.Upgrader{
 }
 
 type WebsocketEventDestination struct {
-	conn *websocket.Conn
+	conn    *websocket.Conn
+	writeMu *sync.Mutex
 }
 
 func (d WebsocketEventDestination) Send(event common.Event) error {
@@ -23,6 +25,8 @@ func (d WebsocketEventDestination) Send(event common.Event) error {
 	if err != nil {
 		return err
 	}
+	d.writeMu.Lock()
+	defer d.writeMu.Unlock()
 	return d.conn.WriteMessage(websocket.TextMessage, eventJSON)
 }
 
@@ -42,17 +46,15 @@ func (as *ApiServer) handleWebsocket(w http.ResponseWriter, req *http.Request) {
 	}
 	defer conn.Close()
 
-	eventDestination := WebsocketEventDestination{conn: conn}
+	eventDestination := WebsocketEventDestination{conn: conn, writeMu: &sync.Mutex{}}
 
-	as.eventRouter.AddEventDestination(eventDestination)
+	as.router.AddEventDestination(eventDestination)
 READ_LOOP:
 	for {
 		messageType, message, err := conn.ReadMessage()
 		if err != nil {
-			_, ok := err.(*websocket.CloseError)
-			if ok {
-				break READ_LOOP
-			}
+			//NOTE(jwetzell): gorilla connections are unusable after any read error
+			break READ_LOOP
 		}
 
 		switch messageType {
@@ -63,11 +65,13 @@ READ_LOOP:
 				as.logger.Error("websocket message unmarshal error", "error", err)
 				continue
 			}
-			as.eventRouter.HandleEvent(event, WebsocketEventDestination{conn: conn})
+			as.router.HandleEvent(event, eventDestination)
 		case websocket.CloseMessage:
 			break READ_LOOP
 		case websocket.PingMessage:
+			eventDestination.writeMu.Lock()
 			err = conn.WriteMessage(websocket.PongMessage, nil)
+			eventDestination.writeMu.Unlock()
 			if err != nil {
 				as.logger.Error("websocket pong error", "error", err)
 			}
@@ -78,5 +82,5 @@ READ_LOOP:
 
 	}
 	//NOTE(jwetzell): remove ws connection
-	as.eventRouter.RemoveEventDestination(eventDestination)
+	as.router.RemoveEventDestination(eventDestination)
 }
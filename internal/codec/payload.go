@@ -0,0 +1,258 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jwetzell/osc-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// DecodePayload turns the loosely typed payload from a test file or event into the value a module would have produced
+func DecodePayload(encoding string, rawPayload any) (any, error) {
+	switch encoding {
+	case "", "json":
+		return rawPayload, nil
+	case "string":
+		payloadString, ok := rawPayload.(string)
+		if !ok {
+			return nil, errors.New("string payload must be a string")
+		}
+		return payloadString, nil
+	case "hex":
+		payloadString, ok := rawPayload.(string)
+		if !ok {
+			return nil, errors.New("hex payload must be a string")
+		}
+		payloadBytes, err := hex.DecodeString(strings.ReplaceAll(payloadString, " ", ""))
+		if err != nil {
+			return nil, fmt.Errorf("hex payload error: %w", err)
+		}
+		return payloadBytes, nil
+	case "midi":
+		payloadString, ok := rawPayload.(string)
+		if !ok {
+			return nil, errors.New("midi payload must be a hex string")
+		}
+		payloadBytes, err := hex.DecodeString(strings.ReplaceAll(payloadString, " ", ""))
+		if err != nil {
+			return nil, fmt.Errorf("midi payload error: %w", err)
+		}
+		return midi.Message(payloadBytes), nil
+	case "osc":
+		return decodeOSCMessage(rawPayload)
+	case "time":
+		payloadString, ok := rawPayload.(string)
+		if !ok {
+			return nil, errors.New("time payload must be a string")
+		}
+		payloadTime, err := time.Parse(time.RFC3339Nano, payloadString)
+		if err != nil {
+			return nil, fmt.Errorf("time payload error: %w", err)
+		}
+		return payloadTime, nil
+	default:
+		return nil, fmt.Errorf("unknown payload encoding: %s", encoding)
+	}
+}
+
+// EncodePayload is the inverse of DecodePayload, types without a dedicated encoding fall back to their JSON form
+func EncodePayload(payload any) (string, any, error) {
+	switch typedPayload := payload.(type) {
+	case []byte:
+		return "hex", hex.EncodeToString(typedPayload), nil
+	case midi.Message:
+		return "midi", hex.EncodeToString(typedPayload), nil
+	case string:
+		return "string", typedPayload, nil
+	case time.Time:
+		return "time", typedPayload.Format(time.RFC3339Nano), nil
+	case *osc.OSCMessage:
+		return "osc", encodeOSCMessage(typedPayload), nil
+	case osc.OSCMessage:
+		return "osc", encodeOSCMessage(&typedPayload), nil
+	}
+
+	normalized, err := normalizePayload(payload)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to encode payload of type %T: %w", payload, err)
+	}
+	return "json", normalized, nil
+}
+
+func encodeOSCMessage(message *osc.OSCMessage) map[string]any {
+	args := []any{}
+	for _, arg := range message.Args {
+		encodedArg := map[string]any{
+			"type": arg.Type,
+		}
+		switch value := arg.Value.(type) {
+		case []byte:
+			encodedArg["value"] = hex.EncodeToString(value)
+		case nil:
+		default:
+			encodedArg["value"] = value
+		}
+		args = append(args, encodedArg)
+	}
+	return map[string]any{
+		"address": message.Address,
+		"args":    args,
+	}
+}
+
+func decodeOSCMessage(rawPayload any) (*osc.OSCMessage, error) {
+	payloadMap, ok := rawPayload.(map[string]any)
+	if !ok {
+		return nil, errors.New("osc payload must be an object")
+	}
+
+	address, ok := payloadMap["address"].(string)
+	if !ok || !strings.HasPrefix(address, "/") {
+		return nil, errors.New("osc payload address must be a string starting with '/'")
+	}
+
+	message := &osc.OSCMessage{
+		Address: address,
+		Args:    []osc.OSCArg{},
+	}
+
+	rawArgs, ok := payloadMap["args"]
+	if !ok || rawArgs == nil {
+		return message, nil
+	}
+
+	args, ok := rawArgs.([]any)
+	if !ok {
+		return nil, errors.New("osc payload args must be an array")
+	}
+
+	for argIndex, rawArg := range args {
+		arg, err := decodeOSCArg(rawArg)
+		if err != nil {
+			return nil, fmt.Errorf("osc payload args[%d] error: %w", argIndex, err)
+		}
+		message.Args = append(message.Args, arg)
+	}
+	return message, nil
+}
+
+func decodeOSCArg(rawArg any) (osc.OSCArg, error) {
+	argMap, ok := rawArg.(map[string]any)
+	if !ok {
+		return osc.OSCArg{}, errors.New("must be an object with a type and value")
+	}
+
+	argType, ok := argMap["type"].(string)
+	if !ok {
+		return osc.OSCArg{}, errors.New("type must be a string")
+	}
+
+	value := argMap["value"]
+
+	switch argType {
+	case "s":
+		stringValue, ok := value.(string)
+		if !ok {
+			return osc.OSCArg{}, errors.New("value must be a string")
+		}
+		return osc.OSCArg{Type: argType, Value: stringValue}, nil
+	case "i":
+		number, ok := common.GetAnyAsInt(value)
+		if !ok {
+			return osc.OSCArg{}, errors.New("value must be an integer")
+		}
+		return osc.OSCArg{Type: argType, Value: int32(number)}, nil
+	case "h":
+		number, ok := common.GetAnyAsInt(value)
+		if !ok {
+			return osc.OSCArg{}, errors.New("value must be an integer")
+		}
+		return osc.OSCArg{Type: argType, Value: int64(number)}, nil
+	case "f":
+		number, ok := value.(float64)
+		if !ok {
+			return osc.OSCArg{}, errors.New("value must be a number")
+		}
+		return osc.OSCArg{Type: argType, Value: float32(number)}, nil
+	case "d":
+		number, ok := value.(float64)
+		if !ok {
+			return osc.OSCArg{}, errors.New("value must be a number")
+		}
+		return osc.OSCArg{Type: argType, Value: number}, nil
+	case "b":
+		hexString, ok := value.(string)
+		if !ok {
+			return osc.OSCArg{}, errors.New("value must be a hex string")
+		}
+		data, err := hex.DecodeString(strings.ReplaceAll(hexString, " ", ""))
+		if err != nil {
+			return osc.OSCArg{}, err
+		}
+		return osc.OSCArg{Type: argType, Value: data}, nil
+	case "T":
+		return osc.OSCArg{Type: argType, Value: true}, nil
+	case "F":
+		return osc.OSCArg{Type: argType, Value: false}, nil
+	case "N":
+		return osc.OSCArg{Type: argType, Value: nil}, nil
+	default:
+		return osc.OSCArg{}, fmt.Errorf("unhandled osc type: %s", argType)
+	}
+}
+
+// PayloadsEqual compares byte payloads exactly and everything else by its JSON form so number types don't matter
+func PayloadsEqual(expected any, actual any) bool {
+	expectedBytes, expectedIsBytes := expected.([]byte)
+	actualBytes, actualIsBytes := actual.([]byte)
+	if expectedIsBytes || actualIsBytes {
+		return expectedIsBytes && actualIsBytes && bytes.Equal(expectedBytes, actualBytes)
+	}
+
+	normalizedExpected, err := normalizePayload(expected)
+	if err != nil {
+		return false
+	}
+	normalizedActual, err := normalizePayload(actual)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(normalizedExpected, normalizedActual)
+}
+
+func normalizePayload(payload any) (any, error) {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var normalized any
+	err = json.Unmarshal(payloadJSON, &normalized)
+	if err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// FormatPayload renders a payload for failure messages
+func FormatPayload(payload any) string {
+	switch typedPayload := payload.(type) {
+	case []byte:
+		return fmt.Sprintf("hex(%s)", hex.EncodeToString(typedPayload))
+	case string:
+		return fmt.Sprintf("%q", typedPayload)
+	}
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Sprintf("%+v", payload)
+	}
+	return string(payloadJSON)
+}
@@ -0,0 +1,276 @@
+package codec_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/osc-go"
+	"github.com/jwetzell/showbridge-go/internal/codec"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func TestGoodDecodePayload(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding string
+		payload  any
+		expected any
+	}{
+		{
+			name:     "default json",
+			encoding: "",
+			payload:  map[string]any{"a": 1.0},
+			expected: map[string]any{"a": 1.0},
+		},
+		{
+			name:     "string",
+			encoding: "string",
+			payload:  "hello",
+			expected: "hello",
+		},
+		{
+			name:     "hex with spaces",
+			encoding: "hex",
+			payload:  "01 02 ff",
+			expected: []byte{0x01, 0x02, 0xff},
+		},
+		{
+			name:     "osc message",
+			encoding: "osc",
+			payload: map[string]any{
+				"address": "/test",
+				"args": []any{
+					map[string]any{"type": "s", "value": "hi"},
+					map[string]any{"type": "i", "value": 1.0},
+					map[string]any{"type": "f", "value": 0.5},
+					map[string]any{"type": "T"},
+				},
+			},
+			expected: &osc.OSCMessage{
+				Address: "/test",
+				Args: []osc.OSCArg{
+					{Type: "s", Value: "hi"},
+					{Type: "i", Value: int32(1)},
+					{Type: "f", Value: float32(0.5)},
+					{Type: "T", Value: true},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := codec.DecodePayload(test.encoding, test.payload)
+			if err != nil {
+				t.Fatalf("DecodePayload failed: %s", err)
+			}
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Fatalf("DecodePayload got %+v, expected %+v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadDecodePayload(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding string
+		payload  any
+		errorMsg string
+	}{
+		{
+			name:     "unknown encoding",
+			encoding: "base32",
+			payload:  "",
+			errorMsg: "unknown payload encoding: base32",
+		},
+		{
+			name:     "string not a string",
+			encoding: "string",
+			payload:  1,
+			errorMsg: "string payload must be a string",
+		},
+		{
+			name:     "bad hex",
+			encoding: "hex",
+			payload:  "zz",
+			errorMsg: "hex payload error: encoding/hex: invalid byte: U+007A 'z'",
+		},
+		{
+			name:     "osc without address",
+			encoding: "osc",
+			payload:  map[string]any{},
+			errorMsg: "osc payload address must be a string starting with '/'",
+		},
+		{
+			name:     "osc bad arg type",
+			encoding: "osc",
+			payload: map[string]any{
+				"address": "/test",
+				"args":    []any{map[string]any{"type": "q", "value": 1.0}},
+			},
+			errorMsg: "osc payload args[0] error: unhandled osc type: q",
+		},
+		{
+			name:     "osc int arg not an integer",
+			encoding: "osc",
+			payload: map[string]any{
+				"address": "/test",
+				"args":    []any{map[string]any{"type": "i", "value": 1.5}},
+			},
+			errorMsg: "osc payload args[0] error: value must be an integer",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := codec.DecodePayload(test.encoding, test.payload)
+			if err == nil {
+				t.Fatalf("DecodePayload expected to fail but succeeded")
+			}
+			if err.Error() != test.errorMsg {
+				t.Fatalf("DecodePayload got error '%s', expected '%s'", err.Error(), test.errorMsg)
+			}
+		})
+	}
+}
+
+func TestPayloadsEqual(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected any
+		actual   any
+		equal    bool
+	}{
+		{
+			name:     "same bytes",
+			expected: []byte{1, 2},
+			actual:   []byte{1, 2},
+			equal:    true,
+		},
+		{
+			name:     "different bytes",
+			expected: []byte{1, 2},
+			actual:   []byte{1, 3},
+			equal:    false,
+		},
+		{
+			name:     "bytes and string",
+			expected: []byte("a"),
+			actual:   "a",
+			equal:    false,
+		},
+		{
+			name:     "numbers of different types",
+			expected: map[string]any{"a": 1.0},
+			actual:   map[string]int{"a": 1},
+			equal:    true,
+		},
+		{
+			name:     "osc message",
+			expected: &osc.OSCMessage{Address: "/a", Args: []osc.OSCArg{{Type: "i", Value: int32(1)}}},
+			actual:   &osc.OSCMessage{Address: "/a", Args: []osc.OSCArg{{Type: "i", Value: int32(1)}}},
+			equal:    true,
+		},
+		{
+			name:     "different osc message",
+			expected: &osc.OSCMessage{Address: "/a"},
+			actual:   &osc.OSCMessage{Address: "/b"},
+			equal:    false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if codec.PayloadsEqual(test.expected, test.actual) != test.equal {
+				t.Fatalf("PayloadsEqual expected %t", test.equal)
+			}
+		})
+	}
+}
+
+func TestEncodePayload(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  any
+		encoding string
+	}{
+		{
+			name:     "bytes",
+			payload:  []byte{0x01, 0x02},
+			encoding: "hex",
+		},
+		{
+			name:     "midi message",
+			payload:  midi.NoteOn(1, 60, 127),
+			encoding: "midi",
+		},
+		{
+			name:     "string",
+			payload:  "hello",
+			encoding: "string",
+		},
+		{
+			name:     "time",
+			payload:  time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC),
+			encoding: "time",
+		},
+		{
+			name: "osc message",
+			payload: &osc.OSCMessage{
+				Address: "/test",
+				Args: []osc.OSCArg{
+					{Type: "i", Value: int32(1)},
+					{Type: "b", Value: []byte{0xff}},
+					{Type: "N", Value: nil},
+				},
+			},
+			encoding: "osc",
+		},
+		{
+			name:     "map",
+			payload:  map[string]any{"a": 1.0},
+			encoding: "json",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoding, encodedPayload, err := codec.EncodePayload(test.payload)
+			if err != nil {
+				t.Fatalf("EncodePayload failed: %s", err)
+			}
+			if encoding != test.encoding {
+				t.Fatalf("EncodePayload got encoding %s, expected %s", encoding, test.encoding)
+			}
+
+			//NOTE(jwetzell): round trip through JSON the same way a recording file would
+			encodedJSON, err := json.Marshal(encodedPayload)
+			if err != nil {
+				t.Fatalf("failed to marshal encoded payload: %s", err)
+			}
+			var rawPayload any
+			err = json.Unmarshal(encodedJSON, &rawPayload)
+			if err != nil {
+				t.Fatalf("failed to unmarshal encoded payload: %s", err)
+			}
+
+			decodedPayload, err := codec.DecodePayload(encoding, rawPayload)
+			if err != nil {
+				t.Fatalf("DecodePayload failed: %s", err)
+			}
+			if !reflect.DeepEqual(decodedPayload, test.payload) {
+				t.Fatalf("payload did not survive a round trip, got %+v, expected %+v", decodedPayload, test.payload)
+			}
+		})
+	}
+}
+
+func TestBadEncodePayload(t *testing.T) {
+	_, _, err := codec.EncodePayload(func() {})
+	if err == nil {
+		t.Fatalf("EncodePayload should fail for a payload that can't be encoded")
+	}
+}
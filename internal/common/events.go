@@ -2,6 +2,7 @@ package common
 
 import (
 	"encoding/json"
+	"time"
 )
 
 type Event struct {
@@ -24,3 +25,25 @@ type EventRouter interface {
 	AddEventDestination(dest EventDestination)
 	RemoveEventDestination(dest EventDestination)
 }
+
+type EventRecord struct {
+	Id        uint64    `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+	Event
+}
+
+type EventQuery struct {
+	After     uint64
+	Since     time.Time
+	Until     time.Time
+	Types     []string
+	Module    string
+	Route     string
+	ErrorOnly bool
+	Limit     int
+}
+
+type EventHistoryRouter interface {
+	QueryEvents(query EventQuery) ([]EventRecord, error)
+	SubscribeEvents() (<-chan EventRecord, func())
+}
@@ -28,3 +28,14 @@ type DatabaseModule interface {
 type PubSubModule interface {
 	Publish(ctx context.Context, topic string, payload any) error
 }
+
+type ModuleStatus struct {
+	Id     string `json:"id"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ModuleStatusRouter interface {
+	GetModuleStatuses() []ModuleStatus
+}
@@ -7,3 +7,6 @@ type WrappedPayload struct {
 	Source       string
 	End          bool
 }
+
+// FanOut is a payload whose items each continue through the rest of the route on their own, in order
+type FanOut []any
@@ -2,6 +2,7 @@ package common
 
 import (
 	"context"
+	"net"
 )
 
 type InputHandler func(ctx context.Context, sourceId string, payload any) (bool, []RouteIOError)
@@ -10,3 +11,35 @@ type RouteIOError struct {
 	Index        int   `json:"index"`
 	ProcessError error `json:"processError"`
 }
+
+type remoteAddrContextKey struct{}
+
+// WithRemoteAddr records the address an input was received from so processors can tell senders on one module apart
+func WithRemoteAddr(ctx context.Context, addr net.Addr) context.Context {
+	return context.WithValue(ctx, remoteAddrContextKey{}, addr)
+}
+
+// RemoteAddr returns the address recorded with WithRemoteAddr
+func RemoteAddr(ctx context.Context) (net.Addr, bool) {
+	addr, ok := ctx.Value(remoteAddrContextKey{}).(net.Addr)
+	return addr, ok && addr != nil
+}
+
+// ModuleStatusReporter records a status for a running module beyond running and stopped, like a connection coming and going
+type ModuleStatusReporter func(status string, err error)
+
+type moduleStatusReporterContextKey struct{}
+
+// WithModuleStatusReporter hands a module the reporter it uses to update its status from inside Start
+func WithModuleStatusReporter(ctx context.Context, reporter ModuleStatusReporter) context.Context {
+	return context.WithValue(ctx, moduleStatusReporterContextKey{}, reporter)
+}
+
+// ReportModuleStatus updates the status of the module the context was started with, it does nothing without a reporter
+func ReportModuleStatus(ctx context.Context, status string, err error) {
+	reporter, ok := ctx.Value(moduleStatusReporterContextKey{}).(ModuleStatusReporter)
+	if !ok || reporter == nil {
+		return
+	}
+	reporter(status, err)
+}
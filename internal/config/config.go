@@ -1,12 +1,15 @@
 package config
 
 type Config struct {
-	Api     ApiConfig      `json:"api"`
-	Modules []ModuleConfig `json:"modules"`
-	Routes  []RouteConfig  `json:"routes"`
+	Api       ApiConfig        `json:"api"`
+	EventLog  EventLogConfig   `json:"eventLog"`
+	Recording *RecordingConfig `json:"recording,omitempty"`
+	Modules   []ModuleConfig   `json:"modules"`
+	Routes    []RouteConfig    `json:"routes"`
 }
 
 type Configurable interface {
 	UpdateConfig(newConfig Config, triggerChangeChannel bool) ([]ModuleError, []RouteError, error)
 	GetRunningConfig() Config
+	ValidateConfig(newConfig Config) ([]ModuleError, []RouteError)
 }
@@ -0,0 +1,7 @@
+package config
+
+type EventLogConfig struct {
+	Size    int    `json:"size"`
+	Path    string `json:"path,omitempty"`
+	MaxRows int    `json:"maxRows,omitempty"`
+}
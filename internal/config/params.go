@@ -18,6 +18,8 @@ var (
 	ErrParamNotStringSlice = errors.New("not a string slice")
 	ErrParamNotByteSlice   = errors.New("not a byte slice")
 	ErrParamNotIntSlice    = errors.New("not an int slice")
+	ErrParamNotMap         = errors.New("not a map")
+	ErrParamNotStringMap   = errors.New("not a string map")
 )
 
 func (p Params) GetString(key string) (string, error) {
@@ -138,3 +140,39 @@ func (p Params) GetByteSlice(key string) ([]byte, error) {
 
 	return byteSlice, nil
 }
+
+func (p Params) GetStringMap(key string) (map[string]string, error) {
+	value, ok := p[key]
+	if !ok {
+		return nil, ErrParamNotFound
+	}
+
+	interfaceMap, ok := value.(map[string]any)
+	if !ok {
+		return nil, ErrParamNotMap
+	}
+
+	stringMap := make(map[string]string, len(interfaceMap))
+	for k, v := range interfaceMap {
+		str, ok := v.(string)
+		if !ok {
+			return nil, ErrParamNotStringMap
+		}
+		stringMap[k] = str
+	}
+	return stringMap, nil
+}
+
+func (p Params) GetParams(key string) (Params, error) {
+	value, ok := p[key]
+	if !ok {
+		return nil, ErrParamNotFound
+	}
+
+	interfaceMap, ok := value.(map[string]any)
+	if !ok {
+		return nil, ErrParamNotMap
+	}
+
+	return Params(interfaceMap), nil
+}
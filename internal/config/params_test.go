@@ -3,6 +3,7 @@ package config_test
 import (
 	"encoding/json"
 	"errors"
+	"maps"
 	"slices"
 	"testing"
 
@@ -626,3 +627,140 @@ func TestBadByteSliceParamsJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestGoodStringMapParamsJSON(t *testing.T) {
+	testCases := []struct {
+		name       string
+		paramsJSON string
+		key        string
+		expected   map[string]string
+	}{
+		{
+			name:       "string map",
+			paramsJSON: `{"key": {"a": "value1", "b": "value2"}}`,
+			key:        "key",
+			expected:   map[string]string{"a": "value1", "b": "value2"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			params := config.Params{}
+			err := json.Unmarshal([]byte(testCase.paramsJSON), &params)
+			if err != nil {
+				t.Fatalf("Failed to unmarshal params JSON: %v", err)
+			}
+			value, err := params.GetStringMap(testCase.key)
+			if err != nil {
+				t.Fatalf("GetStringMap returned error: %v", err)
+			}
+			if !maps.Equal(value, testCase.expected) {
+				t.Fatalf("GetStringMap got %v, expected %v", value, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestBadStringMapParamsJSON(t *testing.T) {
+	testCases := []struct {
+		name        string
+		paramsJSON  string
+		key         string
+		returnError error
+	}{
+		{
+			name:        "key not found",
+			paramsJSON:  `{"key": {"a": "value1"}}`,
+			key:         "test",
+			returnError: config.ErrParamNotFound,
+		},
+		{
+			name:        "not a map",
+			paramsJSON:  `{"key": ["value1"]}`,
+			key:         "key",
+			returnError: config.ErrParamNotMap,
+		},
+		{
+			name:        "not a string map",
+			paramsJSON:  `{"key": {"a": 1}}`,
+			key:         "key",
+			returnError: config.ErrParamNotStringMap,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			params := config.Params{}
+			err := json.Unmarshal([]byte(testCase.paramsJSON), &params)
+			if err != nil {
+				t.Fatalf("Failed to unmarshal params JSON: %v", err)
+			}
+			value, err := params.GetStringMap(testCase.key)
+			if err == nil {
+				t.Fatalf("GetStringMap expected to fail but succeeded, got: %v", value)
+			}
+			if !errors.Is(err, testCase.returnError) {
+				t.Fatalf("GetStringMap got error '%s', expected '%s'", err, testCase.returnError)
+			}
+		})
+	}
+}
+
+func TestGoodParamsParamsJSON(t *testing.T) {
+	params := config.Params{}
+	err := json.Unmarshal([]byte(`{"key": {"a": "value1", "b": 2}}`), &params)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal params JSON: %v", err)
+	}
+	value, err := params.GetParams("key")
+	if err != nil {
+		t.Fatalf("GetParams returned error: %v", err)
+	}
+	valueString, err := value.GetString("a")
+	if err != nil || valueString != "value1" {
+		t.Fatalf("GetParams nested GetString got %q %v", valueString, err)
+	}
+	valueInt, err := value.GetInt("b")
+	if err != nil || valueInt != 2 {
+		t.Fatalf("GetParams nested GetInt got %d %v", valueInt, err)
+	}
+}
+
+func TestBadParamsParamsJSON(t *testing.T) {
+	testCases := []struct {
+		name        string
+		paramsJSON  string
+		key         string
+		returnError error
+	}{
+		{
+			name:        "key not found",
+			paramsJSON:  `{"key": {"a": "value1"}}`,
+			key:         "test",
+			returnError: config.ErrParamNotFound,
+		},
+		{
+			name:        "not a map",
+			paramsJSON:  `{"key": "value1"}`,
+			key:         "key",
+			returnError: config.ErrParamNotMap,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			params := config.Params{}
+			err := json.Unmarshal([]byte(testCase.paramsJSON), &params)
+			if err != nil {
+				t.Fatalf("Failed to unmarshal params JSON: %v", err)
+			}
+			value, err := params.GetParams(testCase.key)
+			if err == nil {
+				t.Fatalf("GetParams expected to fail but succeeded, got: %v", value)
+			}
+			if !errors.Is(err, testCase.returnError) {
+				t.Fatalf("GetParams got error '%s', expected '%s'", err, testCase.returnError)
+			}
+		})
+	}
+}
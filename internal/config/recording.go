@@ -0,0 +1,6 @@
+package config
+
+type RecordingConfig struct {
+	Path    string   `json:"path"`
+	Modules []string `json:"modules,omitempty"`
+}
@@ -0,0 +1,243 @@
+package eventlog
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+)
+
+const subscriberBufferSize = 256
+
+type EventLog struct {
+	config        config.EventLogConfig
+	records       []common.EventRecord
+	start         int
+	count         int
+	nextId        uint64
+	closed        bool
+	store         *sqliteStore
+	subscribers   map[chan common.EventRecord]struct{}
+	subscribersMu sync.Mutex
+	mu            sync.RWMutex
+	logger        *slog.Logger
+}
+
+func New(eventLogConfig config.EventLogConfig) (*EventLog, error) {
+	if eventLogConfig.Size <= 0 {
+		return nil, fmt.Errorf("event log size must be greater than 0")
+	}
+
+	eventLog := &EventLog{
+		config:      eventLogConfig,
+		records:     make([]common.EventRecord, eventLogConfig.Size),
+		nextId:      1,
+		subscribers: make(map[chan common.EventRecord]struct{}),
+		logger:      slog.Default().With("component", "eventlog"),
+	}
+
+	if eventLogConfig.Path != "" {
+		store, err := openSQLiteStore(eventLogConfig.Path, eventLogConfig.MaxRows, eventLog.logger)
+		if err != nil {
+			return nil, err
+		}
+		eventLog.store = store
+		eventLog.nextId = store.lastId + 1
+	}
+
+	return eventLog, nil
+}
+
+func (el *EventLog) Append(event common.Event) common.EventRecord {
+	el.mu.Lock()
+	if el.closed {
+		el.mu.Unlock()
+		return common.EventRecord{}
+	}
+	record := common.EventRecord{
+		Id:        el.nextId,
+		Timestamp: time.Now(),
+		Event:     event,
+	}
+	el.nextId++
+
+	index := (el.start + el.count) % len(el.records)
+	if el.count == len(el.records) {
+		el.start = (el.start + 1) % len(el.records)
+	} else {
+		el.count++
+	}
+	el.records[index] = record
+
+	if el.store != nil {
+		el.store.write(record)
+	}
+	el.mu.Unlock()
+
+	el.subscribersMu.Lock()
+	for subscriber := range el.subscribers {
+		select {
+		case subscriber <- record:
+		default:
+			el.logger.Warn("dropping event for slow subscriber", "id", record.Id)
+		}
+	}
+	el.subscribersMu.Unlock()
+
+	return record
+}
+
+func (el *EventLog) Query(query common.EventQuery) ([]common.EventRecord, error) {
+	el.mu.RLock()
+	memoryRecords := make([]common.EventRecord, 0, el.count)
+	for i := range el.count {
+		record := el.records[(el.start+i)%len(el.records)]
+		if Matches(record, query) {
+			memoryRecords = append(memoryRecords, record)
+		}
+	}
+	var oldestId uint64
+	if el.count > 0 {
+		oldestId = el.records[el.start].Id
+	} else {
+		oldestId = el.nextId
+	}
+	el.mu.RUnlock()
+
+	records := memoryRecords
+
+	//NOTE(jwetzell): only go to disk for events that have already fallen out of memory
+	needsOlder := query.After+1 < oldestId
+	if query.After == 0 && query.Limit > 0 && len(memoryRecords) >= query.Limit {
+		needsOlder = false
+	}
+	if el.store != nil && needsOlder {
+		storedRecords, err := el.store.query(query, oldestId)
+		if err != nil {
+			return nil, err
+		}
+		records = append(storedRecords, memoryRecords...)
+	}
+
+	if query.Limit > 0 && len(records) > query.Limit {
+		if query.After > 0 {
+			records = records[:query.Limit]
+		} else {
+			records = records[len(records)-query.Limit:]
+		}
+	}
+
+	return records, nil
+}
+
+func (el *EventLog) Subscribe() (<-chan common.EventRecord, func()) {
+	subscriber := make(chan common.EventRecord, subscriberBufferSize)
+
+	el.subscribersMu.Lock()
+	el.subscribers[subscriber] = struct{}{}
+	el.subscribersMu.Unlock()
+
+	return subscriber, func() {
+		el.subscribersMu.Lock()
+		defer el.subscribersMu.Unlock()
+		_, ok := el.subscribers[subscriber]
+		if ok {
+			delete(el.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+func (el *EventLog) Close() {
+	el.mu.Lock()
+	if el.closed {
+		el.mu.Unlock()
+		return
+	}
+	el.closed = true
+	el.mu.Unlock()
+
+	el.subscribersMu.Lock()
+	for subscriber := range el.subscribers {
+		delete(el.subscribers, subscriber)
+		close(subscriber)
+	}
+	el.subscribersMu.Unlock()
+
+	if el.store != nil {
+		el.store.close()
+	}
+}
+
+// Matches reports whether a record satisfies every filter set on the query, the limit is ignored
+func Matches(record common.EventRecord, query common.EventQuery) bool {
+	if record.Id <= query.After {
+		return false
+	}
+	if !query.Since.IsZero() && record.Timestamp.Before(query.Since) {
+		return false
+	}
+	if !query.Until.IsZero() && record.Timestamp.After(query.Until) {
+		return false
+	}
+	if len(query.Types) > 0 && !slices.Contains(query.Types, record.Type) {
+		return false
+	}
+	if query.ErrorOnly && record.Error == "" {
+		return false
+	}
+	if query.Module != "" && recordModule(record) != query.Module {
+		return false
+	}
+	if query.Route != "" {
+		routeId, routeIndex := recordRoute(record)
+		if query.Route != routeId && query.Route != routeIndex {
+			return false
+		}
+	}
+	return true
+}
+
+func recordData(record common.EventRecord) map[string]any {
+	data, ok := record.Data.(map[string]any)
+	if !ok {
+		return nil
+	}
+	return data
+}
+
+func recordModule(record common.EventRecord) string {
+	data := recordData(record)
+	if data == nil {
+		return ""
+	}
+	switch record.Type {
+	case "module":
+		moduleId, _ := data["id"].(string)
+		return moduleId
+	default:
+		source, _ := data["source"].(string)
+		return source
+	}
+}
+
+func recordRoute(record common.EventRecord) (string, string) {
+	if record.Type != "route" {
+		return "", ""
+	}
+	data := recordData(record)
+	if data == nil {
+		return "", ""
+	}
+	routeId, _ := data["id"].(string)
+	routeIndex := ""
+	index, ok := common.GetAnyAsInt(data["index"])
+	if ok {
+		routeIndex = fmt.Sprint(index)
+	}
+	return routeId, routeIndex
+}
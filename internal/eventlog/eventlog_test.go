@@ -0,0 +1,262 @@
+package eventlog_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/eventlog"
+)
+
+func recordIds(records []common.EventRecord) []uint64 {
+	ids := []uint64{}
+	for _, record := range records {
+		ids = append(ids, record.Id)
+	}
+	return ids
+}
+
+func idsEqual(a []uint64, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func appendTestEvents(eventLog *eventlog.EventLog) {
+	eventLog.Append(common.Event{Type: "module", Data: map[string]any{"id": "udp", "type": "net.udp.server", "status": "running"}})
+	eventLog.Append(common.Event{Type: "input", Data: map[string]any{"source": "udp"}})
+	eventLog.Append(common.Event{Type: "route", Data: map[string]any{"index": 0, "id": "udp-to-osc", "source": "udp"}})
+	eventLog.Append(common.Event{Type: "input", Data: map[string]any{"source": "http"}})
+	eventLog.Append(common.Event{Type: "route", Data: map[string]any{"index": 1, "id": "http-to-osc", "source": "http"}, Error: "processor[0] error: bad"})
+	eventLog.Append(common.Event{Type: "module", Data: map[string]any{"id": "udp", "type": "net.udp.server", "status": "error"}, Error: "address in use"})
+}
+
+func TestEventLogBadConfig(t *testing.T) {
+	_, err := eventlog.New(config.EventLogConfig{Size: 0})
+	if err == nil {
+		t.Fatalf("event log should not allow a size of 0")
+	}
+}
+
+func TestEventLogRingBuffer(t *testing.T) {
+	eventLog, err := eventlog.New(config.EventLogConfig{Size: 3})
+	if err != nil {
+		t.Fatalf("failed to create event log: %s", err)
+	}
+	defer eventLog.Close()
+
+	appendTestEvents(eventLog)
+
+	records, err := eventLog.Query(common.EventQuery{})
+	if err != nil {
+		t.Fatalf("event log query failed: %s", err)
+	}
+
+	expected := []uint64{4, 5, 6}
+	if !idsEqual(recordIds(records), expected) {
+		t.Fatalf("event log should only keep the most recent events, got %v, expected %v", recordIds(records), expected)
+	}
+}
+
+func TestEventLogQuery(t *testing.T) {
+	eventLog, err := eventlog.New(config.EventLogConfig{Size: 100})
+	if err != nil {
+		t.Fatalf("failed to create event log: %s", err)
+	}
+	defer eventLog.Close()
+
+	appendTestEvents(eventLog)
+
+	tests := []struct {
+		name     string
+		query    common.EventQuery
+		expected []uint64
+	}{
+		{
+			name:     "everything",
+			query:    common.EventQuery{},
+			expected: []uint64{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:     "after cursor",
+			query:    common.EventQuery{After: 4},
+			expected: []uint64{5, 6},
+		},
+		{
+			name:     "after cursor with limit",
+			query:    common.EventQuery{After: 1, Limit: 2},
+			expected: []uint64{2, 3},
+		},
+		{
+			name:     "most recent with limit",
+			query:    common.EventQuery{Limit: 2},
+			expected: []uint64{5, 6},
+		},
+		{
+			name:     "type",
+			query:    common.EventQuery{Types: []string{"input"}},
+			expected: []uint64{2, 4},
+		},
+		{
+			name:     "multiple types",
+			query:    common.EventQuery{Types: []string{"input", "route"}},
+			expected: []uint64{2, 3, 4, 5},
+		},
+		{
+			name:     "module",
+			query:    common.EventQuery{Module: "udp"},
+			expected: []uint64{1, 2, 3, 6},
+		},
+		{
+			name:     "route id",
+			query:    common.EventQuery{Route: "http-to-osc"},
+			expected: []uint64{5},
+		},
+		{
+			name:     "route index",
+			query:    common.EventQuery{Route: "0"},
+			expected: []uint64{3},
+		},
+		{
+			name:     "errors only",
+			query:    common.EventQuery{ErrorOnly: true},
+			expected: []uint64{5, 6},
+		},
+		{
+			name:     "until before everything",
+			query:    common.EventQuery{Until: time.Now().Add(-time.Hour)},
+			expected: []uint64{},
+		},
+		{
+			name:     "since in the future",
+			query:    common.EventQuery{Since: time.Now().Add(time.Hour)},
+			expected: []uint64{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			records, err := eventLog.Query(test.query)
+			if err != nil {
+				t.Fatalf("event log query failed: %s", err)
+			}
+			if !idsEqual(recordIds(records), test.expected) {
+				t.Fatalf("event log query got %v, expected %v", recordIds(records), test.expected)
+			}
+		})
+	}
+}
+
+func TestEventLogSubscribe(t *testing.T) {
+	eventLog, err := eventlog.New(config.EventLogConfig{Size: 10})
+	if err != nil {
+		t.Fatalf("failed to create event log: %s", err)
+	}
+	defer eventLog.Close()
+
+	records, unsubscribe := eventLog.Subscribe()
+
+	eventLog.Append(common.Event{Type: "input", Data: map[string]any{"source": "udp"}})
+
+	select {
+	case record := <-records:
+		if record.Id != 1 || record.Type != "input" {
+			t.Fatalf("subscriber got unexpected record: %+v", record)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("subscriber did not receive record")
+	}
+
+	unsubscribe()
+
+	_, ok := <-records
+	if ok {
+		t.Fatalf("subscriber channel should be closed after unsubscribe")
+	}
+}
+
+func TestEventLogSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.db")
+
+	eventLog, err := eventlog.New(config.EventLogConfig{Size: 2, Path: path})
+	if err != nil {
+		t.Fatalf("failed to create event log: %s", err)
+	}
+
+	appendTestEvents(eventLog)
+
+	// NOTE(jwetzell): give the store a chance to flush the events that have left memory
+	time.Sleep(500 * time.Millisecond)
+
+	records, err := eventLog.Query(common.EventQuery{})
+	if err != nil {
+		t.Fatalf("event log query failed: %s", err)
+	}
+	expected := []uint64{1, 2, 3, 4, 5, 6}
+	if !idsEqual(recordIds(records), expected) {
+		t.Fatalf("event log query should include events from disk, got %v, expected %v", recordIds(records), expected)
+	}
+
+	records, err = eventLog.Query(common.EventQuery{Route: "udp-to-osc"})
+	if err != nil {
+		t.Fatalf("event log query failed: %s", err)
+	}
+	if !idsEqual(recordIds(records), []uint64{3}) {
+		t.Fatalf("event log route query on disk got %v, expected [3]", recordIds(records))
+	}
+
+	eventLog.Close()
+
+	reopenedEventLog, err := eventlog.New(config.EventLogConfig{Size: 2, Path: path})
+	if err != nil {
+		t.Fatalf("failed to reopen event log: %s", err)
+	}
+	defer reopenedEventLog.Close()
+
+	record := reopenedEventLog.Append(common.Event{Type: "input", Data: map[string]any{"source": "udp"}})
+	if record.Id != 7 {
+		t.Fatalf("reopened event log should continue ids, got %d, expected 7", record.Id)
+	}
+
+	records, err = reopenedEventLog.Query(common.EventQuery{ErrorOnly: true})
+	if err != nil {
+		t.Fatalf("event log query failed: %s", err)
+	}
+	if !idsEqual(recordIds(records), []uint64{5, 6}) {
+		t.Fatalf("reopened event log should keep events from before, got %v, expected [5 6]", recordIds(records))
+	}
+	if records[0].Error != "processor[0] error: bad" {
+		t.Fatalf("event log should keep event errors, got '%s'", records[0].Error)
+	}
+}
+
+func TestEventLogSQLiteMaxRows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.db")
+
+	eventLog, err := eventlog.New(config.EventLogConfig{Size: 1, Path: path, MaxRows: 3})
+	if err != nil {
+		t.Fatalf("failed to create event log: %s", err)
+	}
+	defer eventLog.Close()
+
+	appendTestEvents(eventLog)
+
+	time.Sleep(500 * time.Millisecond)
+
+	records, err := eventLog.Query(common.EventQuery{})
+	if err != nil {
+		t.Fatalf("event log query failed: %s", err)
+	}
+	expected := []uint64{4, 5, 6}
+	if !idsEqual(recordIds(records), expected) {
+		t.Fatalf("event log should only keep maxRows events on disk, got %v, expected %v", recordIds(records), expected)
+	}
+}
@@ -0,0 +1,241 @@
+package eventlog
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	storeBatchSize     = 100
+	storeFlushInterval = 200 * time.Millisecond
+	storeQueueSize     = 4096
+)
+
+type sqliteStore struct {
+	db      *sql.DB
+	maxRows int
+	lastId  uint64
+	queue   chan common.EventRecord
+	done    chan struct{}
+	logger  *slog.Logger
+}
+
+func openSQLiteStore(path string, maxRows int, logger *slog.Logger) (*sqliteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("event log error opening database: %w", err)
+	}
+	//NOTE(jwetzell): a single connection keeps writes and reads serialized for sqlite
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`
+	CREATE TABLE IF NOT EXISTS events (
+		id INTEGER PRIMARY KEY,
+		timestamp INTEGER NOT NULL,
+		type TEXT NOT NULL,
+		module TEXT NOT NULL,
+		route_id TEXT NOT NULL,
+		route_index TEXT NOT NULL,
+		data TEXT,
+		error TEXT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS events_timestamp ON events (timestamp);
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("event log error creating table: %w", err)
+	}
+
+	var lastId sql.NullInt64
+	err = db.QueryRow("SELECT MAX(id) FROM events").Scan(&lastId)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("event log error reading last id: %w", err)
+	}
+
+	store := &sqliteStore{
+		db:      db,
+		maxRows: maxRows,
+		queue:   make(chan common.EventRecord, storeQueueSize),
+		done:    make(chan struct{}),
+		logger:  logger,
+	}
+	if lastId.Valid {
+		store.lastId = uint64(lastId.Int64)
+	}
+
+	go store.run()
+	return store, nil
+}
+
+func (s *sqliteStore) write(record common.EventRecord) {
+	select {
+	case s.queue <- record:
+	default:
+		s.logger.Warn("event log store queue full, dropping event", "id", record.Id)
+	}
+}
+
+func (s *sqliteStore) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(storeFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]common.EventRecord, 0, storeBatchSize)
+	for {
+		select {
+		case record, ok := <-s.queue:
+			if !ok {
+				s.flush(batch)
+				return
+			}
+			batch = append(batch, record)
+			if len(batch) >= storeBatchSize {
+				s.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				s.flush(batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+func (s *sqliteStore) flush(batch []common.EventRecord) {
+	if len(batch) == 0 {
+		return
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		s.logger.Error("event log store error starting transaction", "error", err)
+		return
+	}
+
+	statement, err := tx.Prepare("INSERT INTO events (id, timestamp, type, module, route_id, route_index, data, error) VALUES (?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		tx.Rollback()
+		s.logger.Error("event log store error preparing insert", "error", err)
+		return
+	}
+	defer statement.Close()
+
+	for _, record := range batch {
+		var data sql.NullString
+		if record.Data != nil {
+			dataJSON, err := json.Marshal(record.Data)
+			if err != nil {
+				s.logger.Error("event log store error encoding event data", "id", record.Id, "error", err)
+			} else {
+				data = sql.NullString{String: string(dataJSON), Valid: true}
+			}
+		}
+		routeId, routeIndex := recordRoute(record)
+		_, err = statement.Exec(record.Id, record.Timestamp.UnixNano(), record.Type, recordModule(record), routeId, routeIndex, data, record.Error)
+		if err != nil {
+			s.logger.Error("event log store error inserting event", "id", record.Id, "error", err)
+		}
+	}
+
+	if s.maxRows > 0 {
+		lastId := batch[len(batch)-1].Id
+		if lastId > uint64(s.maxRows) {
+			_, err = tx.Exec("DELETE FROM events WHERE id <= ?", lastId-uint64(s.maxRows))
+			if err != nil {
+				s.logger.Error("event log store error removing old events", "error", err)
+			}
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		s.logger.Error("event log store error committing events", "error", err)
+	}
+}
+
+func (s *sqliteStore) query(query common.EventQuery, beforeId uint64) ([]common.EventRecord, error) {
+	conditions := []string{"id > ?", "id < ?"}
+	args := []any{query.After, beforeId}
+
+	if !query.Since.IsZero() {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, query.Since.UnixNano())
+	}
+	if !query.Until.IsZero() {
+		conditions = append(conditions, "timestamp <= ?")
+		args = append(args, query.Until.UnixNano())
+	}
+	if len(query.Types) > 0 {
+		conditions = append(conditions, fmt.Sprintf("type IN (%s)", strings.TrimSuffix(strings.Repeat("?,", len(query.Types)), ",")))
+		for _, eventType := range query.Types {
+			args = append(args, eventType)
+		}
+	}
+	if query.ErrorOnly {
+		conditions = append(conditions, "error != ''")
+	}
+	if query.Module != "" {
+		conditions = append(conditions, "module = ?")
+		args = append(args, query.Module)
+	}
+	if query.Route != "" {
+		conditions = append(conditions, "(route_id = ? OR route_index = ?)")
+		args = append(args, query.Route, query.Route)
+	}
+
+	statement := "SELECT id, timestamp, type, data, error FROM events WHERE " + strings.Join(conditions, " AND ")
+	if query.Limit > 0 {
+		//NOTE(jwetzell): cursor queries page forward, everything else wants the most recent events
+		if query.After > 0 {
+			statement += " ORDER BY id ASC LIMIT ?"
+		} else {
+			statement = "SELECT * FROM (" + statement + " ORDER BY id DESC LIMIT ?) ORDER BY id ASC"
+		}
+		args = append(args, query.Limit)
+	} else {
+		statement += " ORDER BY id ASC"
+	}
+
+	rows, err := s.db.Query(statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("event log error querying events: %w", err)
+	}
+	defer rows.Close()
+
+	records := []common.EventRecord{}
+	for rows.Next() {
+		var record common.EventRecord
+		var timestamp int64
+		var data sql.NullString
+		err := rows.Scan(&record.Id, &timestamp, &record.Type, &data, &record.Error)
+		if err != nil {
+			return nil, fmt.Errorf("event log error reading events: %w", err)
+		}
+		record.Timestamp = time.Unix(0, timestamp)
+		if data.Valid {
+			var decodedData any
+			err := json.Unmarshal([]byte(data.String), &decodedData)
+			if err != nil {
+				return nil, fmt.Errorf("event log error decoding event data: %w", err)
+			}
+			record.Data = decodedData
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+func (s *sqliteStore) close() {
+	close(s.queue)
+	<-s.done
+	s.db.Close()
+}
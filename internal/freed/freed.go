@@ -0,0 +1,178 @@
+// Package freed keeps per camera FreeD state for smoothing, interpolation and packet loss tracking
+package freed
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	freeD "github.com/jwetzell/free-d-go"
+)
+
+// StaleAfter is how long a camera can go without packets before Sample stops returning it
+const StaleAfter = time.Second
+
+type Stats struct {
+	ID             uint8         `json:"id"`
+	Received       uint64        `json:"received"`
+	Lost           uint64        `json:"lost"`
+	ChecksumErrors uint64        `json:"checksumErrors"`
+	Interval       time.Duration `json:"interval"`
+	LastReceived   time.Time     `json:"lastReceived"`
+}
+
+type sample struct {
+	position freeD.FreeDPosition
+	time     time.Time
+}
+
+type camera struct {
+	previous *sample
+	latest   *sample
+	stats    Stats
+}
+
+// Tracker smooths FreeD positions per camera id, Smoothing of 0 passes positions through untouched and values toward 1 smooth harder
+type Tracker struct {
+	Smoothing float64
+	cameras   map[uint8]*camera
+	mu        sync.Mutex
+}
+
+func NewTracker(smoothing float64) *Tracker {
+	return &Tracker{Smoothing: smoothing, cameras: map[uint8]*camera{}}
+}
+
+// Update records a decoded position and returns the smoothed position for that camera
+func (t *Tracker) Update(position freeD.FreeDPosition, now time.Time) freeD.FreeDPosition {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cam, ok := t.cameras[position.ID]
+	if !ok {
+		cam = &camera{stats: Stats{ID: position.ID}}
+		t.cameras[position.ID] = cam
+	}
+
+	if cam.latest != nil {
+		gap := now.Sub(cam.latest.time)
+		if gap > StaleAfter {
+			//NOTE(jwetzell): the camera stopped sending rather than dropping packets
+			cam.stats.Interval = 0
+		} else if cam.stats.Interval > 0 && gap > cam.stats.Interval*3/2 {
+			//NOTE(jwetzell): FreeD has no sequence number so loss is estimated from gaps in the packet rate
+			cam.stats.Lost += uint64(math.Round(float64(gap)/float64(cam.stats.Interval))) - 1
+		} else if cam.stats.Interval == 0 {
+			cam.stats.Interval = gap
+		} else {
+			cam.stats.Interval = (cam.stats.Interval*7 + gap) / 8
+		}
+	}
+
+	smoothed := position
+	if cam.latest != nil && t.Smoothing > 0 {
+		smoothed = blend(cam.latest.position, position, 1-t.Smoothing)
+	}
+
+	cam.previous = cam.latest
+	cam.latest = &sample{position: smoothed, time: now}
+	cam.stats.Received++
+	cam.stats.LastReceived = now
+	return smoothed
+}
+
+// ChecksumError counts a packet for id that failed its checksum
+func (t *Tracker) ChecksumError(id uint8) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cam, ok := t.cameras[id]
+	if !ok {
+		cam = &camera{stats: Stats{ID: id}}
+		t.cameras[id] = cam
+	}
+	cam.stats.ChecksumErrors++
+}
+
+// Sample interpolates a camera position one packet interval behind now so there is always a later packet to move toward
+func (t *Tracker) Sample(id uint8, now time.Time) (freeD.FreeDPosition, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cam, ok := t.cameras[id]
+	if !ok || cam.latest == nil || now.Sub(cam.latest.time) > StaleAfter {
+		return freeD.FreeDPosition{}, false
+	}
+
+	if cam.previous == nil {
+		return cam.latest.position, true
+	}
+
+	span := cam.latest.time.Sub(cam.previous.time)
+	if span <= 0 {
+		return cam.latest.position, true
+	}
+
+	renderTime := now.Add(-span)
+	amount := float64(renderTime.Sub(cam.previous.time)) / float64(span)
+	amount = min(max(amount, 0), 1)
+	return blend(cam.previous.position, cam.latest.position, amount), true
+}
+
+// Cameras returns the ids of every camera seen so far
+func (t *Tracker) Cameras() []uint8 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ids := []uint8{}
+	for id, cam := range t.cameras {
+		if cam.latest != nil {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func (t *Tracker) Stats() []Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := []Stats{}
+	for _, cam := range t.cameras {
+		stats = append(stats, cam.stats)
+	}
+	slices.SortFunc(stats, func(a, b Stats) int {
+		return int(a.ID) - int(b.ID)
+	})
+	return stats
+}
+
+// blend moves amount of the way from a to b taking the short way around for angles
+func blend(a freeD.FreeDPosition, b freeD.FreeDPosition, amount float64) freeD.FreeDPosition {
+	return freeD.FreeDPosition{
+		ID:    b.ID,
+		Pan:   blendAngle(a.Pan, b.Pan, amount),
+		Tilt:  blendAngle(a.Tilt, b.Tilt, amount),
+		Roll:  blendAngle(a.Roll, b.Roll, amount),
+		PosX:  blendFloat(a.PosX, b.PosX, amount),
+		PosY:  blendFloat(a.PosY, b.PosY, amount),
+		PosZ:  blendFloat(a.PosZ, b.PosZ, amount),
+		Zoom:  int32(math.Round(float64(a.Zoom) + float64(b.Zoom-a.Zoom)*amount)),
+		Focus: int32(math.Round(float64(a.Focus) + float64(b.Focus-a.Focus)*amount)),
+	}
+}
+
+func blendFloat(a float32, b float32, amount float64) float32 {
+	return float32(float64(a) + float64(b-a)*amount)
+}
+
+func blendAngle(a float32, b float32, amount float64) float32 {
+	delta := math.Mod(float64(b-a)+540, 360) - 180
+	result := float64(a) + delta*amount
+	//NOTE(jwetzell): keep results in the -180 to 180 range FreeD uses
+	if result > 180 {
+		result -= 360
+	} else if result <= -180 {
+		result += 360
+	}
+	return float32(result)
+}
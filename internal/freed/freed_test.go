@@ -0,0 +1,89 @@
+package freed_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	freeD "github.com/jwetzell/free-d-go"
+	"github.com/jwetzell/showbridge-go/internal/freed"
+)
+
+func TestTrackerLoss(t *testing.T) {
+	tracker := freed.NewTracker(0)
+	start := time.Unix(0, 0)
+	interval := 20 * time.Millisecond
+
+	for _, frame := range []int{0, 1, 2, 3, 6, 7} {
+		tracker.Update(freeD.FreeDPosition{ID: 1}, start.Add(time.Duration(frame)*interval))
+	}
+	tracker.ChecksumError(1)
+
+	stats := tracker.Stats()
+	if len(stats) != 1 {
+		t.Fatalf("Stats should have one camera, got %d", len(stats))
+	}
+
+	if stats[0].Received != 6 || stats[0].Lost != 2 || stats[0].ChecksumErrors != 1 {
+		t.Fatalf("Stats wrong: %+v", stats[0])
+	}
+
+	//NOTE(jwetzell): a long pause is the camera stopping, not loss
+	tracker.Update(freeD.FreeDPosition{ID: 1}, start.Add(time.Minute))
+	if tracker.Stats()[0].Lost != 2 {
+		t.Fatalf("a pause should not count as loss: %+v", tracker.Stats()[0])
+	}
+}
+
+func TestTrackerSmoothing(t *testing.T) {
+	tracker := freed.NewTracker(0.5)
+	now := time.Unix(0, 0)
+
+	tracker.Update(freeD.FreeDPosition{ID: 1, PosX: 0, Pan: 170}, now)
+	smoothed := tracker.Update(freeD.FreeDPosition{ID: 1, PosX: 100, Pan: -170}, now.Add(20*time.Millisecond))
+
+	if smoothed.PosX != 50 {
+		t.Fatalf("smoothed PosX got %f, expected 50", smoothed.PosX)
+	}
+
+	//NOTE(jwetzell): 170 to -170 is 20 degrees through 180, not 340 degrees through 0
+	if math.Abs(math.Abs(float64(smoothed.Pan))-180) > 0.001 {
+		t.Fatalf("smoothed Pan got %f, expected 180", smoothed.Pan)
+	}
+}
+
+func TestTrackerSample(t *testing.T) {
+	tracker := freed.NewTracker(0)
+	start := time.Unix(0, 0)
+
+	_, ok := tracker.Sample(1, start)
+	if ok {
+		t.Fatalf("Sample should not return unknown cameras")
+	}
+
+	tracker.Update(freeD.FreeDPosition{ID: 1, PosY: 0, Zoom: 0}, start)
+	tracker.Update(freeD.FreeDPosition{ID: 1, PosY: 100, Zoom: 1000}, start.Add(40*time.Millisecond))
+
+	sample, ok := tracker.Sample(1, start.Add(50*time.Millisecond))
+	if !ok {
+		t.Fatalf("Sample should return a known camera")
+	}
+
+	if sample.PosY != 25 || sample.Zoom != 250 {
+		t.Fatalf("Sample should interpolate a quarter of the way, got %+v", sample)
+	}
+
+	sample, _ = tracker.Sample(1, start.Add(200*time.Millisecond))
+	if sample.PosY != 100 {
+		t.Fatalf("Sample should hold the latest position past the end, got %+v", sample)
+	}
+
+	_, ok = tracker.Sample(1, start.Add(2*time.Second))
+	if ok {
+		t.Fatalf("Sample should not return stale cameras")
+	}
+
+	if cameras := tracker.Cameras(); len(cameras) != 1 || cameras[0] != 1 {
+		t.Fatalf("Cameras got %+v", cameras)
+	}
+}
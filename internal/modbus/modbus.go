@@ -0,0 +1,341 @@
+// Package modbus encodes and decodes Modbus application data units for TCP and RTU and holds a simple data model for serving them
+package modbus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	FunctionReadCoils              = 0x01
+	FunctionReadDiscreteInputs     = 0x02
+	FunctionReadHoldingRegisters   = 0x03
+	FunctionReadInputRegisters     = 0x04
+	FunctionWriteSingleCoil        = 0x05
+	FunctionWriteSingleRegister    = 0x06
+	FunctionWriteMultipleCoils     = 0x0F
+	FunctionWriteMultipleRegisters = 0x10
+)
+
+const (
+	ExceptionIllegalFunction     = 0x01
+	ExceptionIllegalDataAddress  = 0x02
+	ExceptionIllegalDataValue    = 0x03
+	ExceptionServerDeviceFailure = 0x04
+)
+
+const (
+	MaxReadBits       = 2000
+	MaxReadRegisters  = 125
+	MaxWriteBits      = 1968
+	MaxWriteRegisters = 123
+)
+
+// Table is one of the four Modbus data tables
+type Table string
+
+const (
+	TableCoils            Table = "coils"
+	TableDiscreteInputs   Table = "discreteInputs"
+	TableHoldingRegisters Table = "holdingRegisters"
+	TableInputRegisters   Table = "inputRegisters"
+)
+
+// IsBits reports whether the table holds single bits rather than 16 bit registers
+func (t Table) IsBits() bool {
+	return t == TableCoils || t == TableDiscreteInputs
+}
+
+// ReadFunction is the function code used to read the table
+func (t Table) ReadFunction() (uint8, error) {
+	switch t {
+	case TableCoils:
+		return FunctionReadCoils, nil
+	case TableDiscreteInputs:
+		return FunctionReadDiscreteInputs, nil
+	case TableHoldingRegisters:
+		return FunctionReadHoldingRegisters, nil
+	case TableInputRegisters:
+		return FunctionReadInputRegisters, nil
+	}
+	return 0, fmt.Errorf("unknown modbus table: %s", t)
+}
+
+func ParseTable(value string) (Table, error) {
+	table := Table(value)
+	_, err := table.ReadFunction()
+	if err != nil {
+		return "", err
+	}
+	return table, nil
+}
+
+// PDU is a Modbus protocol data unit, the part of a request or response that doesn't depend on the transport
+type PDU struct {
+	Function uint8
+	Data     []byte
+}
+
+func (p PDU) MarshalBinary() ([]byte, error) {
+	return append([]byte{p.Function}, p.Data...), nil
+}
+
+func DecodePDU(data []byte) (PDU, error) {
+	if len(data) == 0 {
+		return PDU{}, errors.New("modbus PDU must not be empty")
+	}
+	return PDU{Function: data[0], Data: append([]byte{}, data[1:]...)}, nil
+}
+
+// IsException reports whether the PDU is an exception response
+func (p PDU) IsException() bool {
+	return p.Function&0x80 != 0
+}
+
+// IsWrite reports whether the PDU is one of the write requests
+func (p PDU) IsWrite() bool {
+	switch p.Function {
+	case FunctionWriteSingleCoil, FunctionWriteSingleRegister, FunctionWriteMultipleCoils, FunctionWriteMultipleRegisters:
+		return true
+	}
+	return false
+}
+
+// Exception is the error carried by an exception response
+type Exception struct {
+	Function uint8
+	Code     uint8
+}
+
+func (e *Exception) Error() string {
+	name := "unknown exception"
+	switch e.Code {
+	case ExceptionIllegalFunction:
+		name = "illegal function"
+	case ExceptionIllegalDataAddress:
+		name = "illegal data address"
+	case ExceptionIllegalDataValue:
+		name = "illegal data value"
+	case ExceptionServerDeviceFailure:
+		name = "server device failure"
+	}
+	if e.Function == 0 {
+		return fmt.Sprintf("modbus exception %d (%s)", e.Code, name)
+	}
+	return fmt.Sprintf("modbus exception %d (%s) for function %d", e.Code, name, e.Function)
+}
+
+// PDU is the exception response for the exception
+func (e *Exception) PDU() PDU {
+	return PDU{Function: e.Function | 0x80, Data: []byte{e.Code}}
+}
+
+// Block is a run of consecutive values from one table
+type Block struct {
+	Table     Table
+	Address   uint16
+	Bits      []bool
+	Registers []uint16
+}
+
+// Quantity is the number of values in the block
+func (b Block) Quantity() int {
+	if b.Table.IsBits() {
+		return len(b.Bits)
+	}
+	return len(b.Registers)
+}
+
+// Equal reports whether two blocks hold the same values at the same address
+func (b Block) Equal(other Block) bool {
+	if b.Table != other.Table || b.Address != other.Address || len(b.Bits) != len(other.Bits) || len(b.Registers) != len(other.Registers) {
+		return false
+	}
+	for index := range b.Bits {
+		if b.Bits[index] != other.Bits[index] {
+			return false
+		}
+	}
+	for index := range b.Registers {
+		if b.Registers[index] != other.Registers[index] {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteRequest is the write multiple request that writes the block, only coils and holding registers can be written
+func (b Block) WriteRequest() (PDU, error) {
+	switch b.Table {
+	case TableCoils:
+		return WriteMultipleCoils(b.Address, b.Bits)
+	case TableHoldingRegisters:
+		return WriteMultipleRegisters(b.Address, b.Registers)
+	}
+	return PDU{}, fmt.Errorf("modbus table %s can't be written", b.Table)
+}
+
+func ReadRequest(table Table, address uint16, quantity uint16) (PDU, error) {
+	function, err := table.ReadFunction()
+	if err != nil {
+		return PDU{}, err
+	}
+
+	maxQuantity := uint16(MaxReadRegisters)
+	if table.IsBits() {
+		maxQuantity = MaxReadBits
+	}
+	if quantity < 1 || quantity > maxQuantity {
+		return PDU{}, fmt.Errorf("modbus read quantity must be between 1 and %d", maxQuantity)
+	}
+	if int(address)+int(quantity) > 0x10000 {
+		return PDU{}, errors.New("modbus read runs past the end of the address space")
+	}
+
+	data := binary.BigEndian.AppendUint16(nil, address)
+	data = binary.BigEndian.AppendUint16(data, quantity)
+	return PDU{Function: function, Data: data}, nil
+}
+
+func WriteSingleCoil(address uint16, value bool) PDU {
+	data := binary.BigEndian.AppendUint16(nil, address)
+	if value {
+		data = binary.BigEndian.AppendUint16(data, 0xFF00)
+	} else {
+		data = binary.BigEndian.AppendUint16(data, 0x0000)
+	}
+	return PDU{Function: FunctionWriteSingleCoil, Data: data}
+}
+
+func WriteSingleRegister(address uint16, value uint16) PDU {
+	data := binary.BigEndian.AppendUint16(nil, address)
+	data = binary.BigEndian.AppendUint16(data, value)
+	return PDU{Function: FunctionWriteSingleRegister, Data: data}
+}
+
+func WriteMultipleCoils(address uint16, values []bool) (PDU, error) {
+	if len(values) < 1 || len(values) > MaxWriteBits {
+		return PDU{}, fmt.Errorf("modbus coil write quantity must be between 1 and %d", MaxWriteBits)
+	}
+	if int(address)+len(values) > 0x10000 {
+		return PDU{}, errors.New("modbus write runs past the end of the address space")
+	}
+
+	packed := packBits(values)
+	data := binary.BigEndian.AppendUint16(nil, address)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(values)))
+	data = append(data, uint8(len(packed)))
+	data = append(data, packed...)
+	return PDU{Function: FunctionWriteMultipleCoils, Data: data}, nil
+}
+
+func WriteMultipleRegisters(address uint16, values []uint16) (PDU, error) {
+	if len(values) < 1 || len(values) > MaxWriteRegisters {
+		return PDU{}, fmt.Errorf("modbus register write quantity must be between 1 and %d", MaxWriteRegisters)
+	}
+	if int(address)+len(values) > 0x10000 {
+		return PDU{}, errors.New("modbus write runs past the end of the address space")
+	}
+
+	data := binary.BigEndian.AppendUint16(nil, address)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(values)))
+	data = append(data, uint8(len(values)*2))
+	for _, value := range values {
+		data = binary.BigEndian.AppendUint16(data, value)
+	}
+	return PDU{Function: FunctionWriteMultipleRegisters, Data: data}, nil
+}
+
+// ParseReadResponse decodes the values of a read response into a block using the request it answers
+func ParseReadResponse(request PDU, response PDU) (Block, error) {
+	err := CheckResponse(request, response)
+	if err != nil {
+		return Block{}, err
+	}
+
+	table, address, quantity, err := parseReadRequest(request)
+	if err != nil {
+		return Block{}, err
+	}
+
+	if len(response.Data) < 1 || int(response.Data[0]) != len(response.Data)-1 {
+		return Block{}, errors.New("modbus read response byte count does not match its data")
+	}
+	values := response.Data[1:]
+
+	block := Block{Table: table, Address: address}
+	if table.IsBits() {
+		if len(values) != (int(quantity)+7)/8 {
+			return Block{}, errors.New("modbus read response has the wrong number of bytes")
+		}
+		block.Bits = unpackBits(values, int(quantity))
+		return block, nil
+	}
+
+	if len(values) != int(quantity)*2 {
+		return Block{}, errors.New("modbus read response has the wrong number of bytes")
+	}
+	block.Registers = make([]uint16, quantity)
+	for index := range block.Registers {
+		block.Registers[index] = binary.BigEndian.Uint16(values[index*2:])
+	}
+	return block, nil
+}
+
+// CheckResponse turns an exception response into an *Exception and makes sure the response answers the request
+func CheckResponse(request PDU, response PDU) error {
+	if response.IsException() {
+		if response.Function&0x7F != request.Function {
+			return fmt.Errorf("modbus response function %d does not match request function %d", response.Function&0x7F, request.Function)
+		}
+		if len(response.Data) != 1 {
+			return errors.New("modbus exception response must have one data byte")
+		}
+		return &Exception{Function: request.Function, Code: response.Data[0]}
+	}
+
+	if response.Function != request.Function {
+		return fmt.Errorf("modbus response function %d does not match request function %d", response.Function, request.Function)
+	}
+	return nil
+}
+
+func parseReadRequest(request PDU) (Table, uint16, uint16, error) {
+	var table Table
+	switch request.Function {
+	case FunctionReadCoils:
+		table = TableCoils
+	case FunctionReadDiscreteInputs:
+		table = TableDiscreteInputs
+	case FunctionReadHoldingRegisters:
+		table = TableHoldingRegisters
+	case FunctionReadInputRegisters:
+		table = TableInputRegisters
+	default:
+		return "", 0, 0, fmt.Errorf("modbus function %d is not a read", request.Function)
+	}
+
+	if len(request.Data) != 4 {
+		return "", 0, 0, errors.New("modbus read request must have 4 data bytes")
+	}
+	return table, binary.BigEndian.Uint16(request.Data[0:2]), binary.BigEndian.Uint16(request.Data[2:4]), nil
+}
+
+func packBits(values []bool) []byte {
+	packed := make([]byte, (len(values)+7)/8)
+	for index, value := range values {
+		if value {
+			packed[index/8] |= 1 << (index % 8)
+		}
+	}
+	return packed
+}
+
+func unpackBits(packed []byte, quantity int) []bool {
+	values := make([]bool, quantity)
+	for index := range values {
+		values[index] = packed[index/8]&(1<<(index%8)) != 0
+	}
+	return values
+}
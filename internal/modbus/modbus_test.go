@@ -0,0 +1,225 @@
+package modbus_test
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/modbus"
+)
+
+func TestEncode(t *testing.T) {
+	request, err := modbus.ReadRequest(modbus.TableHoldingRegisters, 0, 10)
+	if err != nil {
+		t.Fatalf("modbus read request failed: %s", err)
+	}
+
+	tcp := modbus.EncodeTCP(1, 17, request)
+	expectedTCP := []byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x06, 0x11, 0x03, 0x00, 0x00, 0x00, 0x0a}
+	if !slices.Equal(tcp, expectedTCP) {
+		t.Fatalf("modbus TCP got % x, expected % x", tcp, expectedTCP)
+	}
+
+	rtu := modbus.EncodeRTU(1, request)
+	expectedRTU := []byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x0a, 0xc5, 0xcd}
+	if !slices.Equal(rtu, expectedRTU) {
+		t.Fatalf("modbus RTU got % x, expected % x", rtu, expectedRTU)
+	}
+
+	_, err = modbus.ReadRequest(modbus.TableHoldingRegisters, 0, 126)
+	if err == nil {
+		t.Fatalf("modbus read request should reject more than 125 registers")
+	}
+}
+
+func TestDataModel(t *testing.T) {
+	model := modbus.NewDataModel(16, 8, 8, 4)
+
+	coils, err := modbus.WriteMultipleCoils(3, []bool{true, false, true, true, false, false, false, false, true})
+	if err != nil {
+		t.Fatalf("modbus write coils failed: %s", err)
+	}
+	registers, err := modbus.WriteMultipleRegisters(2, []uint16{0x1234, 0xffff})
+	if err != nil {
+		t.Fatalf("modbus write registers failed: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		request  modbus.PDU
+		expected modbus.Block
+	}{
+		{
+			name:     "write multiple coils",
+			request:  coils,
+			expected: modbus.Block{Table: modbus.TableCoils, Address: 3, Bits: []bool{true, false, true, true, false, false, false, false, true}},
+		},
+		{
+			name:     "write single coil",
+			request:  modbus.WriteSingleCoil(15, true),
+			expected: modbus.Block{Table: modbus.TableCoils, Address: 15, Bits: []bool{true}},
+		},
+		{
+			name:     "write multiple registers",
+			request:  registers,
+			expected: modbus.Block{Table: modbus.TableHoldingRegisters, Address: 2, Registers: []uint16{0x1234, 0xffff}},
+		},
+		{
+			name:     "write single register",
+			request:  modbus.WriteSingleRegister(7, 42),
+			expected: modbus.Block{Table: modbus.TableHoldingRegisters, Address: 7, Registers: []uint16{42}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response, written, err := model.Handle(test.request)
+			if err != nil {
+				t.Fatalf("modbus data model failed: %s", err)
+			}
+			if !reflect.DeepEqual(written, test.expected) {
+				t.Fatalf("modbus data model wrote %+v, expected %+v", written, test.expected)
+			}
+			if response.Function != test.request.Function || !slices.Equal(response.Data, test.request.Data[0:4]) {
+				t.Fatalf("modbus data model write response should echo the request, got %+v", response)
+			}
+		})
+	}
+
+	read, err := modbus.ReadRequest(modbus.TableCoils, 0, 16)
+	if err != nil {
+		t.Fatalf("modbus read request failed: %s", err)
+	}
+	response, _, err := model.Handle(read)
+	if err != nil {
+		t.Fatalf("modbus data model read failed: %s", err)
+	}
+	if !slices.Equal(response.Data, []byte{0x02, 0x68, 0x88}) {
+		t.Fatalf("modbus data model coil response got % x", response.Data)
+	}
+	block, err := modbus.ParseReadResponse(read, response)
+	if err != nil {
+		t.Fatalf("modbus parse read response failed: %s", err)
+	}
+	expectedBits := []bool{false, false, false, true, false, true, true, false, false, false, false, true, false, false, false, true}
+	if !slices.Equal(block.Bits, expectedBits) {
+		t.Fatalf("modbus coils got %v, expected %v", block.Bits, expectedBits)
+	}
+
+	err = model.Write(modbus.Block{Table: modbus.TableInputRegisters, Address: 1, Registers: []uint16{7, 8}})
+	if err != nil {
+		t.Fatalf("modbus data model should write input registers locally: %s", err)
+	}
+	read, _ = modbus.ReadRequest(modbus.TableInputRegisters, 0, 3)
+	response, _, _ = model.Handle(read)
+	block, err = modbus.ParseReadResponse(read, response)
+	if err != nil {
+		t.Fatalf("modbus parse read response failed: %s", err)
+	}
+	if !slices.Equal(block.Registers, []uint16{0, 7, 8}) {
+		t.Fatalf("modbus input registers got %v", block.Registers)
+	}
+
+	read, _ = modbus.ReadRequest(modbus.TableHoldingRegisters, 6, 3)
+	response, _, err = model.Handle(read)
+	var exception *modbus.Exception
+	if !errors.As(err, &exception) || exception.Code != modbus.ExceptionIllegalDataAddress {
+		t.Fatalf("modbus data model should reject reads past the map, got %v", err)
+	}
+	_, err = modbus.ParseReadResponse(read, response)
+	if err == nil || err.Error() != "modbus exception 2 (illegal data address) for function 3" {
+		t.Fatalf("modbus exception response got %v", err)
+	}
+}
+
+type fakeSerial struct {
+	written  bytes.Buffer
+	response []byte
+}
+
+func (fs *fakeSerial) Write(data []byte) (int, error) {
+	return fs.written.Write(data)
+}
+
+// NOTE(jwetzell): hand out a couple of bytes at a time like a slow serial line
+func (fs *fakeSerial) Read(buffer []byte) (int, error) {
+	count := min(2, len(buffer), len(fs.response))
+	copy(buffer, fs.response[:count])
+	fs.response = fs.response[count:]
+	return count, nil
+}
+
+func TestRTUTransport(t *testing.T) {
+	model := modbus.NewDataModel(0, 0, 4, 0)
+	model.Write(modbus.Block{Table: modbus.TableHoldingRegisters, Address: 0, Registers: []uint16{1, 2, 3, 4}})
+
+	read, _ := modbus.ReadRequest(modbus.TableHoldingRegisters, 1, 2)
+	response, _, _ := model.Handle(read)
+
+	port := &fakeSerial{response: modbus.EncodeRTU(5, response)}
+	transport := modbus.NewRTUTransport(port, 50*time.Millisecond)
+
+	got, err := transport.Send(5, read)
+	if err != nil {
+		t.Fatalf("modbus RTU transport failed: %s", err)
+	}
+	if !slices.Equal(port.written.Bytes(), modbus.EncodeRTU(5, read)) {
+		t.Fatalf("modbus RTU transport wrote % x", port.written.Bytes())
+	}
+	block, err := modbus.ParseReadResponse(read, got)
+	if err != nil {
+		t.Fatalf("modbus RTU parse failed: %s", err)
+	}
+	if !slices.Equal(block.Registers, []uint16{2, 3}) {
+		t.Fatalf("modbus RTU registers got %v", block.Registers)
+	}
+
+	corrupt := modbus.EncodeRTU(5, response)
+	corrupt[len(corrupt)-1] ^= 0xff
+	port = &fakeSerial{response: corrupt}
+	_, err = modbus.NewRTUTransport(port, 50*time.Millisecond).Send(5, read)
+	if err == nil || err.Error() != "modbus RTU response CRC mismatch" {
+		t.Fatalf("modbus RTU transport should reject a bad CRC, got %v", err)
+	}
+
+	port = &fakeSerial{}
+	_, err = modbus.NewRTUTransport(port, 20*time.Millisecond).Send(5, read)
+	if !errors.Is(err, modbus.ErrTimeout) {
+		t.Fatalf("modbus RTU transport should time out, got %v", err)
+	}
+}
+
+func TestTCPTransport(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	model := modbus.NewDataModel(0, 0, 0, 2)
+	model.Write(modbus.Block{Table: modbus.TableInputRegisters, Address: 0, Registers: []uint16{100, 200}})
+
+	go func() {
+		transactionID, unitID, request, err := modbus.ReadTCP(server)
+		if err != nil {
+			return
+		}
+		response, _, _ := model.Handle(request)
+		server.Write(modbus.EncodeTCP(transactionID, unitID, response))
+	}()
+
+	read, _ := modbus.ReadRequest(modbus.TableInputRegisters, 0, 2)
+	response, err := modbus.NewTCPTransport(client, time.Second).Send(1, read)
+	if err != nil {
+		t.Fatalf("modbus TCP transport failed: %s", err)
+	}
+	block, err := modbus.ParseReadResponse(read, response)
+	if err != nil {
+		t.Fatalf("modbus TCP parse failed: %s", err)
+	}
+	if !slices.Equal(block.Registers, []uint16{100, 200}) {
+		t.Fatalf("modbus TCP registers got %v", block.Registers)
+	}
+}
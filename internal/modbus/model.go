@@ -0,0 +1,178 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"sync"
+)
+
+// DataModel is the register map a server exposes, every table starts at address 0
+type DataModel struct {
+	mu               sync.Mutex
+	coils            []bool
+	discreteInputs   []bool
+	holdingRegisters []uint16
+	inputRegisters   []uint16
+}
+
+func NewDataModel(coils int, discreteInputs int, holdingRegisters int, inputRegisters int) *DataModel {
+	return &DataModel{
+		coils:            make([]bool, coils),
+		discreteInputs:   make([]bool, discreteInputs),
+		holdingRegisters: make([]uint16, holdingRegisters),
+		inputRegisters:   make([]uint16, inputRegisters),
+	}
+}
+
+// Read copies a block of values out of a table
+func (dm *DataModel) Read(table Table, address uint16, quantity uint16) (Block, error) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	block := Block{Table: table, Address: address}
+	end := int(address) + int(quantity)
+	switch table {
+	case TableCoils, TableDiscreteInputs:
+		bits := dm.coils
+		if table == TableDiscreteInputs {
+			bits = dm.discreteInputs
+		}
+		if end > len(bits) {
+			return Block{}, &Exception{Code: ExceptionIllegalDataAddress}
+		}
+		block.Bits = append([]bool{}, bits[address:end]...)
+	case TableHoldingRegisters, TableInputRegisters:
+		registers := dm.holdingRegisters
+		if table == TableInputRegisters {
+			registers = dm.inputRegisters
+		}
+		if end > len(registers) {
+			return Block{}, &Exception{Code: ExceptionIllegalDataAddress}
+		}
+		block.Registers = append([]uint16{}, registers[address:end]...)
+	default:
+		return Block{}, &Exception{Code: ExceptionIllegalFunction}
+	}
+	return block, nil
+}
+
+// Write copies a block of values into a table, unlike a Modbus client this can write every table
+func (dm *DataModel) Write(block Block) error {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	end := int(block.Address) + block.Quantity()
+	switch block.Table {
+	case TableCoils, TableDiscreteInputs:
+		bits := dm.coils
+		if block.Table == TableDiscreteInputs {
+			bits = dm.discreteInputs
+		}
+		if end > len(bits) {
+			return &Exception{Code: ExceptionIllegalDataAddress}
+		}
+		copy(bits[block.Address:end], block.Bits)
+	case TableHoldingRegisters, TableInputRegisters:
+		registers := dm.holdingRegisters
+		if block.Table == TableInputRegisters {
+			registers = dm.inputRegisters
+		}
+		if end > len(registers) {
+			return &Exception{Code: ExceptionIllegalDataAddress}
+		}
+		copy(registers[block.Address:end], block.Registers)
+	default:
+		return &Exception{Code: ExceptionIllegalFunction}
+	}
+	return nil
+}
+
+// Handle answers a request, the returned block is the values that were read or written and err is set for exceptions
+func (dm *DataModel) Handle(request PDU) (PDU, Block, error) {
+	block, err := dm.handle(request)
+	if err != nil {
+		exception, ok := err.(*Exception)
+		if !ok {
+			exception = &Exception{Code: ExceptionServerDeviceFailure}
+		}
+		exception.Function = request.Function
+		return exception.PDU(), Block{}, exception
+	}
+
+	if request.IsWrite() {
+		//NOTE(jwetzell): write responses echo the address with the value or quantity
+		return PDU{Function: request.Function, Data: append([]byte{}, request.Data[0:4]...)}, block, nil
+	}
+
+	data := []byte{}
+	if block.Table.IsBits() {
+		packed := packBits(block.Bits)
+		data = append(data, uint8(len(packed)))
+		data = append(data, packed...)
+	} else {
+		data = append(data, uint8(len(block.Registers)*2))
+		for _, register := range block.Registers {
+			data = binary.BigEndian.AppendUint16(data, register)
+		}
+	}
+	return PDU{Function: request.Function, Data: data}, block, nil
+}
+
+func (dm *DataModel) handle(request PDU) (Block, error) {
+	switch request.Function {
+	case FunctionReadCoils, FunctionReadDiscreteInputs, FunctionReadHoldingRegisters, FunctionReadInputRegisters:
+		table, address, quantity, err := parseReadRequest(request)
+		if err != nil {
+			return Block{}, &Exception{Code: ExceptionIllegalDataValue}
+		}
+		maxQuantity := uint16(MaxReadRegisters)
+		if table.IsBits() {
+			maxQuantity = MaxReadBits
+		}
+		if quantity < 1 || quantity > maxQuantity {
+			return Block{}, &Exception{Code: ExceptionIllegalDataValue}
+		}
+		return dm.Read(table, address, quantity)
+	case FunctionWriteSingleCoil:
+		if len(request.Data) != 4 {
+			return Block{}, &Exception{Code: ExceptionIllegalDataValue}
+		}
+		value := binary.BigEndian.Uint16(request.Data[2:4])
+		if value != 0xFF00 && value != 0x0000 {
+			return Block{}, &Exception{Code: ExceptionIllegalDataValue}
+		}
+		block := Block{Table: TableCoils, Address: binary.BigEndian.Uint16(request.Data[0:2]), Bits: []bool{value == 0xFF00}}
+		return block, dm.Write(block)
+	case FunctionWriteSingleRegister:
+		if len(request.Data) != 4 {
+			return Block{}, &Exception{Code: ExceptionIllegalDataValue}
+		}
+		block := Block{Table: TableHoldingRegisters, Address: binary.BigEndian.Uint16(request.Data[0:2]), Registers: []uint16{binary.BigEndian.Uint16(request.Data[2:4])}}
+		return block, dm.Write(block)
+	case FunctionWriteMultipleCoils:
+		if len(request.Data) < 5 {
+			return Block{}, &Exception{Code: ExceptionIllegalDataValue}
+		}
+		quantity := int(binary.BigEndian.Uint16(request.Data[2:4]))
+		byteCount := int(request.Data[4])
+		if quantity < 1 || quantity > MaxWriteBits || byteCount != (quantity+7)/8 || len(request.Data) != 5+byteCount {
+			return Block{}, &Exception{Code: ExceptionIllegalDataValue}
+		}
+		block := Block{Table: TableCoils, Address: binary.BigEndian.Uint16(request.Data[0:2]), Bits: unpackBits(request.Data[5:], quantity)}
+		return block, dm.Write(block)
+	case FunctionWriteMultipleRegisters:
+		if len(request.Data) < 5 {
+			return Block{}, &Exception{Code: ExceptionIllegalDataValue}
+		}
+		quantity := int(binary.BigEndian.Uint16(request.Data[2:4]))
+		byteCount := int(request.Data[4])
+		if quantity < 1 || quantity > MaxWriteRegisters || byteCount != quantity*2 || len(request.Data) != 5+byteCount {
+			return Block{}, &Exception{Code: ExceptionIllegalDataValue}
+		}
+		block := Block{Table: TableHoldingRegisters, Address: binary.BigEndian.Uint16(request.Data[0:2]), Registers: make([]uint16, quantity)}
+		for index := range block.Registers {
+			block.Registers[index] = binary.BigEndian.Uint16(request.Data[5+index*2:])
+		}
+		return block, dm.Write(block)
+	}
+	return Block{}, &Exception{Code: ExceptionIllegalFunction}
+}
@@ -0,0 +1,186 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+var ErrTimeout = errors.New("modbus response timed out")
+
+// Transport sends a request to a unit and waits for its response
+type Transport interface {
+	Send(unitID uint8, request PDU) (PDU, error)
+}
+
+// EncodeTCP wraps a PDU in a Modbus TCP (MBAP) header
+func EncodeTCP(transactionID uint16, unitID uint8, pdu PDU) []byte {
+	data := binary.BigEndian.AppendUint16(nil, transactionID)
+	data = binary.BigEndian.AppendUint16(data, 0)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(pdu.Data)+2))
+	data = append(data, unitID, pdu.Function)
+	return append(data, pdu.Data...)
+}
+
+// ReadTCP reads one Modbus TCP frame and returns its transaction id, unit id and PDU
+func ReadTCP(reader io.Reader) (uint16, uint8, PDU, error) {
+	header := make([]byte, 7)
+	_, err := io.ReadFull(reader, header)
+	if err != nil {
+		return 0, 0, PDU{}, err
+	}
+
+	if binary.BigEndian.Uint16(header[2:4]) != 0 {
+		return 0, 0, PDU{}, errors.New("modbus TCP protocol id must be 0")
+	}
+
+	length := binary.BigEndian.Uint16(header[4:6])
+	if length < 2 || length > 254 {
+		return 0, 0, PDU{}, fmt.Errorf("modbus TCP length %d is out of range", length)
+	}
+
+	body := make([]byte, length-1)
+	_, err = io.ReadFull(reader, body)
+	if err != nil {
+		return 0, 0, PDU{}, err
+	}
+
+	pdu, err := DecodePDU(body)
+	return binary.BigEndian.Uint16(header[0:2]), header[6], pdu, err
+}
+
+// EncodeRTU frames a PDU for a serial line with the unit id and CRC
+func EncodeRTU(unitID uint8, pdu PDU) []byte {
+	data := append([]byte{unitID, pdu.Function}, pdu.Data...)
+	return binary.LittleEndian.AppendUint16(data, CRC(data))
+}
+
+// CRC is the CRC-16/MODBUS checksum used by RTU frames
+func CRC(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, value := range data {
+		crc ^= uint16(value)
+		for range 8 {
+			if crc&1 != 0 {
+				crc = (crc >> 1) ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+type TCPTransport struct {
+	conn          net.Conn
+	timeout       time.Duration
+	transactionID uint16
+	mu            sync.Mutex
+}
+
+func NewTCPTransport(conn net.Conn, timeout time.Duration) *TCPTransport {
+	return &TCPTransport{conn: conn, timeout: timeout}
+}
+
+func (tt *TCPTransport) Send(unitID uint8, request PDU) (PDU, error) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+
+	tt.transactionID++
+	tt.conn.SetDeadline(time.Now().Add(tt.timeout))
+	_, err := tt.conn.Write(EncodeTCP(tt.transactionID, unitID, request))
+	if err != nil {
+		return PDU{}, err
+	}
+
+	for {
+		transactionID, _, response, err := ReadTCP(tt.conn)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return PDU{}, ErrTimeout
+			}
+			return PDU{}, err
+		}
+		//NOTE(jwetzell): drop late answers to requests that already timed out
+		if transactionID == tt.transactionID {
+			return response, nil
+		}
+	}
+}
+
+// RTUTransport talks to units on a serial line, the reader should return 0 bytes when its read timeout passes
+type RTUTransport struct {
+	port    io.ReadWriter
+	timeout time.Duration
+	mu      sync.Mutex
+}
+
+func NewRTUTransport(port io.ReadWriter, timeout time.Duration) *RTUTransport {
+	return &RTUTransport{port: port, timeout: timeout}
+}
+
+func (rt *RTUTransport) Send(unitID uint8, request PDU) (PDU, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	_, err := rt.port.Write(EncodeRTU(unitID, request))
+	if err != nil {
+		return PDU{}, err
+	}
+
+	deadline := time.Now().Add(rt.timeout)
+
+	//NOTE(jwetzell): the frame length depends on the function and the byte count so read the start first
+	frame := make([]byte, 3)
+	err = rt.readFull(frame, deadline)
+	if err != nil {
+		return PDU{}, err
+	}
+
+	remaining := 0
+	switch {
+	case frame[1]&0x80 != 0:
+		remaining = 2
+	case frame[1] <= FunctionReadInputRegisters:
+		remaining = int(frame[2]) + 2
+	default:
+		remaining = 5
+	}
+
+	frame = append(frame, make([]byte, remaining)...)
+	err = rt.readFull(frame[3:], deadline)
+	if err != nil {
+		return PDU{}, err
+	}
+
+	body := frame[:len(frame)-2]
+	if binary.LittleEndian.Uint16(frame[len(frame)-2:]) != CRC(body) {
+		return PDU{}, errors.New("modbus RTU response CRC mismatch")
+	}
+
+	if body[0] != unitID {
+		return PDU{}, fmt.Errorf("modbus RTU response from unit %d, expected %d", body[0], unitID)
+	}
+
+	return DecodePDU(body[1:])
+}
+
+func (rt *RTUTransport) readFull(buffer []byte, deadline time.Time) error {
+	read := 0
+	for read < len(buffer) {
+		if time.Now().After(deadline) {
+			return ErrTimeout
+		}
+		count, err := rt.port.Read(buffer[read:])
+		if err != nil {
+			return err
+		}
+		read += count
+	}
+	return nil
+}
@@ -0,0 +1,448 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/artnet-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+)
+
+const (
+	artNetPort           = 6454
+	artNetMaxPortAddress = 32767
+	artNetShortNameMax   = 17
+	artNetLongNameMax    = 63
+)
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "artnet.node",
+		Title:       "Art-Net Node",
+		Description: "Show up as an Art-Net node, receive DMX for subscribed universes and send ArtDmx",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"ip": {
+					Title:       "IP",
+					Description: "the IP address to bind the node to",
+					Type:        "string",
+					Default:     json.RawMessage(`"0.0.0.0"`),
+				},
+				"port": {
+					Title:       "Port",
+					Description: "the port to listen on and send to",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1),
+					Maximum:     jsonschema.Ptr[float64](65535),
+					Default:     json.RawMessage(`6454`),
+				},
+				"shortName": {
+					Title:       "Short Name",
+					Description: "name reported to controllers in ArtPollReply",
+					Type:        "string",
+					MaxLength:   jsonschema.Ptr(artNetShortNameMax),
+					Default:     json.RawMessage(`"showbridge"`),
+				},
+				"longName": {
+					Title:       "Long Name",
+					Description: "long name reported to controllers in ArtPollReply",
+					Type:        "string",
+					MaxLength:   jsonschema.Ptr(artNetLongNameMax),
+					Default:     json.RawMessage(`"showbridge"`),
+				},
+				"universes": {
+					Title:       "Universes",
+					Description: "15-bit port addresses (net, sub-net, universe) to accept ArtDmx for and report as output ports",
+					Type:        "array",
+					Items: &jsonschema.Schema{
+						Type:    "integer",
+						Minimum: jsonschema.Ptr[float64](0),
+						Maximum: jsonschema.Ptr[float64](artNetMaxPortAddress),
+					},
+				},
+				"host": {
+					Title:       "Host",
+					Description: "address to send ArtDmx to, unicast or broadcast",
+					Type:        "string",
+					Default:     json.RawMessage(`"255.255.255.255"`),
+				},
+				"outputUniverse": {
+					Title:       "Output Universe",
+					Description: "port address to send raw DMX bytes to",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](0),
+					Maximum:     jsonschema.Ptr[float64](artNetMaxPortAddress),
+				},
+				"sync": {
+					Title:       "Sync",
+					Description: "send an ArtSync after every ArtDmx",
+					Type:        "boolean",
+					Default:     json.RawMessage(`false`),
+				},
+			},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			ipString, err := params.GetString("ip")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					ipString = "0.0.0.0"
+				} else {
+					return nil, fmt.Errorf("artnet.node ip error: %w", err)
+				}
+			}
+
+			portNum, err := params.GetInt("port")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					portNum = artNetPort
+				} else {
+					return nil, fmt.Errorf("artnet.node port error: %w", err)
+				}
+			}
+
+			addr, err := net.ResolveUDPAddr("udp4", fmt.Sprintf("%s:%d", ipString, uint16(portNum)))
+			if err != nil {
+				return nil, err
+			}
+
+			shortNameString, err := params.GetString("shortName")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					shortNameString = "showbridge"
+				} else {
+					return nil, fmt.Errorf("artnet.node shortName error: %w", err)
+				}
+			}
+
+			if len(shortNameString) > artNetShortNameMax {
+				return nil, fmt.Errorf("artnet.node shortName cannot be longer than %d characters", artNetShortNameMax)
+			}
+
+			longNameString, err := params.GetString("longName")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					longNameString = "showbridge"
+				} else {
+					return nil, fmt.Errorf("artnet.node longName error: %w", err)
+				}
+			}
+
+			if len(longNameString) > artNetLongNameMax {
+				return nil, fmt.Errorf("artnet.node longName cannot be longer than %d characters", artNetLongNameMax)
+			}
+
+			universesSlice, err := params.GetIntSlice("universes")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					universesSlice = []int{}
+				} else {
+					return nil, fmt.Errorf("artnet.node universes error: %w", err)
+				}
+			}
+
+			universes := []uint16{}
+			for _, universe := range universesSlice {
+				if universe < 0 || universe > artNetMaxPortAddress {
+					return nil, fmt.Errorf("artnet.node universe must be between 0 and %d", artNetMaxPortAddress)
+				}
+				universes = append(universes, uint16(universe))
+			}
+
+			hostString, err := params.GetString("host")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					hostString = "255.255.255.255"
+				} else {
+					return nil, fmt.Errorf("artnet.node host error: %w", err)
+				}
+			}
+
+			hostAddr, err := net.ResolveUDPAddr("udp4", fmt.Sprintf("%s:%d", hostString, uint16(portNum)))
+			if err != nil {
+				return nil, err
+			}
+
+			outputUniverseNum, err := params.GetInt("outputUniverse")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					outputUniverseNum = -1
+				} else {
+					return nil, fmt.Errorf("artnet.node outputUniverse error: %w", err)
+				}
+			} else if outputUniverseNum < 0 || outputUniverseNum > artNetMaxPortAddress {
+				return nil, fmt.Errorf("artnet.node outputUniverse must be between 0 and %d", artNetMaxPortAddress)
+			}
+
+			syncBool, err := params.GetBool("sync")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					syncBool = false
+				} else {
+					return nil, fmt.Errorf("artnet.node sync error: %w", err)
+				}
+			}
+
+			return &ArtNetNode{
+				config:         moduleConfig,
+				Addr:           addr,
+				ShortName:      shortNameString,
+				LongName:       longNameString,
+				Universes:      universes,
+				Host:           hostAddr,
+				OutputUniverse: outputUniverseNum,
+				Sync:           syncBool,
+				sequences:      make(map[uint16]uint8),
+				logger:         CreateLogger(moduleConfig),
+			}, nil
+		},
+	})
+}
+
+type ArtNetNode struct {
+	config         config.ModuleConfig
+	Addr           *net.UDPAddr
+	ShortName      string
+	LongName       string
+	Universes      []uint16
+	Host           *net.UDPAddr
+	OutputUniverse int
+	Sync           bool
+	conn           *net.UDPConn
+	connMu         sync.Mutex
+	sequences      map[uint16]uint8
+	ctx            context.Context
+	inputHandler   common.InputHandler
+	logger         *slog.Logger
+	cancel         context.CancelFunc
+}
+
+func (an *ArtNetNode) Id() string {
+	return an.config.Id
+}
+
+func (an *ArtNetNode) Type() string {
+	return an.config.Type
+}
+
+func artNetPortAddress(dmx *artnet.ArtDmx) uint16 {
+	return uint16(dmx.Net&0x7f)<<8 | uint16(dmx.SubUni)
+}
+
+func (an *ArtNetNode) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	an.logger.Debug("running")
+	an.inputHandler = inputHandler
+	moduleContext, cancel := context.WithCancel(ctx)
+	an.ctx = moduleContext
+	an.cancel = cancel
+
+	conn, err := net.ListenUDP("udp4", an.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	an.connMu.Lock()
+	an.conn = conn
+	an.connMu.Unlock()
+
+	buffer := make([]byte, 2048)
+	for an.ctx.Err() == nil {
+		conn.SetDeadline(time.Now().Add(time.Millisecond * 200))
+
+		numBytes, remoteAddr, err := conn.ReadFromUDP(buffer)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				break
+			}
+			//NOTE(jwetzell) we hit deadline
+			opErr, ok := err.(*net.OpError)
+			if ok && opErr.Timeout() {
+				continue
+			}
+			return err
+		}
+
+		packet, err := artnet.Decode(buffer[:numBytes])
+		if err != nil {
+			an.logger.Debug("unable to decode packet", "error", err)
+			continue
+		}
+
+		switch typedPacket := packet.(type) {
+		case *artnet.ArtPoll:
+			err := an.replyToPoll(remoteAddr)
+			if err != nil {
+				an.logger.Warn("unable to reply to ArtPoll", "error", err)
+			}
+		case *artnet.ArtDmx:
+			if !slices.Contains(an.Universes, artNetPortAddress(typedPacket)) {
+				continue
+			}
+			//NOTE(jwetzell): the decoded data points into the read buffer
+			typedPacket.Data = slices.Clone(typedPacket.Data)
+			if an.inputHandler != nil {
+				an.inputHandler(common.WithRemoteAddr(an.ctx, remoteAddr), an.Id(), typedPacket)
+			} else {
+				an.logger.Error("input received but no input handler is configured")
+			}
+		}
+	}
+	<-an.ctx.Done()
+	an.logger.Debug("done")
+	return nil
+}
+
+// localIP works out the address the node is reachable at from the remote address
+func (an *ArtNetNode) localIP(remoteAddr *net.UDPAddr) net.IP {
+	if an.Addr.IP != nil && !an.Addr.IP.IsUnspecified() {
+		return an.Addr.IP.To4()
+	}
+	probe, err := net.DialUDP("udp4", nil, remoteAddr)
+	if err != nil {
+		return net.IPv4zero.To4()
+	}
+	defer probe.Close()
+	return probe.LocalAddr().(*net.UDPAddr).IP.To4()
+}
+
+// PollReplies builds the ArtPollReply packets describing this node, one per subscribed universe
+func (an *ArtNetNode) PollReplies(ip net.IP) []*artnet.ArtPollReply {
+	universes := an.Universes
+	if len(universes) == 0 {
+		//NOTE(jwetzell): still answer polls so the node is listed
+		universes = []uint16{0}
+	}
+
+	replies := []*artnet.ArtPollReply{}
+	for index, universe := range universes {
+		reply := &artnet.ArtPollReply{
+			Port:      uint16(an.Addr.Port),
+			VersInfo:  1,
+			NetSwitch: uint8(universe>>8) & 0x7f,
+			SubSwitch: uint8(universe>>4) & 0x0f,
+			//NOTE(jwetzell): port address is programmed over the network
+			Status1:   0x20,
+			BindIndex: uint8(index + 1),
+			Style:     artnet.StNode,
+			Status2:   0x08,
+		}
+		copy(reply.IPAddress[:], ip.To4())
+		copy(reply.BindIp[:], ip.To4())
+		copy(reply.PortName[:artNetShortNameMax], an.ShortName)
+		copy(reply.LongName[:artNetLongNameMax], an.LongName)
+		copy(reply.NodeReport[:], fmt.Sprintf("#0001 [%04d] showbridge ok", index))
+		if len(an.Universes) > 0 {
+			reply.NumPorts = 1
+			//NOTE(jwetzell): port can output DMX from the network
+			reply.PortTypes[0] = 0x80
+			reply.GoodOutputA[0] = 0x80
+			reply.SwOut[0] = uint8(universe) & 0x0f
+		}
+		replies = append(replies, reply)
+	}
+	return replies
+}
+
+func (an *ArtNetNode) replyToPoll(remoteAddr *net.UDPAddr) error {
+	an.connMu.Lock()
+	defer an.connMu.Unlock()
+	if an.conn == nil {
+		return errors.New("artnet.node connection is not setup")
+	}
+
+	for _, reply := range an.PollReplies(an.localIP(remoteAddr)) {
+		replyBytes, err := reply.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		_, err = an.conn.WriteToUDP(replyBytes, remoteAddr)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (an *ArtNetNode) Output(ctx context.Context, payload any) error {
+	var dmx artnet.ArtDmx
+
+	payloadPacket, ok := common.GetAnyAs[*artnet.ArtDmx](payload)
+	if ok {
+		dmx = *payloadPacket
+	} else {
+		payloadBytes, ok := common.GetAnyAsByteSlice(payload)
+		if !ok {
+			return errors.New("artnet.node can only output an ArtDmx packet or bytes")
+		}
+		if an.OutputUniverse < 0 {
+			return errors.New("artnet.node outputUniverse param is required to output bytes")
+		}
+		dmx = artnet.ArtDmx{
+			Net:    uint8(an.OutputUniverse>>8) & 0x7f,
+			SubUni: uint8(an.OutputUniverse),
+			Data:   payloadBytes,
+		}
+	}
+
+	if len(dmx.Data) > 512 {
+		return errors.New("artnet.node ArtDmx data cannot be more than 512 channels")
+	}
+
+	an.connMu.Lock()
+	defer an.connMu.Unlock()
+	if an.conn == nil {
+		return errors.New("artnet.node connection is not setup")
+	}
+
+	//NOTE(jwetzell): sequence 0 means sequencing is disabled so wrap from 255 back to 1
+	portAddress := artNetPortAddress(&dmx)
+	sequence := an.sequences[portAddress]%255 + 1
+	an.sequences[portAddress] = sequence
+	dmx.Sequence = sequence
+
+	dmxBytes, err := dmx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = an.conn.WriteToUDP(dmxBytes, an.Host)
+	if err != nil {
+		return err
+	}
+
+	if an.Sync {
+		syncBytes, err := (&artnet.ArtSync{}).MarshalBinary()
+		if err != nil {
+			return err
+		}
+		_, err = an.conn.WriteToUDP(syncBytes, an.Host)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (an *ArtNetNode) Stop() {
+	if an.cancel != nil {
+		defer an.cancel()
+	}
+	an.connMu.Lock()
+	defer an.connMu.Unlock()
+	if an.conn != nil {
+		an.conn.Close()
+	}
+}
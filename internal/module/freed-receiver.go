@@ -0,0 +1,289 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	freeD "github.com/jwetzell/free-d-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/freed"
+)
+
+const freeDPositionSize = 29
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "freed.receiver",
+		Title:       "FreeD Receiver",
+		Description: "Receive FreeD D1 camera positions over UDP",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"ip": {
+					Title:       "IP",
+					Description: "the IP address to bind the FreeD receiver to",
+					Type:        "string",
+					Default:     json.RawMessage(`"0.0.0.0"`),
+				},
+				"port": {
+					Title:       "Port",
+					Description: "the port FreeD is sent to",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1024),
+					Maximum:     jsonschema.Ptr[float64](65535),
+				},
+				"cameras": {
+					Title:       "Cameras",
+					Description: "only pass along positions from these camera ids",
+					Type:        "array",
+					Items: &jsonschema.Schema{
+						Type:    "integer",
+						Minimum: jsonschema.Ptr[float64](0),
+						Maximum: jsonschema.Ptr[float64](255),
+					},
+				},
+				"smoothing": {
+					Title:       "Smoothing",
+					Description: "how much to smooth positions from 0 for none up to but not including 1",
+					Type:        "number",
+					Minimum:     jsonschema.Ptr[float64](0),
+					Maximum:     jsonschema.Ptr[float64](0.99),
+					Default:     json.RawMessage(`0`),
+				},
+				"rate": {
+					Title:       "Rate",
+					Description: "positions per second to interpolate to, 0 passes along every packet",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](0),
+					Maximum:     jsonschema.Ptr[float64](1000),
+					Default:     json.RawMessage(`0`),
+				},
+			},
+			Required:             []string{"port"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			portNum, err := params.GetInt("port")
+			if err != nil {
+				return nil, fmt.Errorf("freed.receiver port error: %w", err)
+			}
+
+			ipString, err := params.GetString("ip")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					ipString = "0.0.0.0"
+				} else {
+					return nil, fmt.Errorf("freed.receiver ip error: %w", err)
+				}
+			}
+
+			addr, err := net.ResolveUDPAddr("udp", fmt.Sprintf("%s:%d", ipString, uint16(portNum)))
+			if err != nil {
+				return nil, err
+			}
+
+			var cameras []uint8
+			camerasSlice, err := params.GetIntSlice("cameras")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("freed.receiver cameras error: %w", err)
+				}
+			} else {
+				cameras = []uint8{}
+				for _, camera := range camerasSlice {
+					if camera < 0 || camera > 255 {
+						return nil, errors.New("freed.receiver cameras must be between 0 and 255")
+					}
+					cameras = append(cameras, uint8(camera))
+				}
+			}
+
+			smoothingFloat, err := params.GetFloat64("smoothing")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					smoothingFloat = 0
+				} else {
+					return nil, fmt.Errorf("freed.receiver smoothing error: %w", err)
+				}
+			}
+
+			if smoothingFloat < 0 || smoothingFloat >= 1 {
+				return nil, errors.New("freed.receiver smoothing must be at least 0 and less than 1")
+			}
+
+			rateNum, err := params.GetInt("rate")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					rateNum = 0
+				} else {
+					return nil, fmt.Errorf("freed.receiver rate error: %w", err)
+				}
+			}
+
+			if rateNum < 0 || rateNum > 1000 {
+				return nil, errors.New("freed.receiver rate must be between 0 and 1000")
+			}
+
+			return &FreeDReceiver{
+				config:  moduleConfig,
+				Addr:    addr,
+				Cameras: cameras,
+				Rate:    rateNum,
+				tracker: freed.NewTracker(smoothingFloat),
+				logger:  CreateLogger(moduleConfig),
+			}, nil
+		},
+	})
+}
+
+type FreeDReceiver struct {
+	config       config.ModuleConfig
+	Addr         *net.UDPAddr
+	Cameras      []uint8
+	Rate         int
+	tracker      *freed.Tracker
+	ctx          context.Context
+	inputHandler common.InputHandler
+	logger       *slog.Logger
+	cancel       context.CancelFunc
+	listener     *net.UDPConn
+	listenerMu   sync.Mutex
+}
+
+func (fr *FreeDReceiver) Id() string {
+	return fr.config.Id
+}
+
+func (fr *FreeDReceiver) Type() string {
+	return fr.config.Type
+}
+
+// Stats returns packet counts, estimated loss and checksum errors per camera
+func (fr *FreeDReceiver) Stats() []freed.Stats {
+	return fr.tracker.Stats()
+}
+
+func (fr *FreeDReceiver) emit(position freeD.FreeDPosition) {
+	if fr.inputHandler != nil {
+		fr.inputHandler(fr.ctx, fr.Id(), position)
+	} else {
+		fr.logger.Error("input received but no input handler is configured")
+	}
+}
+
+func (fr *FreeDReceiver) handlePacket(data []byte, now time.Time) {
+	//NOTE(jwetzell): some senders pack several cameras into one datagram
+	if len(data) == 0 || len(data)%freeDPositionSize != 0 {
+		fr.logger.Warn("FreeD packet is not a multiple of 29 bytes", "length", len(data))
+		return
+	}
+
+	for offset := 0; offset < len(data); offset += freeDPositionSize {
+		var message [freeDPositionSize]byte
+		copy(message[:], data[offset:offset+freeDPositionSize])
+
+		if message[0] != 0xd1 {
+			fr.logger.Debug("ignoring FreeD message that is not a D1 position", "type", message[0])
+			continue
+		}
+
+		if fr.Cameras != nil && !slices.Contains(fr.Cameras, message[1]) {
+			continue
+		}
+
+		position, err := freeD.Decode(message)
+		if err != nil {
+			fr.tracker.ChecksumError(message[1])
+			fr.logger.Warn("bad FreeD message", "camera", message[1], "error", err)
+			continue
+		}
+
+		smoothed := fr.tracker.Update(position, now)
+		if fr.Rate == 0 {
+			fr.emit(smoothed)
+		}
+	}
+}
+
+func (fr *FreeDReceiver) runInterpolation() {
+	ticker := time.NewTicker(time.Second / time.Duration(fr.Rate))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-fr.ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, camera := range fr.tracker.Cameras() {
+				position, ok := fr.tracker.Sample(camera, now)
+				if ok {
+					fr.emit(position)
+				}
+			}
+		}
+	}
+}
+
+func (fr *FreeDReceiver) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	fr.logger.Debug("running")
+	fr.inputHandler = inputHandler
+	moduleContext, cancel := context.WithCancel(ctx)
+	fr.ctx = moduleContext
+	fr.cancel = cancel
+
+	listener, err := net.ListenUDP("udp", fr.Addr)
+	if err != nil {
+		return err
+	}
+	fr.listenerMu.Lock()
+	fr.listener = listener
+	fr.listenerMu.Unlock()
+
+	if fr.Rate > 0 {
+		go fr.runInterpolation()
+	}
+
+	buffer := make([]byte, 2048)
+	for fr.ctx.Err() == nil {
+		listener.SetDeadline(time.Now().Add(time.Millisecond * 200))
+
+		numBytes, _, err := listener.ReadFromUDP(buffer)
+		if err != nil {
+			//NOTE(jwetzell) we hit deadline
+			opErr, ok := err.(*net.OpError)
+			if ok && opErr.Timeout() {
+				continue
+			}
+			break
+		}
+		fr.handlePacket(buffer[:numBytes], time.Now())
+	}
+	<-fr.ctx.Done()
+	fr.logger.Debug("done")
+	return nil
+}
+
+func (fr *FreeDReceiver) Output(ctx context.Context, payload any) error {
+	return errors.New("freed.receiver output is not implemented")
+}
+
+func (fr *FreeDReceiver) Stop() {
+	if fr.cancel != nil {
+		defer fr.cancel()
+	}
+	fr.listenerMu.Lock()
+	defer fr.listenerMu.Unlock()
+	if fr.listener != nil {
+		fr.listener.Close()
+	}
+}
@@ -0,0 +1,262 @@
+package module
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+)
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "http.poll",
+		Title:       "HTTP Poll",
+		Description: "Request a URL on an interval, the response is emitted as an HTTPResponse only when it changes",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"url": {
+					Title:       "URL",
+					Description: "the URL to request",
+					Type:        "string",
+				},
+				"interval": {
+					Title:       "Interval",
+					Description: "milliseconds between requests",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1),
+					Default:     json.RawMessage(`1000`),
+				},
+				"timeout": {
+					Title:       "Timeout",
+					Description: "milliseconds to wait for each request",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1),
+					Default:     json.RawMessage(`5000`),
+				},
+				"headers": {
+					Title:       "Headers",
+					Description: "headers to send with every request, like an Authorization header",
+					Type:        "object",
+					AdditionalProperties: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"parseJSON": {
+					Title:       "Parse JSON",
+					Description: "decode the response body as JSON into the JSON field of the response",
+					Type:        "boolean",
+					Default:     json.RawMessage(`false`),
+				},
+			},
+			Required:             []string{"url"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			urlString, err := params.GetString("url")
+			if err != nil {
+				return nil, fmt.Errorf("http.poll url error: %w", err)
+			}
+
+			parsedURL, err := url.Parse(urlString)
+			if err != nil {
+				return nil, fmt.Errorf("http.poll url error: %w", err)
+			}
+
+			if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+				return nil, errors.New("http.poll url error: scheme must be http or https")
+			}
+
+			intervalNum, err := params.GetInt("interval")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					intervalNum = 1000
+				} else {
+					return nil, fmt.Errorf("http.poll interval error: %w", err)
+				}
+			}
+
+			if intervalNum < 1 {
+				return nil, errors.New("http.poll interval must be at least 1")
+			}
+
+			timeoutNum, err := params.GetInt("timeout")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					timeoutNum = 5000
+				} else {
+					return nil, fmt.Errorf("http.poll timeout error: %w", err)
+				}
+			}
+
+			if timeoutNum < 1 {
+				return nil, errors.New("http.poll timeout must be at least 1")
+			}
+
+			headers, err := params.GetStringMap("headers")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					headers = map[string]string{}
+				} else {
+					return nil, fmt.Errorf("http.poll headers error: %w", err)
+				}
+			}
+
+			parseJSONBool, err := params.GetBool("parseJSON")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					parseJSONBool = false
+				} else {
+					return nil, fmt.Errorf("http.poll parseJSON error: %w", err)
+				}
+			}
+
+			return &HTTPPoll{
+				config:    moduleConfig,
+				URL:       parsedURL.String(),
+				Interval:  time.Duration(intervalNum) * time.Millisecond,
+				Headers:   headers,
+				ParseJSON: parseJSONBool,
+				client:    &http.Client{Timeout: time.Duration(timeoutNum) * time.Millisecond},
+				logger:    CreateLogger(moduleConfig),
+			}, nil
+		},
+	})
+}
+
+type HTTPPoll struct {
+	config       config.ModuleConfig
+	URL          string
+	Interval     time.Duration
+	Headers      map[string]string
+	ParseJSON    bool
+	client       *http.Client
+	etag         string
+	last         *processor.HTTPResponse
+	ctx          context.Context
+	inputHandler common.InputHandler
+	logger       *slog.Logger
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+func (hp *HTTPPoll) Id() string {
+	return hp.config.Id
+}
+
+func (hp *HTTPPoll) Type() string {
+	return hp.config.Type
+}
+
+func (hp *HTTPPoll) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	hp.logger.Debug("running")
+	hp.inputHandler = inputHandler
+	moduleContext, cancel := context.WithCancel(ctx)
+	hp.ctx = moduleContext
+	hp.cancel = cancel
+
+	hp.wg.Add(1)
+	defer hp.wg.Done()
+
+	ticker := time.NewTicker(hp.Interval)
+	defer ticker.Stop()
+
+	for {
+		err := hp.poll()
+		if err != nil && hp.ctx.Err() == nil {
+			hp.logger.Error("poll error", "error", err)
+		}
+
+		select {
+		case <-hp.ctx.Done():
+			hp.logger.Debug("done")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (hp *HTTPPoll) poll() error {
+	request, err := http.NewRequestWithContext(hp.ctx, http.MethodGet, hp.URL, nil)
+	if err != nil {
+		return err
+	}
+
+	for name, value := range hp.Headers {
+		request.Header.Set(name, value)
+	}
+	if hp.etag != "" {
+		request.Header.Set("If-None-Match", hp.etag)
+	}
+
+	response, err := hp.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	// NOTE(jwetzell): the server says nothing changed since the last ETag
+	if response.StatusCode == http.StatusNotModified {
+		return nil
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	hp.etag = response.Header.Get("ETag")
+
+	if hp.last != nil && hp.last.Status == response.StatusCode && bytes.Equal(hp.last.Body, body) {
+		return nil
+	}
+
+	responseHeaders := make(map[string]string, len(response.Header))
+	for name, values := range response.Header {
+		responseHeaders[name] = strings.Join(values, ", ")
+	}
+
+	httpResponse := processor.HTTPResponse{
+		Status:  response.StatusCode,
+		Body:    body,
+		Headers: responseHeaders,
+	}
+
+	if hp.ParseJSON && len(body) > 0 {
+		err := json.Unmarshal(body, &httpResponse.JSON)
+		if err != nil {
+			return fmt.Errorf("http.poll parseJSON error: %w", err)
+		}
+	}
+
+	hp.last = &httpResponse
+
+	if hp.inputHandler == nil {
+		hp.logger.Error("input received but no input handler is configured")
+		return nil
+	}
+	hp.inputHandler(hp.ctx, hp.Id(), httpResponse)
+	return nil
+}
+
+func (hp *HTTPPoll) Stop() {
+	if hp.cancel != nil {
+		hp.cancel()
+	}
+	hp.wg.Wait()
+}
@@ -2,11 +2,21 @@ package module
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"mime"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,13 +26,24 @@ import (
 	"github.com/jwetzell/showbridge-go/internal/processor"
 )
 
+const httpServerMaxBodySize = 10 << 20
+
+var httpServerPatternParam = regexp.MustCompile(`\{([^}.$]+)(\.\.\.)?\}`)
+
 func init() {
 	RegisterModule(ModuleRegistration{
-		Type:  "http.server",
-		Title: "HTTP Server",
+		Type:        "http.server",
+		Title:       "HTTP Server",
+		Description: "Serve HTTP, requests matching the declared patterns are emitted as an HTTPServerRequest",
 		ParamsSchema: &jsonschema.Schema{
 			Type: "object",
 			Properties: map[string]*jsonschema.Schema{
+				"ip": {
+					Title:       "IP",
+					Description: "the IP address to bind the HTTP server to",
+					Type:        "string",
+					Default:     json.RawMessage(`"0.0.0.0"`),
+				},
 				"port": {
 					Title:       "Port",
 					Description: "the port for the HTTP server to listen on",
@@ -30,30 +51,389 @@ func init() {
 					Minimum:     jsonschema.Ptr[float64](1024),
 					Maximum:     jsonschema.Ptr[float64](65535),
 				},
+				"patterns": {
+					Title:       "Patterns",
+					Description: "request patterns like POST /cue/{number}/go that are routed, every request is routed when left out",
+					Type:        "array",
+					Items: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"certFile": {
+					Title:       "Certificate File",
+					Description: "path to a PEM certificate, serves HTTPS along with keyFile",
+					Type:        "string",
+				},
+				"keyFile": {
+					Title:       "Key File",
+					Description: "path to the PEM private key for certFile",
+					Type:        "string",
+				},
+				"cors": {
+					Title:       "CORS",
+					Description: "cross origin requests to allow",
+					Type:        "object",
+					Properties: map[string]*jsonschema.Schema{
+						"origins": {
+							Title:       "Origins",
+							Description: "origins allowed to make requests, * allows any",
+							Type:        "array",
+							Items: &jsonschema.Schema{
+								Type: "string",
+							},
+						},
+						"methods": {
+							Title: "Methods",
+							Type:  "array",
+							Items: &jsonschema.Schema{
+								Type: "string",
+							},
+						},
+						"headers": {
+							Title: "Headers",
+							Type:  "array",
+							Items: &jsonschema.Schema{
+								Type: "string",
+							},
+						},
+					},
+					Required:             []string{"origins"},
+					AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+				},
+				"auth": {
+					Title:       "Auth",
+					Description: "credentials every request must send",
+					Type:        "object",
+					Properties: map[string]*jsonschema.Schema{
+						"type": {
+							Title: "Type",
+							Type:  "string",
+							Enum:  []any{"basic", "bearer"},
+						},
+						"username": {
+							Title: "Username",
+							Type:  "string",
+						},
+						"password": {
+							Title: "Password",
+							Type:  "string",
+						},
+						"token": {
+							Title: "Token",
+							Type:  "string",
+						},
+					},
+					Required:             []string{"type"},
+					AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+				},
+				"static": {
+					Title:       "Static Files",
+					Description: "directory of files to serve, for touch panels and dashboards",
+					Type:        "object",
+					Properties: map[string]*jsonschema.Schema{
+						"dir": {
+							Title: "Directory",
+							Type:  "string",
+						},
+						"prefix": {
+							Title:       "Prefix",
+							Description: "path the files are served under",
+							Type:        "string",
+							Default:     json.RawMessage(`"/"`),
+						},
+					},
+					Required:             []string{"dir"},
+					AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+				},
 			},
 			Required:             []string{"port"},
 			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
 		},
-		New: func(config config.ModuleConfig) (common.Module, error) {
-			params := config.Params
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
 			portNum, err := params.GetInt("port")
 			if err != nil {
 				return nil, fmt.Errorf("http.server port error: %w", err)
 			}
-			return &HTTPServer{Port: uint16(portNum), config: config, logger: CreateLogger(config)}, nil
+
+			ipString, err := params.GetString("ip")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					ipString = "0.0.0.0"
+				} else {
+					return nil, fmt.Errorf("http.server ip error: %w", err)
+				}
+			}
+
+			patterns, err := params.GetStringSlice("patterns")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					patterns = []string{"/"}
+				} else {
+					return nil, fmt.Errorf("http.server patterns error: %w", err)
+				}
+			}
+
+			certFileString, err := params.GetString("certFile")
+			if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+				return nil, fmt.Errorf("http.server certFile error: %w", err)
+			}
+
+			keyFileString, err := params.GetString("keyFile")
+			if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+				return nil, fmt.Errorf("http.server keyFile error: %w", err)
+			}
+
+			if (certFileString == "") != (keyFileString == "") {
+				return nil, errors.New("http.server certFile and keyFile must be set together")
+			}
+
+			httpServer := &HTTPServer{
+				config:   moduleConfig,
+				IP:       ipString,
+				Port:     uint16(portNum),
+				CertFile: certFileString,
+				KeyFile:  keyFileString,
+				mux:      http.NewServeMux(),
+				logger:   CreateLogger(moduleConfig),
+			}
+
+			corsParams, err := params.GetParams("cors")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("http.server cors error: %w", err)
+				}
+			} else {
+				cors, err := newHTTPServerCORS(corsParams)
+				if err != nil {
+					return nil, fmt.Errorf("http.server cors %w", err)
+				}
+				httpServer.cors = cors
+			}
+
+			authParams, err := params.GetParams("auth")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("http.server auth error: %w", err)
+				}
+			} else {
+				auth, err := newHTTPServerAuth(authParams)
+				if err != nil {
+					return nil, fmt.Errorf("http.server auth %w", err)
+				}
+				httpServer.auth = auth
+			}
+
+			for _, pattern := range patterns {
+				err := registerHTTPServerPattern(httpServer.mux, pattern, httpServer.inputHandlerFor(pattern))
+				if err != nil {
+					return nil, fmt.Errorf("http.server patterns error: %w", err)
+				}
+			}
+
+			staticParams, err := params.GetParams("static")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("http.server static error: %w", err)
+				}
+			} else {
+				dirString, err := staticParams.GetString("dir")
+				if err != nil {
+					return nil, fmt.Errorf("http.server static dir error: %w", err)
+				}
+
+				info, err := os.Stat(dirString)
+				if err != nil {
+					return nil, fmt.Errorf("http.server static dir error: %w", err)
+				}
+				if !info.IsDir() {
+					return nil, fmt.Errorf("http.server static dir error: %s is not a directory", dirString)
+				}
+
+				prefixString, err := staticParams.GetString("prefix")
+				if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("http.server static prefix error: %w", err)
+				}
+				if prefixString == "" {
+					prefixString = "/"
+				}
+
+				if !strings.HasPrefix(prefixString, "/") || !strings.HasSuffix(prefixString, "/") {
+					return nil, errors.New("http.server static prefix must start and end with /")
+				}
+
+				//NOTE(jwetzell): without declared patterns every request is routed so files need their own prefix
+				if slices.Contains(patterns, prefixString) {
+					return nil, fmt.Errorf("http.server static prefix %s is already a pattern, declare patterns or use another prefix", prefixString)
+				}
+
+				fileServer := http.StripPrefix(strings.TrimSuffix(prefixString, "/"), http.FileServer(http.Dir(dirString)))
+				err = registerHTTPServerPattern(httpServer.mux, "GET "+prefixString, fileServer)
+				if err != nil {
+					return nil, fmt.Errorf("http.server static prefix error: %w", err)
+				}
+			}
+
+			return httpServer, nil
 		},
 	})
 }
 
+// registerHTTPServerPattern turns the panics ServeMux uses for bad or conflicting patterns into errors
+func registerHTTPServerPattern(mux *http.ServeMux, pattern string, handler http.Handler) (err error) {
+	defer func() {
+		recovered := recover()
+		if recovered != nil {
+			err = fmt.Errorf("%v", recovered)
+		}
+	}()
+	mux.Handle(pattern, handler)
+	return nil
+}
+
+type httpServerCORS struct {
+	Origins []string
+	Methods string
+	Headers string
+}
+
+func newHTTPServerCORS(params config.Params) (*httpServerCORS, error) {
+	origins, err := params.GetStringSlice("origins")
+	if err != nil {
+		return nil, fmt.Errorf("origins error: %w", err)
+	}
+
+	methods, err := params.GetStringSlice("methods")
+	if err != nil {
+		if errors.Is(err, config.ErrParamNotFound) {
+			methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+		} else {
+			return nil, fmt.Errorf("methods error: %w", err)
+		}
+	}
+
+	headers, err := params.GetStringSlice("headers")
+	if err != nil {
+		if errors.Is(err, config.ErrParamNotFound) {
+			headers = []string{"Content-Type", "Authorization"}
+		} else {
+			return nil, fmt.Errorf("headers error: %w", err)
+		}
+	}
+
+	return &httpServerCORS{Origins: origins, Methods: strings.Join(methods, ", "), Headers: strings.Join(headers, ", ")}, nil
+}
+
+// apply sets the CORS headers for allowed origins and reports whether the request was a preflight that is now answered
+func (cors *httpServerCORS) apply(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+
+	if slices.Contains(cors.Origins, "*") {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	} else if slices.Contains(cors.Origins, origin) {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+	} else {
+		return false
+	}
+
+	if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Methods", cors.Methods)
+	w.Header().Set("Access-Control-Allow-Headers", cors.Headers)
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+type httpServerAuth struct {
+	Type     string
+	Username string
+	Password string
+	Token    string
+}
+
+func newHTTPServerAuth(params config.Params) (*httpServerAuth, error) {
+	typeString, err := params.GetString("type")
+	if err != nil {
+		return nil, fmt.Errorf("type error: %w", err)
+	}
+
+	auth := &httpServerAuth{Type: typeString}
+	switch typeString {
+	case "basic":
+		auth.Username, err = params.GetString("username")
+		if err != nil {
+			return nil, fmt.Errorf("username error: %w", err)
+		}
+		auth.Password, err = params.GetString("password")
+		if err != nil {
+			return nil, fmt.Errorf("password error: %w", err)
+		}
+	case "bearer":
+		auth.Token, err = params.GetString("token")
+		if err != nil {
+			return nil, fmt.Errorf("token error: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown type: %s", typeString)
+	}
+	return auth, nil
+}
+
+func (auth *httpServerAuth) check(w http.ResponseWriter, r *http.Request) bool {
+	if auth.Type == "basic" {
+		username, password, ok := r.BasicAuth()
+		usernameMatch := subtle.ConstantTimeCompare([]byte(username), []byte(auth.Username)) == 1
+		passwordMatch := subtle.ConstantTimeCompare([]byte(password), []byte(auth.Password)) == 1
+		if ok && usernameMatch && passwordMatch {
+			return true
+		}
+		w.Header().Set("WWW-Authenticate", `Basic realm="showbridge"`)
+	} else {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(token), []byte(auth.Token)) == 1 {
+			return true
+		}
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	http.Error(w, "unauthorized", http.StatusUnauthorized)
+	return false
+}
+
+// HTTPServerRequest is the parsed request routes receive, Body is decoded for JSON and form requests and is the raw bytes otherwise
+type HTTPServerRequest struct {
+	Method     string
+	Path       string
+	Pattern    string
+	Params     map[string]string
+	Query      url.Values
+	Headers    http.Header
+	Body       any
+	RawBody    []byte
+	RemoteAddr string
+	//NOTE(jwetzell): kept so templates written against *http.Request like {{.Payload.URL.Path}} keep working
+	URL *url.URL
+}
+
 type HTTPServer struct {
 	config       config.ModuleConfig
+	IP           string
 	Port         uint16
+	CertFile     string
+	KeyFile      string
 	ctx          context.Context
 	inputHandler common.InputHandler
 	logger       *slog.Logger
 	cancel       context.CancelFunc
 	server       *http.Server
 	serverMu     sync.Mutex
+	mux          *http.ServeMux
+	cors         *httpServerCORS
+	auth         *httpServerAuth
 }
 
 type ResponseIOError struct {
@@ -93,6 +473,77 @@ func (hs *HTTPServer) Type() string {
 }
 
 func (hs *HTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if hs.cors != nil && hs.cors.apply(w, r) {
+		return
+	}
+
+	if hs.auth != nil && !hs.auth.check(w, r) {
+		return
+	}
+
+	hs.mux.ServeHTTP(w, r)
+}
+
+func parseHTTPServerBody(r *http.Request, body []byte) (any, error) {
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		var decoded any
+		err := json.Unmarshal(body, &decoded)
+		if err != nil {
+			return nil, err
+		}
+		return decoded, nil
+	case "application/x-www-form-urlencoded":
+		return url.ParseQuery(string(body))
+	}
+	return body, nil
+}
+
+func (hs *HTTPServer) inputHandlerFor(pattern string) http.HandlerFunc {
+	names := []string{}
+	for _, match := range httpServerPatternParam.FindAllStringSubmatch(pattern, -1) {
+		names = append(names, match[1])
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, httpServerMaxBodySize))
+		if err != nil {
+			http.Error(w, "request body is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		decodedBody, err := parseHTTPServerBody(r, body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("request body could not be decoded: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		pathParams := make(map[string]string, len(names))
+		for _, name := range names {
+			pathParams[name] = r.PathValue(name)
+		}
+
+		hs.route(w, HTTPServerRequest{
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Pattern:    pattern,
+			Params:     pathParams,
+			Query:      r.URL.Query(),
+			Headers:    r.Header,
+			Body:       decodedBody,
+			RawBody:    body,
+			RemoteAddr: r.RemoteAddr,
+			URL:        r.URL,
+		})
+	}
+}
+
+func (hs *HTTPServer) route(w http.ResponseWriter, request HTTPServerRequest) {
 	responseWriter := HTTPServerResponseWriter{ResponseWriter: w}
 
 	response := IOResponseData{
@@ -101,7 +552,7 @@ func (hs *HTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	if hs.inputHandler != nil {
 		inputContext := context.WithValue(hs.ctx, httpServerContextKey("responseWriter"), &responseWriter)
-		aRouteFound, routingErrors := hs.inputHandler(inputContext, hs.Id(), r)
+		aRouteFound, routingErrors := hs.inputHandler(inputContext, hs.Id(), request)
 		if !responseWriter.done {
 			if aRouteFound {
 				if routingErrors != nil {
@@ -156,7 +607,7 @@ func (hs *HTTPServer) Start(ctx context.Context, inputHandler common.InputHandle
 	hs.cancel = cancel
 
 	httpServer := &http.Server{
-		Addr:              fmt.Sprintf(":%d", hs.Port),
+		Addr:              net.JoinHostPort(hs.IP, strconv.Itoa(int(hs.Port))),
 		ReadHeaderTimeout: 5 * time.Second,
 		Handler:           hs,
 	}
@@ -165,7 +616,12 @@ func (hs *HTTPServer) Start(ctx context.Context, inputHandler common.InputHandle
 	hs.server = httpServer
 	hs.serverMu.Unlock()
 
-	err := httpServer.ListenAndServe()
+	var err error
+	if hs.CertFile != "" {
+		err = httpServer.ListenAndServeTLS(hs.CertFile, hs.KeyFile)
+	} else {
+		err = httpServer.ListenAndServe()
+	}
 	// TODO(jwetzell): handle server closed error differently
 	if err != nil {
 		if !errors.Is(err, http.ErrServerClosed) {
@@ -195,6 +651,9 @@ func (hs *HTTPServer) Output(ctx context.Context, payload any) error {
 		return errors.New("http.server response writer has already been written to")
 	}
 
+	for name, value := range payloadResponse.Headers {
+		responseWriter.Header().Set(name, value)
+	}
 	responseWriter.WriteHeader(payloadResponse.Status)
 	responseWriter.Write(payloadResponse.Body)
 	return nil
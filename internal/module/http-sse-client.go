@@ -0,0 +1,213 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/sse"
+)
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "http.sse.client",
+		Title:       "Server-Sent Events Client",
+		Description: "Subscribe to a Server-Sent Events stream, each event is emitted as an sse.Event",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"url": {
+					Title:       "URL",
+					Description: "the URL of the event stream",
+					Type:        "string",
+				},
+				"headers": {
+					Title:       "Headers",
+					Description: "headers to send when connecting, like an Authorization header",
+					Type:        "object",
+					AdditionalProperties: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"reconnect": {
+					Title:       "Reconnect",
+					Description: "milliseconds to wait before reconnecting, a retry sent by the server replaces it",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](0),
+					Default:     json.RawMessage(`3000`),
+				},
+			},
+			Required:             []string{"url"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			urlString, err := params.GetString("url")
+			if err != nil {
+				return nil, fmt.Errorf("http.sse.client url error: %w", err)
+			}
+
+			parsedURL, err := url.Parse(urlString)
+			if err != nil {
+				return nil, fmt.Errorf("http.sse.client url error: %w", err)
+			}
+
+			if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+				return nil, errors.New("http.sse.client url error: scheme must be http or https")
+			}
+
+			headers, err := params.GetStringMap("headers")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					headers = map[string]string{}
+				} else {
+					return nil, fmt.Errorf("http.sse.client headers error: %w", err)
+				}
+			}
+
+			reconnectNum, err := params.GetInt("reconnect")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					reconnectNum = 3000
+				} else {
+					return nil, fmt.Errorf("http.sse.client reconnect error: %w", err)
+				}
+			}
+
+			if reconnectNum < 0 {
+				return nil, errors.New("http.sse.client reconnect must not be negative")
+			}
+
+			return &HTTPSSEClient{
+				config:    moduleConfig,
+				URL:       parsedURL.String(),
+				Headers:   headers,
+				Reconnect: time.Duration(reconnectNum) * time.Millisecond,
+				client:    &http.Client{},
+				logger:    CreateLogger(moduleConfig),
+			}, nil
+		},
+	})
+}
+
+type HTTPSSEClient struct {
+	config       config.ModuleConfig
+	URL          string
+	Headers      map[string]string
+	Reconnect    time.Duration
+	client       *http.Client
+	lastEventID  string
+	ctx          context.Context
+	inputHandler common.InputHandler
+	logger       *slog.Logger
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+func (hsc *HTTPSSEClient) Id() string {
+	return hsc.config.Id
+}
+
+func (hsc *HTTPSSEClient) Type() string {
+	return hsc.config.Type
+}
+
+func (hsc *HTTPSSEClient) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	hsc.logger.Debug("running")
+	hsc.inputHandler = inputHandler
+	moduleContext, cancel := context.WithCancel(ctx)
+	hsc.ctx = moduleContext
+	hsc.cancel = cancel
+
+	hsc.wg.Add(1)
+	defer hsc.wg.Done()
+
+	for hsc.ctx.Err() == nil {
+		err := hsc.subscribe()
+		if err != nil && hsc.ctx.Err() == nil {
+			hsc.logger.Error("stream error", "error", err)
+		}
+
+		// NOTE(jwetzell): wait before reconnecting, the server may have changed the delay with a retry field
+		timer := time.NewTimer(hsc.Reconnect)
+		select {
+		case <-hsc.ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+	hsc.logger.Debug("done")
+	return nil
+}
+
+// subscribe reads events from one connection to the stream until it ends
+func (hsc *HTTPSSEClient) subscribe() error {
+	request, err := http.NewRequestWithContext(hsc.ctx, http.MethodGet, hsc.URL, nil)
+	if err != nil {
+		return err
+	}
+
+	for name, value := range hsc.Headers {
+		request.Header.Set(name, value)
+	}
+	request.Header.Set("Accept", "text/event-stream")
+	request.Header.Set("Cache-Control", "no-cache")
+	if hsc.lastEventID != "" {
+		request.Header.Set("Last-Event-ID", hsc.lastEventID)
+	}
+
+	response, err := hsc.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("http.sse.client got status %s", response.Status)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	if mediaType != "text/event-stream" {
+		return fmt.Errorf("http.sse.client got content type %s", mediaType)
+	}
+
+	hsc.logger.Debug("connected", "lastEventID", hsc.lastEventID)
+
+	reader := sse.NewReader(response.Body)
+	reader.LastEventID = hsc.lastEventID
+	for hsc.ctx.Err() == nil {
+		event, err := reader.Next()
+		hsc.lastEventID = reader.LastEventID
+		if reader.Retry > 0 {
+			hsc.Reconnect = reader.Retry
+		}
+		if err != nil {
+			return err
+		}
+
+		if hsc.inputHandler == nil {
+			hsc.logger.Error("input received but no input handler is configured")
+			continue
+		}
+		hsc.inputHandler(hsc.ctx, hsc.Id(), event)
+	}
+	return nil
+}
+
+func (hsc *HTTPSSEClient) Stop() {
+	if hsc.cancel != nil {
+		hsc.cancel()
+	}
+	hsc.wg.Wait()
+}
@@ -0,0 +1,535 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "midi.file.player",
+		Title:       "MIDI File Player",
+		Description: "Play a Standard MIDI File, emits each midi.Message as it is scheduled and is controlled with play, pause, stop, locate, loop, tempo and mute commands",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"path": {
+					Title:       "Path",
+					Description: "path to a type 0 or type 1 Standard MIDI File",
+					Type:        "string",
+				},
+				"tempo": {
+					Title:       "Tempo",
+					Description: "tempo scale applied on top of the tempo map of the file",
+					Type:        "number",
+					Default:     json.RawMessage(`1`),
+				},
+				"loop": {
+					Title:       "Loop",
+					Description: "loop the whole file",
+					Type:        "boolean",
+					Default:     json.RawMessage(`false`),
+				},
+				"autoPlay": {
+					Title:       "Auto Play",
+					Description: "start playing as soon as the module starts",
+					Type:        "boolean",
+					Default:     json.RawMessage(`false`),
+				},
+			},
+			Required:             []string{"path"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			pathString, err := params.GetString("path")
+			if err != nil {
+				return nil, fmt.Errorf("midi.file.player path error: %w", err)
+			}
+
+			tempoNum, err := params.GetFloat64("tempo")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					tempoNum = 1
+				} else {
+					return nil, fmt.Errorf("midi.file.player tempo error: %w", err)
+				}
+			}
+
+			if tempoNum <= 0 || tempoNum > 10 {
+				return nil, errors.New("midi.file.player tempo must be greater than 0 and at most 10")
+			}
+
+			loopBool, err := params.GetBool("loop")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					loopBool = false
+				} else {
+					return nil, fmt.Errorf("midi.file.player loop error: %w", err)
+				}
+			}
+
+			autoPlayBool, err := params.GetBool("autoPlay")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					autoPlayBool = false
+				} else {
+					return nil, fmt.Errorf("midi.file.player autoPlay error: %w", err)
+				}
+			}
+
+			midiFile, err := smf.ReadFile(pathString)
+			if err != nil {
+				return nil, fmt.Errorf("midi.file.player path error: %w", err)
+			}
+
+			events, err := scheduleMIDIFile(midiFile)
+			if err != nil {
+				return nil, fmt.Errorf("midi.file.player %w", err)
+			}
+
+			length := time.Duration(0)
+			if len(events) > 0 {
+				length = events[len(events)-1].At
+			}
+
+			player := &MIDIFilePlayer{
+				config:   moduleConfig,
+				Path:     pathString,
+				Tempo:    tempoNum,
+				Loop:     loopBool,
+				AutoPlay: autoPlayBool,
+				Tracks:   len(midiFile.Tracks),
+				Length:   length,
+				events:   events,
+				tempo:    tempoNum,
+				muted:    map[int]bool{},
+				sounding: map[midiFileNote]int{},
+				wake:     make(chan struct{}, 1),
+				logger:   CreateLogger(moduleConfig),
+			}
+			if loopBool {
+				player.looping = true
+				player.loopEnd = length
+			}
+			return player, nil
+		},
+	})
+}
+
+type midiFileEvent struct {
+	At      time.Duration
+	Track   int
+	Message midi.Message
+}
+
+type midiFileNote struct {
+	channel uint8
+	key     uint8
+}
+
+// scheduleMIDIFile flattens every track of the file into a single list of playable messages ordered by time
+func scheduleMIDIFile(midiFile *smf.SMF) ([]midiFileEvent, error) {
+	if midiFile.Format() > 1 {
+		return nil, fmt.Errorf("only type 0 and type 1 files are supported, got type %d", midiFile.Format())
+	}
+
+	var tickTime func(int64) time.Duration
+	switch timeFormat := midiFile.TimeFormat.(type) {
+	case smf.MetricTicks:
+		//NOTE(jwetzell): TimeAt applies the tempo map collected from every track
+		tickTime = func(ticks int64) time.Duration {
+			return time.Duration(midiFile.TimeAt(ticks)) * time.Microsecond
+		}
+	case smf.TimeCode:
+		ticksPerSecond := int64(timeFormat.FramesPerSecond) * int64(timeFormat.SubFrames)
+		if ticksPerSecond == 0 {
+			return nil, errors.New("time code format has no ticks per second")
+		}
+		tickTime = func(ticks int64) time.Duration {
+			return time.Duration(ticks * int64(time.Second) / ticksPerSecond)
+		}
+	default:
+		return nil, errors.New("unsupported time format")
+	}
+
+	events := []midiFileEvent{}
+	for trackIndex, track := range midiFile.Tracks {
+		ticks := int64(0)
+		for _, event := range track {
+			ticks += int64(event.Delta)
+			message := event.Message
+			if !message.IsPlayable() {
+				continue
+			}
+			//NOTE(jwetzell): F7 escapes and split sysex packets can't be sent on their own
+			if message[0] == 0xF7 || (message[0] == 0xF0 && message[len(message)-1] != 0xF7) {
+				continue
+			}
+			events = append(events, midiFileEvent{
+				At:      tickTime(ticks),
+				Track:   trackIndex,
+				Message: midi.Message(append([]byte{}, message...)),
+			})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At < events[j].At
+	})
+	return events, nil
+}
+
+type MIDIFilePlayer struct {
+	config       config.ModuleConfig
+	Path         string
+	Tempo        float64
+	Loop         bool
+	AutoPlay     bool
+	Tracks       int
+	Length       time.Duration
+	ctx          context.Context
+	inputHandler common.InputHandler
+	logger       *slog.Logger
+	cancel       context.CancelFunc
+	mu           sync.Mutex
+	events       []midiFileEvent
+	next         int
+	tempo        float64
+	playing      bool
+	anchor       time.Duration
+	anchorTime   time.Time
+	looping      bool
+	loopStart    time.Duration
+	loopEnd      time.Duration
+	muted        map[int]bool
+	sounding     map[midiFileNote]int
+	wake         chan struct{}
+}
+
+func (mfp *MIDIFilePlayer) Id() string {
+	return mfp.config.Id
+}
+
+func (mfp *MIDIFilePlayer) Type() string {
+	return mfp.config.Type
+}
+
+func (mfp *MIDIFilePlayer) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	mfp.logger.Debug("running")
+	mfp.inputHandler = inputHandler
+	moduleContext, cancel := context.WithCancel(ctx)
+	mfp.ctx = moduleContext
+	mfp.cancel = cancel
+
+	if mfp.AutoPlay {
+		mfp.mu.Lock()
+		mfp.play(time.Now())
+		mfp.mu.Unlock()
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		mfp.mu.Lock()
+		messages, wait := mfp.advance(time.Now())
+		mfp.mu.Unlock()
+
+		mfp.emit(messages)
+
+		timer.Reset(wait)
+		select {
+		case <-mfp.ctx.Done():
+			mfp.logger.Debug("done")
+			return nil
+		case <-mfp.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+func (mfp *MIDIFilePlayer) emit(messages []midi.Message) {
+	if len(messages) == 0 {
+		return
+	}
+
+	if mfp.inputHandler == nil {
+		mfp.logger.Error("input received but no input handler is configured")
+		return
+	}
+
+	for _, message := range messages {
+		mfp.inputHandler(mfp.ctx, mfp.Id(), message)
+	}
+}
+
+// position is the current file time, callers hold the lock
+func (mfp *MIDIFilePlayer) position(now time.Time) time.Duration {
+	if !mfp.playing {
+		return mfp.anchor
+	}
+	return mfp.anchor + time.Duration(float64(now.Sub(mfp.anchorTime))*mfp.tempo)
+}
+
+// seek moves the anchor and the next event to a file time, callers hold the lock
+func (mfp *MIDIFilePlayer) seek(at time.Duration, now time.Time) {
+	mfp.anchor = at
+	mfp.anchorTime = now
+	mfp.next = sort.Search(len(mfp.events), func(i int) bool {
+		return mfp.events[i].At >= at
+	})
+}
+
+func (mfp *MIDIFilePlayer) play(now time.Time) {
+	if mfp.playing {
+		return
+	}
+	if mfp.next >= len(mfp.events) {
+		mfp.seek(mfp.loopStart, now)
+	}
+	mfp.anchorTime = now
+	mfp.playing = true
+}
+
+// advance collects the messages that are due and how long to wait for the next one, callers hold the lock
+func (mfp *MIDIFilePlayer) advance(now time.Time) ([]midi.Message, time.Duration) {
+	idle := time.Second
+	if !mfp.playing {
+		return nil, idle
+	}
+
+	messages := []midi.Message{}
+	current := mfp.position(now)
+
+	if mfp.looping && mfp.loopEnd > mfp.loopStart && current >= mfp.loopEnd {
+		messages = append(messages, mfp.collect(mfp.loopEnd)...)
+		messages = append(messages, mfp.notesOff(-1)...)
+		overshoot := (current - mfp.loopStart) % (mfp.loopEnd - mfp.loopStart)
+		mfp.seek(mfp.loopStart, now)
+		//NOTE(jwetzell): keep the time past the loop end so looping doesn't drift
+		mfp.anchor = mfp.loopStart + overshoot
+		current = mfp.anchor
+	}
+
+	messages = append(messages, mfp.collect(current)...)
+
+	if mfp.next >= len(mfp.events) && !mfp.looping {
+		mfp.anchor = mfp.Length
+		mfp.playing = false
+		messages = append(messages, mfp.notesOff(-1)...)
+		return messages, idle
+	}
+
+	target := mfp.loopEnd
+	if mfp.next < len(mfp.events) && (!mfp.looping || mfp.events[mfp.next].At < mfp.loopEnd) {
+		target = mfp.events[mfp.next].At
+	}
+	wait := time.Duration(float64(target-current) / mfp.tempo)
+	if wait > idle {
+		wait = idle
+	}
+	return messages, wait
+}
+
+// collect returns the unmuted messages up to a file time, callers hold the lock
+func (mfp *MIDIFilePlayer) collect(until time.Duration) []midi.Message {
+	messages := []midi.Message{}
+	for mfp.next < len(mfp.events) {
+		event := mfp.events[mfp.next]
+		if event.At > until {
+			break
+		}
+		mfp.next++
+		if mfp.muted[event.Track] {
+			continue
+		}
+		mfp.track(event)
+		messages = append(messages, event.Message)
+	}
+	return messages
+}
+
+// track remembers which notes are sounding so they can be released, callers hold the lock
+func (mfp *MIDIFilePlayer) track(event midiFileEvent) {
+	var channel, key, velocity uint8
+	switch {
+	case event.Message.GetNoteStart(&channel, &key, &velocity):
+		mfp.sounding[midiFileNote{channel: channel, key: key}] = event.Track
+	case event.Message.GetNoteEnd(&channel, &key):
+		delete(mfp.sounding, midiFileNote{channel: channel, key: key})
+	}
+}
+
+// notesOff releases the sounding notes of a track or of every track when track is -1, callers hold the lock
+func (mfp *MIDIFilePlayer) notesOff(track int) []midi.Message {
+	notes := []midiFileNote{}
+	for note, noteTrack := range mfp.sounding {
+		if track == -1 || noteTrack == track {
+			notes = append(notes, note)
+		}
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		if notes[i].channel != notes[j].channel {
+			return notes[i].channel < notes[j].channel
+		}
+		return notes[i].key < notes[j].key
+	})
+
+	messages := []midi.Message{}
+	for _, note := range notes {
+		delete(mfp.sounding, note)
+		messages = append(messages, midi.NoteOff(note.channel, note.key))
+	}
+	return messages
+}
+
+// Output accepts play, pause, stop, locate <seconds>, loop <start> <end>, loop off, tempo <scale>, mute <track> and unmute <track> commands
+func (mfp *MIDIFilePlayer) Output(ctx context.Context, payload any) error {
+	commandString, ok := common.GetAnyAs[string](payload)
+	if !ok {
+		payloadBytes, ok := common.GetAnyAsByteSlice(payload)
+		if !ok {
+			return errors.New("midi.file.player can only output a command string")
+		}
+		commandString = string(payloadBytes)
+	}
+
+	fields := strings.Fields(commandString)
+	if len(fields) == 0 {
+		return errors.New("midi.file.player command must not be empty")
+	}
+
+	mfp.mu.Lock()
+	messages, err := mfp.command(fields, time.Now())
+	mfp.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	mfp.emit(messages)
+
+	select {
+	case mfp.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (mfp *MIDIFilePlayer) command(fields []string, now time.Time) ([]midi.Message, error) {
+	switch strings.ToLower(fields[0]) {
+	case "play":
+		mfp.play(now)
+	case "pause":
+		mfp.anchor = mfp.position(now)
+		mfp.playing = false
+		return mfp.notesOff(-1), nil
+	case "stop":
+		mfp.playing = false
+		mfp.seek(mfp.loopStart, now)
+		return mfp.notesOff(-1), nil
+	case "locate":
+		if len(fields) != 2 {
+			return nil, errors.New("midi.file.player locate needs a time in seconds")
+		}
+		at, err := parseMIDIFileSeconds(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("midi.file.player locate error: %w", err)
+		}
+		mfp.seek(at, now)
+		return mfp.notesOff(-1), nil
+	case "loop":
+		if len(fields) == 2 && strings.ToLower(fields[1]) == "off" {
+			mfp.anchor = mfp.position(now)
+			mfp.anchorTime = now
+			mfp.looping = false
+			mfp.loopStart = 0
+			mfp.loopEnd = 0
+			return nil, nil
+		}
+		if len(fields) != 3 {
+			return nil, errors.New("midi.file.player loop needs a start and end in seconds or off")
+		}
+		loopStart, err := parseMIDIFileSeconds(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("midi.file.player loop start error: %w", err)
+		}
+		loopEnd, err := parseMIDIFileSeconds(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("midi.file.player loop end error: %w", err)
+		}
+		if loopEnd <= loopStart {
+			return nil, errors.New("midi.file.player loop end must be after loop start")
+		}
+		mfp.looping = true
+		mfp.loopStart = loopStart
+		mfp.loopEnd = loopEnd
+	case "tempo":
+		if len(fields) != 2 {
+			return nil, errors.New("midi.file.player tempo needs a number")
+		}
+		tempo, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("midi.file.player tempo error: %w", err)
+		}
+		if tempo <= 0 || tempo > 10 {
+			return nil, errors.New("midi.file.player tempo must be greater than 0 and at most 10")
+		}
+		mfp.anchor = mfp.position(now)
+		mfp.anchorTime = now
+		mfp.tempo = tempo
+	case "mute", "unmute":
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("midi.file.player %s needs a track number", fields[0])
+		}
+		track, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("midi.file.player %s error: %w", fields[0], err)
+		}
+		if track < 0 || track >= mfp.Tracks {
+			return nil, fmt.Errorf("midi.file.player track must be between 0 and %d", mfp.Tracks-1)
+		}
+		if strings.ToLower(fields[0]) == "unmute" {
+			delete(mfp.muted, track)
+			return nil, nil
+		}
+		mfp.muted[track] = true
+		return mfp.notesOff(track), nil
+	default:
+		return nil, fmt.Errorf("midi.file.player unknown command: %s", fields[0])
+	}
+	return nil, nil
+}
+
+func parseMIDIFileSeconds(value string) (time.Duration, error) {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if seconds < 0 {
+		return 0, errors.New("must not be negative")
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+func (mfp *MIDIFilePlayer) Stop() {
+	if mfp.cancel != nil {
+		mfp.cancel()
+	}
+}
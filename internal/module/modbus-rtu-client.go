@@ -0,0 +1,271 @@
+//go:build cgo
+
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/modbus"
+	"go.bug.st/serial"
+)
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "modbus.rtu.client",
+		Title:       "Modbus RTU Client",
+		Description: "Poll coils and registers of a Modbus RTU device on a serial port, emits a modbus.Block when polled values change and writes modbus.PDU or modbus.Block payloads",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"port": {
+					Title:       "Port",
+					Description: "the name of the serial port the Modbus line is on",
+					Type:        "string",
+				},
+				"baudRate": {
+					Title:       "Baud Rate",
+					Description: "the baud rate of the Modbus line",
+					Type:        "integer",
+					Default:     json.RawMessage(`9600`),
+				},
+				"parity": {
+					Title:       "Parity",
+					Description: "the parity of the Modbus line",
+					Type:        "string",
+					Enum:        []any{"none", "even", "odd"},
+					Default:     json.RawMessage(`"even"`),
+				},
+				"stopBits": {
+					Title:       "Stop Bits",
+					Description: "the number of stop bits on the Modbus line",
+					Type:        "integer",
+					Enum:        []any{1, 2},
+					Default:     json.RawMessage(`1`),
+				},
+				"unitId":   modbusUnitIDSchema(247),
+				"interval": modbusIntervalSchema(),
+				"timeout":  modbusTimeoutSchema(),
+				"polls":    modbusPollsSchema(),
+			},
+			Required:             []string{"port"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			portString, err := params.GetString("port")
+			if err != nil {
+				return nil, fmt.Errorf("modbus.rtu.client port error: %w", err)
+			}
+
+			baudRateInt, err := params.GetInt("baudRate")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					baudRateInt = 9600
+				} else {
+					return nil, fmt.Errorf("modbus.rtu.client baudRate error: %w", err)
+				}
+			}
+
+			parityString, err := params.GetString("parity")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					parityString = "even"
+				} else {
+					return nil, fmt.Errorf("modbus.rtu.client parity error: %w", err)
+				}
+			}
+
+			var parity serial.Parity
+			switch parityString {
+			case "none":
+				parity = serial.NoParity
+			case "even":
+				parity = serial.EvenParity
+			case "odd":
+				parity = serial.OddParity
+			default:
+				return nil, fmt.Errorf("modbus.rtu.client unknown parity: %s", parityString)
+			}
+
+			stopBitsInt, err := params.GetInt("stopBits")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					stopBitsInt = 1
+				} else {
+					return nil, fmt.Errorf("modbus.rtu.client stopBits error: %w", err)
+				}
+			}
+
+			var stopBits serial.StopBits
+			switch stopBitsInt {
+			case 1:
+				stopBits = serial.OneStopBit
+			case 2:
+				stopBits = serial.TwoStopBits
+			default:
+				return nil, errors.New("modbus.rtu.client stopBits must be 1 or 2")
+			}
+
+			poller, err := newModbusPoller("modbus.rtu.client", params, 247)
+			if err != nil {
+				return nil, err
+			}
+
+			mode := serial.Mode{
+				BaudRate: baudRateInt,
+				DataBits: 8,
+				Parity:   parity,
+				StopBits: stopBits,
+			}
+
+			return &ModbusRTUClient{config: moduleConfig, Port: portString, Mode: &mode, poller: poller, logger: CreateLogger(moduleConfig)}, nil
+		},
+	})
+}
+
+type ModbusRTUClient struct {
+	config       config.ModuleConfig
+	Port         string
+	Mode         *serial.Mode
+	poller       *modbusPoller
+	ctx          context.Context
+	inputHandler common.InputHandler
+	logger       *slog.Logger
+	cancel       context.CancelFunc
+	port         serial.Port
+	transport    *modbus.RTUTransport
+	portMu       sync.Mutex
+}
+
+func (mrc *ModbusRTUClient) Id() string {
+	return mrc.config.Id
+}
+
+func (mrc *ModbusRTUClient) Type() string {
+	return mrc.config.Type
+}
+
+func (mrc *ModbusRTUClient) SetupPort() error {
+	mrc.portMu.Lock()
+	defer mrc.portMu.Unlock()
+	port, err := serial.Open(mrc.Port, mrc.Mode)
+	if err != nil {
+		return err
+	}
+
+	//NOTE(jwetzell): short reads let the transport check its own response timeout
+	err = port.SetReadTimeout(10 * time.Millisecond)
+	if err != nil {
+		port.Close()
+		return err
+	}
+
+	mrc.port = port
+	mrc.transport = modbus.NewRTUTransport(port, mrc.poller.Timeout)
+	return nil
+}
+
+func (mrc *ModbusRTUClient) closePort() {
+	mrc.portMu.Lock()
+	defer mrc.portMu.Unlock()
+	if mrc.port != nil {
+		mrc.port.Close()
+	}
+	mrc.port = nil
+	mrc.transport = nil
+}
+
+func (mrc *ModbusRTUClient) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	mrc.logger.Debug("running")
+	mrc.inputHandler = inputHandler
+	moduleContext, cancel := context.WithCancel(ctx)
+	mrc.ctx = moduleContext
+	mrc.cancel = cancel
+
+	for mrc.ctx.Err() == nil {
+		err := mrc.SetupPort()
+		if err != nil {
+			if mrc.ctx.Err() != nil {
+				break
+			}
+			mrc.logger.Error("port setup error", "port", mrc.Port, "error", err.Error())
+			time.Sleep(time.Second * 2)
+			continue
+		}
+
+		mrc.poller.forget()
+		err = mrc.pollLoop()
+		mrc.closePort()
+		if err != nil && mrc.ctx.Err() == nil {
+			mrc.logger.Error("poll error", "error", err.Error())
+		}
+	}
+	mrc.logger.Debug("done")
+	return nil
+}
+
+func (mrc *ModbusRTUClient) pollLoop() error {
+	ticker := time.NewTicker(mrc.poller.Interval)
+	defer ticker.Stop()
+
+	for {
+		mrc.portMu.Lock()
+		transport := mrc.transport
+		mrc.portMu.Unlock()
+
+		if transport == nil {
+			return nil
+		}
+
+		changed, err := mrc.poller.poll(transport, mrc.logger)
+		for _, block := range changed {
+			if mrc.inputHandler != nil {
+				mrc.inputHandler(mrc.ctx, mrc.Id(), block)
+			} else {
+				mrc.logger.Error("input received but no input handler is configured")
+			}
+		}
+		//NOTE(jwetzell): a silent unit shouldn't reopen the port, only a failing port should
+		if err != nil && !errors.Is(err, modbus.ErrTimeout) {
+			return err
+		}
+		if err != nil {
+			mrc.logger.Warn("poll timed out", "unitId", mrc.poller.UnitID)
+		}
+
+		select {
+		case <-mrc.ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (mrc *ModbusRTUClient) Output(ctx context.Context, payload any) error {
+	mrc.portMu.Lock()
+	transport := mrc.transport
+	mrc.portMu.Unlock()
+
+	//NOTE(jwetzell): avoid handing a typed nil pointer to the interface
+	if transport == nil {
+		return mrc.poller.write("modbus.rtu.client", nil, payload)
+	}
+	return mrc.poller.write("modbus.rtu.client", transport, payload)
+}
+
+func (mrc *ModbusRTUClient) Stop() {
+	if mrc.cancel != nil {
+		mrc.cancel()
+	}
+	mrc.closePort()
+}
@@ -0,0 +1,447 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/modbus"
+)
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "modbus.tcp.client",
+		Title:       "Modbus TCP Client",
+		Description: "Poll coils and registers of a Modbus TCP device, emits a modbus.Block when polled values change and writes modbus.PDU or modbus.Block payloads",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"host": {
+					Title:       "Host",
+					Description: "the hostname or IP address of the Modbus device",
+					Type:        "string",
+				},
+				"port": {
+					Title:       "Port",
+					Description: "the port of the Modbus device",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1),
+					Maximum:     jsonschema.Ptr[float64](65535),
+					Default:     json.RawMessage(`502`),
+				},
+				"unitId":   modbusUnitIDSchema(255),
+				"interval": modbusIntervalSchema(),
+				"timeout":  modbusTimeoutSchema(),
+				"polls":    modbusPollsSchema(),
+			},
+			Required:             []string{"host"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			hostString, err := params.GetString("host")
+			if err != nil {
+				return nil, fmt.Errorf("modbus.tcp.client host error: %w", err)
+			}
+
+			portNum, err := params.GetInt("port")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					portNum = 502
+				} else {
+					return nil, fmt.Errorf("modbus.tcp.client port error: %w", err)
+				}
+			}
+
+			if portNum < 1 || portNum > 65535 {
+				return nil, errors.New("modbus.tcp.client port must be between 1 and 65535")
+			}
+
+			poller, err := newModbusPoller("modbus.tcp.client", params, 255)
+			if err != nil {
+				return nil, err
+			}
+
+			return &ModbusTCPClient{
+				config: moduleConfig,
+				Addr:   net.JoinHostPort(hostString, fmt.Sprint(portNum)),
+				poller: poller,
+				logger: CreateLogger(moduleConfig),
+			}, nil
+		},
+	})
+}
+
+func modbusUnitIDSchema(maximum float64) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Title:       "Unit ID",
+		Description: "the Modbus unit (slave) id to address",
+		Type:        "integer",
+		Minimum:     jsonschema.Ptr[float64](0),
+		Maximum:     jsonschema.Ptr(maximum),
+		Default:     json.RawMessage(`1`),
+	}
+}
+
+func modbusIntervalSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Title:       "Interval",
+		Description: "milliseconds between polls",
+		Type:        "integer",
+		Minimum:     jsonschema.Ptr[float64](10),
+		Default:     json.RawMessage(`1000`),
+	}
+}
+
+func modbusTimeoutSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Title:       "Timeout",
+		Description: "milliseconds to wait for a response",
+		Type:        "integer",
+		Minimum:     jsonschema.Ptr[float64](1),
+		Default:     json.RawMessage(`1000`),
+	}
+}
+
+func modbusPollsSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Title:       "Polls",
+		Description: "blocks of coils or registers to read every interval",
+		Type:        "array",
+		Items: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"table": {
+					Title: "Table",
+					Type:  "string",
+					Enum:  []any{"coils", "discreteInputs", "holdingRegisters", "inputRegisters"},
+				},
+				"address": {
+					Title:   "Address",
+					Type:    "integer",
+					Minimum: jsonschema.Ptr[float64](0),
+					Maximum: jsonschema.Ptr[float64](65535),
+				},
+				"quantity": {
+					Title:   "Quantity",
+					Type:    "integer",
+					Minimum: jsonschema.Ptr[float64](1),
+					Default: json.RawMessage(`1`),
+				},
+			},
+			Required:             []string{"table", "address"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+	}
+}
+
+// modbusPoller holds the polling setup shared by the Modbus client modules
+type modbusPoller struct {
+	UnitID   uint8
+	Interval time.Duration
+	Timeout  time.Duration
+	Polls    []modbus.PDU
+	last     []*modbus.Block
+}
+
+func newModbusPoller(moduleType string, params config.Params, maxUnitID int) (*modbusPoller, error) {
+	unitIDNum, err := params.GetInt("unitId")
+	if err != nil {
+		if errors.Is(err, config.ErrParamNotFound) {
+			unitIDNum = 1
+		} else {
+			return nil, fmt.Errorf("%s unitId error: %w", moduleType, err)
+		}
+	}
+
+	if unitIDNum < 0 || unitIDNum > maxUnitID {
+		return nil, fmt.Errorf("%s unitId must be between 0 and %d", moduleType, maxUnitID)
+	}
+
+	intervalNum, err := params.GetInt("interval")
+	if err != nil {
+		if errors.Is(err, config.ErrParamNotFound) {
+			intervalNum = 1000
+		} else {
+			return nil, fmt.Errorf("%s interval error: %w", moduleType, err)
+		}
+	}
+
+	if intervalNum < 10 {
+		return nil, fmt.Errorf("%s interval must be at least 10", moduleType)
+	}
+
+	timeoutNum, err := params.GetInt("timeout")
+	if err != nil {
+		if errors.Is(err, config.ErrParamNotFound) {
+			timeoutNum = 1000
+		} else {
+			return nil, fmt.Errorf("%s timeout error: %w", moduleType, err)
+		}
+	}
+
+	if timeoutNum < 1 {
+		return nil, fmt.Errorf("%s timeout must be at least 1", moduleType)
+	}
+
+	polls := []modbus.PDU{}
+	pollsValue, ok := params["polls"]
+	if ok {
+		pollsSlice, ok := pollsValue.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s polls error: %w", moduleType, config.ErrParamNotSlice)
+		}
+
+		for pollIndex, pollValue := range pollsSlice {
+			pollMap, ok := pollValue.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s polls[%d] error: %w", moduleType, pollIndex, config.ErrParamNotMap)
+			}
+			pollParams := config.Params(pollMap)
+
+			tableString, err := pollParams.GetString("table")
+			if err != nil {
+				return nil, fmt.Errorf("%s polls[%d] table error: %w", moduleType, pollIndex, err)
+			}
+
+			table, err := modbus.ParseTable(tableString)
+			if err != nil {
+				return nil, fmt.Errorf("%s polls[%d] table error: %w", moduleType, pollIndex, err)
+			}
+
+			addressNum, err := pollParams.GetInt("address")
+			if err != nil {
+				return nil, fmt.Errorf("%s polls[%d] address error: %w", moduleType, pollIndex, err)
+			}
+
+			if addressNum < 0 || addressNum > 65535 {
+				return nil, fmt.Errorf("%s polls[%d] address must be between 0 and 65535", moduleType, pollIndex)
+			}
+
+			quantityNum, err := pollParams.GetInt("quantity")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					quantityNum = 1
+				} else {
+					return nil, fmt.Errorf("%s polls[%d] quantity error: %w", moduleType, pollIndex, err)
+				}
+			}
+
+			if quantityNum < 1 || quantityNum > modbus.MaxReadBits {
+				return nil, fmt.Errorf("%s polls[%d] quantity must be between 1 and %d", moduleType, pollIndex, modbus.MaxReadBits)
+			}
+
+			request, err := modbus.ReadRequest(table, uint16(addressNum), uint16(quantityNum))
+			if err != nil {
+				return nil, fmt.Errorf("%s polls[%d] error: %w", moduleType, pollIndex, err)
+			}
+			polls = append(polls, request)
+		}
+	}
+
+	return &modbusPoller{
+		UnitID:   uint8(unitIDNum),
+		Interval: time.Duration(intervalNum) * time.Millisecond,
+		Timeout:  time.Duration(timeoutNum) * time.Millisecond,
+		Polls:    polls,
+		last:     make([]*modbus.Block, len(polls)),
+	}, nil
+}
+
+// poll reads every block and returns the ones that changed since the last poll, exceptions are logged and skipped
+func (mp *modbusPoller) poll(transport modbus.Transport, logger *slog.Logger) ([]modbus.Block, error) {
+	changed := []modbus.Block{}
+	for pollIndex, request := range mp.Polls {
+		response, err := transport.Send(mp.UnitID, request)
+		if err != nil {
+			return changed, err
+		}
+
+		block, err := modbus.ParseReadResponse(request, response)
+		if err != nil {
+			logger.Warn("poll failed", "poll", pollIndex, "error", err)
+			continue
+		}
+
+		if mp.last[pollIndex] != nil && mp.last[pollIndex].Equal(block) {
+			continue
+		}
+		mp.last[pollIndex] = &block
+		changed = append(changed, block)
+	}
+	return changed, nil
+}
+
+// forget clears the last polled values so everything is emitted again after a reconnect
+func (mp *modbusPoller) forget() {
+	mp.last = make([]*modbus.Block, len(mp.Polls))
+}
+
+func (mp *modbusPoller) write(moduleType string, transport modbus.Transport, payload any) error {
+	request, ok := common.GetAnyAs[modbus.PDU](payload)
+	if !ok {
+		requestPointer, ok := common.GetAnyAs[*modbus.PDU](payload)
+		if ok && requestPointer != nil {
+			request = *requestPointer
+		} else {
+			block, ok := common.GetAnyAs[modbus.Block](payload)
+			if !ok {
+				return fmt.Errorf("%s can only output a modbus.PDU or modbus.Block", moduleType)
+			}
+			var err error
+			request, err = block.WriteRequest()
+			if err != nil {
+				return fmt.Errorf("%s %w", moduleType, err)
+			}
+		}
+	}
+
+	if transport == nil {
+		return fmt.Errorf("%s is not connected", moduleType)
+	}
+
+	response, err := transport.Send(mp.UnitID, request)
+	if err != nil {
+		return fmt.Errorf("%s %w", moduleType, err)
+	}
+
+	err = modbus.CheckResponse(request, response)
+	if err != nil {
+		return fmt.Errorf("%s %w", moduleType, err)
+	}
+	return nil
+}
+
+type ModbusTCPClient struct {
+	config       config.ModuleConfig
+	Addr         string
+	poller       *modbusPoller
+	ctx          context.Context
+	inputHandler common.InputHandler
+	logger       *slog.Logger
+	cancel       context.CancelFunc
+	conn         net.Conn
+	transport    *modbus.TCPTransport
+	connMu       sync.Mutex
+}
+
+func (mtc *ModbusTCPClient) Id() string {
+	return mtc.config.Id
+}
+
+func (mtc *ModbusTCPClient) Type() string {
+	return mtc.config.Type
+}
+
+func (mtc *ModbusTCPClient) SetupConn() error {
+	mtc.connMu.Lock()
+	defer mtc.connMu.Unlock()
+	dialer := net.Dialer{Timeout: mtc.poller.Timeout}
+	conn, err := dialer.DialContext(mtc.ctx, "tcp", mtc.Addr)
+	if err != nil {
+		return err
+	}
+	mtc.conn = conn
+	mtc.transport = modbus.NewTCPTransport(conn, mtc.poller.Timeout)
+	return nil
+}
+
+func (mtc *ModbusTCPClient) closeConn() {
+	mtc.connMu.Lock()
+	defer mtc.connMu.Unlock()
+	if mtc.conn != nil {
+		mtc.conn.Close()
+	}
+	mtc.conn = nil
+	mtc.transport = nil
+}
+
+func (mtc *ModbusTCPClient) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	mtc.logger.Debug("running")
+	mtc.inputHandler = inputHandler
+	moduleContext, cancel := context.WithCancel(ctx)
+	mtc.ctx = moduleContext
+	mtc.cancel = cancel
+
+	for mtc.ctx.Err() == nil {
+		err := mtc.SetupConn()
+		if err != nil {
+			if mtc.ctx.Err() != nil {
+				break
+			}
+			mtc.logger.Error("connection error", "error", err.Error())
+			time.Sleep(time.Second * 2)
+			continue
+		}
+
+		mtc.poller.forget()
+		err = mtc.pollLoop()
+		mtc.closeConn()
+		if err != nil && mtc.ctx.Err() == nil {
+			mtc.logger.Error("poll error", "error", err.Error())
+		}
+	}
+	mtc.logger.Debug("done")
+	return nil
+}
+
+func (mtc *ModbusTCPClient) pollLoop() error {
+	ticker := time.NewTicker(mtc.poller.Interval)
+	defer ticker.Stop()
+
+	for {
+		mtc.connMu.Lock()
+		transport := mtc.transport
+		mtc.connMu.Unlock()
+
+		if transport == nil {
+			return nil
+		}
+
+		changed, err := mtc.poller.poll(transport, mtc.logger)
+		for _, block := range changed {
+			if mtc.inputHandler != nil {
+				mtc.inputHandler(mtc.ctx, mtc.Id(), block)
+			} else {
+				mtc.logger.Error("input received but no input handler is configured")
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-mtc.ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (mtc *ModbusTCPClient) Output(ctx context.Context, payload any) error {
+	mtc.connMu.Lock()
+	transport := mtc.transport
+	mtc.connMu.Unlock()
+
+	//NOTE(jwetzell): avoid handing a typed nil pointer to the interface
+	if transport == nil {
+		return mtc.poller.write("modbus.tcp.client", nil, payload)
+	}
+	return mtc.poller.write("modbus.tcp.client", transport, payload)
+}
+
+func (mtc *ModbusTCPClient) Stop() {
+	if mtc.cancel != nil {
+		mtc.cancel()
+	}
+	mtc.closeConn()
+}
@@ -0,0 +1,278 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/modbus"
+)
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "modbus.tcp.server",
+		Title:       "Modbus TCP Server",
+		Description: "Serve a Modbus register map over TCP, emits a modbus.Block when a client writes and is read or written by outputting a modbus.PDU or modbus.Block",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"ip": {
+					Title:       "IP",
+					Description: "the IP address to bind the Modbus server to",
+					Type:        "string",
+					Default:     json.RawMessage(`"0.0.0.0"`),
+				},
+				"port": {
+					Title:       "Port",
+					Description: "the port for the Modbus server to listen on",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1),
+					Maximum:     jsonschema.Ptr[float64](65535),
+					Default:     json.RawMessage(`502`),
+				},
+				"unitId": {
+					Title:       "Unit ID",
+					Description: "the unit id to answer, 0 answers every unit id",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](0),
+					Maximum:     jsonschema.Ptr[float64](255),
+					Default:     json.RawMessage(`0`),
+				},
+				"coils":            modbusTableSizeSchema("Coils", "number of coils starting at address 0"),
+				"discreteInputs":   modbusTableSizeSchema("Discrete Inputs", "number of discrete inputs starting at address 0"),
+				"holdingRegisters": modbusTableSizeSchema("Holding Registers", "number of holding registers starting at address 0"),
+				"inputRegisters":   modbusTableSizeSchema("Input Registers", "number of input registers starting at address 0"),
+			},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			ipString, err := params.GetString("ip")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					ipString = "0.0.0.0"
+				} else {
+					return nil, fmt.Errorf("modbus.tcp.server ip error: %w", err)
+				}
+			}
+
+			portNum, err := params.GetInt("port")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					portNum = 502
+				} else {
+					return nil, fmt.Errorf("modbus.tcp.server port error: %w", err)
+				}
+			}
+
+			if portNum < 1 || portNum > 65535 {
+				return nil, errors.New("modbus.tcp.server port must be between 1 and 65535")
+			}
+
+			unitIDNum, err := params.GetInt("unitId")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					unitIDNum = 0
+				} else {
+					return nil, fmt.Errorf("modbus.tcp.server unitId error: %w", err)
+				}
+			}
+
+			if unitIDNum < 0 || unitIDNum > 255 {
+				return nil, errors.New("modbus.tcp.server unitId must be between 0 and 255")
+			}
+
+			sizes := map[string]int{}
+			for _, table := range []string{"coils", "discreteInputs", "holdingRegisters", "inputRegisters"} {
+				sizeNum, err := params.GetInt(table)
+				if err != nil {
+					if errors.Is(err, config.ErrParamNotFound) {
+						sizeNum = 100
+					} else {
+						return nil, fmt.Errorf("modbus.tcp.server %s error: %w", table, err)
+					}
+				}
+
+				if sizeNum < 0 || sizeNum > 65536 {
+					return nil, fmt.Errorf("modbus.tcp.server %s must be between 0 and 65536", table)
+				}
+				sizes[table] = sizeNum
+			}
+
+			addr, err := net.ResolveTCPAddr("tcp", fmt.Sprintf("%s:%d", ipString, uint16(portNum)))
+			if err != nil {
+				return nil, err
+			}
+
+			return &ModbusTCPServer{
+				config: moduleConfig,
+				Addr:   addr,
+				UnitID: uint8(unitIDNum),
+				Model:  modbus.NewDataModel(sizes["coils"], sizes["discreteInputs"], sizes["holdingRegisters"], sizes["inputRegisters"]),
+				logger: CreateLogger(moduleConfig),
+			}, nil
+		},
+	})
+}
+
+func modbusTableSizeSchema(title string, description string) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Title:       title,
+		Description: description,
+		Type:        "integer",
+		Minimum:     jsonschema.Ptr[float64](0),
+		Maximum:     jsonschema.Ptr[float64](65536),
+		Default:     json.RawMessage(`100`),
+	}
+}
+
+type ModbusTCPServer struct {
+	config       config.ModuleConfig
+	Addr         *net.TCPAddr
+	UnitID       uint8
+	Model        *modbus.DataModel
+	ctx          context.Context
+	inputHandler common.InputHandler
+	wg           sync.WaitGroup
+	logger       *slog.Logger
+	cancel       context.CancelFunc
+	listener     *net.TCPListener
+	listenerMu   sync.Mutex
+}
+
+func (mts *ModbusTCPServer) Id() string {
+	return mts.config.Id
+}
+
+func (mts *ModbusTCPServer) Type() string {
+	return mts.config.Type
+}
+
+func (mts *ModbusTCPServer) handleClient(client *net.TCPConn) {
+	mts.logger.Debug("connection accepted", "remoteAddr", client.RemoteAddr().String())
+	defer mts.logger.Debug("connection closed", "remoteAddr", client.RemoteAddr().String())
+
+	//NOTE(jwetzell): a read deadline could split a frame so close the connection to stop reading instead
+	stop := context.AfterFunc(mts.ctx, func() {
+		client.Close()
+	})
+	defer stop()
+	defer client.Close()
+
+	for mts.ctx.Err() == nil {
+		transactionID, unitID, request, err := modbus.ReadTCP(client)
+		if err != nil {
+			return
+		}
+
+		if mts.UnitID != 0 && unitID != mts.UnitID {
+			continue
+		}
+
+		response, block, err := mts.Model.Handle(request)
+		if err == nil && request.IsWrite() {
+			if mts.inputHandler != nil {
+				mts.inputHandler(mts.ctx, mts.Id(), block)
+			} else {
+				mts.logger.Error("input received but no input handler is configured")
+			}
+		}
+
+		_, err = client.Write(modbus.EncodeTCP(transactionID, unitID, response))
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (mts *ModbusTCPServer) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	mts.logger.Debug("running")
+	mts.inputHandler = inputHandler
+	moduleContext, cancel := context.WithCancel(ctx)
+	mts.ctx = moduleContext
+	mts.cancel = cancel
+
+	listener, err := net.ListenTCP("tcp", mts.Addr)
+	if err != nil {
+		return err
+	}
+	mts.listenerMu.Lock()
+	mts.listener = listener
+	mts.listenerMu.Unlock()
+
+	for mts.ctx.Err() == nil {
+		conn, err := listener.AcceptTCP()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				break
+			}
+			mts.logger.Debug("problem with listener", "error", err)
+			continue
+		}
+		mts.wg.Go(func() {
+			mts.handleClient(conn)
+		})
+	}
+	<-mts.ctx.Done()
+	mts.logger.Debug("done")
+	return nil
+}
+
+// Output writes a modbus.Block into any table or answers a modbus.PDU request, reads are emitted as a modbus.Block
+func (mts *ModbusTCPServer) Output(ctx context.Context, payload any) error {
+	block, ok := common.GetAnyAs[modbus.Block](payload)
+	if ok {
+		_, err := modbus.ParseTable(string(block.Table))
+		if err != nil {
+			return fmt.Errorf("modbus.tcp.server %w", err)
+		}
+		err = mts.Model.Write(block)
+		if err != nil {
+			return fmt.Errorf("modbus.tcp.server %w", err)
+		}
+		return nil
+	}
+
+	request, ok := common.GetAnyAs[modbus.PDU](payload)
+	if !ok {
+		requestPointer, ok := common.GetAnyAs[*modbus.PDU](payload)
+		if !ok || requestPointer == nil {
+			return errors.New("modbus.tcp.server can only output a modbus.PDU or modbus.Block")
+		}
+		request = *requestPointer
+	}
+
+	_, block, err := mts.Model.Handle(request)
+	if err != nil {
+		return fmt.Errorf("modbus.tcp.server %w", err)
+	}
+
+	if !request.IsWrite() {
+		if mts.inputHandler == nil {
+			return errors.New("modbus.tcp.server has no input handler for the read")
+		}
+		mts.inputHandler(mts.ctx, mts.Id(), block)
+	}
+	return nil
+}
+
+func (mts *ModbusTCPServer) Stop() {
+	if mts.cancel != nil {
+		mts.cancel()
+	}
+
+	mts.listenerMu.Lock()
+	if mts.listener != nil {
+		mts.listener.Close()
+	}
+	mts.listenerMu.Unlock()
+	mts.wg.Wait()
+}
@@ -1,12 +1,18 @@
 package module
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"strings"
 	"sync"
+	"text/template"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/google/jsonschema-go/jsonschema"
@@ -14,10 +20,22 @@ import (
 	"github.com/jwetzell/showbridge-go/internal/config"
 )
 
+func mqttQoSSchema(description string) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Title:       "QoS",
+		Description: description,
+		Type:        "integer",
+		Minimum:     jsonschema.Ptr[float64](0),
+		Maximum:     jsonschema.Ptr[float64](2),
+		Default:     json.RawMessage(`0`),
+	}
+}
+
 func init() {
 	RegisterModule(ModuleRegistration{
-		Type:  "mqtt.client",
-		Title: "MQTT Client",
+		Type:        "mqtt.client",
+		Title:       "MQTT Client",
+		Description: "Connect to an MQTT broker, messages are emitted as an MQTTMessage and connection changes update the module status",
 		ParamsSchema: &jsonschema.Schema{
 			Type: "object",
 			Properties: map[string]*jsonschema.Schema{
@@ -28,30 +46,111 @@ func init() {
 				},
 				"topic": {
 					Title:       "Topic",
-					Description: "an MQTT topic to subscribe to",
+					Description: "an MQTT topic to subscribe to, shorthand for a single subscription",
 					Type:        "string",
 				},
+				"subscriptions": {
+					Title:       "Subscriptions",
+					Description: "MQTT topic filters to subscribe to, + and # wildcards are supported",
+					Type:        "array",
+					Items: &jsonschema.Schema{
+						Type: "object",
+						Properties: map[string]*jsonschema.Schema{
+							"topic": {
+								Title: "Topic",
+								Type:  "string",
+							},
+							"qos": mqttQoSSchema("the QoS level to subscribe with"),
+						},
+						Required:             []string{"topic"},
+						AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+					},
+				},
 				"clientId": {
 					Title:       "Client ID",
 					Description: "the client ID to use when connecting to the MQTT broker",
 					Type:        "string",
 				},
-				"qos": {
-					Title:       "QoS",
-					Description: "the QoS level to use when publishing messages",
-					Type:        "integer",
-					Minimum:     jsonschema.Ptr[float64](0),
-					Maximum:     jsonschema.Ptr[float64](2),
-					Default:     json.RawMessage(`0`),
-				},
+				"qos": mqttQoSSchema("the QoS level to use when publishing messages"),
 				"retained": {
 					Title:       "Retained",
 					Description: "whether to set the retained flag when publishing messages",
 					Type:        "boolean",
 					Default:     json.RawMessage(`false`),
 				},
+				"outputTopic": {
+					Title:       "Output Topic",
+					Description: "template for the topic payloads sent with module.output are published to",
+					Type:        "string",
+				},
+				"username": {
+					Title: "Username",
+					Type:  "string",
+				},
+				"password": {
+					Title: "Password",
+					Type:  "string",
+				},
+				"cleanSession": {
+					Title:       "Clean Session",
+					Description: "start a new session on every connect instead of resuming subscriptions and queued messages",
+					Type:        "boolean",
+					Default:     json.RawMessage(`false`),
+				},
+				"tls": {
+					Title:       "TLS",
+					Description: "certificates for mqtts or ssl brokers",
+					Type:        "object",
+					Properties: map[string]*jsonschema.Schema{
+						"caFile": {
+							Title:       "CA File",
+							Description: "path to a PEM CA certificate to verify the broker with",
+							Type:        "string",
+						},
+						"certFile": {
+							Title:       "Certificate File",
+							Description: "path to a PEM client certificate",
+							Type:        "string",
+						},
+						"keyFile": {
+							Title:       "Key File",
+							Description: "path to the PEM private key for certFile",
+							Type:        "string",
+						},
+						"insecureSkipVerify": {
+							Title:       "Insecure Skip Verify",
+							Description: "don't verify the broker certificate",
+							Type:        "boolean",
+							Default:     json.RawMessage(`false`),
+						},
+					},
+					AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+				},
+				"will": {
+					Title:       "Last Will",
+					Description: "message the broker publishes when the client disconnects unexpectedly",
+					Type:        "object",
+					Properties: map[string]*jsonschema.Schema{
+						"topic": {
+							Title: "Topic",
+							Type:  "string",
+						},
+						"payload": {
+							Title: "Payload",
+							Type:  "string",
+						},
+						"qos": mqttQoSSchema("the QoS level of the last will"),
+						"retained": {
+							Title:   "Retained",
+							Type:    "boolean",
+							Default: json.RawMessage(`false`),
+						},
+					},
+					Required:             []string{"topic", "payload"},
+					AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+				},
 			},
-			Required:             []string{"broker", "topic", "clientId"},
+			Required:             []string{"broker", "clientId"},
 			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
 		},
 		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
@@ -62,10 +161,50 @@ func init() {
 				return nil, fmt.Errorf("mqtt.client broker error: %w", err)
 			}
 
+			subscriptions := []MQTTSubscription{}
+
 			topicString, err := params.GetString("topic")
 
 			if err != nil {
-				return nil, fmt.Errorf("mqtt.client topic error: %w", err)
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("mqtt.client topic error: %w", err)
+				}
+			} else {
+				subscriptions = append(subscriptions, MQTTSubscription{Topic: topicString, QoS: 1})
+			}
+
+			if subscriptionsValue, ok := params["subscriptions"]; ok {
+				subscriptionsSlice, ok := subscriptionsValue.([]any)
+				if !ok {
+					return nil, errors.New("mqtt.client subscriptions error: not an array")
+				}
+
+				for index, subscriptionValue := range subscriptionsSlice {
+					subscriptionMap, ok := subscriptionValue.(map[string]any)
+					if !ok {
+						return nil, fmt.Errorf("mqtt.client subscriptions[%d] error: %w", index, config.ErrParamNotMap)
+					}
+					subscriptionParams := config.Params(subscriptionMap)
+
+					subscriptionTopic, err := subscriptionParams.GetString("topic")
+					if err != nil {
+						return nil, fmt.Errorf("mqtt.client subscriptions[%d] topic error: %w", index, err)
+					}
+
+					subscriptionQoS, err := getMQTTQoS(subscriptionParams)
+					if err != nil {
+						return nil, fmt.Errorf("mqtt.client subscriptions[%d] %w", index, err)
+					}
+
+					subscriptions = append(subscriptions, MQTTSubscription{Topic: subscriptionTopic, QoS: subscriptionQoS})
+				}
+			}
+
+			for _, subscription := range subscriptions {
+				err := validateMQTTTopicFilter(subscription.Topic)
+				if err != nil {
+					return nil, fmt.Errorf("mqtt.client subscription %s error: %w", subscription.Topic, err)
+				}
 			}
 
 			clientIdString, err := params.GetString("clientId")
@@ -74,14 +213,10 @@ func init() {
 				return nil, fmt.Errorf("mqtt.client clientId error: %w", err)
 			}
 
-			qosString, err := params.GetInt("qos")
+			qosNum, err := getMQTTQoS(params)
 
 			if err != nil {
-				if errors.Is(err, config.ErrParamNotFound) {
-					qosString = 0
-				} else {
-					return nil, fmt.Errorf("mqtt.client qos error: %w", err)
-				}
+				return nil, fmt.Errorf("mqtt.client %w", err)
 			}
 
 			retainedBool, err := params.GetBool("retained")
@@ -94,24 +229,266 @@ func init() {
 				}
 			}
 
-			return &MQTTClient{config: moduleConfig, Broker: brokerString, Topic: topicString, ClientID: clientIdString, QoS: byte(qosString), Retained: retainedBool, logger: CreateLogger(moduleConfig)}, nil
+			var outputTopic *template.Template
+			outputTopicString, err := params.GetString("outputTopic")
+
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("mqtt.client outputTopic error: %w", err)
+				}
+			} else {
+				outputTopic, err = template.New("outputTopic").Parse(outputTopicString)
+				if err != nil {
+					return nil, fmt.Errorf("mqtt.client outputTopic error: %w", err)
+				}
+			}
+
+			usernameString, err := params.GetString("username")
+
+			if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+				return nil, fmt.Errorf("mqtt.client username error: %w", err)
+			}
+
+			passwordString, err := params.GetString("password")
+
+			if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+				return nil, fmt.Errorf("mqtt.client password error: %w", err)
+			}
+
+			cleanSessionBool, err := params.GetBool("cleanSession")
+
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					cleanSessionBool = false
+				} else {
+					return nil, fmt.Errorf("mqtt.client cleanSession error: %w", err)
+				}
+			}
+
+			mqttClient := &MQTTClient{
+				config:        moduleConfig,
+				Broker:        brokerString,
+				Subscriptions: subscriptions,
+				ClientID:      clientIdString,
+				QoS:           qosNum,
+				Retained:      retainedBool,
+				OutputTopic:   outputTopic,
+				Username:      usernameString,
+				Password:      passwordString,
+				CleanSession:  cleanSessionBool,
+				logger:        CreateLogger(moduleConfig),
+			}
+
+			tlsParams, err := params.GetParams("tls")
+
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("mqtt.client tls error: %w", err)
+				}
+			} else {
+				tlsConfig, err := newMQTTTLSConfig(tlsParams)
+				if err != nil {
+					return nil, fmt.Errorf("mqtt.client tls %w", err)
+				}
+				mqttClient.TLSConfig = tlsConfig
+			}
+
+			willParams, err := params.GetParams("will")
+
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("mqtt.client will error: %w", err)
+				}
+			} else {
+				will, err := newMQTTWill(willParams)
+				if err != nil {
+					return nil, fmt.Errorf("mqtt.client will %w", err)
+				}
+				mqttClient.Will = will
+			}
+
+			return mqttClient, nil
 		},
 	})
 }
 
-type MQTTClient struct {
-	config       config.ModuleConfig
-	ctx          context.Context
-	inputHandler common.InputHandler
-	Broker       string
-	ClientID     string
+func getMQTTQoS(params config.Params) (byte, error) {
+	qosNum, err := params.GetInt("qos")
+	if err != nil {
+		if errors.Is(err, config.ErrParamNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("qos error: %w", err)
+	}
+
+	if qosNum < 0 || qosNum > 2 {
+		return 0, errors.New("qos must be 0, 1 or 2")
+	}
+	return byte(qosNum), nil
+}
+
+// validateMQTTTopicFilter checks that wildcards take up a whole level and # is only used as the last level
+func validateMQTTTopicFilter(filter string) error {
+	if filter == "" {
+		return errors.New("topic must not be empty")
+	}
+
+	levels := strings.Split(filter, "/")
+	for index, level := range levels {
+		if level == "#" && index != len(levels)-1 {
+			return errors.New("# must be the last level")
+		}
+		if level != "#" && level != "+" && strings.ContainsAny(level, "#+") {
+			return errors.New("wildcards must take up a whole level")
+		}
+	}
+	return nil
+}
+
+// mqttTopicMatches reports whether a topic matches a topic filter
+func mqttTopicMatches(filter string, topic string) bool {
+	//NOTE(jwetzell): wildcards at the first level don't match topics like $SYS
+	if strings.HasPrefix(topic, "$") && (strings.HasPrefix(filter, "+") || strings.HasPrefix(filter, "#")) {
+		return false
+	}
+
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+	for index, filterLevel := range filterLevels {
+		if filterLevel == "#" {
+			return true
+		}
+		if index >= len(topicLevels) {
+			return false
+		}
+		if filterLevel != "+" && filterLevel != topicLevels[index] {
+			return false
+		}
+	}
+	return len(filterLevels) == len(topicLevels)
+}
+
+func newMQTTTLSConfig(params config.Params) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+
+	insecureSkipVerifyBool, err := params.GetBool("insecureSkipVerify")
+	if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+		return nil, fmt.Errorf("insecureSkipVerify error: %w", err)
+	}
+	tlsConfig.InsecureSkipVerify = insecureSkipVerifyBool
+
+	caFileString, err := params.GetString("caFile")
+	if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+		return nil, fmt.Errorf("caFile error: %w", err)
+	}
+
+	if caFileString != "" {
+		caBytes, err := os.ReadFile(caFileString)
+		if err != nil {
+			return nil, fmt.Errorf("caFile error: %w", err)
+		}
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(caBytes) {
+			return nil, fmt.Errorf("caFile error: no certificates found in %s", caFileString)
+		}
+		tlsConfig.RootCAs = certPool
+	}
+
+	certFileString, err := params.GetString("certFile")
+	if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+		return nil, fmt.Errorf("certFile error: %w", err)
+	}
+
+	keyFileString, err := params.GetString("keyFile")
+	if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+		return nil, fmt.Errorf("keyFile error: %w", err)
+	}
+
+	if (certFileString == "") != (keyFileString == "") {
+		return nil, errors.New("certFile and keyFile must be set together")
+	}
+
+	if certFileString != "" {
+		certificate, err := tls.LoadX509KeyPair(certFileString, keyFileString)
+		if err != nil {
+			return nil, fmt.Errorf("certFile error: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+type mqttWill struct {
+	Topic    string
+	Payload  string
+	QoS      byte
+	Retained bool
+}
+
+func newMQTTWill(params config.Params) (*mqttWill, error) {
+	topicString, err := params.GetString("topic")
+	if err != nil {
+		return nil, fmt.Errorf("topic error: %w", err)
+	}
+
+	if strings.ContainsAny(topicString, "#+") {
+		return nil, errors.New("topic must not contain wildcards")
+	}
+
+	payloadString, err := params.GetString("payload")
+	if err != nil {
+		return nil, fmt.Errorf("payload error: %w", err)
+	}
+
+	qosNum, err := getMQTTQoS(params)
+	if err != nil {
+		return nil, err
+	}
+
+	retainedBool, err := params.GetBool("retained")
+	if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+		return nil, fmt.Errorf("retained error: %w", err)
+	}
+
+	return &mqttWill{Topic: topicString, Payload: payloadString, QoS: qosNum, Retained: retainedBool}, nil
+}
+
+type MQTTSubscription struct {
+	Topic string
+	QoS   byte
+}
+
+// MQTTMessage is a message received on one of the subscriptions, Subscription is the topic filter it matched
+type MQTTMessage struct {
 	Topic        string
+	Subscription string
+	Payload      []byte
 	QoS          byte
 	Retained     bool
-	client       mqtt.Client
-	logger       *slog.Logger
-	cancel       context.CancelFunc
-	clientMu     sync.Mutex
+	Duplicate    bool
+	MessageID    uint16
+}
+
+type MQTTClient struct {
+	config        config.ModuleConfig
+	ctx           context.Context
+	inputHandler  common.InputHandler
+	Broker        string
+	ClientID      string
+	Subscriptions []MQTTSubscription
+	QoS           byte
+	Retained      bool
+	OutputTopic   *template.Template
+	Username      string
+	Password      string
+	CleanSession  bool
+	TLSConfig     *tls.Config
+	Will          *mqttWill
+	client        mqtt.Client
+	logger        *slog.Logger
+	cancel        context.CancelFunc
+	clientMu      sync.Mutex
 }
 
 func (mc *MQTTClient) Id() string {
@@ -122,6 +499,34 @@ func (mc *MQTTClient) Type() string {
 	return mc.config.Type
 }
 
+func (mc *MQTTClient) emit(payload any) {
+	if mc.inputHandler == nil {
+		mc.logger.Error("input received but no input handler is configured")
+		return
+	}
+	mc.inputHandler(mc.ctx, mc.Id(), payload)
+}
+
+func (mc *MQTTClient) handleMessage(c mqtt.Client, m mqtt.Message) {
+	message := MQTTMessage{
+		Topic:     m.Topic(),
+		Payload:   m.Payload(),
+		QoS:       m.Qos(),
+		Retained:  m.Retained(),
+		Duplicate: m.Duplicate(),
+		MessageID: m.MessageID(),
+	}
+
+	for _, subscription := range mc.Subscriptions {
+		if mqttTopicMatches(subscription.Topic, message.Topic) {
+			message.Subscription = subscription.Topic
+			break
+		}
+	}
+
+	mc.emit(message)
+}
+
 func (mc *MQTTClient) Start(ctx context.Context, inputHandler common.InputHandler) error {
 	mc.logger.Debug("running")
 	mc.inputHandler = inputHandler
@@ -133,15 +538,42 @@ func (mc *MQTTClient) Start(ctx context.Context, inputHandler common.InputHandle
 	opts.AddBroker(mc.Broker)
 	opts.SetClientID(mc.ClientID)
 	opts.SetAutoReconnect(true)
-	opts.SetCleanSession(false)
+	opts.SetCleanSession(mc.CleanSession)
+
+	if mc.Username != "" {
+		opts.SetUsername(mc.Username)
+		opts.SetPassword(mc.Password)
+	}
+
+	if mc.TLSConfig != nil {
+		opts.SetTLSConfig(mc.TLSConfig)
+	}
+
+	if mc.Will != nil {
+		opts.SetWill(mc.Will.Topic, mc.Will.Payload, mc.Will.QoS, mc.Will.Retained)
+	}
 
 	opts.OnConnect = func(c mqtt.Client) {
-		token := mc.client.Subscribe(mc.Topic, 1, func(c mqtt.Client, m mqtt.Message) {
-			if mc.inputHandler != nil {
-				mc.inputHandler(mc.ctx, mc.Id(), m)
+		mc.logger.Debug("connected")
+		if len(mc.Subscriptions) > 0 {
+			filters := make(map[string]byte, len(mc.Subscriptions))
+			for _, subscription := range mc.Subscriptions {
+				filters[subscription.Topic] = subscription.QoS
 			}
-		})
-		token.Wait()
+			token := c.SubscribeMultiple(filters, mc.handleMessage)
+			token.Wait()
+			err := token.Error()
+			if err != nil {
+				mc.logger.Error("subscribe error", "error", err)
+			}
+		}
+		//NOTE(jwetzell): connection changes go to the module status so routes only ever see messages
+		common.ReportModuleStatus(mc.ctx, "connected", nil)
+	}
+
+	opts.OnConnectionLost = func(c mqtt.Client, err error) {
+		mc.logger.Warn("connection lost", "error", err)
+		common.ReportModuleStatus(mc.ctx, "disconnected", err)
 	}
 
 	mc.clientMu.Lock()
@@ -172,21 +604,54 @@ func (mc *MQTTClient) Publish(ctx context.Context, topic string, payload any) er
 		payloadBytes = []byte(payloadString)
 	}
 
-	if mc.client == nil {
+	mc.clientMu.Lock()
+	client := mc.client
+	mc.clientMu.Unlock()
+
+	if client == nil {
 		return errors.New("mqtt.client client is not setup")
 	}
 
-	if !mc.client.IsConnected() {
+	if !client.IsConnected() {
 		return errors.New("mqtt.client is not connected")
 	}
 
-	token := mc.client.Publish(topic, mc.QoS, mc.Retained, payloadBytes)
+	token := client.Publish(topic, mc.QoS, mc.Retained, payloadBytes)
 
-	token.Wait()
+	select {
+	case <-token.Done():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 
 	return token.Error()
 }
 
+func (mc *MQTTClient) Output(ctx context.Context, payload any) error {
+	//NOTE(jwetzell): messages carry their own topic so they can be forwarded between brokers
+	message, ok := common.GetAnyAs[MQTTMessage](payload)
+	if ok && message.Topic != "" {
+		return mc.Publish(ctx, message.Topic, message.Payload)
+	}
+
+	if mc.OutputTopic == nil {
+		return errors.New("mqtt.client output requires an outputTopic or an MQTTMessage with a Topic")
+	}
+
+	var topicBuffer bytes.Buffer
+	err := mc.OutputTopic.Execute(&topicBuffer, common.WrappedPayload{Payload: payload})
+	if err != nil {
+		return fmt.Errorf("mqtt.client outputTopic error: %w", err)
+	}
+
+	topic := topicBuffer.String()
+	if topic == "" || strings.ContainsAny(topic, "#+") {
+		return fmt.Errorf("mqtt.client can't publish to topic %q", topic)
+	}
+
+	return mc.Publish(ctx, topic, payload)
+}
+
 func (mc *MQTTClient) Stop() {
 	if mc.cancel != nil {
 		mc.cancel()
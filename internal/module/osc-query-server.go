@@ -0,0 +1,613 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/gorilla/websocket"
+	"github.com/jwetzell/osc-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/oscquery"
+)
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "osc.query.server",
+		Title:       "OSCQuery Server",
+		Description: "Publish OSC addresses over OSCQuery and receive OSC for them over UDP or websocket",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"ip": {
+					Title:       "IP",
+					Description: "the IP address to bind the HTTP and OSC servers to",
+					Type:        "string",
+					Default:     json.RawMessage(`"0.0.0.0"`),
+				},
+				"port": {
+					Title:       "Port",
+					Description: "the port for the OSCQuery HTTP and websocket server",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1024),
+					Maximum:     jsonschema.Ptr[float64](65535),
+				},
+				"oscPort": {
+					Title:       "OSC Port",
+					Description: "the UDP port to receive OSC on, defaults to port",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1024),
+					Maximum:     jsonschema.Ptr[float64](65535),
+				},
+				"name": {
+					Title:       "Name",
+					Description: "name advertised to OSCQuery clients, defaults to the module id",
+					Type:        "string",
+				},
+				"addresses": {
+					Title:       "Addresses",
+					Description: "OSC addresses to publish",
+					Type:        "array",
+					Items: &jsonschema.Schema{
+						Type: "object",
+						Properties: map[string]*jsonschema.Schema{
+							"address": {
+								Title:       "Address",
+								Description: "OSC address",
+								Type:        "string",
+								MinLength:   new(2),
+							},
+							"types": {
+								Title:       "Types",
+								Description: "OSC type tags of the arguments at this address",
+								Type:        "string",
+							},
+							"description": {
+								Title: "Description",
+								Type:  "string",
+							},
+							"access": {
+								Title:       "Access",
+								Description: "whether clients can read, write or do both",
+								Type:        "string",
+								Enum:        []any{"r", "w", "rw"},
+								Default:     json.RawMessage(`"rw"`),
+							},
+							"value": {
+								Title:       "Value",
+								Description: "starting value with one entry per argument",
+								Type:        "array",
+							},
+							"range": {
+								Title:       "Range",
+								Description: "range of each argument",
+								Type:        "array",
+								Items: &jsonschema.Schema{
+									Type: "object",
+									Properties: map[string]*jsonschema.Schema{
+										"min":  {Type: "number"},
+										"max":  {Type: "number"},
+										"vals": {Type: "array"},
+									},
+									AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+								},
+							},
+						},
+						Required:             []string{"address", "types"},
+						AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+					},
+				},
+			},
+			Required:             []string{"port", "addresses"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			portNum, err := params.GetInt("port")
+			if err != nil {
+				return nil, fmt.Errorf("osc.query.server port error: %w", err)
+			}
+
+			oscPortNum, err := params.GetInt("oscPort")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					oscPortNum = portNum
+				} else {
+					return nil, fmt.Errorf("osc.query.server oscPort error: %w", err)
+				}
+			}
+
+			ipString, err := params.GetString("ip")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					ipString = "0.0.0.0"
+				} else {
+					return nil, fmt.Errorf("osc.query.server ip error: %w", err)
+				}
+			}
+
+			oscAddr, err := net.ResolveUDPAddr("udp", fmt.Sprintf("%s:%d", ipString, uint16(oscPortNum)))
+			if err != nil {
+				return nil, err
+			}
+
+			nameString, err := params.GetString("name")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					nameString = moduleConfig.Id
+				} else {
+					return nil, fmt.Errorf("osc.query.server name error: %w", err)
+				}
+			}
+
+			addressesValue, ok := params["addresses"]
+			if !ok {
+				return nil, fmt.Errorf("osc.query.server addresses error: %w", config.ErrParamNotFound)
+			}
+
+			addressesSlice, ok := addressesValue.([]any)
+			if !ok {
+				return nil, fmt.Errorf("osc.query.server addresses error: %w", config.ErrParamNotSlice)
+			}
+
+			namespace := oscquery.NewNamespace()
+			for addressIndex, addressValue := range addressesSlice {
+				addressParams, ok := addressValue.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("osc.query.server addresses[%d] error: %w", addressIndex, config.ErrParamNotMap)
+				}
+				node, err := oscQueryNodeFromParams(addressParams)
+				if err != nil {
+					return nil, fmt.Errorf("osc.query.server addresses[%d] %w", addressIndex, err)
+				}
+				err = namespace.Add(node)
+				if err != nil {
+					return nil, fmt.Errorf("osc.query.server addresses[%d] error: %w", addressIndex, err)
+				}
+			}
+
+			return &OSCQueryServer{
+				config:    moduleConfig,
+				Addr:      fmt.Sprintf("%s:%d", ipString, uint16(portNum)),
+				OSCAddr:   oscAddr,
+				HostInfo:  oscquery.NewHostInfo(nameString, oscPortNum),
+				Namespace: namespace,
+				listeners: map[*oscQueryListener]bool{},
+				logger:    CreateLogger(moduleConfig),
+			}, nil
+		},
+	})
+}
+
+func oscQueryNodeFromParams(params config.Params) (oscquery.Node, error) {
+	addressString, err := params.GetString("address")
+	if err != nil {
+		return oscquery.Node{}, fmt.Errorf("address error: %w", err)
+	}
+
+	typesString, err := params.GetString("types")
+	if err != nil {
+		return oscquery.Node{}, fmt.Errorf("types error: %w", err)
+	}
+
+	node := oscquery.Node{FullPath: addressString, Type: typesString}
+
+	descriptionString, err := params.GetString("description")
+	if err != nil {
+		if !errors.Is(err, config.ErrParamNotFound) {
+			return oscquery.Node{}, fmt.Errorf("description error: %w", err)
+		}
+	} else {
+		node.Description = descriptionString
+	}
+
+	accessString, err := params.GetString("access")
+	if err != nil {
+		if errors.Is(err, config.ErrParamNotFound) {
+			accessString = "rw"
+		} else {
+			return oscquery.Node{}, fmt.Errorf("access error: %w", err)
+		}
+	}
+
+	node.Access, err = oscquery.AccessFromString(accessString)
+	if err != nil {
+		return oscquery.Node{}, fmt.Errorf("access error: %w", err)
+	}
+
+	valueValue, ok := params["value"]
+	if ok {
+		valueSlice, ok := valueValue.([]any)
+		if !ok {
+			return oscquery.Node{}, fmt.Errorf("value error: %w", config.ErrParamNotSlice)
+		}
+		if len(valueSlice) != len(typesString) {
+			return oscquery.Node{}, errors.New("value must have one entry per type")
+		}
+		node.Value = valueSlice
+	}
+
+	rangeValue, ok := params["range"]
+	if ok {
+		rangeSlice, ok := rangeValue.([]any)
+		if !ok {
+			return oscquery.Node{}, fmt.Errorf("range error: %w", config.ErrParamNotSlice)
+		}
+		for rangeIndex, rangeEntry := range rangeSlice {
+			rangeParams, ok := rangeEntry.(map[string]any)
+			if !ok {
+				return oscquery.Node{}, fmt.Errorf("range[%d] error: %w", rangeIndex, config.ErrParamNotMap)
+			}
+			nodeRange, err := oscQueryRangeFromParams(rangeParams)
+			if err != nil {
+				return oscquery.Node{}, fmt.Errorf("range[%d] %w", rangeIndex, err)
+			}
+			node.Range = append(node.Range, nodeRange)
+		}
+	}
+
+	return node, nil
+}
+
+func oscQueryRangeFromParams(params config.Params) (oscquery.Range, error) {
+	nodeRange := oscquery.Range{}
+
+	minFloat, err := params.GetFloat64("min")
+	if err != nil {
+		if !errors.Is(err, config.ErrParamNotFound) {
+			return oscquery.Range{}, fmt.Errorf("min error: %w", err)
+		}
+	} else {
+		nodeRange.Min = &minFloat
+	}
+
+	maxFloat, err := params.GetFloat64("max")
+	if err != nil {
+		if !errors.Is(err, config.ErrParamNotFound) {
+			return oscquery.Range{}, fmt.Errorf("max error: %w", err)
+		}
+	} else {
+		nodeRange.Max = &maxFloat
+	}
+
+	valsValue, ok := params["vals"]
+	if ok {
+		valsSlice, ok := valsValue.([]any)
+		if !ok {
+			return oscquery.Range{}, fmt.Errorf("vals error: %w", config.ErrParamNotSlice)
+		}
+		nodeRange.Vals = valsSlice
+	}
+
+	return nodeRange, nil
+}
+
+type oscQueryListener struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+	paths   map[string]bool
+}
+
+type OSCQueryServer struct {
+	config       config.ModuleConfig
+	Addr         string
+	OSCAddr      *net.UDPAddr
+	HostInfo     oscquery.HostInfo
+	Namespace    *oscquery.Namespace
+	ctx          context.Context
+	inputHandler common.InputHandler
+	logger       *slog.Logger
+	cancel       context.CancelFunc
+	server       *http.Server
+	oscListener  *net.UDPConn
+	listeners    map[*oscQueryListener]bool
+	listenersMu  sync.Mutex
+	serverMu     sync.Mutex
+}
+
+var oscQueryUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+func (oqs *OSCQueryServer) Id() string {
+	return oqs.config.Id
+}
+
+func (oqs *OSCQueryServer) Type() string {
+	return oqs.config.Type
+}
+
+func (oqs *OSCQueryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		oqs.handleWebsocket(w, r)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.RawQuery == "HOST_INFO" {
+		json.NewEncoder(w).Encode(oqs.HostInfo)
+		return
+	}
+
+	path := r.URL.Path
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+
+	var responseBytes []byte
+	var found bool
+	var err error
+	if r.URL.RawQuery != "" {
+		responseBytes, found, err = oqs.Namespace.MarshalAttribute(path, r.URL.RawQuery)
+	} else {
+		responseBytes, found, err = oqs.Namespace.MarshalNode(path)
+	}
+
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	//NOTE(jwetzell): OSCQuery asks for 204 when the node exists but does not have the attribute
+	if responseBytes == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Write(responseBytes)
+}
+
+func (oqs *OSCQueryServer) handleWebsocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := oscQueryUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		oqs.logger.Error("websocket upgrade error", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	listener := &oscQueryListener{conn: conn, paths: map[string]bool{}}
+	oqs.listenersMu.Lock()
+	oqs.listeners[listener] = true
+	oqs.listenersMu.Unlock()
+
+	defer func() {
+		oqs.listenersMu.Lock()
+		delete(oqs.listeners, listener)
+		oqs.listenersMu.Unlock()
+	}()
+
+	for {
+		messageType, message, err := conn.ReadMessage()
+		if err != nil {
+			//NOTE(jwetzell): gorilla connections are unusable after any read error
+			return
+		}
+
+		switch messageType {
+		case websocket.TextMessage:
+			command, err := oscquery.ParseCommand(message)
+			if err != nil {
+				oqs.logger.Warn("bad OSCQuery command", "error", err)
+				continue
+			}
+			path, ok := command.Data.(string)
+			if !ok {
+				oqs.logger.Warn("OSCQuery command DATA must be a path", "command", command.Command)
+				continue
+			}
+			oqs.listenersMu.Lock()
+			switch command.Command {
+			case oscquery.CommandListen:
+				listener.paths[path] = true
+			case oscquery.CommandIgnore:
+				delete(listener.paths, path)
+			default:
+				oqs.logger.Warn("unsupported OSCQuery command", "command", command.Command)
+			}
+			oqs.listenersMu.Unlock()
+		case websocket.BinaryMessage:
+			oqs.handleOSC(message, listener)
+		}
+	}
+}
+
+// handleOSC updates the namespace from an incoming OSC message and passes it on to routes, from is the websocket it came in on if any
+func (oqs *OSCQueryServer) handleOSC(data []byte, from *oscQueryListener) {
+	message, err := osc.MessageFromBytes(data)
+	if err != nil {
+		oqs.logger.Warn("bad OSC message", "error", err)
+		return
+	}
+
+	method, ok := oqs.Namespace.Method(message.Address)
+	if !ok {
+		oqs.logger.Debug("OSC message for unpublished address", "address", message.Address)
+		return
+	}
+
+	if method.Access&oscquery.AccessWrite == 0 {
+		oqs.logger.Warn("OSC message for read only address", "address", message.Address)
+		return
+	}
+
+	value, err := oscQueryValueFromMessage(method, message)
+	if err != nil {
+		oqs.logger.Warn("OSC message does not match published types", "address", message.Address, "error", err)
+		return
+	}
+
+	oqs.Namespace.SetValue(message.Address, value)
+	oqs.notifyListeners(message, from)
+
+	if oqs.inputHandler != nil {
+		oqs.inputHandler(oqs.ctx, oqs.Id(), message)
+	} else {
+		oqs.logger.Error("input received but no input handler is configured")
+	}
+}
+
+func oscQueryValueFromMessage(method oscquery.Node, message *osc.OSCMessage) ([]any, error) {
+	var typesBuilder strings.Builder
+	value := []any{}
+	for _, arg := range message.Args {
+		typesBuilder.WriteString(arg.Type)
+		value = append(value, arg.Value)
+	}
+
+	if typesBuilder.String() != method.Type {
+		return nil, fmt.Errorf("expected types %s, got %s", method.Type, typesBuilder.String())
+	}
+	return value, nil
+}
+
+func (oqs *OSCQueryServer) notifyListeners(message *osc.OSCMessage, from *oscQueryListener) {
+	messageBytes, err := message.ToBytes()
+	if err != nil {
+		oqs.logger.Error("failed to encode OSC message for listeners", "error", err)
+		return
+	}
+
+	oqs.listenersMu.Lock()
+	listeners := []*oscQueryListener{}
+	for listener := range oqs.listeners {
+		if listener != from && listener.paths[message.Address] {
+			listeners = append(listeners, listener)
+		}
+	}
+	oqs.listenersMu.Unlock()
+
+	for _, listener := range listeners {
+		listener.writeMu.Lock()
+		err := listener.conn.WriteMessage(websocket.BinaryMessage, messageBytes)
+		listener.writeMu.Unlock()
+		if err != nil {
+			oqs.logger.Warn("failed to send OSC to listener", "error", err)
+		}
+	}
+}
+
+func (oqs *OSCQueryServer) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	oqs.logger.Debug("running")
+	oqs.inputHandler = inputHandler
+	moduleContext, cancel := context.WithCancel(ctx)
+	oqs.ctx = moduleContext
+	oqs.cancel = cancel
+
+	oscListener, err := net.ListenUDP("udp", oqs.OSCAddr)
+	if err != nil {
+		return err
+	}
+
+	httpListener, err := net.Listen("tcp", oqs.Addr)
+	if err != nil {
+		oscListener.Close()
+		return err
+	}
+
+	httpServer := &http.Server{
+		ReadHeaderTimeout: 5 * time.Second,
+		Handler:           oqs,
+	}
+
+	oqs.serverMu.Lock()
+	oqs.server = httpServer
+	oqs.oscListener = oscListener
+	oqs.serverMu.Unlock()
+
+	go func() {
+		err := httpServer.Serve(httpListener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			oqs.logger.Error("http server error", "error", err)
+		}
+	}()
+
+	buffer := make([]byte, 65535)
+	for oqs.ctx.Err() == nil {
+		oscListener.SetDeadline(time.Now().Add(time.Millisecond * 200))
+
+		numBytes, _, err := oscListener.ReadFromUDP(buffer)
+		if err != nil {
+			//NOTE(jwetzell) we hit deadline
+			opErr, ok := err.(*net.OpError)
+			if ok && opErr.Timeout() {
+				continue
+			}
+			break
+		}
+		oqs.handleOSC(buffer[:numBytes], nil)
+	}
+	<-oqs.ctx.Done()
+	oqs.logger.Debug("done")
+	return nil
+}
+
+// Output sets the value of a published address and pushes it to websocket clients listening to it
+func (oqs *OSCQueryServer) Output(ctx context.Context, payload any) error {
+	payloadMessage, ok := common.GetAnyAs[*osc.OSCMessage](payload)
+
+	if !ok {
+		return errors.New("osc.query.server is only able to output an OSCMessage")
+	}
+
+	method, ok := oqs.Namespace.Method(payloadMessage.Address)
+	if !ok {
+		return fmt.Errorf("osc.query.server address %s is not published", payloadMessage.Address)
+	}
+
+	value, err := oscQueryValueFromMessage(method, payloadMessage)
+	if err != nil {
+		return fmt.Errorf("osc.query.server address %s %w", payloadMessage.Address, err)
+	}
+
+	err = oqs.Namespace.SetValue(payloadMessage.Address, value)
+	if err != nil {
+		return err
+	}
+
+	oqs.notifyListeners(payloadMessage, nil)
+	return nil
+}
+
+func (oqs *OSCQueryServer) Stop() {
+	if oqs.cancel != nil {
+		defer oqs.cancel()
+	}
+	oqs.serverMu.Lock()
+	defer oqs.serverMu.Unlock()
+	if oqs.oscListener != nil {
+		oqs.oscListener.Close()
+	}
+	if oqs.server != nil {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		oqs.server.Shutdown(shutdownCtx)
+		shutdownCancel()
+	}
+	oqs.listenersMu.Lock()
+	for listener := range oqs.listeners {
+		listener.conn.Close()
+	}
+	oqs.listenersMu.Unlock()
+}
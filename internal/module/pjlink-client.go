@@ -0,0 +1,467 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/pjlink"
+)
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "pjlink.client",
+		Title:       "PJLink Client",
+		Description: "Control and poll PJLink projectors, emits a pjlink.Status when a projector changes and a pjlink.Reply for every command sent",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"projectors": {
+					Title:       "Projectors",
+					Description: "projectors managed by the module",
+					Type:        "array",
+					MinItems:    jsonschema.Ptr(1),
+					Items: &jsonschema.Schema{
+						Type: "object",
+						Properties: map[string]*jsonschema.Schema{
+							"name": {
+								Title:       "Name",
+								Description: "name used to address the projector in commands",
+								Type:        "string",
+							},
+							"host": {
+								Title:       "Host",
+								Description: "the hostname or IP address of the projector",
+								Type:        "string",
+							},
+							"port": {
+								Title:   "Port",
+								Type:    "integer",
+								Minimum: jsonschema.Ptr[float64](1),
+								Maximum: jsonschema.Ptr[float64](65535),
+								Default: json.RawMessage(`4352`),
+							},
+							"password": {
+								Title:       "Password",
+								Description: "PJLink password, needed when the projector has authentication on",
+								Type:        "string",
+							},
+							"class": {
+								Title:       "Class",
+								Description: "PJLink class the projector supports",
+								Type:        "integer",
+								Enum:        []any{1, 2},
+								Default:     json.RawMessage(`1`),
+							},
+						},
+						Required:             []string{"name", "host"},
+						AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+					},
+				},
+				"interval": {
+					Title:       "Interval",
+					Description: "milliseconds between status polls, 0 turns polling off",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](0),
+					Default:     json.RawMessage(`5000`),
+				},
+				"timeout": {
+					Title:       "Timeout",
+					Description: "milliseconds to wait for a projector to respond",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1),
+					Default:     json.RawMessage(`2000`),
+				},
+			},
+			Required:             []string{"projectors"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			intervalNum, err := params.GetInt("interval")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					intervalNum = 5000
+				} else {
+					return nil, fmt.Errorf("pjlink.client interval error: %w", err)
+				}
+			}
+
+			if intervalNum < 0 {
+				return nil, errors.New("pjlink.client interval must not be negative")
+			}
+
+			timeoutNum, err := params.GetInt("timeout")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					timeoutNum = 2000
+				} else {
+					return nil, fmt.Errorf("pjlink.client timeout error: %w", err)
+				}
+			}
+
+			if timeoutNum < 1 {
+				return nil, errors.New("pjlink.client timeout must be at least 1")
+			}
+			timeout := time.Duration(timeoutNum) * time.Millisecond
+
+			projectorsValue, ok := params["projectors"]
+			if !ok {
+				return nil, fmt.Errorf("pjlink.client projectors error: %w", config.ErrParamNotFound)
+			}
+
+			projectorsSlice, ok := projectorsValue.([]any)
+			if !ok {
+				return nil, fmt.Errorf("pjlink.client projectors error: %w", config.ErrParamNotSlice)
+			}
+
+			if len(projectorsSlice) == 0 {
+				return nil, errors.New("pjlink.client projectors must not be empty")
+			}
+
+			projectors := []*pjlinkProjector{}
+			for projectorIndex, projectorValue := range projectorsSlice {
+				projectorMap, ok := projectorValue.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("pjlink.client projectors[%d] error: %w", projectorIndex, config.ErrParamNotMap)
+				}
+				projectorParams := config.Params(projectorMap)
+
+				nameString, err := projectorParams.GetString("name")
+				if err != nil {
+					return nil, fmt.Errorf("pjlink.client projectors[%d] name error: %w", projectorIndex, err)
+				}
+
+				if nameString == "" || nameString == "all" || strings.ContainsAny(nameString, " \t") {
+					return nil, fmt.Errorf("pjlink.client projectors[%d] name must be a single word other than all", projectorIndex)
+				}
+
+				for _, existing := range projectors {
+					if existing.Name == nameString {
+						return nil, fmt.Errorf("pjlink.client projectors[%d] name %s is already used", projectorIndex, nameString)
+					}
+				}
+
+				hostString, err := projectorParams.GetString("host")
+				if err != nil {
+					return nil, fmt.Errorf("pjlink.client projectors[%d] host error: %w", projectorIndex, err)
+				}
+
+				portNum, err := projectorParams.GetInt("port")
+				if err != nil {
+					if errors.Is(err, config.ErrParamNotFound) {
+						portNum = pjlink.DefaultPort
+					} else {
+						return nil, fmt.Errorf("pjlink.client projectors[%d] port error: %w", projectorIndex, err)
+					}
+				}
+
+				if portNum < 1 || portNum > 65535 {
+					return nil, fmt.Errorf("pjlink.client projectors[%d] port must be between 1 and 65535", projectorIndex)
+				}
+
+				passwordString, err := projectorParams.GetString("password")
+				if err != nil {
+					if errors.Is(err, config.ErrParamNotFound) {
+						passwordString = ""
+					} else {
+						return nil, fmt.Errorf("pjlink.client projectors[%d] password error: %w", projectorIndex, err)
+					}
+				}
+
+				classNum, err := projectorParams.GetInt("class")
+				if err != nil {
+					if errors.Is(err, config.ErrParamNotFound) {
+						classNum = 1
+					} else {
+						return nil, fmt.Errorf("pjlink.client projectors[%d] class error: %w", projectorIndex, err)
+					}
+				}
+
+				if classNum != 1 && classNum != 2 {
+					return nil, fmt.Errorf("pjlink.client projectors[%d] class must be 1 or 2", projectorIndex)
+				}
+
+				projectors = append(projectors, &pjlinkProjector{
+					Name:   nameString,
+					Class:  uint8(classNum),
+					client: pjlink.NewClient(net.JoinHostPort(hostString, strconv.Itoa(portNum)), passwordString, timeout),
+					wake:   make(chan struct{}, 1),
+				})
+			}
+
+			return &PJLinkClient{
+				config:     moduleConfig,
+				Projectors: projectors,
+				Interval:   time.Duration(intervalNum) * time.Millisecond,
+				Timeout:    timeout,
+				logger:     CreateLogger(moduleConfig),
+			}, nil
+		},
+	})
+}
+
+type pjlinkProjector struct {
+	Name   string
+	Class  uint8
+	client *pjlink.Client
+	wake   chan struct{}
+	last   *pjlink.Status
+}
+
+type PJLinkClient struct {
+	config       config.ModuleConfig
+	Projectors   []*pjlinkProjector
+	Interval     time.Duration
+	Timeout      time.Duration
+	ctx          context.Context
+	inputHandler common.InputHandler
+	logger       *slog.Logger
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+func (pc *PJLinkClient) Id() string {
+	return pc.config.Id
+}
+
+func (pc *PJLinkClient) Type() string {
+	return pc.config.Type
+}
+
+func (pc *PJLinkClient) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	pc.logger.Debug("running")
+	pc.inputHandler = inputHandler
+	moduleContext, cancel := context.WithCancel(ctx)
+	pc.ctx = moduleContext
+	pc.cancel = cancel
+
+	if pc.Interval > 0 {
+		for _, projector := range pc.Projectors {
+			pc.wg.Go(func() {
+				pc.pollLoop(projector)
+			})
+		}
+	}
+
+	<-pc.ctx.Done()
+	pc.wg.Wait()
+	pc.logger.Debug("done")
+	return nil
+}
+
+func (pc *PJLinkClient) emit(payload any) {
+	if pc.inputHandler == nil {
+		pc.logger.Error("input received but no input handler is configured")
+		return
+	}
+	pc.inputHandler(pc.ctx, pc.Id(), payload)
+}
+
+func (pc *PJLinkClient) pollLoop(projector *pjlinkProjector) {
+	ticker := time.NewTicker(pc.Interval)
+	defer ticker.Stop()
+
+	for {
+		status := pc.poll(projector)
+		if projector.last == nil || !reflect.DeepEqual(*projector.last, status) {
+			projector.last = &status
+			pc.emit(status)
+		}
+
+		select {
+		case <-pc.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-projector.wake:
+		}
+	}
+}
+
+// poll queries power, input, lamps and errors, queries the projector can't answer right now are left empty
+func (pc *PJLinkClient) poll(projector *pjlinkProjector) pjlink.Status {
+	status := pjlink.Status{Projector: projector.Name}
+
+	power, err := projector.client.Send(pc.ctx, pjlink.Query(1, pjlink.CommandPower))
+	if err != nil {
+		pc.logger.Debug("poll failed", "projector", projector.Name, "error", err)
+		return status
+	}
+	status.Connected = true
+	if power.Error != "" {
+		pc.logger.Warn("poll failed", "projector", projector.Name, "error", power.Err())
+		return status
+	}
+	status.Power, _ = pjlink.ParsePower(power.Value)
+
+	input, err := projector.client.Send(pc.ctx, pjlink.Query(projector.Class, pjlink.CommandInput))
+	if err == nil && input.Error == "" {
+		status.Input = input.Value
+	}
+
+	lamp, err := projector.client.Send(pc.ctx, pjlink.Query(1, pjlink.CommandLamp))
+	if err == nil && lamp.Error == "" {
+		status.Lamps, _ = pjlink.ParseLamps(lamp.Value)
+	}
+
+	errorStatus, err := projector.client.Send(pc.ctx, pjlink.Query(1, pjlink.CommandErrorStatus))
+	if err == nil && errorStatus.Error == "" {
+		parsed, err := pjlink.ParseErrorStatus(errorStatus.Value)
+		if err == nil {
+			status.Errors = &parsed
+		}
+	}
+	return status
+}
+
+// pjlinkCommand turns command words like power on or input 31 into a PJLink command for a projector
+func pjlinkCommand(class uint8, words []string) (pjlink.Command, error) {
+	if len(words) == 0 {
+		return pjlink.Command{}, errors.New("command is missing")
+	}
+
+	onOff := func(on string, off string) (string, error) {
+		if len(words) != 2 {
+			return "", fmt.Errorf("%s needs on or off", words[0])
+		}
+		switch strings.ToLower(words[1]) {
+		case "on":
+			return on, nil
+		case "off":
+			return off, nil
+		}
+		return "", fmt.Errorf("%s needs on or off", words[0])
+	}
+
+	//NOTE(jwetzell): raw commands like %1POWR ? pass straight through
+	if strings.HasPrefix(words[0], "%") {
+		if len(words[0]) != 6 {
+			return pjlink.Command{}, fmt.Errorf("raw command %s must be a class and a 4 character command", words[0])
+		}
+		rawClass, err := strconv.ParseUint(words[0][1:2], 10, 8)
+		if err != nil {
+			return pjlink.Command{}, fmt.Errorf("raw command %s has a bad class", words[0])
+		}
+		command := pjlink.Command{Class: uint8(rawClass), Name: strings.ToUpper(words[0][2:]), Parameter: strings.Join(words[1:], " ")}
+		return command, command.Validate()
+	}
+
+	switch strings.ToLower(words[0]) {
+	case "power":
+		parameter, err := onOff("1", "0")
+		return pjlink.Command{Class: 1, Name: pjlink.CommandPower, Parameter: parameter}, err
+	case "input":
+		if len(words) != 2 {
+			return pjlink.Command{}, errors.New("input needs an input code")
+		}
+		return pjlink.Command{Class: class, Name: pjlink.CommandInput, Parameter: strings.ToUpper(words[1])}, nil
+	case "mute":
+		target := "3"
+		if len(words) == 3 {
+			switch strings.ToLower(words[1]) {
+			case "video":
+				target = "1"
+			case "audio":
+				target = "2"
+			default:
+				return pjlink.Command{}, errors.New("mute target must be video or audio")
+			}
+			words = []string{words[0], words[2]}
+		}
+		parameter, err := onOff(target+"1", target+"0")
+		return pjlink.Command{Class: 1, Name: pjlink.CommandAVMute, Parameter: parameter}, err
+	case "freeze":
+		if class != 2 {
+			return pjlink.Command{}, errors.New("freeze needs a class 2 projector")
+		}
+		parameter, err := onOff("1", "0")
+		return pjlink.Command{Class: 2, Name: pjlink.CommandFreeze, Parameter: parameter}, err
+	}
+	return pjlink.Command{}, fmt.Errorf("unknown command: %s", words[0])
+}
+
+// Output accepts <projector|all> <command> strings like projector1 power on, all mute video on or projector2 %1LAMP ?
+func (pc *PJLinkClient) Output(ctx context.Context, payload any) error {
+	commandString, ok := common.GetAnyAs[string](payload)
+	if !ok {
+		payloadBytes, ok := common.GetAnyAsByteSlice(payload)
+		if !ok {
+			return errors.New("pjlink.client can only output a command string")
+		}
+		commandString = string(payloadBytes)
+	}
+
+	words := strings.Fields(commandString)
+	if len(words) < 2 {
+		return errors.New("pjlink.client command must start with a projector name or all")
+	}
+
+	targets := []*pjlinkProjector{}
+	for _, projector := range pc.Projectors {
+		if words[0] == "all" || projector.Name == words[0] {
+			targets = append(targets, projector)
+		}
+	}
+
+	if len(targets) == 0 {
+		return fmt.Errorf("pjlink.client unknown projector: %s", words[0])
+	}
+
+	commands := []pjlink.Command{}
+	for _, projector := range targets {
+		command, err := pjlinkCommand(projector.Class, words[1:])
+		if err != nil {
+			return fmt.Errorf("pjlink.client %w", err)
+		}
+		commands = append(commands, command)
+	}
+
+	var errorString strings.Builder
+	for index, projector := range targets {
+		response, err := projector.client.Send(ctx, commands[index])
+		if err != nil {
+			fmt.Fprintf(&errorString, "%s: %s\n", projector.Name, err.Error())
+			continue
+		}
+
+		pc.emit(pjlink.Reply{Projector: projector.Name, Command: commands[index], Response: response})
+
+		if response.Error != "" {
+			fmt.Fprintf(&errorString, "%s: %s\n", projector.Name, response.Err().Error())
+			continue
+		}
+
+		if commands[index].Parameter != "?" {
+			select {
+			case projector.wake <- struct{}{}:
+			default:
+			}
+		}
+	}
+
+	if errorString.Len() == 0 {
+		return nil
+	}
+	return fmt.Errorf("pjlink.client error during output: %s", strings.TrimSpace(errorString.String()))
+}
+
+func (pc *PJLinkClient) Stop() {
+	if pc.cancel != nil {
+		pc.cancel()
+	}
+	for _, projector := range pc.Projectors {
+		projector.client.Close()
+	}
+}
@@ -1,36 +1,165 @@
 package module
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net"
+	"slices"
 	"sync"
 	"time"
 
+	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/jwetzell/psn-go"
 	"github.com/jwetzell/showbridge-go/internal/common"
 	"github.com/jwetzell/showbridge-go/internal/config"
 )
 
+const (
+	psnMulticastHost = "236.10.10.10"
+	psnPort          = 56565
+)
+
 func init() {
 	RegisterModule(ModuleRegistration{
 		Type:  "psn.client",
 		Title: "PosiStageNet Client",
-		New: func(config config.ModuleConfig) (common.Module, error) {
-			return &PSNClient{config: config, decoder: psn.NewDecoder(), logger: CreateLogger(config)}, nil
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"interface": {
+					Title:       "Interface",
+					Description: "name of the network interface to join the PSN multicast group on",
+					Type:        "string",
+				},
+				"port": {
+					Title:       "Port",
+					Description: "the port PSN is sent on",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1),
+					Maximum:     jsonschema.Ptr[float64](65535),
+					Default:     json.RawMessage(`56565`),
+				},
+				"systemName": {
+					Title:       "System Name",
+					Description: "only pass along trackers from the PSN server with this system name",
+					Type:        "string",
+				},
+				"trackers": {
+					Title:       "Trackers",
+					Description: "only pass along trackers with these ids",
+					Type:        "array",
+					Items: &jsonschema.Schema{
+						Type:    "integer",
+						Minimum: jsonschema.Ptr[float64](0),
+						Maximum: jsonschema.Ptr[float64](65535),
+					},
+				},
+				"onChange": {
+					Title:       "On Change",
+					Description: "only pass along trackers whose name or data changed",
+					Type:        "boolean",
+					Default:     json.RawMessage(`false`),
+				},
+			},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			var iface *net.Interface
+			interfaceString, err := params.GetString("interface")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("psn.client interface error: %w", err)
+				}
+			} else {
+				iface, err = net.InterfaceByName(interfaceString)
+				if err != nil {
+					return nil, fmt.Errorf("psn.client interface error: %w", err)
+				}
+			}
+
+			portNum, err := params.GetInt("port")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					portNum = psnPort
+				} else {
+					return nil, fmt.Errorf("psn.client port error: %w", err)
+				}
+			}
+
+			addr, err := net.ResolveUDPAddr("udp4", fmt.Sprintf("%s:%d", psnMulticastHost, uint16(portNum)))
+			if err != nil {
+				return nil, err
+			}
+
+			systemNameString, err := params.GetString("systemName")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("psn.client systemName error: %w", err)
+				}
+			}
+
+			var trackerIds []uint16
+			trackersSlice, err := params.GetIntSlice("trackers")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("psn.client trackers error: %w", err)
+				}
+			} else {
+				trackerIds = []uint16{}
+				for _, trackerId := range trackersSlice {
+					if trackerId < 0 || trackerId > 65535 {
+						return nil, errors.New("psn.client trackers must be between 0 and 65535")
+					}
+					trackerIds = append(trackerIds, uint16(trackerId))
+				}
+			}
+
+			onChangeBool, err := params.GetBool("onChange")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					onChangeBool = false
+				} else {
+					return nil, fmt.Errorf("psn.client onChange error: %w", err)
+				}
+			}
+
+			return &PSNClient{
+				config:     moduleConfig,
+				Addr:       addr,
+				Interface:  iface,
+				SystemName: systemNameString,
+				Trackers:   trackerIds,
+				OnChange:   onChangeBool,
+				decoders:   map[string]*psn.Decoder{},
+				lastSent:   map[string][]byte{},
+				logger:     CreateLogger(moduleConfig),
+			}, nil
 		},
 	})
 }
 
 type PSNClient struct {
 	config       config.ModuleConfig
+	Addr         *net.UDPAddr
+	Interface    *net.Interface
+	SystemName   string
+	Trackers     []uint16
+	OnChange     bool
 	conn         *net.UDPConn
 	ctx          context.Context
 	inputHandler common.InputHandler
-	decoder      *psn.Decoder
-	logger       *slog.Logger
-	cancel       context.CancelFunc
-	connMu       sync.Mutex
+	//NOTE(jwetzell): one decoder per PSN server so system names and tracker ids do not mix
+	decoders map[string]*psn.Decoder
+	lastSent map[string][]byte
+	logger   *slog.Logger
+	cancel   context.CancelFunc
+	connMu   sync.Mutex
 }
 
 func (pc *PSNClient) Id() string {
@@ -41,6 +170,79 @@ func (pc *PSNClient) Type() string {
 	return pc.config.Type
 }
 
+// clonePSNTracker copies a tracker so later decodes do not change payloads already handed to routes
+func clonePSNTracker(tracker *psn.Tracker) *psn.Tracker {
+	clone := &psn.Tracker{Name: tracker.Name, Id: tracker.Id}
+	if tracker.Pos != nil {
+		clone.SetPos(tracker.Pos.X, tracker.Pos.Y, tracker.Pos.Z)
+	}
+	if tracker.Speed != nil {
+		clone.SetSpeed(tracker.Speed.X, tracker.Speed.Y, tracker.Speed.Z)
+	}
+	if tracker.Ori != nil {
+		clone.SetOri(tracker.Ori.X, tracker.Ori.Y, tracker.Ori.Z)
+	}
+	if tracker.Validity != nil {
+		clone.SetStatus(*tracker.Validity)
+	}
+	if tracker.Accel != nil {
+		clone.SetAccel(tracker.Accel.X, tracker.Accel.Y, tracker.Accel.Z)
+	}
+	if tracker.TrgtPos != nil {
+		clone.SetTrgtPos(tracker.TrgtPos.X, tracker.TrgtPos.Y, tracker.TrgtPos.Z)
+	}
+	if tracker.Timestamp != nil {
+		clone.SetTimestamp(*tracker.Timestamp)
+	}
+	return clone
+}
+
+// handlePacket decodes PSN from source and returns the trackers that pass the filters
+func (pc *PSNClient) handlePacket(source string, message []byte) []*psn.Tracker {
+	decoder, ok := pc.decoders[source]
+	if !ok {
+		decoder = psn.NewDecoder()
+		pc.decoders[source] = decoder
+	}
+
+	err := decoder.Decode(message)
+	if err != nil {
+		pc.logger.Error("problem decoding psn traffic", "error", err)
+		return nil
+	}
+
+	if pc.SystemName != "" && decoder.SystemName != pc.SystemName {
+		return nil
+	}
+
+	trackerIds := []uint16{}
+	for trackerId := range decoder.Trackers {
+		if pc.Trackers != nil && !slices.Contains(pc.Trackers, trackerId) {
+			continue
+		}
+		trackerIds = append(trackerIds, trackerId)
+	}
+	slices.Sort(trackerIds)
+
+	trackers := []*psn.Tracker{}
+	for _, trackerId := range trackerIds {
+		tracker := decoder.Trackers[trackerId]
+		if pc.OnChange {
+			trackerKey := fmt.Sprintf("%s/%d", source, trackerId)
+			//NOTE(jwetzell): the timestamp changes every frame so leave it out of the comparison
+			comparable := clonePSNTracker(tracker)
+			comparable.Timestamp = nil
+			state := append([]byte(tracker.Name), comparable.GetDataChunk()...)
+			if bytes.Equal(pc.lastSent[trackerKey], state) {
+				continue
+			}
+			pc.lastSent[trackerKey] = state
+		}
+		trackers = append(trackers, clonePSNTracker(tracker))
+	}
+	return trackers
+}
+
 func (pc *PSNClient) Start(ctx context.Context, inputHandler common.InputHandler) error {
 	pc.logger.Debug("running")
 	pc.inputHandler = inputHandler
@@ -48,12 +250,7 @@ func (pc *PSNClient) Start(ctx context.Context, inputHandler common.InputHandler
 	pc.ctx = moduleContext
 	pc.cancel = cancel
 
-	addr, err := net.ResolveUDPAddr("udp", "236.10.10.10:56565")
-	if err != nil {
-		return err
-	}
-
-	client, err := net.ListenMulticastUDP("udp", nil, addr)
+	client, err := net.ListenMulticastUDP("udp4", pc.Interface, pc.Addr)
 	if err != nil {
 		return err
 	}
@@ -71,7 +268,7 @@ func (pc *PSNClient) Start(ctx context.Context, inputHandler common.InputHandler
 			pc.connMu.Lock()
 			pc.conn.SetDeadline(time.Now().Add(time.Millisecond * 200))
 
-			numBytes, _, err := pc.conn.ReadFromUDP(buffer)
+			numBytes, sourceAddr, err := pc.conn.ReadFromUDP(buffer)
 			pc.connMu.Unlock()
 			if err != nil {
 				//NOTE(jwetzell) we hit deadline
@@ -83,15 +280,10 @@ func (pc *PSNClient) Start(ctx context.Context, inputHandler common.InputHandler
 			}
 
 			if numBytes > 0 {
-				message := buffer[:numBytes]
-				err := pc.decoder.Decode(message)
-				if err != nil {
-					pc.logger.Error("problem decoding psn traffic", "error", err)
-				}
+				trackers := pc.handlePacket(sourceAddr.String(), buffer[:numBytes])
 
 				if pc.inputHandler != nil {
-					// TODO(jwetzell): better input handling
-					for _, tracker := range pc.decoder.Trackers {
+					for _, tracker := range trackers {
 						pc.inputHandler(pc.ctx, pc.Id(), tracker)
 					}
 				} else {
@@ -0,0 +1,271 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/psn-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+)
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "psn.server",
+		Title:       "PosiStageNet Server",
+		Description: "Send trackers output to the module as PSN info and data packets",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"host": {
+					Title:       "Host",
+					Description: "where to send PSN, the PSN multicast group by default",
+					Type:        "string",
+					Default:     json.RawMessage(`"236.10.10.10"`),
+				},
+				"port": {
+					Title:       "Port",
+					Description: "the port to send PSN to",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1),
+					Maximum:     jsonschema.Ptr[float64](65535),
+					Default:     json.RawMessage(`56565`),
+				},
+				"systemName": {
+					Title:       "System Name",
+					Description: "system name sent in PSN info packets",
+					Type:        "string",
+					Default:     json.RawMessage(`"showbridge"`),
+				},
+				"rate": {
+					Title:       "Rate",
+					Description: "data packets sent per second on top of the ones sent on output, 0 only sends on output",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](0),
+					Maximum:     jsonschema.Ptr[float64](1000),
+					Default:     json.RawMessage(`60`),
+				},
+			},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			hostString, err := params.GetString("host")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					hostString = psnMulticastHost
+				} else {
+					return nil, fmt.Errorf("psn.server host error: %w", err)
+				}
+			}
+
+			portNum, err := params.GetInt("port")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					portNum = psnPort
+				} else {
+					return nil, fmt.Errorf("psn.server port error: %w", err)
+				}
+			}
+
+			addr, err := net.ResolveUDPAddr("udp4", fmt.Sprintf("%s:%d", hostString, uint16(portNum)))
+			if err != nil {
+				return nil, err
+			}
+
+			systemNameString, err := params.GetString("systemName")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					systemNameString = "showbridge"
+				} else {
+					return nil, fmt.Errorf("psn.server systemName error: %w", err)
+				}
+			}
+
+			rateNum, err := params.GetInt("rate")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					rateNum = 60
+				} else {
+					return nil, fmt.Errorf("psn.server rate error: %w", err)
+				}
+			}
+
+			if rateNum < 0 || rateNum > 1000 {
+				return nil, errors.New("psn.server rate must be between 0 and 1000")
+			}
+
+			return &PSNServer{
+				config:   moduleConfig,
+				Addr:     addr,
+				Rate:     rateNum,
+				encoder:  &psn.Encoder{SystemName: systemNameString, VersionHigh: 2, VersionLow: 3},
+				trackers: map[uint16]*psn.Tracker{},
+				logger:   CreateLogger(moduleConfig),
+			}, nil
+		},
+	})
+}
+
+type PSNServer struct {
+	config    config.ModuleConfig
+	Addr      *net.UDPAddr
+	Rate      int
+	conn      *net.UDPConn
+	ctx       context.Context
+	encoder   *psn.Encoder
+	trackers  map[uint16]*psn.Tracker
+	startTime time.Time
+	logger    *slog.Logger
+	cancel    context.CancelFunc
+	mu        sync.Mutex
+}
+
+func (ps *PSNServer) Id() string {
+	return ps.config.Id
+}
+
+func (ps *PSNServer) Type() string {
+	return ps.config.Type
+}
+
+func (ps *PSNServer) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	ps.logger.Debug("running")
+	moduleContext, cancel := context.WithCancel(ctx)
+	ps.ctx = moduleContext
+	ps.cancel = cancel
+
+	conn, err := net.DialUDP("udp4", nil, ps.Addr)
+	if err != nil {
+		return err
+	}
+
+	ps.mu.Lock()
+	ps.conn = conn
+	ps.startTime = time.Now()
+	ps.mu.Unlock()
+
+	//NOTE(jwetzell): PSN asks for info packets once a second
+	infoTicker := time.NewTicker(time.Second)
+	defer infoTicker.Stop()
+
+	var dataTick <-chan time.Time
+	if ps.Rate > 0 {
+		dataTicker := time.NewTicker(time.Second / time.Duration(ps.Rate))
+		defer dataTicker.Stop()
+		dataTick = dataTicker.C
+	}
+
+	for {
+		select {
+		case <-ps.ctx.Done():
+			ps.logger.Debug("done")
+			return nil
+		case <-infoTicker.C:
+			ps.send(true)
+		case <-dataTick:
+			ps.send(false)
+		}
+	}
+}
+
+// send writes info or data packets for every tracker the server knows about
+func (ps *PSNServer) send(info bool) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if ps.conn == nil {
+		return errors.New("psn.server is not started")
+	}
+
+	if len(ps.trackers) == 0 {
+		return nil
+	}
+
+	trackerIds := []uint16{}
+	for trackerId := range ps.trackers {
+		trackerIds = append(trackerIds, trackerId)
+	}
+	slices.Sort(trackerIds)
+
+	trackers := []*psn.Tracker{}
+	for _, trackerId := range trackerIds {
+		trackers = append(trackers, ps.trackers[trackerId])
+	}
+
+	timestamp := uint64(time.Since(ps.startTime).Microseconds())
+	var packets [][]byte
+	if info {
+		packets = ps.encoder.GetInfoPackets(timestamp, trackers)
+	} else {
+		packets = ps.encoder.GetDataPackets(timestamp, trackers)
+	}
+
+	for _, packet := range packets {
+		_, err := ps.conn.Write(packet)
+		if err != nil {
+			ps.logger.Warn("failed to send PSN", "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (ps *PSNServer) Output(ctx context.Context, payload any) error {
+	trackers := []*psn.Tracker{}
+	switch typedPayload := payload.(type) {
+	case *psn.Tracker:
+		trackers = append(trackers, typedPayload)
+	case psn.Tracker:
+		trackers = append(trackers, &typedPayload)
+	case []*psn.Tracker:
+		trackers = typedPayload
+	default:
+		return errors.New("psn.server is only able to output a PSN tracker")
+	}
+
+	ps.mu.Lock()
+	newTracker := false
+	for _, tracker := range trackers {
+		if tracker == nil {
+			ps.mu.Unlock()
+			return errors.New("psn.server cannot output a nil tracker")
+		}
+		_, ok := ps.trackers[tracker.Id]
+		if !ok {
+			newTracker = true
+		}
+		ps.trackers[tracker.Id] = clonePSNTracker(tracker)
+	}
+	ps.mu.Unlock()
+
+	//NOTE(jwetzell): receivers need a name for new trackers before the next info tick
+	if newTracker {
+		err := ps.send(true)
+		if err != nil {
+			return err
+		}
+	}
+	return ps.send(false)
+}
+
+func (ps *PSNServer) Stop() {
+	if ps.cancel != nil {
+		defer ps.cancel()
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.conn != nil {
+		ps.conn.Close()
+		ps.conn = nil
+	}
+}
@@ -0,0 +1,148 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/recording"
+)
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "recording.replay",
+		Title:       "Recording Replay",
+		Description: "Feed a recording of module inputs back into the router",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"path": {
+					Title:       "Path",
+					Description: "path of the recording file",
+					Type:        "string",
+					MinLength:   new(1),
+				},
+				"speed": {
+					Title:       "Speed",
+					Description: "playback speed, 1 is real time and 0 is as fast as possible",
+					Type:        "number",
+					Minimum:     jsonschema.Ptr[float64](0),
+					Default:     json.RawMessage(`1`),
+				},
+				"loop": {
+					Title:       "Loop",
+					Description: "start the recording over when it ends",
+					Type:        "boolean",
+					Default:     json.RawMessage(`false`),
+				},
+				"source": {
+					Title:       "Source",
+					Description: "source id to use for every replayed input instead of the recorded one",
+					Type:        "string",
+				},
+			},
+			Required:             []string{"path"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			pathString, err := params.GetString("path")
+			if err != nil {
+				return nil, fmt.Errorf("recording.replay path error: %w", err)
+			}
+
+			speedFloat, err := params.GetFloat64("speed")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					speedFloat = 1
+				} else {
+					return nil, fmt.Errorf("recording.replay speed error: %w", err)
+				}
+			}
+
+			if speedFloat < 0 {
+				return nil, errors.New("recording.replay speed cannot be negative")
+			}
+
+			loopBool, err := params.GetBool("loop")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					loopBool = false
+				} else {
+					return nil, fmt.Errorf("recording.replay loop error: %w", err)
+				}
+			}
+
+			sourceString, err := params.GetString("source")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					sourceString = ""
+				} else {
+					return nil, fmt.Errorf("recording.replay source error: %w", err)
+				}
+			}
+
+			return &RecordingReplay{
+				Path: pathString,
+				Options: recording.ReplayOptions{
+					Speed:  speedFloat,
+					Loop:   loopBool,
+					Source: sourceString,
+				},
+				config: moduleConfig,
+				logger: CreateLogger(moduleConfig),
+			}, nil
+		},
+	})
+}
+
+type RecordingReplay struct {
+	config  config.ModuleConfig
+	Path    string
+	Options recording.ReplayOptions
+	ctx     context.Context
+	logger  *slog.Logger
+	cancel  context.CancelFunc
+}
+
+func (rr *RecordingReplay) Id() string {
+	return rr.config.Id
+}
+
+func (rr *RecordingReplay) Type() string {
+	return rr.config.Type
+}
+
+func (rr *RecordingReplay) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	rr.logger.Debug("running")
+	moduleContext, cancel := context.WithCancel(ctx)
+	rr.ctx = moduleContext
+	rr.cancel = cancel
+
+	frames, err := recording.Load(rr.Path)
+	if err != nil {
+		return fmt.Errorf("recording.replay unable to load recording: %w", err)
+	}
+
+	err = recording.Replay(rr.ctx, frames, inputHandler, rr.Options)
+	if err != nil {
+		return fmt.Errorf("recording.replay error: %w", err)
+	}
+	rr.logger.Debug("replay finished")
+
+	<-rr.ctx.Done()
+	rr.logger.Debug("done")
+	return nil
+}
+
+func (rr *RecordingReplay) Stop() {
+	if rr.cancel != nil {
+		rr.cancel()
+	}
+}
@@ -0,0 +1,533 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/rtpmidi"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+const (
+	rtpMIDIClockSyncInterval = 10 * time.Second
+	rtpMIDIInviteInterval    = 5 * time.Second
+	rtpMIDITimeout           = 60 * time.Second
+)
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "rtpmidi.session",
+		Title:       "RTP-MIDI Session",
+		Description: "AppleMIDI (RTP-MIDI) network session, emits midi.Message from peers and sends midi.Message output to every connected peer",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"ip": {
+					Title:       "IP",
+					Description: "the IP address to listen on",
+					Type:        "string",
+					Default:     json.RawMessage(`"0.0.0.0"`),
+				},
+				"port": {
+					Title:       "Port",
+					Description: "the control port to listen on, the data port is the next port up",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1),
+					Maximum:     jsonschema.Ptr[float64](65534),
+					Default:     json.RawMessage(`5004`),
+				},
+				"name": {
+					Title:       "Name",
+					Description: "session name sent to peers, the module id by default",
+					Type:        "string",
+				},
+				"peers": {
+					Title:       "Peers",
+					Description: "host:port control ports of sessions to invite",
+					Type:        "array",
+					Items: &jsonschema.Schema{
+						Type:      "string",
+						MinLength: new(1),
+					},
+				},
+			},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			ipString, err := params.GetString("ip")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					ipString = "0.0.0.0"
+				} else {
+					return nil, fmt.Errorf("rtpmidi.session ip error: %w", err)
+				}
+			}
+
+			portNum, err := params.GetInt("port")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					portNum = 5004
+				} else {
+					return nil, fmt.Errorf("rtpmidi.session port error: %w", err)
+				}
+			}
+
+			if portNum < 1 || portNum > 65534 {
+				return nil, errors.New("rtpmidi.session port must be between 1 and 65534")
+			}
+
+			addr, err := net.ResolveUDPAddr("udp4", fmt.Sprintf("%s:%d", ipString, portNum))
+			if err != nil {
+				return nil, err
+			}
+
+			nameString, err := params.GetString("name")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					nameString = moduleConfig.Id
+				} else {
+					return nil, fmt.Errorf("rtpmidi.session name error: %w", err)
+				}
+			}
+
+			peerStrings, err := params.GetStringSlice("peers")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("rtpmidi.session peers error: %w", err)
+				}
+			}
+
+			invites := map[uint32]*rtpMIDIInvite{}
+			for _, peerString := range peerStrings {
+				peerAddr, err := net.ResolveUDPAddr("udp4", peerString)
+				if err != nil {
+					return nil, fmt.Errorf("rtpmidi.session peers error: %w", err)
+				}
+				if peerAddr.Port < 1 || peerAddr.Port > 65534 {
+					return nil, errors.New("rtpmidi.session peer port must be between 1 and 65534")
+				}
+				invites[rand.Uint32()] = &rtpMIDIInvite{controlAddr: peerAddr}
+			}
+
+			return &RTPMIDISession{
+				config:   moduleConfig,
+				Addr:     addr,
+				Name:     nameString,
+				ssrc:     rand.Uint32(),
+				invites:  invites,
+				sessions: map[uint32]*rtpMIDIPeer{},
+				logger:   CreateLogger(moduleConfig),
+			}, nil
+		},
+	})
+}
+
+// rtpMIDIInvite tracks a session this module initiates, stage 1 is waiting on the control port and stage 2 on the data port
+type rtpMIDIInvite struct {
+	controlAddr *net.UDPAddr
+	stage       int
+	ssrc        uint32
+	lastAttempt time.Time
+}
+
+type rtpMIDIPeer struct {
+	name          string
+	ssrc          uint32
+	controlAddr   *net.UDPAddr
+	dataAddr      *net.UDPAddr
+	initiator     bool
+	sender        *rtpmidi.Sender
+	receiver      *rtpmidi.Receiver
+	unacked       bool
+	lastClockSync time.Time
+	lastSeen      time.Time
+	latency       time.Duration
+}
+
+type RTPMIDISession struct {
+	config       config.ModuleConfig
+	Addr         *net.UDPAddr
+	Name         string
+	ssrc         uint32
+	controlConn  *net.UDPConn
+	dataConn     *net.UDPConn
+	invites      map[uint32]*rtpMIDIInvite
+	sessions     map[uint32]*rtpMIDIPeer
+	startTime    time.Time
+	ctx          context.Context
+	inputHandler common.InputHandler
+	logger       *slog.Logger
+	cancel       context.CancelFunc
+	mu           sync.Mutex
+}
+
+func (rs *RTPMIDISession) Id() string {
+	return rs.config.Id
+}
+
+func (rs *RTPMIDISession) Type() string {
+	return rs.config.Type
+}
+
+func (rs *RTPMIDISession) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	rs.logger.Debug("running")
+	rs.inputHandler = inputHandler
+	moduleContext, cancel := context.WithCancel(ctx)
+	rs.ctx = moduleContext
+	rs.cancel = cancel
+
+	controlConn, err := net.ListenUDP("udp4", rs.Addr)
+	if err != nil {
+		return err
+	}
+
+	dataConn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: rs.Addr.IP, Port: rs.Addr.Port + 1})
+	if err != nil {
+		controlConn.Close()
+		return err
+	}
+
+	rs.mu.Lock()
+	rs.controlConn = controlConn
+	rs.dataConn = dataConn
+	rs.startTime = time.Now()
+	rs.mu.Unlock()
+
+	errs := make(chan error, 2)
+	var readers sync.WaitGroup
+	for _, conn := range []*net.UDPConn{controlConn, dataConn} {
+		readers.Go(func() {
+			defer conn.Close()
+			err := rs.read(conn, conn == dataConn)
+			if err != nil {
+				errs <- err
+				rs.cancel()
+			}
+		})
+	}
+
+	rs.tick()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-rs.ctx.Done():
+			rs.bye()
+			controlConn.Close()
+			dataConn.Close()
+			readers.Wait()
+			close(errs)
+			rs.logger.Debug("done")
+			return <-errs
+		case <-ticker.C:
+			rs.tick()
+		}
+	}
+}
+
+func (rs *RTPMIDISession) read(conn *net.UDPConn, data bool) error {
+	buffer := make([]byte, 2048)
+	for rs.ctx.Err() == nil {
+		conn.SetDeadline(time.Now().Add(time.Millisecond * 200))
+
+		numBytes, addr, err := conn.ReadFromUDP(buffer)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			//NOTE(jwetzell) we hit deadline
+			opErr, ok := err.(*net.OpError)
+			if ok && opErr.Timeout() {
+				continue
+			}
+			return err
+		}
+
+		message := buffer[:numBytes]
+		if rtpmidi.IsControl(message) {
+			packet, err := rtpmidi.DecodeControl(message)
+			if err != nil {
+				rs.logger.Debug("unable to decode control packet", "error", err)
+				continue
+			}
+			rs.handleControl(packet, addr, data)
+			continue
+		}
+
+		if !data {
+			continue
+		}
+
+		packet, err := rtpmidi.DecodeMIDIPacket(message)
+		if err != nil {
+			rs.logger.Debug("unable to decode RTP-MIDI packet", "error", err)
+			continue
+		}
+		rs.handleMIDI(packet)
+	}
+	return nil
+}
+
+// timestamp is the session clock in 100 microsecond units
+func (rs *RTPMIDISession) timestamp() uint64 {
+	return uint64(time.Since(rs.startTime) / (time.Second / rtpmidi.ClockRate))
+}
+
+func (rs *RTPMIDISession) send(conn *net.UDPConn, addr *net.UDPAddr, packet rtpmidi.Packet) {
+	packetBytes, err := packet.MarshalBinary()
+	if err != nil {
+		rs.logger.Error("unable to encode packet", "error", err)
+		return
+	}
+	_, err = conn.WriteToUDP(packetBytes, addr)
+	if err != nil {
+		rs.logger.Debug("unable to send packet", "addr", addr, "error", err)
+	}
+}
+
+func (rs *RTPMIDISession) handleControl(packet rtpmidi.Packet, addr *net.UDPAddr, data bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	conn := rs.controlConn
+	if data {
+		conn = rs.dataConn
+	}
+
+	switch packet := packet.(type) {
+	case *rtpmidi.Invitation:
+		switch packet.Command {
+		case rtpmidi.CommandInvitation:
+			reply := &rtpmidi.Invitation{Command: rtpmidi.CommandAccept, Version: rtpmidi.ProtocolVersion, Token: packet.Token, SSRC: rs.ssrc, Name: rs.Name}
+			if !data {
+				rs.sessions[packet.SSRC] = &rtpMIDIPeer{
+					name:        packet.Name,
+					ssrc:        packet.SSRC,
+					controlAddr: addr,
+					sender:      rtpmidi.NewSender(rs.ssrc, uint16(rand.Uint32())),
+					receiver:    rtpmidi.NewReceiver(),
+					lastSeen:    time.Now(),
+				}
+			} else {
+				session, ok := rs.sessions[packet.SSRC]
+				if !ok {
+					//NOTE(jwetzell): the data port invitation has to follow one on the control port
+					reply.Command = rtpmidi.CommandReject
+				} else {
+					session.dataAddr = addr
+					session.lastSeen = time.Now()
+					rs.logger.Info("session connected", "peer", session.name, "addr", addr)
+				}
+			}
+			rs.send(conn, addr, reply)
+		case rtpmidi.CommandAccept:
+			invite, ok := rs.invites[packet.Token]
+			if !ok {
+				return
+			}
+			if !data && invite.stage == 1 {
+				invite.stage = 2
+				invite.ssrc = packet.SSRC
+				dataAddr := &net.UDPAddr{IP: invite.controlAddr.IP, Port: invite.controlAddr.Port + 1}
+				rs.send(rs.dataConn, dataAddr, &rtpmidi.Invitation{Command: rtpmidi.CommandInvitation, Version: rtpmidi.ProtocolVersion, Token: packet.Token, SSRC: rs.ssrc, Name: rs.Name})
+			} else if data && invite.stage == 2 {
+				invite.stage = 3
+				session := &rtpMIDIPeer{
+					name:          packet.Name,
+					ssrc:          packet.SSRC,
+					controlAddr:   invite.controlAddr,
+					dataAddr:      addr,
+					initiator:     true,
+					sender:        rtpmidi.NewSender(rs.ssrc, uint16(rand.Uint32())),
+					receiver:      rtpmidi.NewReceiver(),
+					lastClockSync: time.Now(),
+					lastSeen:      time.Now(),
+				}
+				rs.sessions[packet.SSRC] = session
+				rs.logger.Info("session connected", "peer", session.name, "addr", addr)
+				rs.send(rs.dataConn, addr, &rtpmidi.ClockSync{SSRC: rs.ssrc, Count: 0, Timestamps: [3]uint64{rs.timestamp(), 0, 0}})
+			}
+		case rtpmidi.CommandReject:
+			invite, ok := rs.invites[packet.Token]
+			if ok {
+				rs.logger.Warn("invitation rejected", "addr", addr)
+				invite.stage = 0
+			}
+		case rtpmidi.CommandBye:
+			rs.endSession(packet.SSRC)
+		}
+	case *rtpmidi.ClockSync:
+		session, ok := rs.sessions[packet.SSRC]
+		if !ok || session.dataAddr == nil {
+			return
+		}
+		session.lastSeen = time.Now()
+		switch packet.Count {
+		case 0:
+			packet.Timestamps[1] = rs.timestamp()
+			rs.send(rs.dataConn, session.dataAddr, &rtpmidi.ClockSync{SSRC: rs.ssrc, Count: 1, Timestamps: packet.Timestamps})
+		case 1:
+			packet.Timestamps[2] = rs.timestamp()
+			session.latency = time.Duration(packet.Timestamps[2]-packet.Timestamps[0]) * (time.Second / rtpmidi.ClockRate) / 2
+			rs.logger.Debug("clock sync", "peer", session.name, "latency", session.latency)
+			rs.send(rs.dataConn, session.dataAddr, &rtpmidi.ClockSync{SSRC: rs.ssrc, Count: 2, Timestamps: packet.Timestamps})
+		case 2:
+			session.latency = time.Duration(packet.Timestamps[2]-packet.Timestamps[0]) * (time.Second / rtpmidi.ClockRate) / 2
+			rs.logger.Debug("clock sync", "peer", session.name, "latency", session.latency)
+		}
+	case *rtpmidi.ReceiverFeedback:
+		session, ok := rs.sessions[packet.SSRC]
+		if ok {
+			session.lastSeen = time.Now()
+			session.sender.Ack(packet.Sequence)
+		}
+	}
+}
+
+// endSession drops a session, an invite for it goes back to being retried
+func (rs *RTPMIDISession) endSession(ssrc uint32) {
+	session, ok := rs.sessions[ssrc]
+	if ok {
+		rs.logger.Info("session ended", "peer", session.name)
+		delete(rs.sessions, ssrc)
+	}
+	for _, invite := range rs.invites {
+		if invite.ssrc == ssrc {
+			invite.stage = 0
+			invite.ssrc = 0
+		}
+	}
+}
+
+func (rs *RTPMIDISession) handleMIDI(packet *rtpmidi.MIDIPacket) {
+	rs.mu.Lock()
+	session, ok := rs.sessions[packet.SSRC]
+	if !ok || session.dataAddr == nil {
+		rs.mu.Unlock()
+		return
+	}
+	session.lastSeen = time.Now()
+	session.unacked = true
+	messages, lost := session.receiver.Receive(packet)
+	rs.mu.Unlock()
+
+	if lost {
+		rs.logger.Debug("packets lost", "peer", session.name, "journal", packet.Journal != nil)
+	}
+
+	for _, message := range messages {
+		if rs.inputHandler != nil {
+			rs.inputHandler(rs.ctx, rs.Id(), midi.Message(message))
+		} else {
+			rs.logger.Error("input received but no input handler is configured")
+		}
+	}
+}
+
+// tick retries invitations, runs clock sync for sessions this module started, sends receiver feedback and drops silent peers
+func (rs *RTPMIDISession) tick() {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	now := time.Now()
+
+	for token, invite := range rs.invites {
+		if invite.stage == 3 || now.Sub(invite.lastAttempt) < rtpMIDIInviteInterval {
+			continue
+		}
+		invite.stage = 1
+		invite.lastAttempt = now
+		rs.send(rs.controlConn, invite.controlAddr, &rtpmidi.Invitation{Command: rtpmidi.CommandInvitation, Version: rtpmidi.ProtocolVersion, Token: token, SSRC: rs.ssrc, Name: rs.Name})
+	}
+
+	for ssrc, session := range rs.sessions {
+		if now.Sub(session.lastSeen) > rtpMIDITimeout {
+			rs.logger.Warn("session timed out", "peer", session.name)
+			rs.endSession(ssrc)
+			continue
+		}
+		if session.dataAddr == nil {
+			continue
+		}
+		if session.unacked {
+			session.unacked = false
+			rs.send(rs.controlConn, session.controlAddr, &rtpmidi.ReceiverFeedback{SSRC: rs.ssrc, Sequence: session.receiver.Sequence()})
+		}
+		if session.initiator && now.Sub(session.lastClockSync) >= rtpMIDIClockSyncInterval {
+			session.lastClockSync = now
+			rs.send(rs.dataConn, session.dataAddr, &rtpmidi.ClockSync{SSRC: rs.ssrc, Count: 0, Timestamps: [3]uint64{rs.timestamp(), 0, 0}})
+		}
+	}
+}
+
+func (rs *RTPMIDISession) bye() {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	for ssrc, session := range rs.sessions {
+		rs.send(rs.controlConn, session.controlAddr, &rtpmidi.Invitation{Command: rtpmidi.CommandBye, Version: rtpmidi.ProtocolVersion, SSRC: rs.ssrc})
+		delete(rs.sessions, ssrc)
+	}
+}
+
+func (rs *RTPMIDISession) Output(ctx context.Context, payload any) error {
+	payloadMessage, ok := common.GetAnyAs[midi.Message](payload)
+	if !ok {
+		payloadBytes, ok := common.GetAnyAsByteSlice(payload)
+		if !ok {
+			return errors.New("rtpmidi.session can only output midi.Message")
+		}
+		payloadMessage = midi.Message(payloadBytes)
+	}
+
+	if len(payloadMessage) == 0 || payloadMessage[0] < 0x80 {
+		return errors.New("rtpmidi.session output must start with a MIDI status byte")
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.dataConn == nil {
+		return errors.New("rtpmidi.session is not started")
+	}
+
+	sent := 0
+	timestamp := uint32(rs.timestamp())
+	for _, session := range rs.sessions {
+		if session.dataAddr == nil {
+			continue
+		}
+		packetBytes, err := session.sender.Packet([][]byte{payloadMessage}, timestamp).MarshalBinary()
+		if err != nil {
+			return err
+		}
+		_, err = rs.dataConn.WriteToUDP(packetBytes, session.dataAddr)
+		if err != nil {
+			return err
+		}
+		sent++
+	}
+
+	if sent == 0 {
+		return errors.New("rtpmidi.session has no connected peers")
+	}
+	return nil
+}
+
+func (rs *RTPMIDISession) Stop() {
+	if rs.cancel != nil {
+		rs.cancel()
+	}
+}
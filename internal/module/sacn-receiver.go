@@ -0,0 +1,227 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/sacn"
+)
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "sacn.receiver",
+		Title:       "sACN Receiver",
+		Description: "Receive DMX from sACN (E1.31) sources",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"universes": {
+					Title:       "Universes",
+					Description: "the universes to join",
+					Type:        "array",
+					Items: &jsonschema.Schema{
+						Type:    "integer",
+						Minimum: jsonschema.Ptr[float64](sacn.MinUniverse),
+						Maximum: jsonschema.Ptr[float64](sacn.MaxUniverse),
+					},
+					MinItems: new(1),
+				},
+				"interface": {
+					Title:       "Interface",
+					Description: "name of the network interface to join the multicast groups on",
+					Type:        "string",
+				},
+				"preview": {
+					Title:       "Preview",
+					Description: "pass along packets marked as preview data",
+					Type:        "boolean",
+					Default:     json.RawMessage(`false`),
+				},
+			},
+			Required:             []string{"universes"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			universesSlice, err := params.GetIntSlice("universes")
+			if err != nil {
+				return nil, fmt.Errorf("sacn.receiver universes error: %w", err)
+			}
+
+			if len(universesSlice) == 0 {
+				return nil, errors.New("sacn.receiver universes must not be empty")
+			}
+
+			universes := []uint16{}
+			for _, universe := range universesSlice {
+				if universe < sacn.MinUniverse || universe > sacn.MaxUniverse {
+					return nil, fmt.Errorf("sacn.receiver universe must be between %d and %d", sacn.MinUniverse, sacn.MaxUniverse)
+				}
+				universes = append(universes, uint16(universe))
+			}
+
+			var iface *net.Interface
+			interfaceString, err := params.GetString("interface")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("sacn.receiver interface error: %w", err)
+				}
+			} else {
+				iface, err = net.InterfaceByName(interfaceString)
+				if err != nil {
+					return nil, fmt.Errorf("sacn.receiver interface error: %w", err)
+				}
+			}
+
+			previewBool, err := params.GetBool("preview")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					previewBool = false
+				} else {
+					return nil, fmt.Errorf("sacn.receiver preview error: %w", err)
+				}
+			}
+
+			return &SACNReceiver{
+				config:    moduleConfig,
+				Universes: universes,
+				Interface: iface,
+				Preview:   previewBool,
+				tracker:   sacn.NewTracker(),
+				logger:    CreateLogger(moduleConfig),
+			}, nil
+		},
+	})
+}
+
+type SACNReceiver struct {
+	config       config.ModuleConfig
+	Universes    []uint16
+	Interface    *net.Interface
+	Preview      bool
+	tracker      *sacn.Tracker
+	conns        []*net.UDPConn
+	connsMu      sync.Mutex
+	ctx          context.Context
+	inputHandler common.InputHandler
+	logger       *slog.Logger
+	cancel       context.CancelFunc
+}
+
+func (sr *SACNReceiver) Id() string {
+	return sr.config.Id
+}
+
+func (sr *SACNReceiver) Type() string {
+	return sr.config.Type
+}
+
+func (sr *SACNReceiver) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	sr.logger.Debug("running")
+	sr.inputHandler = inputHandler
+	moduleContext, cancel := context.WithCancel(ctx)
+	sr.ctx = moduleContext
+	sr.cancel = cancel
+
+	conns := []*net.UDPConn{}
+	for _, universe := range sr.Universes {
+		conn, err := net.ListenMulticastUDP("udp4", sr.Interface, sacn.MulticastAddr(universe))
+		if err != nil {
+			for _, openConn := range conns {
+				openConn.Close()
+			}
+			return fmt.Errorf("sacn.receiver unable to join universe %d: %w", universe, err)
+		}
+		conns = append(conns, conn)
+	}
+
+	sr.connsMu.Lock()
+	sr.conns = conns
+	sr.connsMu.Unlock()
+
+	errs := make(chan error, len(conns))
+	var readers sync.WaitGroup
+	for _, conn := range conns {
+		readers.Go(func() {
+			defer conn.Close()
+			err := sr.read(conn)
+			if err != nil {
+				errs <- err
+				sr.cancel()
+			}
+		})
+	}
+
+	<-sr.ctx.Done()
+	readers.Wait()
+	close(errs)
+	sr.logger.Debug("done")
+	return <-errs
+}
+
+func (sr *SACNReceiver) read(conn *net.UDPConn) error {
+	buffer := make([]byte, 1144)
+	for sr.ctx.Err() == nil {
+		conn.SetDeadline(time.Now().Add(time.Millisecond * 200))
+
+		numBytes, _, err := conn.ReadFromUDP(buffer)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			//NOTE(jwetzell) we hit deadline
+			opErr, ok := err.(*net.OpError)
+			if ok && opErr.Timeout() {
+				continue
+			}
+			return err
+		}
+
+		packet, err := sacn.Decode(buffer[:numBytes])
+		if err != nil {
+			sr.logger.Debug("unable to decode packet", "error", err)
+			continue
+		}
+
+		dataPacket, ok := packet.(*sacn.DataPacket)
+		if !ok {
+			continue
+		}
+
+		if dataPacket.Preview && !sr.Preview {
+			continue
+		}
+
+		if !sr.tracker.Accept(dataPacket, time.Now()) {
+			continue
+		}
+
+		if sr.inputHandler != nil {
+			sr.inputHandler(sr.ctx, sr.Id(), dataPacket)
+		} else {
+			sr.logger.Error("input received but no input handler is configured")
+		}
+	}
+	return nil
+}
+
+func (sr *SACNReceiver) Stop() {
+	if sr.cancel != nil {
+		defer sr.cancel()
+	}
+	sr.connsMu.Lock()
+	defer sr.connsMu.Unlock()
+	for _, conn := range sr.conns {
+		conn.Close()
+	}
+}
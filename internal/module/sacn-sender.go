@@ -0,0 +1,399 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/sacn"
+)
+
+const sacnDiscoveryInterval = 10 * time.Second
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "sacn.sender",
+		Title:       "sACN Sender",
+		Description: "Send DMX as an sACN (E1.31) source",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"sourceName": {
+					Title:       "Source Name",
+					Description: "name other devices will see for this source",
+					Type:        "string",
+					Default:     json.RawMessage(`"showbridge"`),
+				},
+				"cid": {
+					Title:       "CID",
+					Description: "component identifier (UUID) for this source, a random one is used if not set",
+					Type:        "string",
+					Pattern:     "^[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}$",
+				},
+				"priority": {
+					Title:       "Priority",
+					Description: "priority of the data sent by this source, used when the packet being output does not carry a priority of its own",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](0),
+					Maximum:     jsonschema.Ptr[float64](sacn.MaxPriority),
+					Default:     json.RawMessage(`100`),
+				},
+				"universe": {
+					Title:       "Universe",
+					Description: "universe to send raw DMX bytes to",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](sacn.MinUniverse),
+					Maximum:     jsonschema.Ptr[float64](sacn.MaxUniverse),
+				},
+				"host": {
+					Title:       "Host",
+					Description: "send to this host instead of the universe multicast group",
+					Type:        "string",
+				},
+				"refresh": {
+					Title:       "Refresh",
+					Description: "how often in milliseconds to resend the last frame of each universe, 0 disables",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](0),
+					Default:     json.RawMessage(`1000`),
+				},
+				"discovery": {
+					Title:       "Discovery",
+					Description: "send universe discovery packets",
+					Type:        "boolean",
+					Default:     json.RawMessage(`true`),
+				},
+			},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			sourceNameString, err := params.GetString("sourceName")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					sourceNameString = "showbridge"
+				} else {
+					return nil, fmt.Errorf("sacn.sender sourceName error: %w", err)
+				}
+			}
+
+			cid := sacn.NewCID()
+			cidString, err := params.GetString("cid")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("sacn.sender cid error: %w", err)
+				}
+			} else {
+				cid, err = sacn.ParseCID(cidString)
+				if err != nil {
+					return nil, fmt.Errorf("sacn.sender cid error: %w", err)
+				}
+			}
+
+			priorityNum, err := params.GetInt("priority")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					priorityNum = sacn.DefaultPriority
+				} else {
+					return nil, fmt.Errorf("sacn.sender priority error: %w", err)
+				}
+			}
+
+			if priorityNum < 0 || priorityNum > sacn.MaxPriority {
+				return nil, fmt.Errorf("sacn.sender priority must be between 0 and %d", sacn.MaxPriority)
+			}
+
+			universeNum, err := params.GetInt("universe")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					universeNum = 0
+				} else {
+					return nil, fmt.Errorf("sacn.sender universe error: %w", err)
+				}
+			} else if universeNum < sacn.MinUniverse || universeNum > sacn.MaxUniverse {
+				return nil, fmt.Errorf("sacn.sender universe must be between %d and %d", sacn.MinUniverse, sacn.MaxUniverse)
+			}
+
+			var hostAddr *net.UDPAddr
+			hostString, err := params.GetString("host")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("sacn.sender host error: %w", err)
+				}
+			} else {
+				hostAddr, err = net.ResolveUDPAddr("udp4", net.JoinHostPort(hostString, fmt.Sprint(sacn.Port)))
+				if err != nil {
+					return nil, fmt.Errorf("sacn.sender host error: %w", err)
+				}
+			}
+
+			refreshNum, err := params.GetInt("refresh")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					refreshNum = 1000
+				} else {
+					return nil, fmt.Errorf("sacn.sender refresh error: %w", err)
+				}
+			}
+
+			if refreshNum < 0 {
+				return nil, errors.New("sacn.sender refresh cannot be negative")
+			}
+
+			discoveryBool, err := params.GetBool("discovery")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					discoveryBool = true
+				} else {
+					return nil, fmt.Errorf("sacn.sender discovery error: %w", err)
+				}
+			}
+
+			return &SACNSender{
+				config:     moduleConfig,
+				SourceName: sourceNameString,
+				CID:        cid,
+				Priority:   uint8(priorityNum),
+				Universe:   uint16(universeNum),
+				Host:       hostAddr,
+				Refresh:    time.Duration(refreshNum) * time.Millisecond,
+				Discovery:  discoveryBool,
+				universes:  make(map[uint16]*sacnUniverseState),
+				logger:     CreateLogger(moduleConfig),
+			}, nil
+		},
+	})
+}
+
+type sacnUniverseState struct {
+	sequence uint8
+	last     sacn.DataPacket
+	lastSent time.Time
+}
+
+type SACNSender struct {
+	config     config.ModuleConfig
+	SourceName string
+	CID        sacn.CID
+	Priority   uint8
+	Universe   uint16
+	Host       *net.UDPAddr
+	Refresh    time.Duration
+	Discovery  bool
+	conn       *net.UDPConn
+	universes  map[uint16]*sacnUniverseState
+	mu         sync.Mutex
+	ctx        context.Context
+	logger     *slog.Logger
+	cancel     context.CancelFunc
+}
+
+func (ss *SACNSender) Id() string {
+	return ss.config.Id
+}
+
+func (ss *SACNSender) Type() string {
+	return ss.config.Type
+}
+
+func (ss *SACNSender) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	ss.logger.Debug("running")
+	moduleContext, cancel := context.WithCancel(ctx)
+	ss.ctx = moduleContext
+	ss.cancel = cancel
+
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return err
+	}
+
+	ss.mu.Lock()
+	ss.conn = conn
+	ss.mu.Unlock()
+
+	var refreshTick <-chan time.Time
+	if ss.Refresh > 0 {
+		refreshTicker := time.NewTicker(max(ss.Refresh/4, time.Millisecond))
+		defer refreshTicker.Stop()
+		refreshTick = refreshTicker.C
+	}
+
+	var discoveryTick <-chan time.Time
+	if ss.Discovery {
+		discoveryTicker := time.NewTicker(sacnDiscoveryInterval)
+		defer discoveryTicker.Stop()
+		discoveryTick = discoveryTicker.C
+	}
+
+	for {
+		select {
+		case <-ss.ctx.Done():
+			ss.terminate()
+			ss.logger.Debug("done")
+			return nil
+		case <-refreshTick:
+			ss.refresh()
+		case <-discoveryTick:
+			ss.discover()
+		}
+	}
+}
+
+func (ss *SACNSender) destination(universe uint16) *net.UDPAddr {
+	if ss.Host != nil {
+		return ss.Host
+	}
+	return sacn.MulticastAddr(universe)
+}
+
+// send stamps the packet with this source's details, its priority when the packet has none, and the next sequence number for the universe, ss.mu must be held
+func (ss *SACNSender) send(packet sacn.DataPacket) error {
+	if ss.conn == nil {
+		return errors.New("sacn.sender connection is not setup")
+	}
+
+	state, ok := ss.universes[packet.Universe]
+	if !ok {
+		state = &sacnUniverseState{}
+		ss.universes[packet.Universe] = state
+	}
+
+	packet.CID = ss.CID
+	packet.SourceName = ss.SourceName
+	//NOTE(jwetzell): a priority of 0 counts as unset so packets forwarded with their own priority keep it
+	if packet.Priority == 0 {
+		packet.Priority = ss.Priority
+	}
+	packet.Sequence = state.sequence
+
+	packetBytes, err := packet.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = ss.conn.WriteToUDP(packetBytes, ss.destination(packet.Universe))
+	if err != nil {
+		return err
+	}
+
+	state.sequence++
+	state.last = packet
+	state.lastSent = time.Now()
+	return nil
+}
+
+func (ss *SACNSender) Output(ctx context.Context, payload any) error {
+	var packet sacn.DataPacket
+
+	payloadPacket, ok := common.GetAnyAs[*sacn.DataPacket](payload)
+	if ok {
+		packet = *payloadPacket
+	} else {
+		payloadBytes, ok := common.GetAnyAsByteSlice(payload)
+		if !ok {
+			return errors.New("sacn.sender can only output a sACN data packet or bytes")
+		}
+		if ss.Universe == 0 {
+			return errors.New("sacn.sender universe param is required to output bytes")
+		}
+		packet = sacn.DataPacket{
+			Universe: ss.Universe,
+			Data:     payloadBytes,
+		}
+	}
+
+	if packet.StreamTerminated {
+		return errors.New("sacn.sender stream termination is handled when the module stops")
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.send(packet)
+}
+
+func (ss *SACNSender) refresh() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	for universe, state := range ss.universes {
+		if time.Since(state.lastSent) < ss.Refresh {
+			continue
+		}
+		err := ss.send(state.last)
+		if err != nil {
+			ss.logger.Warn("unable to refresh universe", "universe", universe, "error", err)
+		}
+	}
+}
+
+func (ss *SACNSender) discover() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.conn == nil || len(ss.universes) == 0 {
+		return
+	}
+
+	universes := []uint16{}
+	for universe := range ss.universes {
+		universes = append(universes, universe)
+	}
+	slices.Sort(universes)
+
+	pages := slices.Collect(slices.Chunk(universes, 512))
+	for pageIndex, pageUniverses := range pages {
+		packetBytes, err := sacn.DiscoveryPacket{
+			CID:        ss.CID,
+			SourceName: ss.SourceName,
+			Page:       uint8(pageIndex),
+			LastPage:   uint8(len(pages) - 1),
+			Universes:  pageUniverses,
+		}.MarshalBinary()
+		if err != nil {
+			ss.logger.Warn("unable to create discovery packet", "error", err)
+			return
+		}
+		_, err = ss.conn.WriteToUDP(packetBytes, ss.destination(sacn.DiscoveryUniverse))
+		if err != nil {
+			ss.logger.Warn("unable to send discovery packet", "error", err)
+			return
+		}
+	}
+}
+
+// terminate lets receivers know this source is going away and closes the connection
+func (ss *SACNSender) terminate() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.conn == nil {
+		return
+	}
+	for universe, state := range ss.universes {
+		terminated := state.last
+		terminated.StreamTerminated = true
+		//NOTE(jwetzell): E1.31 asks for three terminated packets
+		for range 3 {
+			err := ss.send(terminated)
+			if err != nil {
+				ss.logger.Warn("unable to terminate universe", "universe", universe, "error", err)
+				break
+			}
+		}
+	}
+	ss.conn.Close()
+	ss.conn = nil
+}
+
+func (ss *SACNSender) Stop() {
+	if ss.cancel != nil {
+		ss.cancel()
+	}
+}
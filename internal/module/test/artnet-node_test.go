@@ -0,0 +1,241 @@
+package module_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/artnet-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/module"
+)
+
+func TestArtNetNodeFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("artnet.node")
+	if !ok {
+		t.Fatalf("artnet.node module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "artnet.node",
+		Params: map[string]any{
+			"universes": []any{0, 1},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create artnet.node module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("artnet.node module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "artnet.node" {
+		t.Fatalf("artnet.node module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+func TestGoodArtNetNode(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("artnet.node")
+	if !ok {
+		t.Fatalf("artnet.node module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "artnet.node",
+		Params: map[string]any{
+			"ip":             "127.0.0.1",
+			"port":           16454,
+			"host":           "127.0.0.1",
+			"shortName":      "test node",
+			"universes":      []any{1, 258},
+			"outputUniverse": 258,
+		},
+	})
+	if err != nil {
+		t.Fatalf("artnet.node failed to create module: %s", err)
+	}
+
+	var inputsMu sync.Mutex
+	inputs := []*artnet.ArtDmx{}
+	inputHandler := func(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+		dmx, ok := payload.(*artnet.ArtDmx)
+		if ok {
+			inputsMu.Lock()
+			inputs = append(inputs, dmx)
+			inputsMu.Unlock()
+		}
+		return true, nil
+	}
+
+	go moduleInstance.Start(t.Context(), inputHandler)
+	defer moduleInstance.Stop()
+
+	client, err := net.DialUDP("udp4", nil, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 16454})
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	defer client.Close()
+
+	pollBytes, err := (&artnet.ArtPoll{}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to create ArtPoll: %s", err)
+	}
+
+	replies := []*artnet.ArtPollReply{}
+	buffer := make([]byte, 2048)
+	deadline := time.Now().Add(2 * time.Second)
+	for len(replies) < 2 && time.Now().Before(deadline) {
+		client.Write(pollBytes)
+		client.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		numBytes, err := client.Read(buffer)
+		if err != nil {
+			continue
+		}
+		packet, err := artnet.Decode(buffer[:numBytes])
+		if err != nil {
+			t.Fatalf("artnet.node sent an invalid packet: %s", err)
+		}
+		reply, ok := packet.(*artnet.ArtPollReply)
+		if !ok {
+			t.Fatalf("artnet.node replied with a %T", packet)
+		}
+		replies = append(replies, reply)
+	}
+
+	if len(replies) < 2 {
+		t.Fatalf("artnet.node should send a reply per universe, got %d", len(replies))
+	}
+
+	if !strings.HasPrefix(string(replies[0].PortName[:]), "test node") {
+		t.Fatalf("artnet.node reply has wrong short name: %s", replies[0].PortName)
+	}
+
+	if replies[1].NetSwitch != 1 || replies[1].SubSwitch != 0 || replies[1].SwOut[0] != 2 {
+		t.Fatalf("artnet.node reply has wrong port address: %+v", replies[1])
+	}
+
+	for _, subUni := range []uint8{1, 2} {
+		dmxBytes, err := (&artnet.ArtDmx{SubUni: subUni, Data: []byte{subUni}}).MarshalBinary()
+		if err != nil {
+			t.Fatalf("failed to create ArtDmx: %s", err)
+		}
+		client.Write(dmxBytes)
+	}
+
+	outputModule, ok := moduleInstance.(common.OutputModule)
+	if !ok {
+		t.Fatalf("artnet.node should be an output module")
+	}
+
+	//NOTE(jwetzell): output goes back to the node itself on universe 258
+	err = outputModule.Output(t.Context(), []byte{9, 9})
+	if err != nil {
+		t.Fatalf("artnet.node output failed: %s", err)
+	}
+
+	deadline = time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		inputsMu.Lock()
+		count := len(inputs)
+		inputsMu.Unlock()
+		if count >= 2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	inputsMu.Lock()
+	defer inputsMu.Unlock()
+	if len(inputs) != 2 {
+		t.Fatalf("artnet.node should only emit subscribed universes, got %d inputs", len(inputs))
+	}
+	if inputs[0].SubUni != 1 || inputs[0].Data[0] != 1 {
+		t.Fatalf("artnet.node emitted wrong ArtDmx: %+v", inputs[0])
+	}
+	if inputs[1].Net != 1 || inputs[1].SubUni != 2 || inputs[1].Sequence != 1 {
+		t.Fatalf("artnet.node output wrong ArtDmx: %+v", inputs[1])
+	}
+}
+
+func TestBadArtNetNode(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name: "non-number port param",
+			params: map[string]any{
+				"port": "6454",
+			},
+			errorString: "artnet.node port error: not a number",
+		},
+		{
+			name: "short name too long",
+			params: map[string]any{
+				"shortName": "a very long short name",
+			},
+			errorString: "artnet.node shortName cannot be longer than 17 characters",
+		},
+		{
+			name: "universe out of range",
+			params: map[string]any{
+				"universes": []any{32768},
+			},
+			errorString: "artnet.node universe must be between 0 and 32767",
+		},
+		{
+			name: "outputUniverse out of range",
+			params: map[string]any{
+				"outputUniverse": -1,
+			},
+			errorString: "artnet.node outputUniverse must be between 0 and 32767",
+		},
+		{
+			name: "non-boolean sync param",
+			params: map[string]any{
+				"sync": 1,
+			},
+			errorString: "artnet.node sync error: not a boolean",
+		},
+		{
+			name: "invalid ip",
+			params: map[string]any{
+				"ip": "127.0.0.",
+			},
+			errorString: "lookup 127.0.0.: no such host",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := module.GetModuleRegistration("artnet.node")
+			if !ok {
+				t.Fatalf("artnet.node module not registered")
+			}
+
+			_, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "artnet.node",
+				Params: test.params,
+			})
+
+			if err == nil {
+				t.Fatalf("artnet.node expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("artnet.node got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
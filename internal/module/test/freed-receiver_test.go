@@ -0,0 +1,259 @@
+package module_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	freeD "github.com/jwetzell/free-d-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/freed"
+	"github.com/jwetzell/showbridge-go/internal/module"
+)
+
+func TestFreeDReceiverFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("freed.receiver")
+	if !ok {
+		t.Fatalf("freed.receiver module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "freed.receiver",
+		Params: map[string]any{
+			"port": 40000,
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create freed.receiver module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("freed.receiver module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "freed.receiver" {
+		t.Fatalf("freed.receiver module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+func TestGoodFreeDReceiver(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("freed.receiver")
+	if !ok {
+		t.Fatalf("freed.receiver module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "freed.receiver",
+		Params: map[string]any{
+			"ip":      "127.0.0.1",
+			"port":    40001,
+			"cameras": []any{1, 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("freed.receiver failed to create module: %s", err)
+	}
+
+	var inputsMu sync.Mutex
+	inputs := []freeD.FreeDPosition{}
+	inputHandler := func(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+		position, ok := payload.(freeD.FreeDPosition)
+		if ok {
+			inputsMu.Lock()
+			inputs = append(inputs, position)
+			inputsMu.Unlock()
+		}
+		return true, nil
+	}
+
+	go moduleInstance.Start(t.Context(), inputHandler)
+	defer moduleInstance.Stop()
+
+	client, err := net.DialUDP("udp4", nil, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 40001})
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	defer client.Close()
+
+	camera1 := freeD.Encode(freeD.FreeDPosition{ID: 1, Pan: 10, PosX: 100})
+	camera2 := freeD.Encode(freeD.FreeDPosition{ID: 2, Tilt: 5})
+	camera3 := freeD.Encode(freeD.FreeDPosition{ID: 3})
+	badChecksum := freeD.Encode(freeD.FreeDPosition{ID: 1})
+	badChecksum[28]++
+
+	//NOTE(jwetzell): two cameras packed into one datagram
+	packed := append(camera1[:], camera2[:]...)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		client.Write(badChecksum[:])
+		client.Write(camera3[:])
+		client.Write(packed)
+
+		inputsMu.Lock()
+		count := len(inputs)
+		inputsMu.Unlock()
+		if count >= 2 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	inputsMu.Lock()
+	defer inputsMu.Unlock()
+	if len(inputs) < 2 {
+		t.Fatalf("freed.receiver should emit both cameras, got %d inputs", len(inputs))
+	}
+
+	for _, input := range inputs {
+		if input.ID == 3 {
+			t.Fatalf("freed.receiver should filter out camera 3")
+		}
+	}
+
+	if inputs[0].ID != 1 || inputs[0].Pan != 10 || inputs[0].PosX != 100 || inputs[1].ID != 2 || inputs[1].Tilt != 5 {
+		t.Fatalf("freed.receiver emitted wrong positions: %+v", inputs[:2])
+	}
+
+	receiver, ok := moduleInstance.(*module.FreeDReceiver)
+	if !ok {
+		t.Fatalf("freed.receiver should be a FreeDReceiver")
+	}
+
+	//NOTE(jwetzell): the first bad packet can go out before the receiver is listening
+	var stats []freed.Stats
+	deadline = time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		client.Write(badChecksum[:])
+		time.Sleep(20 * time.Millisecond)
+		stats = receiver.Stats()
+		if len(stats) > 0 && stats[0].ChecksumErrors > 0 {
+			break
+		}
+	}
+
+	if len(stats) != 2 || stats[0].ID != 1 || stats[0].ChecksumErrors == 0 || stats[0].Received == 0 {
+		t.Fatalf("freed.receiver stats wrong: %+v", stats)
+	}
+}
+
+func TestFreeDReceiverRate(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("freed.receiver")
+	if !ok {
+		t.Fatalf("freed.receiver module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "freed.receiver",
+		Params: map[string]any{
+			"ip":        "127.0.0.1",
+			"port":      40002,
+			"rate":      100,
+			"smoothing": 0.5,
+		},
+	})
+	if err != nil {
+		t.Fatalf("freed.receiver failed to create module: %s", err)
+	}
+
+	var inputsMu sync.Mutex
+	count := 0
+	inputHandler := func(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+		inputsMu.Lock()
+		count++
+		inputsMu.Unlock()
+		return true, nil
+	}
+
+	go moduleInstance.Start(t.Context(), inputHandler)
+	defer moduleInstance.Stop()
+
+	client, err := net.DialUDP("udp4", nil, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 40002})
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	defer client.Close()
+
+	position := freeD.Encode(freeD.FreeDPosition{ID: 1})
+	for range 10 {
+		client.Write(position[:])
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	//NOTE(jwetzell): positions keep coming at the output rate after packets stop until the camera goes stale
+	inputsMu.Lock()
+	before := count
+	inputsMu.Unlock()
+	time.Sleep(200 * time.Millisecond)
+	inputsMu.Lock()
+	after := count
+	inputsMu.Unlock()
+
+	if after-before < 5 {
+		t.Fatalf("freed.receiver should keep emitting at its rate, got %d in 200ms", after-before)
+	}
+}
+
+func TestBadFreeDReceiver(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name:        "no port param",
+			params:      map[string]any{},
+			errorString: "freed.receiver port error: not found",
+		},
+		{
+			name:        "non-string ip param",
+			params:      map[string]any{"port": 40000, "ip": 1},
+			errorString: "freed.receiver ip error: not a string",
+		},
+		{
+			name:        "camera out of range",
+			params:      map[string]any{"port": 40000, "cameras": []any{300}},
+			errorString: "freed.receiver cameras must be between 0 and 255",
+		},
+		{
+			name:        "smoothing too high",
+			params:      map[string]any{"port": 40000, "smoothing": 1},
+			errorString: "freed.receiver smoothing must be at least 0 and less than 1",
+		},
+		{
+			name:        "non-number rate param",
+			params:      map[string]any{"port": 40000, "rate": "60"},
+			errorString: "freed.receiver rate error: not a number",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := module.GetModuleRegistration("freed.receiver")
+			if !ok {
+				t.Fatalf("freed.receiver module not registered")
+			}
+
+			_, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "freed.receiver",
+				Params: test.params,
+			})
+
+			if err == nil {
+				t.Fatalf("freed.receiver expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("freed.receiver got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package module_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/module"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+)
+
+func TestHTTPPollFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("http.poll")
+	if !ok {
+		t.Fatalf("http.poll module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "http.poll",
+		Params: map[string]any{
+			"url": "http://localhost/status",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create http.poll module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("http.poll module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "http.poll" {
+		t.Fatalf("http.poll module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+func TestGoodHTTPPoll(t *testing.T) {
+	var requests atomic.Int32
+	var notModified atomic.Int32
+
+	//NOTE(jwetzell): the state changes once every five requests and is tagged with its version
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := (requests.Add(1) + 4) / 5
+		etag := fmt.Sprintf(`"v%d"`, version)
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprintf(w, `{"version":%d}`, version)
+	}))
+	defer server.Close()
+
+	registration, ok := module.GetModuleRegistration("http.poll")
+	if !ok {
+		t.Fatalf("http.poll module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "http.poll",
+		Params: map[string]any{
+			"url":       server.URL,
+			"interval":  10,
+			"parseJSON": true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("http.poll failed to create module: %s", err)
+	}
+
+	var responsesMu sync.Mutex
+	responses := []processor.HTTPResponse{}
+	inputHandler := func(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+		responsesMu.Lock()
+		responses = append(responses, payload.(processor.HTTPResponse))
+		responsesMu.Unlock()
+		return true, nil
+	}
+
+	go moduleInstance.Start(t.Context(), inputHandler)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for requests.Load() < 12 {
+		if time.Now().After(deadline) {
+			t.Fatalf("http.poll timed out, only %d requests made", requests.Load())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	moduleInstance.Stop()
+
+	responsesMu.Lock()
+	got := slices.Clone(responses)
+	responsesMu.Unlock()
+
+	if len(got) < 3 {
+		t.Fatalf("http.poll should emit once per version, got %d responses", len(got))
+	}
+
+	for index, response := range got {
+		expected := map[string]any{"version": float64(index + 1)}
+		if response.Status != http.StatusOK || fmt.Sprint(response.JSON) != fmt.Sprint(expected) {
+			t.Fatalf("http.poll response %d got %d %v", index, response.Status, response.JSON)
+		}
+		if response.Headers["Etag"] != fmt.Sprintf(`"v%d"`, index+1) {
+			t.Fatalf("http.poll response %d got headers %v", index, response.Headers)
+		}
+	}
+
+	if notModified.Load() == 0 {
+		t.Fatalf("http.poll should send If-None-Match with the last ETag")
+	}
+}
+
+func TestBadHTTPPoll(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name:        "no url param",
+			params:      map[string]any{},
+			errorString: "http.poll url error: not found",
+		},
+		{
+			name:        "non-http url",
+			params:      map[string]any{"url": "ftp://localhost/status"},
+			errorString: "http.poll url error: scheme must be http or https",
+		},
+		{
+			name:        "zero interval",
+			params:      map[string]any{"url": "http://localhost/status", "interval": 0},
+			errorString: "http.poll interval must be at least 1",
+		},
+		{
+			name:        "non-numeric timeout",
+			params:      map[string]any{"url": "http://localhost/status", "timeout": "5s"},
+			errorString: "http.poll timeout error: not a number",
+		},
+		{
+			name:        "non-boolean parseJSON",
+			params:      map[string]any{"url": "http://localhost/status", "parseJSON": "yes"},
+			errorString: "http.poll parseJSON error: not a boolean",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := module.GetModuleRegistration("http.poll")
+			if !ok {
+				t.Fatalf("http.poll module not registered")
+			}
+
+			_, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "http.poll",
+				Params: test.params,
+			})
+
+			if err == nil {
+				t.Fatalf("http.poll expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("http.poll got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -1,11 +1,22 @@
 package module_test
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/jwetzell/showbridge-go/internal/common"
 	"github.com/jwetzell/showbridge-go/internal/config"
 	"github.com/jwetzell/showbridge-go/internal/module"
+	"github.com/jwetzell/showbridge-go/internal/processor"
 )
 
 func TestHTTPServerFromRegistry(t *testing.T) {
@@ -81,6 +92,156 @@ func TestGoodHTTPServer(t *testing.T) {
 	}
 }
 
+func doHTTPServerRequest(t *testing.T, method string, url string, contentType string, body string, header http.Header) (*http.Response, string) {
+	request, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("http.server failed to build request: %s", err)
+	}
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		response, err := http.DefaultClient.Do(request)
+		if err == nil {
+			defer response.Body.Close()
+			responseBody, _ := io.ReadAll(response.Body)
+			return response, string(responseBody)
+		}
+		//NOTE(jwetzell): keep trying until the server is listening
+		if time.Now().After(deadline) {
+			t.Fatalf("http.server request failed: %s", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+		request.Body = io.NopCloser(strings.NewReader(body))
+	}
+}
+
+func TestHTTPServerRouting(t *testing.T) {
+	staticDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(staticDir, "index.html"), []byte("<h1>panel</h1>"), 0o644)
+	if err != nil {
+		t.Fatalf("failed to write static file: %s", err)
+	}
+
+	registration, ok := module.GetModuleRegistration("http.server")
+	if !ok {
+		t.Fatalf("http.server module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "http.server",
+		Params: map[string]any{
+			"ip":       "127.0.0.1",
+			"port":     18767,
+			"patterns": []any{"POST /cue/{number}/go", "/status"},
+			"static":   map[string]any{"dir": staticDir, "prefix": "/panel/"},
+			"cors":     map[string]any{"origins": []any{"http://panel.local"}},
+			"auth":     map[string]any{"type": "bearer", "token": "secret"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("http.server failed to create module: %s", err)
+	}
+
+	outputModule, ok := moduleInstance.(common.OutputModule)
+	if !ok {
+		t.Fatalf("http.server should be an output module")
+	}
+
+	var requestsMu sync.Mutex
+	requests := []module.HTTPServerRequest{}
+	inputHandler := func(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+		request := payload.(module.HTTPServerRequest)
+		requestsMu.Lock()
+		requests = append(requests, request)
+		requestsMu.Unlock()
+		if request.Pattern == "/status" {
+			return false, nil
+		}
+		err := outputModule.Output(ctx, processor.HTTPResponse{
+			Status:  http.StatusAccepted,
+			Body:    []byte("going"),
+			Headers: map[string]string{"X-Cue": request.Params["number"]},
+		})
+		if err != nil {
+			return true, []common.RouteIOError{{ProcessError: err}}
+		}
+		return true, nil
+	}
+
+	go moduleInstance.Start(t.Context(), inputHandler)
+	defer moduleInstance.Stop()
+
+	authorized := http.Header{"Authorization": {"Bearer secret"}}
+
+	response, body := doHTTPServerRequest(t, "POST", "http://127.0.0.1:18767/cue/12/go?fade=3", "application/json", `{"level":50}`, authorized)
+	if response.StatusCode != http.StatusAccepted || body != "going" || response.Header.Get("X-Cue") != "12" {
+		t.Fatalf("http.server cue response got %d %q %v", response.StatusCode, body, response.Header)
+	}
+
+	requestsMu.Lock()
+	request := requests[0]
+	requestsMu.Unlock()
+	if request.Method != "POST" || request.Path != "/cue/12/go" || request.URL.Path != "/cue/12/go" {
+		t.Fatalf("http.server request got %s %s", request.Method, request.Path)
+	}
+	if !reflect.DeepEqual(request.Params, map[string]string{"number": "12"}) || request.Query.Get("fade") != "3" {
+		t.Fatalf("http.server request params got %v query %v", request.Params, request.Query)
+	}
+	if !reflect.DeepEqual(request.Body, map[string]any{"level": float64(50)}) || string(request.RawBody) != `{"level":50}` {
+		t.Fatalf("http.server request body got %#v", request.Body)
+	}
+
+	response, body = doHTTPServerRequest(t, "POST", "http://127.0.0.1:18767/status", "application/x-www-form-urlencoded", "level=50", authorized)
+	if response.StatusCode != http.StatusNotFound || !strings.Contains(body, "no matching routes found") {
+		t.Fatalf("http.server unrouted response got %d %q", response.StatusCode, body)
+	}
+
+	requestsMu.Lock()
+	request = requests[1]
+	requestsMu.Unlock()
+	if !reflect.DeepEqual(request.Body, url.Values{"level": {"50"}}) {
+		t.Fatalf("http.server form body got %#v", request.Body)
+	}
+
+	response, _ = doHTTPServerRequest(t, "POST", "http://127.0.0.1:18767/cue/12/go", "application/json", `{"level":`, authorized)
+	if response.StatusCode != http.StatusBadRequest {
+		t.Fatalf("http.server should reject a bad JSON body, got %d", response.StatusCode)
+	}
+
+	response, _ = doHTTPServerRequest(t, "GET", "http://127.0.0.1:18767/cue/12/go", "", "", authorized)
+	if response.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("http.server should only route the declared method, got %d", response.StatusCode)
+	}
+
+	response, _ = doHTTPServerRequest(t, "POST", "http://127.0.0.1:18767/cue/12/go", "", "", http.Header{"Authorization": {"Bearer wrong"}})
+	if response.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("http.server should reject a bad token, got %d", response.StatusCode)
+	}
+
+	response, body = doHTTPServerRequest(t, "GET", "http://127.0.0.1:18767/panel/", "", "", authorized)
+	if response.StatusCode != http.StatusOK || body != "<h1>panel</h1>" {
+		t.Fatalf("http.server static response got %d %q", response.StatusCode, body)
+	}
+
+	preflight := http.Header{"Origin": {"http://panel.local"}, "Access-Control-Request-Method": {"POST"}}
+	response, _ = doHTTPServerRequest(t, "OPTIONS", "http://127.0.0.1:18767/cue/12/go", "", "", preflight)
+	if response.StatusCode != http.StatusNoContent || response.Header.Get("Access-Control-Allow-Origin") != "http://panel.local" {
+		t.Fatalf("http.server preflight got %d %v", response.StatusCode, response.Header)
+	}
+
+	response, _ = doHTTPServerRequest(t, "OPTIONS", "http://127.0.0.1:18767/cue/12/go", "", "", http.Header{"Origin": {"http://other.local"}, "Access-Control-Request-Method": {"POST"}})
+	if response.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("http.server should not allow other origins, got %v", response.Header)
+	}
+}
+
 func TestBadHTTPServer(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -97,6 +258,51 @@ func TestBadHTTPServer(t *testing.T) {
 			params:      map[string]any{"port": "3000"},
 			errorString: "http.server port error: not a number",
 		},
+		{
+			name:        "cert without key",
+			params:      map[string]any{"port": 3000, "certFile": "cert.pem"},
+			errorString: "http.server certFile and keyFile must be set together",
+		},
+		{
+			name:        "non-string patterns",
+			params:      map[string]any{"port": 3000, "patterns": []any{1}},
+			errorString: "http.server patterns error: not a string slice",
+		},
+		{
+			name:        "invalid pattern",
+			params:      map[string]any{"port": 3000, "patterns": []any{"/cue/{number"}},
+			errorString: "http.server patterns error: parsing \"/cue/{number\": at offset 5: bad wildcard segment (must end with '}')",
+		},
+		{
+			name:        "unknown auth type",
+			params:      map[string]any{"port": 3000, "auth": map[string]any{"type": "digest"}},
+			errorString: "http.server auth unknown type: digest",
+		},
+		{
+			name:        "basic auth without password",
+			params:      map[string]any{"port": 3000, "auth": map[string]any{"type": "basic", "username": "show"}},
+			errorString: "http.server auth password error: not found",
+		},
+		{
+			name:        "non-object cors",
+			params:      map[string]any{"port": 3000, "cors": "*"},
+			errorString: "http.server cors error: not a map",
+		},
+		{
+			name:        "cors without origins",
+			params:      map[string]any{"port": 3000, "cors": map[string]any{}},
+			errorString: "http.server cors origins error: not found",
+		},
+		{
+			name:        "missing static dir",
+			params:      map[string]any{"port": 3000, "static": map[string]any{"dir": "/does/not/exist"}},
+			errorString: "http.server static dir error: stat /does/not/exist: no such file or directory",
+		},
+		{
+			name:        "static prefix conflicts with catch all",
+			params:      map[string]any{"port": 3000, "static": map[string]any{"dir": "."}},
+			errorString: "http.server static prefix / is already a pattern, declare patterns or use another prefix",
+		},
 	}
 
 	for _, test := range tests {
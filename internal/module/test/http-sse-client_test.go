@@ -0,0 +1,170 @@
+package module_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/module"
+	"github.com/jwetzell/showbridge-go/internal/sse"
+)
+
+func TestHTTPSSEClientFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("http.sse.client")
+	if !ok {
+		t.Fatalf("http.sse.client module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "http.sse.client",
+		Params: map[string]any{
+			"url": "http://localhost/events",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create http.sse.client module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("http.sse.client module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "http.sse.client" {
+		t.Fatalf("http.sse.client module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+func TestGoodHTTPSSEClient(t *testing.T) {
+	var lastEventIDsMu sync.Mutex
+	lastEventIDs := []string{}
+
+	//NOTE(jwetzell): every connection sends one event and closes so the client has to reconnect
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		lastEventIDsMu.Lock()
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		count := len(lastEventIDs)
+		lastEventIDsMu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "retry: 10\nid: %d\nevent: cue\ndata: cue %d\n\n", count, count)
+	}))
+	defer server.Close()
+
+	registration, ok := module.GetModuleRegistration("http.sse.client")
+	if !ok {
+		t.Fatalf("http.sse.client module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "http.sse.client",
+		Params: map[string]any{
+			"url":     server.URL,
+			"headers": map[string]any{"Authorization": "Bearer secret"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("http.sse.client failed to create module: %s", err)
+	}
+
+	var eventsMu sync.Mutex
+	events := []sse.Event{}
+	inputHandler := func(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+		eventsMu.Lock()
+		events = append(events, payload.(sse.Event))
+		eventsMu.Unlock()
+		return true, nil
+	}
+
+	go moduleInstance.Start(t.Context(), inputHandler)
+	defer moduleInstance.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		eventsMu.Lock()
+		got := slices.Clone(events)
+		eventsMu.Unlock()
+		if len(got) >= 2 {
+			if got[0] != (sse.Event{ID: "1", Event: "cue", Data: "cue 1"}) || got[1] != (sse.Event{ID: "2", Event: "cue", Data: "cue 2"}) {
+				t.Fatalf("http.sse.client got events %+v", got)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("http.sse.client timed out waiting for events, got %+v", got)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	lastEventIDsMu.Lock()
+	defer lastEventIDsMu.Unlock()
+	if lastEventIDs[0] != "" || lastEventIDs[1] != "1" {
+		t.Fatalf("http.sse.client should reconnect with the last event id, got %q", lastEventIDs)
+	}
+}
+
+func TestBadHTTPSSEClient(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name:        "no url param",
+			params:      map[string]any{},
+			errorString: "http.sse.client url error: not found",
+		},
+		{
+			name:        "non-http url",
+			params:      map[string]any{"url": "ws://localhost/events"},
+			errorString: "http.sse.client url error: scheme must be http or https",
+		},
+		{
+			name:        "non-string headers",
+			params:      map[string]any{"url": "http://localhost/events", "headers": map[string]any{"Authorization": 1}},
+			errorString: "http.sse.client headers error: not a string map",
+		},
+		{
+			name:        "negative reconnect",
+			params:      map[string]any{"url": "http://localhost/events", "reconnect": -1},
+			errorString: "http.sse.client reconnect must not be negative",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := module.GetModuleRegistration("http.sse.client")
+			if !ok {
+				t.Fatalf("http.sse.client module not registered")
+			}
+
+			_, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "http.sse.client",
+				Params: test.params,
+			})
+
+			if err == nil {
+				t.Fatalf("http.sse.client expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("http.sse.client got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
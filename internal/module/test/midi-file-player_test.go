@@ -0,0 +1,284 @@
+package module_test
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/module"
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+// writeTestMIDIFile writes a type 1 file with a tempo change halfway through and a note on each of two tracks
+func writeTestMIDIFile(t *testing.T) string {
+	midiFile := smf.NewSMF1()
+	midiFile.TimeFormat = smf.MetricTicks(96)
+
+	var tempoTrack smf.Track
+	tempoTrack.Add(0, smf.MetaTempo(120))
+	tempoTrack.Add(96, smf.MetaTempo(240))
+	tempoTrack.Close(0)
+
+	var firstTrack smf.Track
+	firstTrack.Add(0, midi.NoteOn(0, 60, 100))
+	firstTrack.Add(96, midi.NoteOff(0, 60))
+	firstTrack.Add(96, midi.NoteOn(0, 62, 100))
+	firstTrack.Add(96, midi.NoteOff(0, 62))
+	firstTrack.Close(0)
+
+	var secondTrack smf.Track
+	secondTrack.Add(96, midi.NoteOn(1, 48, 90))
+	secondTrack.Add(192, midi.NoteOff(1, 48))
+	secondTrack.Close(0)
+
+	for _, track := range []smf.Track{tempoTrack, firstTrack, secondTrack} {
+		err := midiFile.Add(track)
+		if err != nil {
+			t.Fatalf("failed to add track to test MIDI file: %s", err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "test.mid")
+	err := midiFile.WriteFile(path)
+	if err != nil {
+		t.Fatalf("failed to write test MIDI file: %s", err)
+	}
+	return path
+}
+
+type midiFileInputs struct {
+	mu       sync.Mutex
+	messages []midi.Message
+	times    []time.Time
+}
+
+func (mfi *midiFileInputs) handle(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+	message, ok := payload.(midi.Message)
+	if ok {
+		mfi.mu.Lock()
+		mfi.messages = append(mfi.messages, message)
+		mfi.times = append(mfi.times, time.Now())
+		mfi.mu.Unlock()
+	}
+	return true, nil
+}
+
+func (mfi *midiFileInputs) waitFor(t *testing.T, count int) ([]midi.Message, []time.Time) {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		mfi.mu.Lock()
+		if len(mfi.messages) >= count {
+			messages := slices.Clone(mfi.messages)
+			times := slices.Clone(mfi.times)
+			mfi.mu.Unlock()
+			return messages, times
+		}
+		mfi.mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("midi.file.player timed out waiting for %d messages, got %v", count, mfi.messages)
+	return nil, nil
+}
+
+func (mfi *midiFileInputs) reset() {
+	mfi.mu.Lock()
+	defer mfi.mu.Unlock()
+	mfi.messages = nil
+	mfi.times = nil
+}
+
+func TestMIDIFilePlayerFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("midi.file.player")
+	if !ok {
+		t.Fatalf("midi.file.player module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "midi.file.player",
+		Params: map[string]any{
+			"path": writeTestMIDIFile(t),
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create midi.file.player module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("midi.file.player module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "midi.file.player" {
+		t.Fatalf("midi.file.player module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+func TestGoodMIDIFilePlayer(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("midi.file.player")
+	if !ok {
+		t.Fatalf("midi.file.player module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "player",
+		Type: "midi.file.player",
+		Params: map[string]any{
+			"path":  writeTestMIDIFile(t),
+			"tempo": 2,
+		},
+	})
+	if err != nil {
+		t.Fatalf("midi.file.player failed to create module: %s", err)
+	}
+
+	inputs := &midiFileInputs{}
+	go moduleInstance.Start(t.Context(), inputs.handle)
+	defer moduleInstance.Stop()
+	time.Sleep(20 * time.Millisecond)
+
+	outputModule := moduleInstance.(common.OutputModule)
+	err = outputModule.Output(t.Context(), "play")
+	if err != nil {
+		t.Fatalf("midi.file.player play failed: %s", err)
+	}
+
+	expected := []midi.Message{
+		midi.NoteOn(0, 60, 100),
+		midi.NoteOff(0, 60),
+		midi.NoteOn(1, 48, 90),
+		midi.NoteOn(0, 62, 100),
+		midi.NoteOff(0, 62),
+		midi.NoteOff(1, 48),
+	}
+
+	messages, times := inputs.waitFor(t, len(expected))
+	if !slices.EqualFunc(messages, expected, slices.Equal) {
+		t.Fatalf("midi.file.player got %v, expected %v", messages, expected)
+	}
+
+	//NOTE(jwetzell): 192 ticks at 120bpm then 96 ticks at 240bpm is 1s, 500ms at double tempo
+	elapsed := times[len(times)-1].Sub(times[0])
+	if elapsed < 400*time.Millisecond || elapsed > 700*time.Millisecond {
+		t.Fatalf("midi.file.player should follow the tempo map, took %s", elapsed)
+	}
+
+	inputs.reset()
+	for _, command := range []string{"mute 2", "tempo 4", "locate 0.5", "play"} {
+		err = outputModule.Output(t.Context(), command)
+		if err != nil {
+			t.Fatalf("midi.file.player %s failed: %s", command, err)
+		}
+	}
+
+	expected = []midi.Message{
+		midi.NoteOff(0, 60),
+		midi.NoteOn(0, 62, 100),
+		midi.NoteOff(0, 62),
+	}
+	inputs.waitFor(t, len(expected))
+	//NOTE(jwetzell): give a muted message the chance to show up
+	time.Sleep(50 * time.Millisecond)
+	messages, _ = inputs.waitFor(t, len(expected))
+	if !slices.EqualFunc(messages, expected, slices.Equal) {
+		t.Fatalf("midi.file.player with track 2 muted got %v, expected %v", messages, expected)
+	}
+
+	inputs.reset()
+	for _, command := range []string{"unmute 2", "loop 0 0.5", "locate 0", "play"} {
+		err = outputModule.Output(t.Context(), command)
+		if err != nil {
+			t.Fatalf("midi.file.player %s failed: %s", command, err)
+		}
+	}
+
+	//NOTE(jwetzell): note 48 starts at the end of the loop region so it is released when the loop wraps
+	expected = []midi.Message{
+		midi.NoteOn(0, 60, 100),
+		midi.NoteOff(0, 60),
+		midi.NoteOn(1, 48, 90),
+		midi.NoteOff(1, 48),
+		midi.NoteOn(0, 60, 100),
+		midi.NoteOff(0, 60),
+		midi.NoteOn(1, 48, 90),
+		midi.NoteOff(1, 48),
+	}
+	messages, _ = inputs.waitFor(t, len(expected))
+	if !slices.EqualFunc(messages[:len(expected)], expected, slices.Equal) {
+		t.Fatalf("midi.file.player loop got %v, expected %v", messages, expected)
+	}
+
+	err = outputModule.Output(t.Context(), "stop")
+	if err != nil {
+		t.Fatalf("midi.file.player stop failed: %s", err)
+	}
+}
+
+func TestBadMIDIFilePlayer(t *testing.T) {
+	path := writeTestMIDIFile(t)
+
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name:        "no path param",
+			params:      map[string]any{},
+			errorString: "midi.file.player path error: not found",
+		},
+		{
+			name: "missing file",
+			params: map[string]any{
+				"path": "/nonexistent/test.mid",
+			},
+			errorString: "midi.file.player path error: open /nonexistent/test.mid: no such file or directory",
+		},
+		{
+			name: "tempo out of range",
+			params: map[string]any{
+				"path":  path,
+				"tempo": 0,
+			},
+			errorString: "midi.file.player tempo must be greater than 0 and at most 10",
+		},
+		{
+			name: "non-bool loop param",
+			params: map[string]any{
+				"path": path,
+				"loop": "true",
+			},
+			errorString: "midi.file.player loop error: not a boolean",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := module.GetModuleRegistration("midi.file.player")
+			if !ok {
+				t.Fatalf("midi.file.player module not registered")
+			}
+
+			_, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "midi.file.player",
+				Params: test.params,
+			})
+
+			if err == nil {
+				t.Fatalf("midi.file.player expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("midi.file.player got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
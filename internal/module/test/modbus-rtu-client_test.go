@@ -0,0 +1,108 @@
+package module_test
+
+import (
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/module"
+)
+
+func TestModbusRTUClientFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("modbus.rtu.client")
+	if !ok {
+		t.Fatalf("modbus.rtu.client module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "modbus.rtu.client",
+		Params: map[string]any{
+			"port": "/dev/ttyUSB0",
+			"polls": []any{
+				map[string]any{"table": "holdingRegisters", "address": 0, "quantity": 8},
+			},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create modbus.rtu.client module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("modbus.rtu.client module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "modbus.rtu.client" {
+		t.Fatalf("modbus.rtu.client module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+func TestBadModbusRTUClient(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name:        "no port param",
+			params:      map[string]any{},
+			errorString: "modbus.rtu.client port error: not found",
+		},
+		{
+			name: "unknown parity",
+			params: map[string]any{
+				"port":   "/dev/ttyUSB0",
+				"parity": "mark",
+			},
+			errorString: "modbus.rtu.client unknown parity: mark",
+		},
+		{
+			name: "bad stop bits",
+			params: map[string]any{
+				"port":     "/dev/ttyUSB0",
+				"stopBits": 3,
+			},
+			errorString: "modbus.rtu.client stopBits must be 1 or 2",
+		},
+		{
+			name: "unit id out of range",
+			params: map[string]any{
+				"port":   "/dev/ttyUSB0",
+				"unitId": 248,
+			},
+			errorString: "modbus.rtu.client unitId must be between 0 and 247",
+		},
+		{
+			name: "poll without address",
+			params: map[string]any{
+				"port":  "/dev/ttyUSB0",
+				"polls": []any{map[string]any{"table": "coils"}},
+			},
+			errorString: "modbus.rtu.client polls[0] address error: not found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := module.GetModuleRegistration("modbus.rtu.client")
+			if !ok {
+				t.Fatalf("modbus.rtu.client module not registered")
+			}
+
+			_, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "modbus.rtu.client",
+				Params: test.params,
+			})
+
+			if err == nil {
+				t.Fatalf("modbus.rtu.client expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("modbus.rtu.client got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package module_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/modbus"
+	"github.com/jwetzell/showbridge-go/internal/module"
+)
+
+func TestModbusTCPClientFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("modbus.tcp.client")
+	if !ok {
+		t.Fatalf("modbus.tcp.client module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "modbus.tcp.client",
+		Params: map[string]any{
+			"host": "127.0.0.1",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create modbus.tcp.client module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("modbus.tcp.client module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "modbus.tcp.client" {
+		t.Fatalf("modbus.tcp.client module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+func TestGoodModbusTCPClient(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("modbus.tcp.server")
+	if !ok {
+		t.Fatalf("modbus.tcp.server module not registered")
+	}
+
+	server, err := registration.New(config.ModuleConfig{
+		Id:   "server",
+		Type: "modbus.tcp.server",
+		Params: map[string]any{
+			"ip":   "127.0.0.1",
+			"port": 15503,
+		},
+	})
+	if err != nil {
+		t.Fatalf("modbus.tcp.server failed to create module: %s", err)
+	}
+
+	registration, ok = module.GetModuleRegistration("modbus.tcp.client")
+	if !ok {
+		t.Fatalf("modbus.tcp.client module not registered")
+	}
+
+	client, err := registration.New(config.ModuleConfig{
+		Id:   "client",
+		Type: "modbus.tcp.client",
+		Params: map[string]any{
+			"host":     "127.0.0.1",
+			"port":     15503,
+			"interval": 20,
+			"polls": []any{
+				map[string]any{"table": "coils", "address": 0, "quantity": 4},
+				map[string]any{"table": "inputRegisters", "address": 10, "quantity": 2},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("modbus.tcp.client failed to create module: %s", err)
+	}
+
+	inputs := &modbusInputs{blocks: map[string][]modbus.Block{}}
+	go server.Start(t.Context(), inputs.handle)
+	defer server.Stop()
+	go client.Start(t.Context(), inputs.handle)
+	defer client.Stop()
+
+	//NOTE(jwetzell): the first poll of each block is always emitted
+	initial := inputs.waitFor(t, "client", 2)
+	if !reflect.DeepEqual(initial[0], modbus.Block{Table: modbus.TableCoils, Address: 0, Bits: []bool{false, false, false, false}}) {
+		t.Fatalf("modbus.tcp.client first coil poll got %+v", initial[0])
+	}
+
+	err = server.(common.OutputModule).Output(t.Context(), modbus.Block{Table: modbus.TableInputRegisters, Address: 11, Registers: []uint16{500}})
+	if err != nil {
+		t.Fatalf("modbus.tcp.server output failed: %s", err)
+	}
+
+	changed := inputs.waitFor(t, "client", 3)
+	expected := modbus.Block{Table: modbus.TableInputRegisters, Address: 10, Registers: []uint16{0, 500}}
+	if !reflect.DeepEqual(changed[2], expected) {
+		t.Fatalf("modbus.tcp.client changed poll got %+v, expected %+v", changed[2], expected)
+	}
+
+	err = client.(common.OutputModule).Output(t.Context(), modbus.WriteSingleCoil(2, true))
+	if err != nil {
+		t.Fatalf("modbus.tcp.client write failed: %s", err)
+	}
+
+	written := inputs.waitFor(t, "server", 1)
+	if !reflect.DeepEqual(written[0], modbus.Block{Table: modbus.TableCoils, Address: 2, Bits: []bool{true}}) {
+		t.Fatalf("modbus.tcp.server saw write %+v", written[0])
+	}
+
+	changed = inputs.waitFor(t, "client", 4)
+	if !reflect.DeepEqual(changed[3].Bits, []bool{false, false, true, false}) {
+		t.Fatalf("modbus.tcp.client should see its own coil write, got %+v", changed[3])
+	}
+
+	err = client.(common.OutputModule).Output(t.Context(), modbus.WriteSingleRegister(1000, 1))
+	if err == nil || err.Error() != "modbus.tcp.client modbus exception 2 (illegal data address) for function 6" {
+		t.Fatalf("modbus.tcp.client should return exceptions from writes, got %v", err)
+	}
+
+	err = client.(common.OutputModule).Output(t.Context(), "coil")
+	if err == nil || err.Error() != "modbus.tcp.client can only output a modbus.PDU or modbus.Block" {
+		t.Fatalf("modbus.tcp.client should reject other payloads, got %v", err)
+	}
+}
+
+func TestBadModbusTCPClient(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name:        "no host param",
+			params:      map[string]any{},
+			errorString: "modbus.tcp.client host error: not found",
+		},
+		{
+			name: "unit id out of range",
+			params: map[string]any{
+				"host":   "127.0.0.1",
+				"unitId": 256,
+			},
+			errorString: "modbus.tcp.client unitId must be between 0 and 255",
+		},
+		{
+			name: "interval too short",
+			params: map[string]any{
+				"host":     "127.0.0.1",
+				"interval": 1,
+			},
+			errorString: "modbus.tcp.client interval must be at least 10",
+		},
+		{
+			name: "non-slice polls param",
+			params: map[string]any{
+				"host":  "127.0.0.1",
+				"polls": "coils",
+			},
+			errorString: "modbus.tcp.client polls error: not a slice",
+		},
+		{
+			name: "unknown table",
+			params: map[string]any{
+				"host":  "127.0.0.1",
+				"polls": []any{map[string]any{"table": "registers", "address": 0}},
+			},
+			errorString: "modbus.tcp.client polls[0] table error: unknown modbus table: registers",
+		},
+		{
+			name: "too many registers",
+			params: map[string]any{
+				"host":  "127.0.0.1",
+				"polls": []any{map[string]any{"table": "holdingRegisters", "address": 0, "quantity": 126}},
+			},
+			errorString: "modbus.tcp.client polls[0] error: modbus read quantity must be between 1 and 125",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := module.GetModuleRegistration("modbus.tcp.client")
+			if !ok {
+				t.Fatalf("modbus.tcp.client module not registered")
+			}
+
+			_, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "modbus.tcp.client",
+				Params: test.params,
+			})
+
+			if err == nil {
+				t.Fatalf("modbus.tcp.client expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("modbus.tcp.client got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
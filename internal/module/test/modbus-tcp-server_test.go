@@ -0,0 +1,226 @@
+package module_test
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/modbus"
+	"github.com/jwetzell/showbridge-go/internal/module"
+)
+
+func TestModbusTCPServerFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("modbus.tcp.server")
+	if !ok {
+		t.Fatalf("modbus.tcp.server module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "modbus.tcp.server",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create modbus.tcp.server module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("modbus.tcp.server module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "modbus.tcp.server" {
+		t.Fatalf("modbus.tcp.server module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+type modbusInputs struct {
+	mu     sync.Mutex
+	blocks map[string][]modbus.Block
+}
+
+func (mi *modbusInputs) handle(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+	block, ok := payload.(modbus.Block)
+	if ok {
+		mi.mu.Lock()
+		mi.blocks[sourceId] = append(mi.blocks[sourceId], block)
+		mi.mu.Unlock()
+	}
+	return true, nil
+}
+
+func (mi *modbusInputs) waitFor(t *testing.T, sourceId string, count int) []modbus.Block {
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		mi.mu.Lock()
+		blocks := slices.Clone(mi.blocks[sourceId])
+		mi.mu.Unlock()
+		if len(blocks) >= count {
+			return blocks
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("modbus timed out waiting for %d blocks from %s", count, sourceId)
+	return nil
+}
+
+func TestGoodModbusTCPServer(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("modbus.tcp.server")
+	if !ok {
+		t.Fatalf("modbus.tcp.server module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "server",
+		Type: "modbus.tcp.server",
+		Params: map[string]any{
+			"ip":               "127.0.0.1",
+			"port":             15502,
+			"unitId":           1,
+			"holdingRegisters": 10,
+		},
+	})
+	if err != nil {
+		t.Fatalf("modbus.tcp.server failed to create module: %s", err)
+	}
+
+	inputs := &modbusInputs{blocks: map[string][]modbus.Block{}}
+	go moduleInstance.Start(t.Context(), inputs.handle)
+	defer moduleInstance.Stop()
+
+	var conn net.Conn
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		conn, err = net.Dial("tcp", "127.0.0.1:15502")
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("modbus.tcp.server never started listening: %s", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	defer conn.Close()
+	transport := modbus.NewTCPTransport(conn, time.Second)
+
+	write, _ := modbus.WriteMultipleRegisters(4, []uint16{11, 22})
+	response, err := transport.Send(1, write)
+	if err != nil {
+		t.Fatalf("modbus.tcp.server write failed: %s", err)
+	}
+	err = modbus.CheckResponse(write, response)
+	if err != nil {
+		t.Fatalf("modbus.tcp.server write returned an error: %s", err)
+	}
+
+	written := inputs.waitFor(t, "server", 1)
+	expected := modbus.Block{Table: modbus.TableHoldingRegisters, Address: 4, Registers: []uint16{11, 22}}
+	if !reflect.DeepEqual(written[0], expected) {
+		t.Fatalf("modbus.tcp.server emitted %+v, expected %+v", written[0], expected)
+	}
+
+	outputModule := moduleInstance.(common.OutputModule)
+	err = outputModule.Output(t.Context(), modbus.Block{Table: modbus.TableInputRegisters, Address: 0, Registers: []uint16{99}})
+	if err != nil {
+		t.Fatalf("modbus.tcp.server block output failed: %s", err)
+	}
+
+	read, _ := modbus.ReadRequest(modbus.TableInputRegisters, 0, 1)
+	response, err = transport.Send(1, read)
+	if err != nil {
+		t.Fatalf("modbus.tcp.server read failed: %s", err)
+	}
+	block, err := modbus.ParseReadResponse(read, response)
+	if err != nil {
+		t.Fatalf("modbus.tcp.server read returned an error: %s", err)
+	}
+	if !slices.Equal(block.Registers, []uint16{99}) {
+		t.Fatalf("modbus.tcp.server read got %v", block.Registers)
+	}
+
+	read, _ = modbus.ReadRequest(modbus.TableHoldingRegisters, 3, 3)
+	err = outputModule.Output(t.Context(), read)
+	if err != nil {
+		t.Fatalf("modbus.tcp.server read output failed: %s", err)
+	}
+	readBack := inputs.waitFor(t, "server", 2)
+	if !slices.Equal(readBack[1].Registers, []uint16{0, 11, 22}) {
+		t.Fatalf("modbus.tcp.server read output emitted %+v", readBack[1])
+	}
+
+	read, _ = modbus.ReadRequest(modbus.TableHoldingRegisters, 9, 2)
+	response, err = transport.Send(1, read)
+	if err != nil {
+		t.Fatalf("modbus.tcp.server read failed: %s", err)
+	}
+	err = modbus.CheckResponse(read, response)
+	if err == nil || err.Error() != "modbus exception 2 (illegal data address) for function 3" {
+		t.Fatalf("modbus.tcp.server should answer reads past the map with an exception, got %v", err)
+	}
+}
+
+func TestBadModbusTCPServer(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name: "non-number port param",
+			params: map[string]any{
+				"port": "502",
+			},
+			errorString: "modbus.tcp.server port error: not a number",
+		},
+		{
+			name: "unit id out of range",
+			params: map[string]any{
+				"unitId": 256,
+			},
+			errorString: "modbus.tcp.server unitId must be between 0 and 255",
+		},
+		{
+			name: "table size out of range",
+			params: map[string]any{
+				"coils": 65537,
+			},
+			errorString: "modbus.tcp.server coils must be between 0 and 65536",
+		},
+		{
+			name: "non-number table size",
+			params: map[string]any{
+				"inputRegisters": "10",
+			},
+			errorString: "modbus.tcp.server inputRegisters error: not a number",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := module.GetModuleRegistration("modbus.tcp.server")
+			if !ok {
+				t.Fatalf("modbus.tcp.server module not registered")
+			}
+
+			_, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "modbus.tcp.server",
+				Params: test.params,
+			})
+
+			if err == nil {
+				t.Fatalf("modbus.tcp.server expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("modbus.tcp.server got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -1,10 +1,16 @@
 package module_test
 
 import (
+	"context"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/jwetzell/showbridge-go/internal/common"
 	"github.com/jwetzell/showbridge-go/internal/config"
 	"github.com/jwetzell/showbridge-go/internal/module"
+	"github.com/nats-io/nats-server/v2/server"
 )
 
 func TestMQTTClientFromRegistry(t *testing.T) {
@@ -36,6 +42,172 @@ func TestMQTTClientFromRegistry(t *testing.T) {
 	}
 }
 
+type mqttClientInputs struct {
+	mu       sync.Mutex
+	payloads []any
+	statuses []string
+}
+
+func (mci *mqttClientInputs) report(status string, err error) {
+	mci.mu.Lock()
+	mci.statuses = append(mci.statuses, status)
+	mci.mu.Unlock()
+}
+
+func (mci *mqttClientInputs) waitForStatus(t *testing.T, status string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		mci.mu.Lock()
+		found := slices.Contains(mci.statuses, status)
+		mci.mu.Unlock()
+		if found {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("mqtt.client timed out waiting for %s status", status)
+}
+
+func (mci *mqttClientInputs) handle(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+	mci.mu.Lock()
+	mci.payloads = append(mci.payloads, payload)
+	mci.mu.Unlock()
+	return true, nil
+}
+
+func (mci *mqttClientInputs) waitFor(t *testing.T, match func(any) bool) any {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		mci.mu.Lock()
+		payloads := slices.Clone(mci.payloads)
+		mci.mu.Unlock()
+		for _, payload := range payloads {
+			if match(payload) {
+				return payload
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("mqtt.client timed out waiting for input")
+	return nil
+}
+
+func TestGoodMQTTClient(t *testing.T) {
+	//NOTE(jwetzell): nats-server speaks MQTT so it stands in for a broker
+	broker, err := server.NewServer(&server.Options{
+		Host:       "127.0.0.1",
+		Port:       -1,
+		ServerName: "mqtt-test",
+		JetStream:  true,
+		StoreDir:   t.TempDir(),
+		MQTT:       server.MQTTOpts{Host: "127.0.0.1", Port: 18883},
+		NoLog:      true,
+		NoSigs:     true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create broker: %s", err)
+	}
+	broker.Start()
+	defer broker.Shutdown()
+	if !broker.ReadyForConnections(5 * time.Second) {
+		t.Fatalf("broker failed to start")
+	}
+
+	registration, ok := module.GetModuleRegistration("mqtt.client")
+	if !ok {
+		t.Fatalf("mqtt.client module not registered")
+	}
+
+	subscriber, err := registration.New(config.ModuleConfig{
+		Id:   "subscriber",
+		Type: "mqtt.client",
+		Params: map[string]any{
+			"broker":   "tcp://127.0.0.1:18883",
+			"clientId": "subscriber",
+			"subscriptions": []any{
+				map[string]any{"topic": "show/+/level", "qos": 1},
+				map[string]any{"topic": "status/#"},
+			},
+			"cleanSession": true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("mqtt.client failed to create subscriber: %s", err)
+	}
+
+	publisher, err := registration.New(config.ModuleConfig{
+		Id:   "publisher",
+		Type: "mqtt.client",
+		Params: map[string]any{
+			"broker":       "tcp://127.0.0.1:18883",
+			"clientId":     "publisher",
+			"outputTopic":  "show/{{.Payload}}/level",
+			"cleanSession": true,
+			"will":         map[string]any{"topic": "status/publisher", "payload": "offline"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("mqtt.client failed to create publisher: %s", err)
+	}
+
+	subscriberInputs := &mqttClientInputs{}
+	go subscriber.Start(common.WithModuleStatusReporter(t.Context(), subscriberInputs.report), subscriberInputs.handle)
+	defer subscriber.Stop()
+
+	publisherInputs := &mqttClientInputs{}
+	go publisher.Start(common.WithModuleStatusReporter(t.Context(), publisherInputs.report), publisherInputs.handle)
+	defer publisher.Stop()
+
+	subscriberInputs.waitForStatus(t, "connected")
+	publisherInputs.waitForStatus(t, "connected")
+
+	outputModule, ok := publisher.(common.OutputModule)
+	if !ok {
+		t.Fatalf("mqtt.client should be an output module")
+	}
+
+	err = outputModule.Output(t.Context(), "3")
+	if err != nil {
+		t.Fatalf("mqtt.client output failed: %s", err)
+	}
+
+	err = outputModule.Output(t.Context(), module.MQTTMessage{Topic: "status/console/main", Payload: []byte("online")})
+	if err != nil {
+		t.Fatalf("mqtt.client message output failed: %s", err)
+	}
+
+	levelMessage := subscriberInputs.waitFor(t, func(payload any) bool {
+		message, ok := payload.(module.MQTTMessage)
+		return ok && message.Topic == "show/3/level"
+	}).(module.MQTTMessage)
+	if levelMessage.Subscription != "show/+/level" || string(levelMessage.Payload) != "3" {
+		t.Fatalf("mqtt.client level message got %+v", levelMessage)
+	}
+
+	statusMessage := subscriberInputs.waitFor(t, func(payload any) bool {
+		message, ok := payload.(module.MQTTMessage)
+		return ok && message.Topic == "status/console/main"
+	}).(module.MQTTMessage)
+	if statusMessage.Subscription != "status/#" || string(statusMessage.Payload) != "online" {
+		t.Fatalf("mqtt.client status message got %+v", statusMessage)
+	}
+
+	//NOTE(jwetzell): routes on an mqtt.client should only ever see messages
+	subscriberInputs.mu.Lock()
+	for _, payload := range subscriberInputs.payloads {
+		_, ok := payload.(module.MQTTMessage)
+		if !ok {
+			t.Fatalf("mqtt.client emitted a %T input", payload)
+		}
+	}
+	subscriberInputs.mu.Unlock()
+
+	err = outputModule.Output(t.Context(), "show/#")
+	if err == nil || err.Error() != `mqtt.client can't publish to topic "show/show/#/level"` {
+		t.Fatalf("mqtt.client should refuse to publish to a wildcard topic, got %v", err)
+	}
+}
+
 func TestBadMQTTClient(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -59,14 +231,6 @@ func TestBadMQTTClient(t *testing.T) {
 			},
 			errorString: "mqtt.client broker error: not a string",
 		},
-		{
-			name: "no topic param",
-			params: map[string]any{
-				"broker":   "mqtt://localhost:1883",
-				"clientId": "test",
-			},
-			errorString: "mqtt.client topic error: not found",
-		},
 		{
 			name: "non-string topic",
 			params: map[string]any{
@@ -93,6 +257,96 @@ func TestBadMQTTClient(t *testing.T) {
 			},
 			errorString: "mqtt.client clientId error: not a string",
 		},
+		{
+			name: "non-array subscriptions",
+			params: map[string]any{
+				"broker":        "mqtt://localhost:1883",
+				"clientId":      "test",
+				"subscriptions": "test/topic",
+			},
+			errorString: "mqtt.client subscriptions error: not an array",
+		},
+		{
+			name: "subscription without topic",
+			params: map[string]any{
+				"broker":        "mqtt://localhost:1883",
+				"clientId":      "test",
+				"subscriptions": []any{map[string]any{"qos": 1}},
+			},
+			errorString: "mqtt.client subscriptions[0] topic error: not found",
+		},
+		{
+			name: "subscription qos out of range",
+			params: map[string]any{
+				"broker":        "mqtt://localhost:1883",
+				"clientId":      "test",
+				"subscriptions": []any{map[string]any{"topic": "test/topic", "qos": 3}},
+			},
+			errorString: "mqtt.client subscriptions[0] qos must be 0, 1 or 2",
+		},
+		{
+			name: "multi level wildcard not last",
+			params: map[string]any{
+				"broker":   "mqtt://localhost:1883",
+				"clientId": "test",
+				"topic":    "test/#/topic",
+			},
+			errorString: "mqtt.client subscription test/#/topic error: # must be the last level",
+		},
+		{
+			name: "partial level wildcard",
+			params: map[string]any{
+				"broker":        "mqtt://localhost:1883",
+				"clientId":      "test",
+				"subscriptions": []any{map[string]any{"topic": "test/fader+"}},
+			},
+			errorString: "mqtt.client subscription test/fader+ error: wildcards must take up a whole level",
+		},
+		{
+			name: "outputTopic template error",
+			params: map[string]any{
+				"broker":      "mqtt://localhost:1883",
+				"clientId":    "test",
+				"outputTopic": "{{.Payload",
+			},
+			errorString: "mqtt.client outputTopic error: template: outputTopic:1: unclosed action",
+		},
+		{
+			name: "cert without key",
+			params: map[string]any{
+				"broker":   "mqtts://localhost:8883",
+				"clientId": "test",
+				"tls":      map[string]any{"certFile": "client.pem"},
+			},
+			errorString: "mqtt.client tls certFile and keyFile must be set together",
+		},
+		{
+			name: "missing ca file",
+			params: map[string]any{
+				"broker":   "mqtts://localhost:8883",
+				"clientId": "test",
+				"tls":      map[string]any{"caFile": "/does/not/exist.pem"},
+			},
+			errorString: "mqtt.client tls caFile error: open /does/not/exist.pem: no such file or directory",
+		},
+		{
+			name: "will without payload",
+			params: map[string]any{
+				"broker":   "mqtt://localhost:1883",
+				"clientId": "test",
+				"will":     map[string]any{"topic": "test/status"},
+			},
+			errorString: "mqtt.client will payload error: not found",
+		},
+		{
+			name: "will with wildcard",
+			params: map[string]any{
+				"broker":   "mqtt://localhost:1883",
+				"clientId": "test",
+				"will":     map[string]any{"topic": "test/#", "payload": "offline"},
+			},
+			errorString: "mqtt.client will topic must not contain wildcards",
+		},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,327 @@
+package module_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/jwetzell/osc-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/module"
+)
+
+func TestOSCQueryServerFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("osc.query.server")
+	if !ok {
+		t.Fatalf("osc.query.server module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "osc.query.server",
+		Params: map[string]any{
+			"port": 8000,
+			"addresses": []any{
+				map[string]any{"address": "/fader", "types": "f"},
+			},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create osc.query.server module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("osc.query.server module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "osc.query.server" {
+		t.Fatalf("osc.query.server module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+func getOSCQuery(t *testing.T, url string) (int, map[string]any) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		response, err := http.Get(url)
+		if err != nil {
+			if time.Now().After(deadline) {
+				t.Fatalf("osc.query.server did not answer %s: %s", url, err)
+			}
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+		defer response.Body.Close()
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			t.Fatalf("failed to read osc.query.server response: %s", err)
+		}
+		data := map[string]any{}
+		if len(body) > 0 {
+			err = json.Unmarshal(body, &data)
+			if err != nil {
+				t.Fatalf("osc.query.server returned invalid JSON: %s", body)
+			}
+		}
+		return response.StatusCode, data
+	}
+}
+
+func TestGoodOSCQueryServer(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("osc.query.server")
+	if !ok {
+		t.Fatalf("osc.query.server module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "osc.query.server",
+		Params: map[string]any{
+			"ip":      "127.0.0.1",
+			"port":    18765,
+			"oscPort": 18766,
+			"name":    "showbridge",
+			"addresses": []any{
+				map[string]any{
+					"address":     "/mixer/fader/1",
+					"types":       "f",
+					"description": "fader one",
+					"value":       []any{0.5},
+					"range":       []any{map[string]any{"min": 0, "max": 1}},
+				},
+				map[string]any{"address": "/mixer/meter", "types": "f", "access": "r"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("osc.query.server failed to create module: %s", err)
+	}
+
+	var inputsMu sync.Mutex
+	inputs := []*osc.OSCMessage{}
+	inputHandler := func(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+		message, ok := payload.(*osc.OSCMessage)
+		if ok {
+			inputsMu.Lock()
+			inputs = append(inputs, message)
+			inputsMu.Unlock()
+		}
+		return true, nil
+	}
+
+	go moduleInstance.Start(t.Context(), inputHandler)
+	defer moduleInstance.Stop()
+
+	status, hostInfo := getOSCQuery(t, "http://127.0.0.1:18765/?HOST_INFO")
+	if status != http.StatusOK || hostInfo["NAME"] != "showbridge" || hostInfo["OSC_PORT"] != float64(18766) {
+		t.Fatalf("osc.query.server HOST_INFO wrong: %d %+v", status, hostInfo)
+	}
+
+	status, fader := getOSCQuery(t, "http://127.0.0.1:18765/mixer/fader/1")
+	if status != http.StatusOK || fader["TYPE"] != "f" || fader["DESCRIPTION"] != "fader one" || fader["ACCESS"] != float64(3) {
+		t.Fatalf("osc.query.server node wrong: %d %+v", status, fader)
+	}
+
+	status, _ = getOSCQuery(t, "http://127.0.0.1:18765/mixer/meter?VALUE")
+	if status != http.StatusNoContent {
+		t.Fatalf("osc.query.server should answer 204 for a missing attribute, got %d", status)
+	}
+
+	status, _ = getOSCQuery(t, "http://127.0.0.1:18765/nope")
+	if status != http.StatusNotFound {
+		t.Fatalf("osc.query.server should answer 404 for a missing node, got %d", status)
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://127.0.0.1:18765/", nil)
+	if err != nil {
+		t.Fatalf("failed to open websocket: %s", err)
+	}
+	defer conn.Close()
+
+	err = conn.WriteJSON(map[string]any{"COMMAND": "LISTEN", "DATA": "/mixer/meter"})
+	if err != nil {
+		t.Fatalf("failed to send LISTEN: %s", err)
+	}
+
+	outputModule, ok := moduleInstance.(common.OutputModule)
+	if !ok {
+		t.Fatalf("osc.query.server should be an output module")
+	}
+
+	pushedMessages := make(chan []byte, 1)
+	go func() {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		messageType, data, err := conn.ReadMessage()
+		if err != nil || messageType != websocket.BinaryMessage {
+			close(pushedMessages)
+			return
+		}
+		pushedMessages <- data
+	}()
+
+	//NOTE(jwetzell): the LISTEN is handled asynchronously so keep pushing until it lands
+	var pushed *osc.OSCMessage
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+PUSH_LOOP:
+	for {
+		err = outputModule.Output(t.Context(), &osc.OSCMessage{Address: "/mixer/meter", Args: []osc.OSCArg{{Type: "f", Value: float32(0.25)}}})
+		if err != nil {
+			t.Fatalf("osc.query.server output failed: %s", err)
+		}
+		select {
+		case data, ok := <-pushedMessages:
+			if ok {
+				pushed, err = osc.MessageFromBytes(data)
+				if err != nil {
+					t.Fatalf("osc.query.server pushed invalid OSC: %s", err)
+				}
+			}
+			break PUSH_LOOP
+		case <-ticker.C:
+		}
+	}
+
+	if pushed == nil || pushed.Address != "/mixer/meter" {
+		t.Fatalf("osc.query.server did not push the output to the listener: %+v", pushed)
+	}
+
+	status, meterValue := getOSCQuery(t, "http://127.0.0.1:18765/mixer/meter?VALUE")
+	if status != http.StatusOK || meterValue["VALUE"].([]any)[0] != 0.25 {
+		t.Fatalf("osc.query.server output should update VALUE: %d %+v", status, meterValue)
+	}
+
+	client, err := net.DialUDP("udp4", nil, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 18766})
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	defer client.Close()
+
+	for _, message := range []*osc.OSCMessage{
+		{Address: "/mixer/meter", Args: []osc.OSCArg{{Type: "f", Value: float32(1)}}},
+		{Address: "/mixer/fader/1", Args: []osc.OSCArg{{Type: "i", Value: int32(1)}}},
+		{Address: "/mixer/fader/1", Args: []osc.OSCArg{{Type: "f", Value: float32(0.75)}}},
+	} {
+		messageBytes, err := message.ToBytes()
+		if err != nil {
+			t.Fatalf("failed to encode OSC: %s", err)
+		}
+		client.Write(messageBytes)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		inputsMu.Lock()
+		count := len(inputs)
+		inputsMu.Unlock()
+		if count >= 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	inputsMu.Lock()
+	defer inputsMu.Unlock()
+	if len(inputs) != 1 || inputs[0].Address != "/mixer/fader/1" {
+		t.Fatalf("osc.query.server should only route writable, correctly typed OSC, got %+v", inputs)
+	}
+
+	status, faderValue := getOSCQuery(t, "http://127.0.0.1:18765/mixer/fader/1?VALUE")
+	if status != http.StatusOK || faderValue["VALUE"].([]any)[0] != 0.75 {
+		t.Fatalf("osc.query.server incoming OSC should update VALUE: %d %+v", status, faderValue)
+	}
+}
+
+func TestBadOSCQueryServer(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name:        "no port param",
+			params:      map[string]any{"addresses": []any{}},
+			errorString: "osc.query.server port error: not found",
+		},
+		{
+			name:        "non-number oscPort param",
+			params:      map[string]any{"port": 8000, "oscPort": "9000", "addresses": []any{}},
+			errorString: "osc.query.server oscPort error: not a number",
+		},
+		{
+			name:        "no addresses param",
+			params:      map[string]any{"port": 8000},
+			errorString: "osc.query.server addresses error: not found",
+		},
+		{
+			name:        "address not a map",
+			params:      map[string]any{"port": 8000, "addresses": []any{"/fader"}},
+			errorString: "osc.query.server addresses[0] error: not a map",
+		},
+		{
+			name:        "address missing types",
+			params:      map[string]any{"port": 8000, "addresses": []any{map[string]any{"address": "/fader"}}},
+			errorString: "osc.query.server addresses[0] types error: not found",
+		},
+		{
+			name: "bad access",
+			params: map[string]any{"port": 8000, "addresses": []any{
+				map[string]any{"address": "/fader", "types": "f", "access": "x"},
+			}},
+			errorString: "osc.query.server addresses[0] access error: OSCQuery access must be r, w or rw, got x",
+		},
+		{
+			name: "value length",
+			params: map[string]any{"port": 8000, "addresses": []any{
+				map[string]any{"address": "/fader", "types": "ff", "value": []any{1}},
+			}},
+			errorString: "osc.query.server addresses[0] value must have one entry per type",
+		},
+		{
+			name: "range min not a number",
+			params: map[string]any{"port": 8000, "addresses": []any{
+				map[string]any{"address": "/fader", "types": "f", "range": []any{map[string]any{"min": "0"}}},
+			}},
+			errorString: "osc.query.server addresses[0] range[0] min error: not a number",
+		},
+		{
+			name: "duplicate address",
+			params: map[string]any{"port": 8000, "addresses": []any{
+				map[string]any{"address": "/fader", "types": "f"},
+				map[string]any{"address": "/fader", "types": "f"},
+			}},
+			errorString: "osc.query.server addresses[1] error: OSCQuery path /fader is already in the namespace",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := module.GetModuleRegistration("osc.query.server")
+			if !ok {
+				t.Fatalf("osc.query.server module not registered")
+			}
+
+			_, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "osc.query.server",
+				Params: test.params,
+			})
+
+			if err == nil {
+				t.Fatalf("osc.query.server expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("osc.query.server got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
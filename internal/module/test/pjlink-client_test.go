@@ -0,0 +1,345 @@
+package module_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/module"
+	"github.com/jwetzell/showbridge-go/internal/pjlink"
+)
+
+type pjlinkInputs struct {
+	mu       sync.Mutex
+	statuses []pjlink.Status
+	replies  []pjlink.Reply
+}
+
+func (pi *pjlinkInputs) handle(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+	switch payload := payload.(type) {
+	case pjlink.Status:
+		pi.statuses = append(pi.statuses, payload)
+	case pjlink.Reply:
+		pi.replies = append(pi.replies, payload)
+	}
+	return true, nil
+}
+
+func (pi *pjlinkInputs) waitForStatus(t *testing.T, match func(pjlink.Status) bool) pjlink.Status {
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		pi.mu.Lock()
+		statuses := slices.Clone(pi.statuses)
+		pi.mu.Unlock()
+		for _, status := range statuses {
+			if match(status) {
+				return status
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("pjlink.client timed out waiting for status")
+	return pjlink.Status{}
+}
+
+// fakeProjector answers a handful of class 1 commands and requires authentication
+func fakeProjector(t *testing.T, address string) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	var mu sync.Mutex
+	power := "0"
+	input := "31"
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.Write([]byte("PJLINK 1 498e4a67\r"))
+				reader := bufio.NewReader(conn)
+				authenticated := false
+				for {
+					line, err := reader.ReadString('\r')
+					if err != nil {
+						return
+					}
+					if !authenticated {
+						if !strings.HasPrefix(line, pjlink.AuthDigest("498e4a67", "secret")) {
+							conn.Write([]byte("PJLINK ERRA\r"))
+							return
+						}
+						authenticated = true
+						line = line[32:]
+					}
+
+					command := strings.TrimSpace(line[2:6])
+					parameter := strings.TrimSpace(line[7:])
+					value := "OK"
+					mu.Lock()
+					switch {
+					case command == "POWR" && parameter == "?":
+						value = power
+					case command == "POWR":
+						power = parameter
+					case command == "INPT" && parameter == "?":
+						value = input
+					case command == "INPT" && (parameter == "31" || parameter == "32"):
+						input = parameter
+					case command == "INPT":
+						value = "ERR2"
+					case command == "LAMP":
+						value = "1200 1"
+					case command == "ERST":
+						value = "000000"
+					}
+					mu.Unlock()
+					conn.Write([]byte("%1" + command + "=" + value + "\r"))
+				}
+			}()
+		}
+	}()
+}
+
+func TestPJLinkClientFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("pjlink.client")
+	if !ok {
+		t.Fatalf("pjlink.client module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "pjlink.client",
+		Params: map[string]any{
+			"projectors": []any{
+				map[string]any{"name": "left", "host": "10.0.0.10"},
+				map[string]any{"name": "right", "host": "10.0.0.11", "class": 2, "password": "secret"},
+			},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create pjlink.client module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("pjlink.client module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "pjlink.client" {
+		t.Fatalf("pjlink.client module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+func TestGoodPJLinkClient(t *testing.T) {
+	fakeProjector(t, "127.0.0.1:14352")
+
+	registration, ok := module.GetModuleRegistration("pjlink.client")
+	if !ok {
+		t.Fatalf("pjlink.client module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "pjlink.client",
+		Params: map[string]any{
+			"projectors": []any{
+				map[string]any{"name": "main", "host": "127.0.0.1", "port": 14352, "password": "secret"},
+			},
+			"interval": 50,
+		},
+	})
+	if err != nil {
+		t.Fatalf("pjlink.client failed to create module: %s", err)
+	}
+
+	inputs := &pjlinkInputs{}
+	go moduleInstance.Start(t.Context(), inputs.handle)
+	defer moduleInstance.Stop()
+
+	status := inputs.waitForStatus(t, func(status pjlink.Status) bool { return status.Connected })
+	if status.Power != pjlink.PowerOff || status.Input != "31" || len(status.Lamps) != 1 || status.Lamps[0].Hours != 1200 || status.Errors == nil || status.Errors.Fan != "ok" {
+		t.Fatalf("pjlink.client first status got %+v", status)
+	}
+
+	err = moduleInstance.(common.OutputModule).Output(t.Context(), "main power on")
+	if err != nil {
+		t.Fatalf("pjlink.client power on failed: %s", err)
+	}
+
+	inputs.waitForStatus(t, func(status pjlink.Status) bool { return status.Power == pjlink.PowerOn })
+
+	inputs.mu.Lock()
+	reply := inputs.replies[0]
+	inputs.mu.Unlock()
+	if reply.Projector != "main" || reply.Command.String() != "%1POWR 1" || !reply.Response.OK() {
+		t.Fatalf("pjlink.client power on reply got %+v", reply)
+	}
+
+	err = moduleInstance.(common.OutputModule).Output(t.Context(), "all input 99")
+	if err == nil || err.Error() != "pjlink.client error during output: main: PJLink INPT ERR2 (out of parameter)" {
+		t.Fatalf("pjlink.client should return projector errors, got %v", err)
+	}
+
+	err = moduleInstance.(common.OutputModule).Output(t.Context(), "main freeze on")
+	if err == nil || err.Error() != "pjlink.client freeze needs a class 2 projector" {
+		t.Fatalf("pjlink.client should reject class 2 commands on class 1 projectors, got %v", err)
+	}
+
+	err = moduleInstance.(common.OutputModule).Output(t.Context(), "side power on")
+	if err == nil || err.Error() != "pjlink.client unknown projector: side" {
+		t.Fatalf("pjlink.client should reject unknown projectors, got %v", err)
+	}
+}
+
+func TestPJLinkClientAll(t *testing.T) {
+	fakeProjector(t, "127.0.0.1:14353")
+	fakeProjector(t, "127.0.0.1:14354")
+
+	registration, ok := module.GetModuleRegistration("pjlink.client")
+	if !ok {
+		t.Fatalf("pjlink.client module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "pjlink.client",
+		Params: map[string]any{
+			"projectors": []any{
+				map[string]any{"name": "left", "host": "127.0.0.1", "port": 14353, "password": "secret"},
+				map[string]any{"name": "right", "host": "127.0.0.1", "port": 14354, "password": "secret"},
+			},
+			"interval": 50,
+		},
+	})
+	if err != nil {
+		t.Fatalf("pjlink.client failed to create module: %s", err)
+	}
+
+	inputs := &pjlinkInputs{}
+	go moduleInstance.Start(t.Context(), inputs.handle)
+	defer moduleInstance.Stop()
+
+	for _, projector := range []string{"left", "right"} {
+		inputs.waitForStatus(t, func(status pjlink.Status) bool { return status.Projector == projector && status.Connected })
+	}
+
+	//NOTE(jwetzell): the same words are parsed for every projector so they must not be modified along the way
+	err = moduleInstance.(common.OutputModule).Output(t.Context(), "all mute video on")
+	if err != nil {
+		t.Fatalf("pjlink.client all mute video on failed: %s", err)
+	}
+
+	inputs.mu.Lock()
+	replies := slices.Clone(inputs.replies)
+	inputs.mu.Unlock()
+
+	projectors := []string{}
+	for _, reply := range replies {
+		if reply.Command.String() != "%1AVMT 11" || !reply.Response.OK() {
+			t.Fatalf("pjlink.client mute reply got %+v", reply)
+		}
+		projectors = append(projectors, reply.Projector)
+	}
+	slices.Sort(projectors)
+	if !slices.Equal(projectors, []string{"left", "right"}) {
+		t.Fatalf("pjlink.client should mute every projector, got replies from %v", projectors)
+	}
+}
+
+func TestBadPJLinkClient(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name:        "no projectors param",
+			params:      map[string]any{},
+			errorString: "pjlink.client projectors error: not found",
+		},
+		{
+			name:        "empty projectors",
+			params:      map[string]any{"projectors": []any{}},
+			errorString: "pjlink.client projectors must not be empty",
+		},
+		{
+			name: "projector without host",
+			params: map[string]any{
+				"projectors": []any{map[string]any{"name": "main"}},
+			},
+			errorString: "pjlink.client projectors[0] host error: not found",
+		},
+		{
+			name: "projector named all",
+			params: map[string]any{
+				"projectors": []any{map[string]any{"name": "all", "host": "10.0.0.10"}},
+			},
+			errorString: "pjlink.client projectors[0] name must be a single word other than all",
+		},
+		{
+			name: "duplicate names",
+			params: map[string]any{
+				"projectors": []any{
+					map[string]any{"name": "main", "host": "10.0.0.10"},
+					map[string]any{"name": "main", "host": "10.0.0.11"},
+				},
+			},
+			errorString: "pjlink.client projectors[1] name main is already used",
+		},
+		{
+			name: "class 3",
+			params: map[string]any{
+				"projectors": []any{map[string]any{"name": "main", "host": "10.0.0.10", "class": 3}},
+			},
+			errorString: "pjlink.client projectors[0] class must be 1 or 2",
+		},
+		{
+			name: "interval not a number",
+			params: map[string]any{
+				"projectors": []any{map[string]any{"name": "main", "host": "10.0.0.10"}},
+				"interval":   "fast",
+			},
+			errorString: "pjlink.client interval error: not a number",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := module.GetModuleRegistration("pjlink.client")
+			if !ok {
+				t.Fatalf("pjlink.client module not registered")
+			}
+
+			_, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "pjlink.client",
+				Params: test.params,
+			})
+
+			if err == nil {
+				t.Fatalf("pjlink.client expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("pjlink.client got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -1,8 +1,13 @@
 package module_test
 
 import (
+	"context"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/jwetzell/psn-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
 	"github.com/jwetzell/showbridge-go/internal/config"
 	"github.com/jwetzell/showbridge-go/internal/module"
 )
@@ -36,7 +41,43 @@ func TestBadPSNClient(t *testing.T) {
 		name        string
 		params      map[string]any
 		errorString string
-	}{}
+	}{
+		{
+			name:        "non-string interface param",
+			params:      map[string]any{"interface": 1},
+			errorString: "psn.client interface error: not a string",
+		},
+		{
+			name:        "unknown interface",
+			params:      map[string]any{"interface": "nope0"},
+			errorString: "psn.client interface error: route ip+net: no such network interface",
+		},
+		{
+			name:        "non-number port param",
+			params:      map[string]any{"port": "56565"},
+			errorString: "psn.client port error: not a number",
+		},
+		{
+			name:        "non-string systemName param",
+			params:      map[string]any{"systemName": 1},
+			errorString: "psn.client systemName error: not a string",
+		},
+		{
+			name:        "non-slice trackers param",
+			params:      map[string]any{"trackers": 1},
+			errorString: "psn.client trackers error: not an int slice",
+		},
+		{
+			name:        "tracker out of range",
+			params:      map[string]any{"trackers": []any{70000}},
+			errorString: "psn.client trackers must be between 0 and 65535",
+		},
+		{
+			name:        "non-boolean onChange param",
+			params:      map[string]any{"onChange": "true"},
+			errorString: "psn.client onChange error: not a boolean",
+		},
+	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -71,3 +112,107 @@ func TestBadPSNClient(t *testing.T) {
 		})
 	}
 }
+
+func TestGoodPSNClient(t *testing.T) {
+	serverRegistration, ok := module.GetModuleRegistration("psn.server")
+	if !ok {
+		t.Fatalf("psn.server module not registered")
+	}
+
+	server, err := serverRegistration.New(config.ModuleConfig{
+		Id:   "server",
+		Type: "psn.server",
+		Params: map[string]any{
+			"host":       "127.0.0.1",
+			"port":       56566,
+			"systemName": "stage",
+			"rate":       0,
+		},
+	})
+	if err != nil {
+		t.Fatalf("psn.server failed to create module: %s", err)
+	}
+
+	clientRegistration, ok := module.GetModuleRegistration("psn.client")
+	if !ok {
+		t.Fatalf("psn.client module not registered")
+	}
+
+	client, err := clientRegistration.New(config.ModuleConfig{
+		Id:   "client",
+		Type: "psn.client",
+		Params: map[string]any{
+			"port":       56566,
+			"systemName": "stage",
+			"trackers":   []any{2},
+			"onChange":   true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("psn.client failed to create module: %s", err)
+	}
+
+	var inputsMu sync.Mutex
+	inputs := []*psn.Tracker{}
+	inputHandler := func(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+		tracker, ok := payload.(*psn.Tracker)
+		if ok {
+			inputsMu.Lock()
+			inputs = append(inputs, tracker)
+			inputsMu.Unlock()
+		}
+		return true, nil
+	}
+
+	clientErrors := make(chan error, 1)
+	go func() {
+		clientErrors <- client.Start(t.Context(), inputHandler)
+	}()
+	defer client.Stop()
+
+	go server.Start(t.Context(), nil)
+	defer server.Stop()
+
+	outputModule, ok := server.(common.OutputModule)
+	if !ok {
+		t.Fatalf("psn.server should be an output module")
+	}
+
+	tracker := &psn.Tracker{Id: 2, Name: "actor"}
+	tracker.SetPos(1, 2, 3)
+	other := &psn.Tracker{Id: 3, Name: "other"}
+	other.SetPos(4, 5, 6)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-clientErrors:
+			t.Skipf("psn.client could not join multicast in this environment: %v", err)
+		default:
+		}
+
+		//NOTE(jwetzell): output errors are expected until the server has started
+		outputModule.Output(t.Context(), []*psn.Tracker{tracker, other})
+
+		inputsMu.Lock()
+		count := len(inputs)
+		inputsMu.Unlock()
+		if count > 0 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	//NOTE(jwetzell): repeated outputs of the same position should not reach the client again
+	time.Sleep(100 * time.Millisecond)
+
+	inputsMu.Lock()
+	defer inputsMu.Unlock()
+	if len(inputs) != 1 {
+		t.Fatalf("psn.client should pass tracker 2 once, got %d inputs", len(inputs))
+	}
+
+	if inputs[0].Id != 2 || inputs[0].Name != "actor" || inputs[0].Pos == nil || inputs[0].Pos.Z != 3 {
+		t.Fatalf("psn.client passed the wrong tracker: %+v", inputs[0])
+	}
+}
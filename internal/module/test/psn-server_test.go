@@ -0,0 +1,172 @@
+package module_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/psn-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/module"
+)
+
+func TestPSNServerFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("psn.server")
+	if !ok {
+		t.Fatalf("psn.server module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "psn.server",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create psn.server module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("psn.server module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "psn.server" {
+		t.Fatalf("psn.server module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+func TestGoodPSNServer(t *testing.T) {
+	listener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 56567})
+	if err != nil {
+		t.Fatalf("failed to create listener: %s", err)
+	}
+	defer listener.Close()
+
+	registration, ok := module.GetModuleRegistration("psn.server")
+	if !ok {
+		t.Fatalf("psn.server module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "psn.server",
+		Params: map[string]any{
+			"host":       "127.0.0.1",
+			"port":       56567,
+			"systemName": "stage",
+			"rate":       30,
+		},
+	})
+	if err != nil {
+		t.Fatalf("psn.server failed to create module: %s", err)
+	}
+
+	outputModule, ok := moduleInstance.(common.OutputModule)
+	if !ok {
+		t.Fatalf("psn.server should be an output module")
+	}
+
+	err = outputModule.Output(t.Context(), &psn.Tracker{Id: 1})
+	if err == nil {
+		t.Fatalf("psn.server output should fail before the module is started")
+	}
+
+	go moduleInstance.Start(t.Context(), nil)
+	defer moduleInstance.Stop()
+
+	tracker := psn.Tracker{Id: 7, Name: "actor"}
+	tracker.SetPos(1.5, 0, -2)
+	tracker.SetStatus(1)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		err = outputModule.Output(t.Context(), tracker)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("psn.server output failed: %s", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	decoder := psn.NewDecoder()
+	buffer := make([]byte, 2048)
+	for decoder.SystemName == "" || decoder.Trackers[7] == nil || decoder.Trackers[7].Pos == nil {
+		listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+		numBytes, err := listener.Read(buffer)
+		if err != nil {
+			t.Fatalf("psn.server did not send PSN: %s", err)
+		}
+		err = decoder.Decode(buffer[:numBytes])
+		if err != nil {
+			t.Fatalf("psn.server sent invalid PSN: %s", err)
+		}
+	}
+
+	if decoder.SystemName != "stage" {
+		t.Fatalf("psn.server sent wrong system name: %s", decoder.SystemName)
+	}
+
+	got := decoder.Trackers[7]
+	if got.Name != "actor" || got.Pos.X != 1.5 || got.Pos.Z != -2 || got.Validity == nil || *got.Validity != 1 {
+		t.Fatalf("psn.server sent wrong tracker: %+v", got)
+	}
+}
+
+func TestBadPSNServer(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name:        "non-string host param",
+			params:      map[string]any{"host": 1},
+			errorString: "psn.server host error: not a string",
+		},
+		{
+			name:        "non-number port param",
+			params:      map[string]any{"port": "56565"},
+			errorString: "psn.server port error: not a number",
+		},
+		{
+			name:        "non-string systemName param",
+			params:      map[string]any{"systemName": 1},
+			errorString: "psn.server systemName error: not a string",
+		},
+		{
+			name:        "non-number rate param",
+			params:      map[string]any{"rate": "60"},
+			errorString: "psn.server rate error: not a number",
+		},
+		{
+			name:        "rate out of range",
+			params:      map[string]any{"rate": 5000},
+			errorString: "psn.server rate must be between 0 and 1000",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := module.GetModuleRegistration("psn.server")
+			if !ok {
+				t.Fatalf("psn.server module not registered")
+			}
+
+			_, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "psn.server",
+				Params: test.params,
+			})
+
+			if err == nil {
+				t.Fatalf("psn.server expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("psn.server got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package module_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/module"
+)
+
+func TestRecordingReplayFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("recording.replay")
+	if !ok {
+		t.Fatalf("recording.replay module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "recording.replay",
+		Params: map[string]any{
+			"path": "recording.jsonl",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create recording.replay module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("recording.replay module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "recording.replay" {
+		t.Fatalf("recording.replay module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+func TestGoodRecordingReplay(t *testing.T) {
+	recordingPath := filepath.Join(t.TempDir(), "recording.jsonl")
+	err := os.WriteFile(recordingPath, []byte(`{"time":"2026-01-01T00:00:00Z","source":"udp","encoding":"hex","payload":"0102"}
+{"time":"2026-01-01T00:00:00.01Z","source":"osc","encoding":"string","payload":"hello"}
+`), 0644)
+	if err != nil {
+		t.Fatalf("failed to write recording: %s", err)
+	}
+
+	testCases := []struct {
+		name            string
+		params          map[string]any
+		expectedSources []string
+	}{
+		{
+			name: "real time",
+			params: map[string]any{
+				"path": recordingPath,
+			},
+			expectedSources: []string{"udp", "osc"},
+		},
+		{
+			name: "as fast as possible with source override",
+			params: map[string]any{
+				"path":   recordingPath,
+				"speed":  0,
+				"source": "replay",
+			},
+			expectedSources: []string{"replay", "replay"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := module.GetModuleRegistration("recording.replay")
+			if !ok {
+				t.Fatalf("recording.replay module not registered")
+			}
+
+			moduleInstance, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "recording.replay",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("recording.replay failed to create module: %s", err)
+			}
+
+			sources := []string{}
+			var sourcesMu sync.Mutex
+			inputHandler := func(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+				sourcesMu.Lock()
+				defer sourcesMu.Unlock()
+				sources = append(sources, sourceId)
+				return true, nil
+			}
+
+			// TODO(jwetzell) this is kind of hacky
+			go func() {
+				time.Sleep(200 * time.Millisecond)
+				moduleInstance.Stop()
+			}()
+			err = moduleInstance.Start(t.Context(), inputHandler)
+
+			if err != nil {
+				t.Fatalf("recording.replay failed to start: %s", err)
+			}
+
+			sourcesMu.Lock()
+			defer sourcesMu.Unlock()
+			if len(sources) != len(test.expectedSources) {
+				t.Fatalf("recording.replay replayed %v, expected %v", sources, test.expectedSources)
+			}
+			for i := range sources {
+				if sources[i] != test.expectedSources[i] {
+					t.Fatalf("recording.replay replayed %v, expected %v", sources, test.expectedSources)
+				}
+			}
+		})
+	}
+}
+
+func TestBadRecordingReplay(t *testing.T) {
+	missingPath := filepath.Join(t.TempDir(), "missing.jsonl")
+
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name:        "no path param",
+			params:      map[string]any{},
+			errorString: "recording.replay path error: not found",
+		},
+		{
+			name: "non-number speed param",
+			params: map[string]any{
+				"path":  "recording.jsonl",
+				"speed": "fast",
+			},
+			errorString: "recording.replay speed error: not a number",
+		},
+		{
+			name: "negative speed param",
+			params: map[string]any{
+				"path":  "recording.jsonl",
+				"speed": -1,
+			},
+			errorString: "recording.replay speed cannot be negative",
+		},
+		{
+			name: "non-bool loop param",
+			params: map[string]any{
+				"path": "recording.jsonl",
+				"loop": "yes",
+			},
+			errorString: "recording.replay loop error: not a boolean",
+		},
+		{
+			name: "missing recording file",
+			params: map[string]any{
+				"path": missingPath,
+			},
+			errorString: "recording.replay unable to load recording: open " + missingPath + ": no such file or directory",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := module.GetModuleRegistration("recording.replay")
+			if !ok {
+				t.Fatalf("recording.replay module not registered")
+			}
+
+			moduleInstance, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "recording.replay",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("recording.replay got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			err = moduleInstance.Start(t.Context(), nil)
+
+			if err == nil {
+				t.Fatalf("recording.replay expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("recording.replay got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package module_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/module"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func TestRTPMIDISessionFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("rtpmidi.session")
+	if !ok {
+		t.Fatalf("rtpmidi.session module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "rtpmidi.session",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create rtpmidi.session module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("rtpmidi.session module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "rtpmidi.session" {
+		t.Fatalf("rtpmidi.session module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+func TestGoodRTPMIDISession(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("rtpmidi.session")
+	if !ok {
+		t.Fatalf("rtpmidi.session module not registered")
+	}
+
+	responder, err := registration.New(config.ModuleConfig{
+		Id:   "responder",
+		Type: "rtpmidi.session",
+		Params: map[string]any{
+			"ip":   "127.0.0.1",
+			"port": 15004,
+		},
+	})
+	if err != nil {
+		t.Fatalf("rtpmidi.session failed to create responder: %s", err)
+	}
+
+	initiator, err := registration.New(config.ModuleConfig{
+		Id:   "initiator",
+		Type: "rtpmidi.session",
+		Params: map[string]any{
+			"ip":    "127.0.0.1",
+			"port":  15006,
+			"peers": []any{"127.0.0.1:15004"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("rtpmidi.session failed to create initiator: %s", err)
+	}
+
+	var inputsMu sync.Mutex
+	inputs := map[string][]midi.Message{}
+	inputHandler := func(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+		message, ok := payload.(midi.Message)
+		if ok {
+			inputsMu.Lock()
+			inputs[sourceId] = append(inputs[sourceId], message)
+			inputsMu.Unlock()
+		}
+		return true, nil
+	}
+
+	go responder.Start(t.Context(), inputHandler)
+	defer responder.Stop()
+	time.Sleep(50 * time.Millisecond)
+	go initiator.Start(t.Context(), inputHandler)
+	defer initiator.Stop()
+
+	noteOn := midi.NoteOn(1, 60, 100)
+	controlChange := midi.ControlChange(2, 7, 90)
+
+	//NOTE(jwetzell): output fails until the invitation handshake finishes
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		err = initiator.(common.OutputModule).Output(t.Context(), noteOn)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("rtpmidi.session initiator never connected: %s", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	err = responder.(common.OutputModule).Output(t.Context(), []byte(controlChange))
+	if err != nil {
+		t.Fatalf("rtpmidi.session responder output failed: %s", err)
+	}
+
+	deadline = time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		inputsMu.Lock()
+		done := len(inputs["responder"]) > 0 && len(inputs["initiator"]) > 0
+		inputsMu.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	inputsMu.Lock()
+	defer inputsMu.Unlock()
+	if len(inputs["responder"]) != 1 || !slices.Equal(inputs["responder"][0], noteOn) {
+		t.Fatalf("rtpmidi.session responder received wrong messages: %v", inputs["responder"])
+	}
+	if len(inputs["initiator"]) != 1 || !slices.Equal(inputs["initiator"][0], controlChange) {
+		t.Fatalf("rtpmidi.session initiator received wrong messages: %v", inputs["initiator"])
+	}
+}
+
+func TestBadRTPMIDISession(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name: "non-number port param",
+			params: map[string]any{
+				"port": "5004",
+			},
+			errorString: "rtpmidi.session port error: not a number",
+		},
+		{
+			name: "port out of range",
+			params: map[string]any{
+				"port": 65535,
+			},
+			errorString: "rtpmidi.session port must be between 1 and 65534",
+		},
+		{
+			name: "non-string name param",
+			params: map[string]any{
+				"name": 1,
+			},
+			errorString: "rtpmidi.session name error: not a string",
+		},
+		{
+			name: "non-slice peers param",
+			params: map[string]any{
+				"peers": "127.0.0.1:5004",
+			},
+			errorString: "rtpmidi.session peers error: not a slice",
+		},
+		{
+			name: "peer missing port",
+			params: map[string]any{
+				"peers": []any{"127.0.0.1"},
+			},
+			errorString: "rtpmidi.session peers error: address 127.0.0.1: missing port in address",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := module.GetModuleRegistration("rtpmidi.session")
+			if !ok {
+				t.Fatalf("rtpmidi.session module not registered")
+			}
+
+			_, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "rtpmidi.session",
+				Params: test.params,
+			})
+
+			if err == nil {
+				t.Fatalf("rtpmidi.session expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("rtpmidi.session got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package module_test
+
+import (
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/module"
+)
+
+func TestSACNReceiverFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("sacn.receiver")
+	if !ok {
+		t.Fatalf("sacn.receiver module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "sacn.receiver",
+		Params: map[string]any{
+			"universes": []any{1, 2},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create sacn.receiver module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("sacn.receiver module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "sacn.receiver" {
+		t.Fatalf("sacn.receiver module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+func TestBadSACNReceiver(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name:        "no universes param",
+			params:      map[string]any{},
+			errorString: "sacn.receiver universes error: not found",
+		},
+		{
+			name: "empty universes param",
+			params: map[string]any{
+				"universes": []any{},
+			},
+			errorString: "sacn.receiver universes must not be empty",
+		},
+		{
+			name: "universe out of range",
+			params: map[string]any{
+				"universes": []any{64000},
+			},
+			errorString: "sacn.receiver universe must be between 1 and 63999",
+		},
+		{
+			name: "non-boolean preview param",
+			params: map[string]any{
+				"universes": []any{1},
+				"preview":   "yes",
+			},
+			errorString: "sacn.receiver preview error: not a boolean",
+		},
+		{
+			name: "unknown interface",
+			params: map[string]any{
+				"universes": []any{1},
+				"interface": "not-an-interface",
+			},
+			errorString: "sacn.receiver interface error: route ip+net: no such network interface",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := module.GetModuleRegistration("sacn.receiver")
+			if !ok {
+				t.Fatalf("sacn.receiver module not registered")
+			}
+
+			_, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "sacn.receiver",
+				Params: test.params,
+			})
+
+			if err == nil {
+				t.Fatalf("sacn.receiver expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("sacn.receiver got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package module_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/module"
+	"github.com/jwetzell/showbridge-go/internal/sacn"
+)
+
+func TestSACNSenderFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("sacn.sender")
+	if !ok {
+		t.Fatalf("sacn.sender module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:     "test",
+		Type:   "sacn.sender",
+		Params: map[string]any{},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create sacn.sender module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("sacn.sender module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "sacn.sender" {
+		t.Fatalf("sacn.sender module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+func TestGoodSACNSender(t *testing.T) {
+	listener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: sacn.Port})
+	if err != nil {
+		t.Skipf("unable to listen on the sACN port: %s", err)
+	}
+	defer listener.Close()
+
+	registration, ok := module.GetModuleRegistration("sacn.sender")
+	if !ok {
+		t.Fatalf("sacn.sender module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "sacn.sender",
+		Params: map[string]any{
+			"host":       "127.0.0.1",
+			"universe":   5,
+			"sourceName": "test source",
+			"cid":        "5b7a1c2e-3d4f-4011-9233-445566778899",
+			"priority":   150,
+			"discovery":  false,
+			"refresh":    0,
+		},
+	})
+	if err != nil {
+		t.Fatalf("sacn.sender failed to create module: %s", err)
+	}
+
+	outputModule, ok := moduleInstance.(common.OutputModule)
+	if !ok {
+		t.Fatalf("sacn.sender should be an output module")
+	}
+
+	started := make(chan error)
+	go func() {
+		started <- moduleInstance.Start(t.Context(), nil)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		err = outputModule.Output(t.Context(), []byte{1, 2, 3})
+		if err == nil || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("sacn.sender output failed: %s", err)
+	}
+
+	readPacket := func() *sacn.DataPacket {
+		buffer := make([]byte, 1144)
+		listener.SetDeadline(time.Now().Add(time.Second))
+		numBytes, _, err := listener.ReadFromUDP(buffer)
+		if err != nil {
+			t.Fatalf("sacn.sender packet not received: %s", err)
+		}
+		packet, err := sacn.Decode(buffer[:numBytes])
+		if err != nil {
+			t.Fatalf("sacn.sender sent an invalid packet: %s", err)
+		}
+		dataPacket, ok := packet.(*sacn.DataPacket)
+		if !ok {
+			t.Fatalf("sacn.sender sent a %T, expected a data packet", packet)
+		}
+		return dataPacket
+	}
+
+	packet := readPacket()
+	if packet.Universe != 5 || packet.Priority != 150 || packet.SourceName != "test source" || packet.CID.String() != "5b7a1c2e-3d4f-4011-9233-445566778899" {
+		t.Fatalf("sacn.sender sent wrong packet: %+v", packet)
+	}
+
+	err = outputModule.Output(t.Context(), &sacn.DataPacket{Universe: 5, Priority: 50, Data: []byte{4, 5, 6}})
+	if err != nil {
+		t.Fatalf("sacn.sender output failed: %s", err)
+	}
+
+	packet = readPacket()
+	if packet.Priority != 50 {
+		t.Fatalf("sacn.sender should keep the priority of the packet, got %d", packet.Priority)
+	}
+
+	moduleInstance.Stop()
+	err = <-started
+	if err != nil {
+		t.Fatalf("sacn.sender failed to start: %s", err)
+	}
+
+	for range 3 {
+		terminated := readPacket()
+		if !terminated.StreamTerminated {
+			t.Fatalf("sacn.sender should send stream terminated packets when stopped")
+		}
+	}
+}
+
+func TestBadSACNSender(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name: "non-string sourceName param",
+			params: map[string]any{
+				"sourceName": 1,
+			},
+			errorString: "sacn.sender sourceName error: not a string",
+		},
+		{
+			name: "invalid cid param",
+			params: map[string]any{
+				"cid": "1234",
+			},
+			errorString: "sacn.sender cid error: cid must be 16 bytes",
+		},
+		{
+			name: "priority out of range",
+			params: map[string]any{
+				"priority": 201,
+			},
+			errorString: "sacn.sender priority must be between 0 and 200",
+		},
+		{
+			name: "universe out of range",
+			params: map[string]any{
+				"universe": 0,
+			},
+			errorString: "sacn.sender universe must be between 1 and 63999",
+		},
+		{
+			name: "negative refresh",
+			params: map[string]any{
+				"refresh": -1,
+			},
+			errorString: "sacn.sender refresh cannot be negative",
+		},
+		{
+			name: "non-boolean discovery param",
+			params: map[string]any{
+				"discovery": "no",
+			},
+			errorString: "sacn.sender discovery error: not a boolean",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := module.GetModuleRegistration("sacn.sender")
+			if !ok {
+				t.Fatalf("sacn.sender module not registered")
+			}
+
+			_, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "sacn.sender",
+				Params: test.params,
+			})
+
+			if err == nil {
+				t.Fatalf("sacn.sender expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("sacn.sender got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
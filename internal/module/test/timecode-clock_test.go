@@ -0,0 +1,306 @@
+package module_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/module"
+	"github.com/jwetzell/showbridge-go/internal/timecode"
+)
+
+func TestTimecodeClockFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("timecode.clock")
+	if !ok {
+		t.Fatalf("timecode.clock module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "timecode.clock",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create timecode.clock module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("timecode.clock module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "timecode.clock" {
+		t.Fatalf("timecode.clock module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+type timecodeInputs struct {
+	mu     sync.Mutex
+	inputs map[string][]timecode.Timecode
+}
+
+func (ti *timecodeInputs) handle(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+	current, ok := payload.(timecode.Timecode)
+	if ok {
+		ti.mu.Lock()
+		ti.inputs[sourceId] = append(ti.inputs[sourceId], current)
+		ti.mu.Unlock()
+	}
+	return true, nil
+}
+
+func (ti *timecodeInputs) get(sourceId string) []timecode.Timecode {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	return append([]timecode.Timecode{}, ti.inputs[sourceId]...)
+}
+
+func (ti *timecodeInputs) waitFor(t *testing.T, sourceId string, done func([]timecode.Timecode) bool) []timecode.Timecode {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		inputs := ti.get(sourceId)
+		if done(inputs) {
+			return inputs
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timecode.clock timed out waiting on %s, got %v", sourceId, ti.get(sourceId))
+	return nil
+}
+
+func TestGoodTimecodeClockMaster(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("timecode.clock")
+	if !ok {
+		t.Fatalf("timecode.clock module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "clock",
+		Type: "timecode.clock",
+		Params: map[string]any{
+			"rate":      "25",
+			"start":     "01:00:00:00",
+			"autoStart": true,
+			"speed":     2,
+			"triggers": []any{
+				map[string]any{"timecode": "01:00:00:10", "input": "cue1"},
+				map[string]any{"timecode": "00:00:00:05", "input": "never"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("timecode.clock failed to create module: %s", err)
+	}
+
+	inputs := &timecodeInputs{inputs: map[string][]timecode.Timecode{}}
+	go moduleInstance.Start(t.Context(), inputs.handle)
+	defer moduleInstance.Stop()
+
+	triggered := inputs.waitFor(t, "cue1", func(got []timecode.Timecode) bool { return len(got) > 0 })
+	if triggered[0].String() != "01:00:00:10" {
+		t.Fatalf("timecode.clock trigger fired with wrong timecode: %s", triggered[0])
+	}
+
+	frames := inputs.get("clock")
+	start := timecode.Timecode{Hours: 1, Rate: timecode.Rate25}
+	if offset := frames[0].FrameCount() - start.FrameCount(); offset < 0 || offset > 5 {
+		t.Fatalf("timecode.clock should start at the start timecode, got %s", frames[0])
+	}
+	for index := 1; index < len(frames); index++ {
+		if frames[index].FrameCount() <= frames[index-1].FrameCount() {
+			t.Fatalf("timecode.clock frames should increase: %s then %s", frames[index-1], frames[index])
+		}
+	}
+
+	outputModule := moduleInstance.(common.OutputModule)
+	for _, command := range []string{"stop", "locate 00:00:00:00"} {
+		err = outputModule.Output(t.Context(), command)
+		if err != nil {
+			t.Fatalf("timecode.clock %s failed: %s", command, err)
+		}
+	}
+
+	inputs.waitFor(t, "clock", func(got []timecode.Timecode) bool {
+		return len(got) > 0 && got[len(got)-1].String() == "00:00:00:00"
+	})
+
+	//NOTE(jwetzell): locating past a trigger does not fire it
+	err = outputModule.Output(t.Context(), "locate 00:00:00:06")
+	if err != nil {
+		t.Fatalf("timecode.clock locate failed: %s", err)
+	}
+	inputs.waitFor(t, "clock", func(got []timecode.Timecode) bool {
+		return len(got) > 0 && got[len(got)-1].String() == "00:00:00:06"
+	})
+	if len(inputs.get("never")) != 0 {
+		t.Fatalf("timecode.clock fired a trigger while locating")
+	}
+}
+
+func TestGoodTimecodeClockChase(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("timecode.clock")
+	if !ok {
+		t.Fatalf("timecode.clock module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "chase",
+		Type: "timecode.clock",
+		Params: map[string]any{
+			"mode":    "chase",
+			"timeout": 100,
+		},
+	})
+	if err != nil {
+		t.Fatalf("timecode.clock failed to create module: %s", err)
+	}
+
+	inputs := &timecodeInputs{inputs: map[string][]timecode.Timecode{}}
+	go moduleInstance.Start(t.Context(), inputs.handle)
+	defer moduleInstance.Stop()
+
+	outputModule := moduleInstance.(common.OutputModule)
+	err = outputModule.Output(t.Context(), "start")
+	if err == nil {
+		t.Fatalf("timecode.clock start should fail when chasing")
+	}
+
+	err = outputModule.Output(t.Context(), &timecode.Timecode{Hours: 10, Rate: timecode.Rate24})
+	if err != nil {
+		t.Fatalf("timecode.clock chase output failed: %s", err)
+	}
+
+	inputs.waitFor(t, "chase", func(got []timecode.Timecode) bool {
+		return len(got) > 0 && got[len(got)-1].Hours == 10 && got[len(got)-1].Frames > 0 && got[len(got)-1].Rate == timecode.Rate24
+	})
+
+	//NOTE(jwetzell): without more incoming timecode the clock stops after the timeout
+	time.Sleep(250 * time.Millisecond)
+	count := len(inputs.get("chase"))
+	time.Sleep(100 * time.Millisecond)
+	if len(inputs.get("chase")) != count {
+		t.Fatalf("timecode.clock should stop chasing after the timeout")
+	}
+}
+
+func TestTimecodeClockChaseRateMismatch(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("timecode.clock")
+	if !ok {
+		t.Fatalf("timecode.clock module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "chase",
+		Type: "timecode.clock",
+		Params: map[string]any{
+			"mode":    "chase",
+			"rate":    "25",
+			"timeout": 2000,
+			"triggers": []any{
+				map[string]any{"timecode": "00:00:01:00", "input": "cue1"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("timecode.clock failed to create module: %s", err)
+	}
+
+	inputs := &timecodeInputs{inputs: map[string][]timecode.Timecode{}}
+	go moduleInstance.Start(t.Context(), inputs.handle)
+	defer moduleInstance.Stop()
+
+	//NOTE(jwetzell): one second is frame 25 at the configured rate but frame 30 at the chased rate
+	outputModule := moduleInstance.(common.OutputModule)
+	err = outputModule.Output(t.Context(), timecode.Timecode{Frames: 24, Rate: timecode.Rate30})
+	if err != nil {
+		t.Fatalf("timecode.clock chase output failed: %s", err)
+	}
+
+	triggered := inputs.waitFor(t, "cue1", func(got []timecode.Timecode) bool { return len(got) > 0 })
+	if triggered[0].Rate != timecode.Rate30 || triggered[0].String() != "00:00:01:00" {
+		t.Fatalf("timecode.clock trigger should fire at one second in the chased rate, got %s at %s", triggered[0], triggered[0].Rate)
+	}
+
+	latest := 0
+	for _, current := range inputs.get("chase") {
+		latest = max(latest, current.FrameCount())
+	}
+	if latest < 30 {
+		t.Fatalf("timecode.clock trigger fired before the clock reached it, clock was at frame %d", latest)
+	}
+}
+
+func TestBadTimecodeClock(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name:        "bad mode",
+			params:      map[string]any{"mode": "slave"},
+			errorString: "timecode.clock mode must be master or chase",
+		},
+		{
+			name:        "bad rate",
+			params:      map[string]any{"rate": "60"},
+			errorString: "timecode.clock rate error: unsupported timecode rate: 60",
+		},
+		{
+			name:        "bad start",
+			params:      map[string]any{"start": "00:00:00:30", "rate": "30"},
+			errorString: "timecode.clock start error: timecode is out of range: 00:00:00:30",
+		},
+		{
+			name:        "non-number speed",
+			params:      map[string]any{"speed": "fast"},
+			errorString: "timecode.clock speed error: not a number",
+		},
+		{
+			name:        "speed out of range",
+			params:      map[string]any{"speed": 0},
+			errorString: "timecode.clock speed must be greater than 0 and at most 10",
+		},
+		{
+			name:        "non-boolean autoStart",
+			params:      map[string]any{"autoStart": "yes"},
+			errorString: "timecode.clock autoStart error: not a boolean",
+		},
+		{
+			name:        "trigger without timecode",
+			params:      map[string]any{"triggers": []any{map[string]any{"input": "cue"}}},
+			errorString: "timecode.clock triggers[0] timecode error: not found",
+		},
+		{
+			name:        "trigger not an object",
+			params:      map[string]any{"triggers": []any{"01:00:00:00"}},
+			errorString: "timecode.clock triggers[0] error: not a map",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := module.GetModuleRegistration("timecode.clock")
+			if !ok {
+				t.Fatalf("timecode.clock module not registered")
+			}
+
+			_, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "timecode.clock",
+				Params: test.params,
+			})
+
+			if err == nil {
+				t.Fatalf("timecode.clock expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("timecode.clock got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
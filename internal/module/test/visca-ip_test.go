@@ -0,0 +1,225 @@
+package module_test
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/module"
+	"github.com/jwetzell/showbridge-go/internal/visca"
+)
+
+type viscaInputs struct {
+	mu        sync.Mutex
+	responses []visca.Response
+}
+
+func (vi *viscaInputs) handle(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+	response, ok := payload.(visca.Response)
+	if ok {
+		vi.mu.Lock()
+		vi.responses = append(vi.responses, response)
+		vi.mu.Unlock()
+	}
+	return true, nil
+}
+
+func (vi *viscaInputs) waitFor(t *testing.T, count int) []visca.Response {
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		vi.mu.Lock()
+		responses := slices.Clone(vi.responses)
+		vi.mu.Unlock()
+		if len(responses) >= count {
+			return responses
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("visca.ip timed out waiting for %d responses", count)
+	return nil
+}
+
+// fakeVISCACamera acks and completes every command except power off and answers the power inquiry
+func fakeVISCACamera(t *testing.T, address string) {
+	camera, err := net.ListenPacket("udp", address)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	t.Cleanup(func() { camera.Close() })
+
+	go func() {
+		buffer := make([]byte, 1024)
+		for {
+			n, addr, err := camera.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			payloadType, sequence, payload, err := visca.DecodeIP(buffer[:n])
+			if err != nil {
+				continue
+			}
+			reply := func(data ...byte) {
+				camera.WriteTo(visca.EncodeIP(visca.PayloadReply, sequence, data), addr)
+			}
+			switch payloadType {
+			case visca.PayloadControl:
+				camera.WriteTo(visca.EncodeIP(visca.PayloadControlReply, sequence, []byte{0x01}), addr)
+			case visca.PayloadCommand:
+				if reflect.DeepEqual(payload, visca.Power(false).Data) {
+					reply(0x90, 0x60, 0x41, 0xff)
+					continue
+				}
+				reply(0x90, 0x42, 0xff)
+				reply(0x90, 0x52, 0xff)
+			case visca.PayloadInquiry:
+				reply(0x90, 0x50, 0x02, 0xff)
+			}
+		}
+	}()
+}
+
+func TestVISCAIPFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("visca.ip")
+	if !ok {
+		t.Fatalf("visca.ip module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "visca.ip",
+		Params: map[string]any{
+			"host": "127.0.0.1",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create visca.ip module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("visca.ip module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "visca.ip" {
+		t.Fatalf("visca.ip module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+func TestGoodVISCAIP(t *testing.T) {
+	fakeVISCACamera(t, "127.0.0.1:15381")
+
+	registration, ok := module.GetModuleRegistration("visca.ip")
+	if !ok {
+		t.Fatalf("visca.ip module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "visca.ip",
+		Params: map[string]any{
+			"host":    "127.0.0.1",
+			"port":    15381,
+			"timeout": 100,
+		},
+	})
+	if err != nil {
+		t.Fatalf("visca.ip failed to create module: %s", err)
+	}
+
+	inputs := &viscaInputs{}
+	go moduleInstance.Start(t.Context(), inputs.handle)
+	defer moduleInstance.Stop()
+
+	//NOTE(jwetzell): give Start time to dial and reset the sequence number
+	time.Sleep(100 * time.Millisecond)
+
+	err = moduleInstance.(common.OutputModule).Output(t.Context(), visca.Power(true))
+	if err != nil {
+		t.Fatalf("visca.ip power on failed: %s", err)
+	}
+
+	responses := inputs.waitFor(t, 1)
+	if responses[0].Reply.Kind != visca.ReplyCompletion || responses[0].Reply.Socket != 2 || !reflect.DeepEqual(responses[0].Message, visca.Power(true)) {
+		t.Fatalf("visca.ip power on completion got %+v", responses[0])
+	}
+
+	inquiry, _ := visca.Inquiry(visca.InquiryPower)
+	err = moduleInstance.(common.OutputModule).Output(t.Context(), inquiry.Data)
+	if err != nil {
+		t.Fatalf("visca.ip power inquiry failed: %s", err)
+	}
+
+	responses = inputs.waitFor(t, 2)
+	if responses[1].Message.InquiryKind() != visca.InquiryPower || !reflect.DeepEqual(responses[1].Reply.Data, []byte{0x02}) {
+		t.Fatalf("visca.ip power inquiry got %+v", responses[1])
+	}
+
+	err = moduleInstance.(common.OutputModule).Output(t.Context(), visca.Power(false))
+	if err == nil || err.Error() != "visca.ip VISCA error 41 (command not executable)" {
+		t.Fatalf("visca.ip should return camera errors, got %v", err)
+	}
+
+	err = moduleInstance.(common.OutputModule).Output(t.Context(), []byte{0x01, 0x02})
+	if err == nil || err.Error() != "visca.ip VISCA message must be between 3 and 16 bytes" {
+		t.Fatalf("visca.ip should reject bad messages, got %v", err)
+	}
+}
+
+func TestBadVISCAIP(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name:        "no host param",
+			params:      map[string]any{},
+			errorString: "visca.ip host error: not found",
+		},
+		{
+			name:        "port out of range",
+			params:      map[string]any{"host": "127.0.0.1", "port": 70000},
+			errorString: "visca.ip port must be between 1 and 65535",
+		},
+		{
+			name:        "header not a bool",
+			params:      map[string]any{"host": "127.0.0.1", "header": "yes"},
+			errorString: "visca.ip header error: not a boolean",
+		},
+		{
+			name:        "negative retries",
+			params:      map[string]any{"host": "127.0.0.1", "retries": -1},
+			errorString: "visca.ip retries must not be negative",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := module.GetModuleRegistration("visca.ip")
+			if !ok {
+				t.Fatalf("visca.ip module not registered")
+			}
+
+			_, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "visca.ip",
+				Params: test.params,
+			})
+
+			if err == nil {
+				t.Fatalf("visca.ip expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("visca.ip got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package module_test
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/module"
+)
+
+type webSocketServerInputs struct {
+	mu       sync.Mutex
+	messages []module.WebSocketServerMessage
+}
+
+func (wsi *webSocketServerInputs) handle(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+	message, ok := payload.(module.WebSocketServerMessage)
+	if ok {
+		wsi.mu.Lock()
+		wsi.messages = append(wsi.messages, message)
+		wsi.mu.Unlock()
+	}
+	return true, nil
+}
+
+func (wsi *webSocketServerInputs) waitFor(t *testing.T, count int) []module.WebSocketServerMessage {
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		wsi.mu.Lock()
+		messages := slices.Clone(wsi.messages)
+		wsi.mu.Unlock()
+		if len(messages) >= count {
+			return messages
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("websocket.server timed out waiting for %d messages", count)
+	return nil
+}
+
+func dialWebSocketServer(t *testing.T, url string, header http.Header) *websocket.Conn {
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		conn, response, err := websocket.DefaultDialer.Dial(url, header)
+		if err == nil {
+			t.Cleanup(func() { conn.Close() })
+			return conn
+		}
+		//NOTE(jwetzell): keep trying until the server is listening but not past a real rejection
+		if response != nil || time.Now().After(deadline) {
+			t.Fatalf("websocket.server dial failed: %s", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestWebSocketServerFromRegistry(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("websocket.server")
+	if !ok {
+		t.Fatalf("websocket.server module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "websocket.server",
+		Params: map[string]any{
+			"port": 8000,
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create websocket.server module: %s", err)
+	}
+
+	if moduleInstance.Id() != "test" {
+		t.Fatalf("websocket.server module has wrong id: %s", moduleInstance.Id())
+	}
+
+	if moduleInstance.Type() != "websocket.server" {
+		t.Fatalf("websocket.server module has wrong type: %s", moduleInstance.Type())
+	}
+}
+
+func TestGoodWebSocketServer(t *testing.T) {
+	registration, ok := module.GetModuleRegistration("websocket.server")
+	if !ok {
+		t.Fatalf("websocket.server module not registered")
+	}
+
+	moduleInstance, err := registration.New(config.ModuleConfig{
+		Id:   "test",
+		Type: "websocket.server",
+		Params: map[string]any{
+			"ip":           "127.0.0.1",
+			"port":         18766,
+			"path":         "/show",
+			"subprotocols": []any{"showbridge"},
+			"token":        "secret",
+		},
+	})
+	if err != nil {
+		t.Fatalf("websocket.server failed to create module: %s", err)
+	}
+
+	inputs := &webSocketServerInputs{}
+	go moduleInstance.Start(t.Context(), inputs.handle)
+	defer moduleInstance.Stop()
+
+	dialer := websocket.Dialer{Subprotocols: []string{"showbridge"}}
+	first := dialWebSocketServer(t, "ws://127.0.0.1:18766/show?token=secret", http.Header{"Sec-WebSocket-Protocol": {"showbridge"}})
+	if first.Subprotocol() != "showbridge" {
+		t.Fatalf("websocket.server should select the showbridge subprotocol, got %q", first.Subprotocol())
+	}
+
+	second, _, err := dialer.Dial("ws://127.0.0.1:18766/show", http.Header{"Authorization": {"Bearer secret"}})
+	if err != nil {
+		t.Fatalf("websocket.server should accept a bearer token: %s", err)
+	}
+	defer second.Close()
+
+	_, response, err := dialer.Dial("ws://127.0.0.1:18766/show?token=wrong", nil)
+	if err == nil || response.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("websocket.server should reject a bad token, got %v", err)
+	}
+
+	_, response, err = websocket.DefaultDialer.Dial("ws://127.0.0.1:18766/show?token=secret", nil)
+	if err == nil || response.StatusCode != http.StatusBadRequest {
+		t.Fatalf("websocket.server should reject connections without a supported subprotocol, got %v", err)
+	}
+
+	err = first.WriteMessage(websocket.TextMessage, []byte("go"))
+	if err != nil {
+		t.Fatalf("websocket.server client write failed: %s", err)
+	}
+	err = second.WriteMessage(websocket.BinaryMessage, []byte{0x01})
+	if err != nil {
+		t.Fatalf("websocket.server client write failed: %s", err)
+	}
+
+	messages := inputs.waitFor(t, 2)
+	slices.SortFunc(messages, func(a, b module.WebSocketServerMessage) int {
+		if a.Connection < b.Connection {
+			return -1
+		}
+		return 1
+	})
+	if messages[0].Data != "go" || messages[0].Connection == messages[1].Connection {
+		t.Fatalf("websocket.server first message got %+v", messages[0])
+	}
+	data, ok := messages[1].Data.([]byte)
+	if !ok || len(data) != 1 || data[0] != 0x01 {
+		t.Fatalf("websocket.server binary message got %+v", messages[1])
+	}
+
+	//NOTE(jwetzell): sending a received message back targets the connection it came from
+	err = moduleInstance.(common.OutputModule).Output(t.Context(), module.WebSocketServerMessage{Connection: messages[1].Connection, Data: "reply"})
+	if err != nil {
+		t.Fatalf("websocket.server targeted output failed: %s", err)
+	}
+
+	second.SetReadDeadline(time.Now().Add(time.Second))
+	messageType, message, err := second.ReadMessage()
+	if err != nil || messageType != websocket.TextMessage || string(message) != "reply" {
+		t.Fatalf("websocket.server targeted output got %d %q %v", messageType, message, err)
+	}
+
+	err = moduleInstance.(common.OutputModule).Output(t.Context(), []byte("all"))
+	if err != nil {
+		t.Fatalf("websocket.server broadcast failed: %s", err)
+	}
+
+	for _, conn := range []*websocket.Conn{first, second} {
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		messageType, message, err := conn.ReadMessage()
+		if err != nil || messageType != websocket.BinaryMessage || string(message) != "all" {
+			t.Fatalf("websocket.server broadcast got %d %q %v", messageType, message, err)
+		}
+	}
+
+	err = moduleInstance.(common.OutputModule).Output(t.Context(), module.WebSocketServerMessage{Connection: "99", Data: "lost"})
+	if err == nil || err.Error() != "websocket.server unknown connection: 99" {
+		t.Fatalf("websocket.server should reject unknown connections, got %v", err)
+	}
+
+	err = moduleInstance.(common.OutputModule).Output(t.Context(), 12)
+	if err == nil || err.Error() != "websocket.server payload must be string or []byte" {
+		t.Fatalf("websocket.server should reject other payloads, got %v", err)
+	}
+}
+
+func TestBadWebSocketServer(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name:        "no port param",
+			params:      map[string]any{},
+			errorString: "websocket.server port error: not found",
+		},
+		{
+			name:        "relative path",
+			params:      map[string]any{"port": 8000, "path": "ws"},
+			errorString: "websocket.server path must start with /",
+		},
+		{
+			name:        "unknown mode",
+			params:      map[string]any{"port": 8000, "mode": "json"},
+			errorString: "websocket.server unknown mode: json",
+		},
+		{
+			name:        "subprotocols not strings",
+			params:      map[string]any{"port": 8000, "subprotocols": []any{1}},
+			errorString: "websocket.server subprotocols error: not a string slice",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := module.GetModuleRegistration("websocket.server")
+			if !ok {
+				t.Fatalf("websocket.server module not registered")
+			}
+
+			_, err := registration.New(config.ModuleConfig{
+				Id:     "test",
+				Type:   "websocket.server",
+				Params: test.params,
+			})
+
+			if err == nil {
+				t.Fatalf("websocket.server expected to fail")
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("websocket.server got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
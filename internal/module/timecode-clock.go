@@ -0,0 +1,455 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/timecode"
+)
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "timecode.clock",
+		Title:       "Timecode Clock",
+		Description: "Generate or chase SMPTE timecode, emits a timecode.Timecode every frame and fires triggers when configured points are crossed",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"mode": {
+					Title:       "Mode",
+					Description: "master generates timecode, chase follows timecode output to the module",
+					Type:        "string",
+					Enum:        []any{"master", "chase"},
+					Default:     json.RawMessage(`"master"`),
+				},
+				"rate": {
+					Title:       "Rate",
+					Description: "frame rate",
+					Type:        "string",
+					Enum:        []any{"24", "25", "29.97df", "30"},
+					Default:     json.RawMessage(`"30"`),
+				},
+				"start": {
+					Title:       "Start",
+					Description: "timecode the clock starts at",
+					Type:        "string",
+					Default:     json.RawMessage(`"00:00:00:00"`),
+				},
+				"speed": {
+					Title:       "Speed",
+					Description: "playback speed of the master clock",
+					Type:        "number",
+					Default:     json.RawMessage(`1`),
+				},
+				"autoStart": {
+					Title:       "Auto Start",
+					Description: "start running as soon as the module starts",
+					Type:        "boolean",
+					Default:     json.RawMessage(`false`),
+				},
+				"timeout": {
+					Title:       "Timeout",
+					Description: "milliseconds without incoming timecode before a chasing clock stops",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1),
+					Default:     json.RawMessage(`500`),
+				},
+				"triggers": {
+					Title:       "Triggers",
+					Description: "timecode points that send the timecode to a route input when the running clock crosses them",
+					Type:        "array",
+					Items: &jsonschema.Schema{
+						Type: "object",
+						Properties: map[string]*jsonschema.Schema{
+							"timecode": {
+								Title: "Timecode",
+								Type:  "string",
+							},
+							"input": {
+								Title:       "Input",
+								Description: "route input to fire, the module id by default",
+								Type:        "string",
+							},
+						},
+						Required:             []string{"timecode"},
+						AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+					},
+				},
+			},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			modeString, err := params.GetString("mode")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					modeString = "master"
+				} else {
+					return nil, fmt.Errorf("timecode.clock mode error: %w", err)
+				}
+			}
+
+			if modeString != "master" && modeString != "chase" {
+				return nil, errors.New("timecode.clock mode must be master or chase")
+			}
+
+			rateString, err := params.GetString("rate")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					rateString = "30"
+				} else {
+					return nil, fmt.Errorf("timecode.clock rate error: %w", err)
+				}
+			}
+
+			rate, err := timecode.ParseRate(rateString)
+			if err != nil {
+				return nil, fmt.Errorf("timecode.clock rate error: %w", err)
+			}
+
+			startString, err := params.GetString("start")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					startString = "00:00:00:00"
+				} else {
+					return nil, fmt.Errorf("timecode.clock start error: %w", err)
+				}
+			}
+
+			start, err := timecode.Parse(startString, rate)
+			if err != nil {
+				return nil, fmt.Errorf("timecode.clock start error: %w", err)
+			}
+
+			speedNum, err := params.GetFloat64("speed")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					speedNum = 1
+				} else {
+					return nil, fmt.Errorf("timecode.clock speed error: %w", err)
+				}
+			}
+
+			if speedNum <= 0 || speedNum > 10 {
+				return nil, errors.New("timecode.clock speed must be greater than 0 and at most 10")
+			}
+
+			autoStartBool, err := params.GetBool("autoStart")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					autoStartBool = false
+				} else {
+					return nil, fmt.Errorf("timecode.clock autoStart error: %w", err)
+				}
+			}
+
+			timeoutNum, err := params.GetInt("timeout")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					timeoutNum = 500
+				} else {
+					return nil, fmt.Errorf("timecode.clock timeout error: %w", err)
+				}
+			}
+
+			if timeoutNum < 1 {
+				return nil, errors.New("timecode.clock timeout must be at least 1")
+			}
+
+			triggers := []timecodeTrigger{}
+			triggersValue, ok := params["triggers"]
+			if ok {
+				triggersSlice, ok := triggersValue.([]any)
+				if !ok {
+					return nil, fmt.Errorf("timecode.clock triggers error: %w", config.ErrParamNotSlice)
+				}
+
+				for triggerIndex, triggerValue := range triggersSlice {
+					triggerMap, ok := triggerValue.(map[string]any)
+					if !ok {
+						return nil, fmt.Errorf("timecode.clock triggers[%d] error: %w", triggerIndex, config.ErrParamNotMap)
+					}
+					triggerParams := config.Params(triggerMap)
+
+					timecodeString, err := triggerParams.GetString("timecode")
+					if err != nil {
+						return nil, fmt.Errorf("timecode.clock triggers[%d] timecode error: %w", triggerIndex, err)
+					}
+
+					triggerTimecode, err := timecode.Parse(timecodeString, rate)
+					if err != nil {
+						return nil, fmt.Errorf("timecode.clock triggers[%d] timecode error: %w", triggerIndex, err)
+					}
+
+					inputString, err := triggerParams.GetString("input")
+					if err != nil {
+						if errors.Is(err, config.ErrParamNotFound) {
+							inputString = moduleConfig.Id
+						} else {
+							return nil, fmt.Errorf("timecode.clock triggers[%d] input error: %w", triggerIndex, err)
+						}
+					}
+
+					triggers = append(triggers, timecodeTrigger{Timecode: triggerTimecode, Input: inputString})
+				}
+			}
+
+			return &TimecodeClock{
+				config:       moduleConfig,
+				Mode:         modeString,
+				Rate:         rate,
+				StartAt:      start,
+				Speed:        speedNum,
+				AutoStart:    autoStartBool,
+				Timeout:      time.Duration(timeoutNum) * time.Millisecond,
+				Triggers:     triggers,
+				rate:         rate,
+				speed:        speedNum,
+				anchorFrames: float64(start.FrameCount()),
+				logger:       CreateLogger(moduleConfig),
+			}, nil
+		},
+	})
+}
+
+type timecodeTrigger struct {
+	Timecode timecode.Timecode
+	Input    string
+}
+
+type TimecodeClock struct {
+	config       config.ModuleConfig
+	Mode         string
+	Rate         timecode.Rate
+	StartAt      timecode.Timecode
+	Speed        float64
+	AutoStart    bool
+	Timeout      time.Duration
+	Triggers     []timecodeTrigger
+	ctx          context.Context
+	inputHandler common.InputHandler
+	logger       *slog.Logger
+	cancel       context.CancelFunc
+	mu           sync.Mutex
+	rate         timecode.Rate
+	speed        float64
+	running      bool
+	anchorFrames float64
+	anchorTime   time.Time
+	lastFrame    int
+	hasLastFrame bool
+	lastChase    time.Time
+}
+
+func (tc *TimecodeClock) Id() string {
+	return tc.config.Id
+}
+
+func (tc *TimecodeClock) Type() string {
+	return tc.config.Type
+}
+
+func (tc *TimecodeClock) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	tc.logger.Debug("running")
+	tc.inputHandler = inputHandler
+	moduleContext, cancel := context.WithCancel(ctx)
+	tc.ctx = moduleContext
+	tc.cancel = cancel
+
+	if tc.AutoStart && tc.Mode == "master" {
+		tc.mu.Lock()
+		tc.reanchor(time.Now())
+		tc.running = true
+		tc.mu.Unlock()
+	}
+
+	//NOTE(jwetzell): check the clock four times a frame so frames go out close to when they start
+	ticker := time.NewTicker(time.Second / time.Duration(tc.Rate.Nominal()*4))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-tc.ctx.Done():
+			tc.logger.Debug("done")
+			return nil
+		case <-ticker.C:
+			tc.tick(time.Now())
+		}
+	}
+}
+
+// position is the real frame count of the clock, callers hold the lock
+func (tc *TimecodeClock) position(now time.Time) float64 {
+	if !tc.running {
+		return tc.anchorFrames
+	}
+	return tc.anchorFrames + now.Sub(tc.anchorTime).Seconds()*tc.rate.FPS()*tc.speed
+}
+
+// reanchor freezes the current position so the rate, speed or running state can change
+func (tc *TimecodeClock) reanchor(now time.Time) {
+	tc.anchorFrames = tc.position(now)
+	tc.anchorTime = now
+}
+
+func (tc *TimecodeClock) tick(now time.Time) {
+	tc.mu.Lock()
+
+	if tc.Mode == "chase" && tc.running && now.Sub(tc.lastChase) > tc.Timeout {
+		tc.reanchor(now)
+		tc.running = false
+		tc.logger.Debug("chase timecode lost")
+	}
+
+	frame := int(math.Floor(tc.position(now)))
+	if tc.hasLastFrame && frame == tc.lastFrame {
+		tc.mu.Unlock()
+		return
+	}
+
+	//NOTE(jwetzell): triggers only fire while running forward at speed, not when locating or jumping more than a second
+	fired := []timecodeTrigger{}
+	if tc.hasLastFrame && tc.running && frame > tc.lastFrame && frame-tc.lastFrame <= tc.rate.Nominal() {
+		for _, trigger := range tc.Triggers {
+			//NOTE(jwetzell): triggers are parsed at the configured rate but chasing can switch the clock to another one
+			triggerFrame := trigger.Timecode.FrameCount()
+			if trigger.Timecode.Rate != tc.rate {
+				triggerFrame = int(math.Round(trigger.Timecode.Duration().Seconds() * tc.rate.FPS()))
+			}
+			if triggerFrame > tc.lastFrame && triggerFrame <= frame {
+				fired = append(fired, timecodeTrigger{Timecode: timecode.FromFrameCount(triggerFrame, tc.rate), Input: trigger.Input})
+			}
+		}
+	}
+
+	tc.lastFrame = frame
+	tc.hasLastFrame = true
+	current := timecode.FromFrameCount(frame, tc.rate)
+	tc.mu.Unlock()
+
+	if tc.inputHandler == nil {
+		tc.logger.Error("input received but no input handler is configured")
+		return
+	}
+
+	tc.inputHandler(tc.ctx, tc.Id(), current)
+	for _, trigger := range fired {
+		tc.inputHandler(tc.ctx, trigger.Input, trigger.Timecode)
+	}
+}
+
+// Output accepts start, stop, locate <timecode> and speed <number> commands or a timecode.Timecode to chase
+func (tc *TimecodeClock) Output(ctx context.Context, payload any) error {
+	incoming, ok := common.GetAnyAs[timecode.Timecode](payload)
+	if !ok {
+		incomingPointer, ok := common.GetAnyAs[*timecode.Timecode](payload)
+		if ok && incomingPointer != nil {
+			incoming = *incomingPointer
+		} else {
+			return tc.command(payload)
+		}
+	}
+
+	err := incoming.Validate()
+	if err != nil {
+		return fmt.Errorf("timecode.clock %w", err)
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	now := time.Now()
+
+	if tc.Mode == "chase" {
+		tc.lastChase = now
+		//NOTE(jwetzell): stay on the free running clock while it is within a frame of the incoming timecode
+		if tc.running && tc.rate == incoming.Rate && math.Abs(float64(incoming.FrameCount())-tc.position(now)) < 1 {
+			return nil
+		}
+		tc.rate = incoming.Rate
+		tc.speed = 1
+		tc.running = true
+	}
+	tc.anchorFrames = float64(incoming.FrameCount())
+	tc.anchorTime = now
+	if tc.Mode == "master" {
+		tc.hasLastFrame = false
+	}
+	return nil
+}
+
+func (tc *TimecodeClock) command(payload any) error {
+	commandString, ok := common.GetAnyAs[string](payload)
+	if !ok {
+		payloadBytes, ok := common.GetAnyAsByteSlice(payload)
+		if !ok {
+			return errors.New("timecode.clock can only output a timecode.Timecode or a command string")
+		}
+		commandString = string(payloadBytes)
+	}
+
+	fields := strings.Fields(commandString)
+	if len(fields) == 0 {
+		return errors.New("timecode.clock command must not be empty")
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	now := time.Now()
+
+	switch strings.ToLower(fields[0]) {
+	case "start":
+		if tc.Mode == "chase" {
+			return errors.New("timecode.clock start is only supported in master mode")
+		}
+		tc.reanchor(now)
+		tc.running = true
+	case "stop":
+		tc.reanchor(now)
+		tc.running = false
+	case "locate":
+		if len(fields) != 2 {
+			return errors.New("timecode.clock locate needs a timecode")
+		}
+		located, err := timecode.Parse(fields[1], tc.rate)
+		if err != nil {
+			return fmt.Errorf("timecode.clock locate error: %w", err)
+		}
+		tc.anchorFrames = float64(located.FrameCount())
+		tc.anchorTime = now
+		tc.hasLastFrame = false
+	case "speed":
+		if len(fields) != 2 {
+			return errors.New("timecode.clock speed needs a number")
+		}
+		speed, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return fmt.Errorf("timecode.clock speed error: %w", err)
+		}
+		if speed <= 0 || speed > 10 {
+			return errors.New("timecode.clock speed must be greater than 0 and at most 10")
+		}
+		tc.reanchor(now)
+		tc.speed = speed
+	default:
+		return fmt.Errorf("timecode.clock unknown command: %s", fields[0])
+	}
+	return nil
+}
+
+func (tc *TimecodeClock) Stop() {
+	if tc.cancel != nil {
+		tc.cancel()
+	}
+}
@@ -98,7 +98,7 @@ func (um *UDPMulticast) Start(ctx context.Context, inputHandler common.InputHand
 	for um.ctx.Err() == nil {
 		um.conn.SetDeadline(time.Now().Add(time.Millisecond * 200))
 
-		numBytes, _, err := um.conn.ReadFromUDP(buffer)
+		numBytes, remoteAddr, err := um.conn.ReadFromUDP(buffer)
 		if err != nil {
 			if errors.Is(err, net.ErrClosed) {
 				break
@@ -115,7 +115,7 @@ func (um *UDPMulticast) Start(ctx context.Context, inputHandler common.InputHand
 			message := buffer[:numBytes]
 
 			if um.inputHandler != nil {
-				um.inputHandler(um.ctx, um.Id(), message)
+				um.inputHandler(common.WithRemoteAddr(um.ctx, remoteAddr), um.Id(), message)
 			} else {
 				um.logger.Error("input received but no input handler is configured")
 			}
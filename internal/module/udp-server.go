@@ -120,7 +120,7 @@ func (us *UDPServer) Start(ctx context.Context, inputHandler common.InputHandler
 	for us.ctx.Err() == nil {
 		listener.SetDeadline(time.Now().Add(time.Millisecond * 200))
 
-		numBytes, _, err := listener.ReadFromUDP(buffer)
+		numBytes, remoteAddr, err := listener.ReadFromUDP(buffer)
 		if err != nil {
 			//NOTE(jwetzell) we hit deadline
 			opErr, ok := err.(*net.OpError)
@@ -131,7 +131,7 @@ func (us *UDPServer) Start(ctx context.Context, inputHandler common.InputHandler
 		}
 		message := buffer[:numBytes]
 		if us.inputHandler != nil {
-			us.inputHandler(us.ctx, us.Id(), message)
+			us.inputHandler(common.WithRemoteAddr(us.ctx, remoteAddr), us.Id(), message)
 		} else {
 			us.logger.Error("input received but no input handler is configured")
 		}
@@ -0,0 +1,237 @@
+package module
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/visca"
+)
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "visca.ip",
+		Title:       "VISCA over IP",
+		Description: "Control a PTZ camera with VISCA over UDP, inquiry replies and command completions are emitted as a visca.Response",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"host": {
+					Title:       "Host",
+					Description: "the hostname or IP address of the camera",
+					Type:        "string",
+				},
+				"port": {
+					Title:       "Port",
+					Description: "the VISCA port of the camera",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1),
+					Maximum:     jsonschema.Ptr[float64](65535),
+					Default:     json.RawMessage(`52381`),
+				},
+				"header": {
+					Title:       "Header",
+					Description: "wrap messages in the VISCA over IP header with sequence numbers, turn off for cameras that take raw VISCA over UDP",
+					Type:        "boolean",
+					Default:     json.RawMessage(`true`),
+				},
+				"timeout": {
+					Title:       "Timeout",
+					Description: "milliseconds to wait for the camera to acknowledge a message before resending it",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1),
+					Default:     json.RawMessage(`500`),
+				},
+				"retries": {
+					Title:       "Retries",
+					Description: "number of times a message is resent before giving up",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](0),
+					Default:     json.RawMessage(`2`),
+				},
+			},
+			Required:             []string{"host"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			hostString, err := params.GetString("host")
+			if err != nil {
+				return nil, fmt.Errorf("visca.ip host error: %w", err)
+			}
+
+			portNum, err := params.GetInt("port")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					portNum = visca.DefaultPort
+				} else {
+					return nil, fmt.Errorf("visca.ip port error: %w", err)
+				}
+			}
+
+			if portNum < 1 || portNum > 65535 {
+				return nil, errors.New("visca.ip port must be between 1 and 65535")
+			}
+
+			headerBool, err := params.GetBool("header")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					headerBool = true
+				} else {
+					return nil, fmt.Errorf("visca.ip header error: %w", err)
+				}
+			}
+
+			timeoutNum, err := params.GetInt("timeout")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					timeoutNum = 500
+				} else {
+					return nil, fmt.Errorf("visca.ip timeout error: %w", err)
+				}
+			}
+
+			if timeoutNum < 1 {
+				return nil, errors.New("visca.ip timeout must be at least 1")
+			}
+
+			retriesNum, err := params.GetInt("retries")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					retriesNum = 2
+				} else {
+					return nil, fmt.Errorf("visca.ip retries error: %w", err)
+				}
+			}
+
+			if retriesNum < 0 {
+				return nil, errors.New("visca.ip retries must not be negative")
+			}
+
+			return &VISCAIP{
+				config:  moduleConfig,
+				Address: net.JoinHostPort(hostString, strconv.Itoa(portNum)),
+				Header:  headerBool,
+				Timeout: time.Duration(timeoutNum) * time.Millisecond,
+				Retries: retriesNum,
+				logger:  CreateLogger(moduleConfig),
+			}, nil
+		},
+	})
+}
+
+type VISCAIP struct {
+	config       config.ModuleConfig
+	Address      string
+	Header       bool
+	Timeout      time.Duration
+	Retries      int
+	client       *visca.Client
+	clientMu     sync.Mutex
+	ctx          context.Context
+	inputHandler common.InputHandler
+	logger       *slog.Logger
+	cancel       context.CancelFunc
+}
+
+func (vi *VISCAIP) Id() string {
+	return vi.config.Id
+}
+
+func (vi *VISCAIP) Type() string {
+	return vi.config.Type
+}
+
+func (vi *VISCAIP) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	vi.logger.Debug("running")
+	vi.inputHandler = inputHandler
+	moduleContext, cancel := context.WithCancel(ctx)
+	vi.ctx = moduleContext
+	vi.cancel = cancel
+
+	conn, err := net.Dial("udp", vi.Address)
+	if err != nil {
+		return err
+	}
+
+	client := visca.NewClient(conn, vi.Header, vi.Timeout, vi.Retries)
+	client.OnCompletion = vi.emit
+
+	vi.clientMu.Lock()
+	vi.client = client
+	vi.clientMu.Unlock()
+
+	if vi.Header {
+		err := client.Reset()
+		if err != nil {
+			//NOTE(jwetzell): the camera may just be off, Send resets again if it complains about the sequence number
+			vi.logger.Warn("sequence number reset failed", "error", err)
+		}
+	}
+
+	<-vi.ctx.Done()
+	vi.logger.Debug("done")
+	return nil
+}
+
+func (vi *VISCAIP) emit(response visca.Response) {
+	if vi.inputHandler == nil {
+		vi.logger.Error("input received but no input handler is configured")
+		return
+	}
+	vi.inputHandler(vi.ctx, vi.Id(), response)
+}
+
+func (vi *VISCAIP) Output(ctx context.Context, payload any) error {
+	message, ok := common.GetAnyAs[visca.Message](payload)
+	if !ok {
+		payloadBytes, ok := common.GetAnyAsByteSlice(payload)
+		if !ok {
+			return errors.New("visca.ip can only output a visca.Message or bytes")
+		}
+		message = visca.Message{Data: payloadBytes}
+	}
+
+	vi.clientMu.Lock()
+	client := vi.client
+	vi.clientMu.Unlock()
+
+	if client == nil {
+		return errors.New("visca.ip client is not setup")
+	}
+
+	reply, err := client.Send(message)
+	if err != nil {
+		if reply.Kind == visca.ReplyError {
+			vi.emit(visca.Response{Message: message, Reply: reply})
+		}
+		return fmt.Errorf("visca.ip %w", err)
+	}
+
+	//NOTE(jwetzell): acks are only tracked, completions for commands arrive later through OnCompletion
+	if reply.Kind == visca.ReplyCompletion {
+		vi.emit(visca.Response{Message: message, Reply: reply})
+	}
+	return nil
+}
+
+func (vi *VISCAIP) Stop() {
+	if vi.cancel != nil {
+		vi.cancel()
+	}
+	vi.clientMu.Lock()
+	defer vi.clientMu.Unlock()
+	if vi.client != nil {
+		vi.client.Close()
+	}
+}
@@ -0,0 +1,411 @@
+package module
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/gorilla/websocket"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+)
+
+func init() {
+	RegisterModule(ModuleRegistration{
+		Type:        "websocket.server",
+		Title:       "WebSocket Server",
+		Description: "Accept WebSocket connections, messages are emitted as a WebSocketServerMessage with the id of the connection they came from",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"ip": {
+					Title:       "IP",
+					Description: "the IP address to bind the WebSocket server to",
+					Type:        "string",
+					Default:     json.RawMessage(`"0.0.0.0"`),
+				},
+				"port": {
+					Title:       "Port",
+					Description: "the port for the WebSocket server to listen on",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1024),
+					Maximum:     jsonschema.Ptr[float64](65535),
+				},
+				"path": {
+					Title:       "Path",
+					Description: "the path WebSocket connections are accepted on",
+					Type:        "string",
+					Default:     json.RawMessage(`"/"`),
+				},
+				"mode": {
+					Title:       "Mode",
+					Description: "text or binary treat every message as that type, auto emits text messages as strings and binary messages as bytes and sends strings as text and bytes as binary",
+					Type:        "string",
+					Enum:        []any{"auto", "text", "binary"},
+					Default:     json.RawMessage(`"auto"`),
+				},
+				"subprotocols": {
+					Title:       "Subprotocols",
+					Description: "subprotocols the server supports in order of preference, connections that don't offer one of them are rejected",
+					Type:        "array",
+					Items: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"token": {
+					Title:       "Token",
+					Description: "token connections must send as a token query parameter or a bearer Authorization header",
+					Type:        "string",
+				},
+			},
+			Required:             []string{"port"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(moduleConfig config.ModuleConfig) (common.Module, error) {
+			params := moduleConfig.Params
+
+			portNum, err := params.GetInt("port")
+			if err != nil {
+				return nil, fmt.Errorf("websocket.server port error: %w", err)
+			}
+
+			if portNum < 1 || portNum > 65535 {
+				return nil, errors.New("websocket.server port must be between 1 and 65535")
+			}
+
+			ipString, err := params.GetString("ip")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					ipString = "0.0.0.0"
+				} else {
+					return nil, fmt.Errorf("websocket.server ip error: %w", err)
+				}
+			}
+
+			pathString, err := params.GetString("path")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					pathString = "/"
+				} else {
+					return nil, fmt.Errorf("websocket.server path error: %w", err)
+				}
+			}
+
+			if !strings.HasPrefix(pathString, "/") {
+				return nil, errors.New("websocket.server path must start with /")
+			}
+
+			modeString, err := params.GetString("mode")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					modeString = "auto"
+				} else {
+					return nil, fmt.Errorf("websocket.server mode error: %w", err)
+				}
+			}
+
+			if modeString != "auto" && modeString != "text" && modeString != "binary" {
+				return nil, fmt.Errorf("websocket.server unknown mode: %s", modeString)
+			}
+
+			subprotocols, err := params.GetStringSlice("subprotocols")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					subprotocols = nil
+				} else {
+					return nil, fmt.Errorf("websocket.server subprotocols error: %w", err)
+				}
+			}
+
+			tokenString, err := params.GetString("token")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					tokenString = ""
+				} else {
+					return nil, fmt.Errorf("websocket.server token error: %w", err)
+				}
+			}
+
+			return &WebSocketServer{
+				config:       moduleConfig,
+				Addr:         net.JoinHostPort(ipString, strconv.Itoa(portNum)),
+				Path:         pathString,
+				Mode:         modeString,
+				Subprotocols: subprotocols,
+				Token:        tokenString,
+				connections:  map[string]*webSocketServerConnection{},
+				logger:       CreateLogger(moduleConfig),
+			}, nil
+		},
+	})
+}
+
+// WebSocketServerMessage is a message from or to one connection, an empty Connection on output sends to every connection
+type WebSocketServerMessage struct {
+	Connection string
+	RemoteAddr string
+	Data       any
+}
+
+type webSocketServerConnection struct {
+	id      string
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+}
+
+func (wsc *webSocketServerConnection) write(messageType int, data []byte) error {
+	wsc.writeMu.Lock()
+	defer wsc.writeMu.Unlock()
+	wsc.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	return wsc.conn.WriteMessage(messageType, data)
+}
+
+type WebSocketServer struct {
+	config        config.ModuleConfig
+	Addr          string
+	Path          string
+	Mode          string
+	Subprotocols  []string
+	Token         string
+	ctx           context.Context
+	inputHandler  common.InputHandler
+	logger        *slog.Logger
+	cancel        context.CancelFunc
+	server        *http.Server
+	serverMu      sync.Mutex
+	connections   map[string]*webSocketServerConnection
+	connectionsMu sync.RWMutex
+	nextId        uint64
+	wg            sync.WaitGroup
+}
+
+func (ws *WebSocketServer) Id() string {
+	return ws.config.Id
+}
+
+func (ws *WebSocketServer) Type() string {
+	return ws.config.Type
+}
+
+func (ws *WebSocketServer) authorized(r *http.Request) bool {
+	if ws.Token == "" {
+		return true
+	}
+	token := r.URL.Query().Get("token")
+	bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if ok {
+		token = bearer
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(ws.Token)) == 1
+}
+
+func (ws *WebSocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != ws.Path {
+		http.NotFound(w, r)
+		return
+	}
+
+	if !ws.authorized(r) {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	if len(ws.Subprotocols) > 0 {
+		offered := websocket.Subprotocols(r)
+		supported := false
+		for _, subprotocol := range ws.Subprotocols {
+			for _, offer := range offered {
+				supported = supported || offer == subprotocol
+			}
+		}
+		if !supported {
+			http.Error(w, "no supported subprotocol offered", http.StatusBadRequest)
+			return
+		}
+	}
+
+	upgrader := websocket.Upgrader{
+		Subprotocols: ws.Subprotocols,
+		//NOTE(jwetzell): dashboards are often served from somewhere else, the token is the access check
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		ws.logger.Debug("upgrade failed", "error", err)
+		return
+	}
+
+	ws.connectionsMu.Lock()
+	ws.nextId++
+	connection := &webSocketServerConnection{id: strconv.FormatUint(ws.nextId, 10), conn: conn}
+	ws.connections[connection.id] = connection
+	ws.connectionsMu.Unlock()
+
+	ws.wg.Go(func() {
+		ws.readLoop(connection)
+	})
+}
+
+func (ws *WebSocketServer) readLoop(connection *webSocketServerConnection) {
+	remoteAddr := connection.conn.RemoteAddr().String()
+	ws.logger.Debug("connection accepted", "connection", connection.id, "remoteAddr", remoteAddr)
+	defer func() {
+		connection.conn.Close()
+		ws.connectionsMu.Lock()
+		delete(ws.connections, connection.id)
+		ws.connectionsMu.Unlock()
+		ws.logger.Debug("connection closed", "connection", connection.id, "remoteAddr", remoteAddr)
+	}()
+
+	for ws.ctx.Err() == nil {
+		messageType, message, err := connection.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var data any
+		switch {
+		case ws.Mode == "text":
+			data = string(message)
+		case ws.Mode == "binary":
+			data = message
+		case messageType == websocket.TextMessage:
+			data = string(message)
+		default:
+			data = message
+		}
+
+		if ws.inputHandler == nil {
+			ws.logger.Error("input received but no input handler is configured")
+			continue
+		}
+		ws.inputHandler(ws.ctx, ws.Id(), WebSocketServerMessage{Connection: connection.id, RemoteAddr: remoteAddr, Data: data})
+	}
+}
+
+func (ws *WebSocketServer) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	ws.logger.Debug("running")
+	ws.inputHandler = inputHandler
+	moduleContext, cancel := context.WithCancel(ctx)
+	ws.ctx = moduleContext
+	ws.cancel = cancel
+
+	httpServer := &http.Server{
+		Addr:              ws.Addr,
+		ReadHeaderTimeout: 5 * time.Second,
+		Handler:           ws,
+	}
+
+	ws.serverMu.Lock()
+	ws.server = httpServer
+	ws.serverMu.Unlock()
+
+	err := httpServer.ListenAndServe()
+	if err != nil {
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+	}
+
+	<-ws.ctx.Done()
+	ws.logger.Debug("done")
+	return nil
+}
+
+func (ws *WebSocketServer) Output(ctx context.Context, payload any) error {
+	target := ""
+	data := payload
+
+	message, ok := common.GetAnyAs[WebSocketServerMessage](payload)
+	if ok {
+		target = message.Connection
+		data = message.Data
+	}
+
+	var messageType int
+	var messageBytes []byte
+
+	payloadBytes, ok := common.GetAnyAsByteSlice(data)
+	if ok {
+		messageType = websocket.BinaryMessage
+		messageBytes = payloadBytes
+	} else {
+		payloadString, ok := common.GetAnyAs[string](data)
+		if !ok {
+			return errors.New("websocket.server payload must be string or []byte")
+		}
+		messageType = websocket.TextMessage
+		messageBytes = []byte(payloadString)
+	}
+
+	switch ws.Mode {
+	case "text":
+		messageType = websocket.TextMessage
+	case "binary":
+		messageType = websocket.BinaryMessage
+	}
+
+	ws.connectionsMu.RLock()
+	connections := []*webSocketServerConnection{}
+	if target == "" {
+		for _, connection := range ws.connections {
+			connections = append(connections, connection)
+		}
+	} else if connection, ok := ws.connections[target]; ok {
+		connections = append(connections, connection)
+	}
+	ws.connectionsMu.RUnlock()
+
+	if target != "" && len(connections) == 0 {
+		return fmt.Errorf("websocket.server unknown connection: %s", target)
+	}
+
+	var errorString strings.Builder
+	for _, connection := range connections {
+		err := connection.write(messageType, messageBytes)
+		if err != nil {
+			fmt.Fprintf(&errorString, "%s: %s\n", connection.id, err.Error())
+		}
+	}
+
+	if errorString.Len() == 0 {
+		return nil
+	}
+	return fmt.Errorf("websocket.server error during output: %s", strings.TrimSpace(errorString.String()))
+}
+
+func (ws *WebSocketServer) Stop() {
+	if ws.cancel != nil {
+		defer ws.cancel()
+	}
+
+	ws.serverMu.Lock()
+	if ws.server != nil {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ws.server.Shutdown(shutdownCtx)
+		shutdownCancel()
+	}
+	ws.serverMu.Unlock()
+
+	//NOTE(jwetzell): upgraded connections are hijacked so Shutdown leaves them open
+	ws.connectionsMu.RLock()
+	for _, connection := range ws.connections {
+		connection.writeMu.Lock()
+		connection.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(time.Second))
+		connection.writeMu.Unlock()
+		connection.conn.Close()
+	}
+	ws.connectionsMu.RUnlock()
+	ws.wg.Wait()
+}
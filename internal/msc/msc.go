@@ -0,0 +1,376 @@
+// Package msc encodes and decodes MIDI Show Control (MSC) system exclusive messages
+package msc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jwetzell/showbridge-go/internal/timecode"
+)
+
+const (
+	// AllCall is the device id every MSC device responds to
+	AllCall = 0x7f
+
+	universalRealTime = 0x7f
+	subIDShowControl  = 0x02
+)
+
+const (
+	CommandGo      = 0x01
+	CommandStop    = 0x02
+	CommandResume  = 0x03
+	CommandTimedGo = 0x04
+	CommandLoad    = 0x05
+	CommandSet     = 0x06
+	CommandFire    = 0x07
+	CommandAllOff  = 0x08
+	CommandRestore = 0x09
+	CommandReset   = 0x0a
+	CommandGoOff   = 0x0b
+)
+
+const (
+	CommandFormatLighting = 0x01
+	CommandFormatSound    = 0x10
+	CommandFormatAll      = 0x7f
+)
+
+var commandNames = map[uint8]string{
+	CommandGo:      "go",
+	CommandStop:    "stop",
+	CommandResume:  "resume",
+	CommandTimedGo: "timed_go",
+	CommandLoad:    "load",
+	CommandSet:     "set",
+	CommandFire:    "fire",
+	CommandAllOff:  "all_off",
+	CommandRestore: "restore",
+	CommandReset:   "reset",
+	CommandGoOff:   "go_off",
+}
+
+var commandFormatNames = map[uint8]string{
+	0x01:             "lighting",
+	0x02:             "moving_lights",
+	0x03:             "color_changers",
+	0x04:             "strobes",
+	0x05:             "lasers",
+	0x06:             "chasers",
+	0x10:             "sound",
+	0x11:             "music",
+	0x12:             "cd_players",
+	0x13:             "eprom_playback",
+	0x14:             "audio_tape_machines",
+	0x15:             "intercoms",
+	0x16:             "amplifiers",
+	0x17:             "audio_effects",
+	0x18:             "equalizers",
+	0x20:             "machinery",
+	0x21:             "rigging",
+	0x22:             "flys",
+	0x23:             "lifts",
+	0x24:             "turntables",
+	0x25:             "trusses",
+	0x26:             "robots",
+	0x27:             "animation",
+	0x28:             "floats",
+	0x29:             "breakaways",
+	0x2a:             "barges",
+	0x30:             "video",
+	0x31:             "video_tape_machines",
+	0x32:             "video_cassette_machines",
+	0x33:             "video_disc_players",
+	0x34:             "video_switchers",
+	0x35:             "video_effects",
+	0x36:             "video_character_generators",
+	0x37:             "video_still_stores",
+	0x38:             "video_monitors",
+	0x40:             "projection",
+	0x41:             "film_projectors",
+	0x42:             "slide_projectors",
+	0x43:             "video_projectors",
+	0x44:             "dissolvers",
+	0x45:             "shutter_controls",
+	0x50:             "process_control",
+	0x51:             "hydraulic_oil",
+	0x52:             "h2o",
+	0x53:             "co2",
+	0x54:             "compressed_air",
+	0x55:             "natural_gas",
+	0x56:             "fog",
+	0x57:             "smoke",
+	0x58:             "cracked_haze",
+	0x60:             "pyro",
+	0x61:             "fireworks",
+	0x62:             "explosions",
+	0x63:             "flame",
+	0x64:             "smoke_pots",
+	CommandFormatAll: "all_types",
+}
+
+// Time is the MSC time code, Rate is the frame rate code stored in the upper bits of the hours byte (0=24, 1=25, 2=29.97 drop, 3=30)
+type Time struct {
+	Rate      uint8
+	Hours     uint8
+	Minutes   uint8
+	Seconds   uint8
+	Frames    uint8
+	Subframes uint8
+}
+
+type Message struct {
+	DeviceID      uint8
+	CommandFormat uint8
+	Command       uint8
+	Cue           string
+	List          string
+	Path          string
+	Time          *Time
+	Control       uint16
+	Value         uint16
+	Macro         uint8
+}
+
+func CommandName(command uint8) string {
+	name, ok := commandNames[command]
+	if !ok {
+		return fmt.Sprintf("0x%02x", command)
+	}
+	return name
+}
+
+func CommandFormatName(commandFormat uint8) string {
+	name, ok := commandFormatNames[commandFormat]
+	if !ok {
+		return fmt.Sprintf("0x%02x", commandFormat)
+	}
+	return name
+}
+
+// CommandFromString accepts a command name like timed_go or a number
+func CommandFromString(value string) (uint8, error) {
+	return fromString(value, commandNames, "command")
+}
+
+// CommandFormatFromString accepts a command format name like lighting or a number
+func CommandFormatFromString(value string) (uint8, error) {
+	return fromString(value, commandFormatNames, "command format")
+}
+
+func fromString(value string, names map[uint8]string, kind string) (uint8, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for code, name := range names {
+		if name == value {
+			return code, nil
+		}
+	}
+	number, err := strconv.ParseUint(value, 0, 8)
+	if err != nil || number > 0x7f {
+		return 0, fmt.Errorf("unknown MSC %s: %s", kind, value)
+	}
+	return uint8(number), nil
+}
+
+func (m *Message) CommandName() string {
+	return CommandName(m.Command)
+}
+
+func (m *Message) CommandFormatName() string {
+	return CommandFormatName(m.CommandFormat)
+}
+
+// ParseTime parses hh:mm:ss:ff with an optional .sf subframe suffix, frames and drop frame labels are checked against the rate
+func ParseTime(value string, rate timecode.Rate) (*Time, error) {
+	subframes := uint64(0)
+	main, subframeString, hasSubframes := strings.Cut(value, ".")
+	if hasSubframes {
+		parsed, err := strconv.ParseUint(subframeString, 10, 8)
+		if err != nil || parsed > 99 {
+			return nil, fmt.Errorf("MSC time subframes must be between 0 and 99: %s", value)
+		}
+		subframes = parsed
+	}
+
+	parsedTimecode, err := timecode.Parse(main, rate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Time{
+		Rate:      uint8(parsedTimecode.Rate),
+		Hours:     parsedTimecode.Hours,
+		Minutes:   parsedTimecode.Minutes,
+		Seconds:   parsedTimecode.Seconds,
+		Frames:    parsedTimecode.Frames,
+		Subframes: uint8(subframes),
+	}, nil
+}
+
+func (t *Time) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d:%02d.%02d", t.Hours, t.Minutes, t.Seconds, t.Frames, t.Subframes)
+}
+
+func (t *Time) bytes() []byte {
+	return []byte{(t.Rate&0x03)<<5 | t.Hours&0x1f, t.Minutes & 0x3f, t.Seconds & 0x3f, t.Frames & 0x1f, t.Subframes & 0x7f}
+}
+
+func timeFromBytes(data []byte) *Time {
+	return &Time{
+		Rate:      (data[0] >> 5) & 0x03,
+		Hours:     data[0] & 0x1f,
+		Minutes:   data[1] & 0x3f,
+		Seconds:   data[2] & 0x3f,
+		Frames:    data[3] & 0x1f,
+		Subframes: data[4] & 0x7f,
+	}
+}
+
+func validCueNumber(value string) bool {
+	for _, character := range value {
+		if (character < '0' || character > '9') && character != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalBinary encodes the message as a complete F0 ... F7 system exclusive message
+func (m *Message) MarshalBinary() ([]byte, error) {
+	if m.DeviceID > 0x7f {
+		return nil, errors.New("MSC device id must be between 0 and 127")
+	}
+	if m.CommandFormat == 0 || m.CommandFormat > 0x7f {
+		return nil, errors.New("MSC command format must be between 1 and 127")
+	}
+
+	data := []byte{0xf0, universalRealTime, m.DeviceID, subIDShowControl, m.CommandFormat, m.Command}
+
+	switch m.Command {
+	case CommandGo, CommandStop, CommandResume, CommandLoad, CommandGoOff, CommandTimedGo:
+		if m.Command == CommandTimedGo {
+			if m.Time == nil {
+				return nil, errors.New("MSC timed_go requires a time")
+			}
+			data = append(data, m.Time.bytes()...)
+		}
+		if m.Command == CommandLoad && m.Cue == "" {
+			return nil, errors.New("MSC load requires a cue")
+		}
+		cueData, err := m.cueBytes()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, cueData...)
+	case CommandSet:
+		if m.Control > 0x3fff || m.Value > 0x3fff {
+			return nil, errors.New("MSC set control and value must be between 0 and 16383")
+		}
+		data = append(data, byte(m.Control&0x7f), byte(m.Control>>7), byte(m.Value&0x7f), byte(m.Value>>7))
+		if m.Time != nil {
+			data = append(data, m.Time.bytes()...)
+		}
+	case CommandFire:
+		if m.Macro > 0x7f {
+			return nil, errors.New("MSC fire macro must be between 0 and 127")
+		}
+		data = append(data, m.Macro)
+	case CommandAllOff, CommandRestore, CommandReset:
+	default:
+		return nil, fmt.Errorf("unsupported MSC command %s", CommandName(m.Command))
+	}
+
+	return append(data, 0xf7), nil
+}
+
+// cueBytes encodes the optional cue, list and path, a list needs a cue and a path needs a list
+func (m *Message) cueBytes() ([]byte, error) {
+	if m.List != "" && m.Cue == "" {
+		return nil, errors.New("MSC list requires a cue")
+	}
+	if m.Path != "" && m.List == "" {
+		return nil, errors.New("MSC path requires a list")
+	}
+
+	data := []byte{}
+	for index, part := range []string{m.Cue, m.List, m.Path} {
+		if part == "" {
+			break
+		}
+		if !validCueNumber(part) {
+			return nil, fmt.Errorf("MSC cue numbers may only contain digits and '.': %s", part)
+		}
+		if index > 0 {
+			data = append(data, 0x00)
+		}
+		data = append(data, part...)
+	}
+	return data, nil
+}
+
+func IsMSC(data []byte) bool {
+	return len(data) >= 6 && data[0] == 0xf0 && data[1] == universalRealTime && data[3] == subIDShowControl
+}
+
+func Decode(data []byte) (*Message, error) {
+	if !IsMSC(data) {
+		return nil, errors.New("not a MIDI Show Control message")
+	}
+	if data[len(data)-1] != 0xf7 {
+		return nil, errors.New("MSC message must end with 0xf7")
+	}
+
+	message := &Message{
+		DeviceID:      data[2],
+		CommandFormat: data[4],
+		Command:       data[5],
+	}
+	body := data[6 : len(data)-1]
+
+	switch message.Command {
+	case CommandGo, CommandStop, CommandResume, CommandLoad, CommandGoOff, CommandTimedGo:
+		if message.Command == CommandTimedGo {
+			if len(body) < 5 {
+				return nil, errors.New("MSC timed_go is missing its time")
+			}
+			message.Time = timeFromBytes(body[:5])
+			body = body[5:]
+		}
+		//NOTE(jwetzell): some consoles terminate the cue data with an extra 0x00
+		body = bytes.TrimRight(body, "\x00")
+		if len(body) > 0 {
+			parts := bytes.SplitN(body, []byte{0x00}, 3)
+			for index, part := range parts {
+				if !validCueNumber(string(part)) {
+					return nil, fmt.Errorf("MSC cue data is invalid: %q", part)
+				}
+				switch index {
+				case 0:
+					message.Cue = string(part)
+				case 1:
+					message.List = string(part)
+				case 2:
+					message.Path = string(part)
+				}
+			}
+		}
+	case CommandSet:
+		if len(body) < 4 {
+			return nil, errors.New("MSC set is missing its control and value")
+		}
+		message.Control = uint16(body[0]&0x7f) | uint16(body[1]&0x7f)<<7
+		message.Value = uint16(body[2]&0x7f) | uint16(body[3]&0x7f)<<7
+		if len(body) >= 9 {
+			message.Time = timeFromBytes(body[4:9])
+		}
+	case CommandFire:
+		if len(body) < 1 {
+			return nil, errors.New("MSC fire is missing its macro number")
+		}
+		message.Macro = body[0] & 0x7f
+	}
+	return message, nil
+}
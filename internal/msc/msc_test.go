@@ -0,0 +1,124 @@
+package msc_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/msc"
+	"github.com/jwetzell/showbridge-go/internal/timecode"
+)
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  msc.Message
+		expected []byte
+	}{
+		{
+			name:     "go with cue list and path",
+			message:  msc.Message{DeviceID: 1, CommandFormat: msc.CommandFormatLighting, Command: msc.CommandGo, Cue: "1.5", List: "2", Path: "3"},
+			expected: []byte{0xf0, 0x7f, 0x01, 0x02, 0x01, 0x01, '1', '.', '5', 0x00, '2', 0x00, '3', 0xf7},
+		},
+		{
+			name:     "stop without cue",
+			message:  msc.Message{DeviceID: msc.AllCall, CommandFormat: msc.CommandFormatSound, Command: msc.CommandStop},
+			expected: []byte{0xf0, 0x7f, 0x7f, 0x02, 0x10, 0x02, 0xf7},
+		},
+		{
+			name:     "timed go",
+			message:  msc.Message{CommandFormat: msc.CommandFormatAll, Command: msc.CommandTimedGo, Cue: "4", Time: &msc.Time{Rate: 3, Hours: 1, Minutes: 2, Seconds: 3, Frames: 4, Subframes: 5}},
+			expected: []byte{0xf0, 0x7f, 0x00, 0x02, 0x7f, 0x04, 0x61, 0x02, 0x03, 0x04, 0x05, '4', 0xf7},
+		},
+		{
+			name:     "set",
+			message:  msc.Message{DeviceID: 2, CommandFormat: msc.CommandFormatLighting, Command: msc.CommandSet, Control: 300, Value: 16383},
+			expected: []byte{0xf0, 0x7f, 0x02, 0x02, 0x01, 0x06, 0x2c, 0x02, 0x7f, 0x7f, 0xf7},
+		},
+		{
+			name:     "fire",
+			message:  msc.Message{DeviceID: 2, CommandFormat: msc.CommandFormatLighting, Command: msc.CommandFire, Macro: 12},
+			expected: []byte{0xf0, 0x7f, 0x02, 0x02, 0x01, 0x07, 0x0c, 0xf7},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.message.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary failed: %s", err)
+			}
+			if !slices.Equal(got, test.expected) {
+				t.Fatalf("MarshalBinary got %x, expected %x", got, test.expected)
+			}
+
+			decoded, err := msc.Decode(got)
+			if err != nil {
+				t.Fatalf("Decode failed: %s", err)
+			}
+			if !reflect.DeepEqual(*decoded, test.message) {
+				t.Fatalf("Decode got %+v, expected %+v", *decoded, test.message)
+			}
+		})
+	}
+}
+
+func TestBadMarshal(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     msc.Message
+		errorString string
+	}{
+		{name: "no command format", message: msc.Message{Command: msc.CommandGo}, errorString: "MSC command format must be between 1 and 127"},
+		{name: "timed go without time", message: msc.Message{CommandFormat: 1, Command: msc.CommandTimedGo}, errorString: "MSC timed_go requires a time"},
+		{name: "list without cue", message: msc.Message{CommandFormat: 1, Command: msc.CommandGo, List: "1"}, errorString: "MSC list requires a cue"},
+		{name: "bad cue", message: msc.Message{CommandFormat: 1, Command: msc.CommandGo, Cue: "1a"}, errorString: "MSC cue numbers may only contain digits and '.': 1a"},
+		{name: "set out of range", message: msc.Message{CommandFormat: 1, Command: msc.CommandSet, Control: 16384}, errorString: "MSC set control and value must be between 0 and 16383"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.message.MarshalBinary()
+			if err == nil || err.Error() != test.errorString {
+				t.Fatalf("got error '%v', expected '%s'", err, test.errorString)
+			}
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	command, err := msc.CommandFromString("TIMED_GO")
+	if err != nil || command != msc.CommandTimedGo {
+		t.Fatalf("CommandFromString got %d %v", command, err)
+	}
+
+	commandFormat, err := msc.CommandFormatFromString("0x10")
+	if err != nil || msc.CommandFormatName(commandFormat) != "sound" {
+		t.Fatalf("CommandFormatFromString got %d %v", commandFormat, err)
+	}
+
+	_, err = msc.CommandFormatFromString("toasters")
+	if err == nil || err.Error() != "unknown MSC command format: toasters" {
+		t.Fatalf("CommandFormatFromString should reject unknown names, got %v", err)
+	}
+
+	parsed, err := msc.ParseTime("01:02:03:04.05", timecode.Rate30)
+	if err != nil || parsed.String() != "01:02:03:04.05" || parsed.Rate != 3 {
+		t.Fatalf("ParseTime got %v %v", parsed, err)
+	}
+
+	parsed, err = msc.ParseTime("01:02:03:24", timecode.Rate25)
+	if err != nil || parsed.Rate != 1 || parsed.Frames != 24 {
+		t.Fatalf("ParseTime at 25 fps got %v %v", parsed, err)
+	}
+
+	_, err = msc.ParseTime("01:02:03:24", timecode.Rate24)
+	if err == nil || err.Error() != "timecode is out of range: 01:02:03:24" {
+		t.Fatalf("ParseTime should reject frames past the rate, got %v", err)
+	}
+
+	_, err = msc.ParseTime("00:01:00;00", timecode.Rate2997DF)
+	if err == nil || err.Error() != "timecode 00:01:00;00 does not exist in drop frame" {
+		t.Fatalf("ParseTime should reject dropped frame labels, got %v", err)
+	}
+}
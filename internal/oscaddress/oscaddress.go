@@ -0,0 +1,245 @@
+// Package oscaddress matches OSC addresses against OSC 1.0 address patterns
+package oscaddress
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenLiteral tokenKind = iota
+	tokenAnyChar
+	tokenAnyString
+	tokenClass
+	tokenAlternation
+)
+
+type classRange struct {
+	low  rune
+	high rune
+}
+
+type token struct {
+	kind         tokenKind
+	literal      rune
+	negate       bool
+	ranges       []classRange
+	alternatives []string
+}
+
+type segment struct {
+	name   string
+	tokens []token
+	//NOTE(jwetzell): literal segments are not reported as captures
+	literal bool
+}
+
+// Pattern is a compiled OSC address pattern. On top of the OSC 1.0 rules a segment of the form :name matches any single segment and captures it by name.
+type Pattern struct {
+	pattern  string
+	segments []segment
+}
+
+type Match struct {
+	Segments []string          `json:"segments"`
+	Captures []string          `json:"captures"`
+	Params   map[string]string `json:"params"`
+}
+
+func Compile(pattern string) (*Pattern, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, errors.New("OSC address pattern must start with '/'")
+	}
+
+	compiled := &Pattern{pattern: pattern}
+	names := map[string]bool{}
+	for segmentIndex, segmentString := range strings.Split(pattern[1:], "/") {
+		if strings.HasPrefix(segmentString, ":") {
+			name := segmentString[1:]
+			if name == "" {
+				return nil, fmt.Errorf("OSC address pattern segment %d has an empty name", segmentIndex)
+			}
+			if names[name] {
+				return nil, fmt.Errorf("OSC address pattern uses the name %s more than once", name)
+			}
+			names[name] = true
+			compiled.segments = append(compiled.segments, segment{name: name, tokens: []token{{kind: tokenAnyString}}})
+			continue
+		}
+
+		tokens, err := compileSegment(segmentString)
+		if err != nil {
+			return nil, fmt.Errorf("OSC address pattern segment %d: %w", segmentIndex, err)
+		}
+		literal := true
+		for _, token := range tokens {
+			if token.kind != tokenLiteral {
+				literal = false
+			}
+		}
+		compiled.segments = append(compiled.segments, segment{tokens: tokens, literal: literal})
+	}
+	return compiled, nil
+}
+
+func compileSegment(segmentString string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(segmentString)
+	for index := 0; index < len(runes); index++ {
+		switch runes[index] {
+		case '?':
+			tokens = append(tokens, token{kind: tokenAnyChar})
+		case '*':
+			//NOTE(jwetzell): repeated stars match the same thing as one
+			if len(tokens) > 0 && tokens[len(tokens)-1].kind == tokenAnyString {
+				continue
+			}
+			tokens = append(tokens, token{kind: tokenAnyString})
+		case '[':
+			end := index + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.New("unclosed '['")
+			}
+			classToken := token{kind: tokenClass}
+			class := runes[index+1 : end]
+			if len(class) > 0 && class[0] == '!' {
+				classToken.negate = true
+				class = class[1:]
+			}
+			if len(class) == 0 {
+				return nil, errors.New("empty '[]'")
+			}
+			for classIndex := 0; classIndex < len(class); classIndex++ {
+				low := class[classIndex]
+				high := low
+				//NOTE(jwetzell): a '-' at the start or end of the brackets is a literal '-'
+				if classIndex+2 < len(class) && class[classIndex+1] == '-' {
+					high = class[classIndex+2]
+					classIndex += 2
+				}
+				if high < low {
+					low, high = high, low
+				}
+				classToken.ranges = append(classToken.ranges, classRange{low: low, high: high})
+			}
+			tokens = append(tokens, classToken)
+			index = end
+		case '{':
+			end := index + 1
+			for end < len(runes) && runes[end] != '}' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.New("unclosed '{'")
+			}
+			tokens = append(tokens, token{kind: tokenAlternation, alternatives: strings.Split(string(runes[index+1:end]), ",")})
+			index = end
+		case ']', '}':
+			return nil, fmt.Errorf("unexpected '%c'", runes[index])
+		default:
+			tokens = append(tokens, token{kind: tokenLiteral, literal: runes[index]})
+		}
+	}
+	return tokens, nil
+}
+
+func (p *Pattern) String() string {
+	return p.pattern
+}
+
+// Match reports whether address matches the pattern, capturing the segments matched by wildcards and names
+func (p *Pattern) Match(address string) (Match, bool) {
+	if !strings.HasPrefix(address, "/") {
+		return Match{}, false
+	}
+
+	addressSegments := strings.Split(address[1:], "/")
+	if len(addressSegments) != len(p.segments) {
+		return Match{}, false
+	}
+
+	match := Match{
+		Segments: addressSegments,
+		Captures: []string{},
+		Params:   map[string]string{},
+	}
+
+	for segmentIndex, segment := range p.segments {
+		addressSegment := addressSegments[segmentIndex]
+		if segment.name != "" && addressSegment == "" {
+			return Match{}, false
+		}
+		if !matchTokens(segment.tokens, []rune(addressSegment)) {
+			return Match{}, false
+		}
+		if segment.literal {
+			continue
+		}
+		match.Captures = append(match.Captures, addressSegment)
+		if segment.name != "" {
+			match.Params[segment.name] = addressSegment
+		}
+	}
+	return match, true
+}
+
+func matchTokens(tokens []token, runes []rune) bool {
+	if len(tokens) == 0 {
+		return len(runes) == 0
+	}
+
+	current := tokens[0]
+	switch current.kind {
+	case tokenLiteral:
+		return len(runes) > 0 && runes[0] == current.literal && matchTokens(tokens[1:], runes[1:])
+	case tokenAnyChar:
+		return len(runes) > 0 && matchTokens(tokens[1:], runes[1:])
+	case tokenAnyString:
+		for consumed := 0; consumed <= len(runes); consumed++ {
+			if matchTokens(tokens[1:], runes[consumed:]) {
+				return true
+			}
+		}
+		return false
+	case tokenClass:
+		if len(runes) == 0 {
+			return false
+		}
+		inClass := false
+		for _, classRange := range current.ranges {
+			if runes[0] >= classRange.low && runes[0] <= classRange.high {
+				inClass = true
+				break
+			}
+		}
+		return inClass != current.negate && matchTokens(tokens[1:], runes[1:])
+	case tokenAlternation:
+		for _, alternative := range current.alternatives {
+			alternativeRunes := []rune(alternative)
+			if len(alternativeRunes) <= len(runes) && string(runes[:len(alternativeRunes)]) == alternative && matchTokens(tokens[1:], runes[len(alternativeRunes):]) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// MatchTypes checks OSC type tags against an expected type string where '?' stands for any one type
+func MatchTypes(expected string, types string) bool {
+	if len(expected) != len(types) {
+		return false
+	}
+	for index := range len(expected) {
+		if expected[index] != '?' && expected[index] != types[index] {
+			return false
+		}
+	}
+	return true
+}
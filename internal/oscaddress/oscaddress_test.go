@@ -0,0 +1,98 @@
+package oscaddress_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/oscaddress"
+)
+
+func TestPatternMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		address  string
+		matches  bool
+		captures []string
+		params   map[string]string
+	}{
+		{name: "literal", pattern: "/eos/out/cmd", address: "/eos/out/cmd", matches: true, captures: []string{}, params: map[string]string{}},
+		{name: "literal mismatch", pattern: "/eos/out/cmd", address: "/eos/out/cmds"},
+		{name: "star", pattern: "/eos/out/active/chan/*", address: "/eos/out/active/chan/12", matches: true, captures: []string{"12"}, params: map[string]string{}},
+		{name: "star does not cross segments", pattern: "/eos/*", address: "/eos/out/cmd"},
+		{name: "star inside segment", pattern: "/fader*/level", address: "/fader12/level", matches: true, captures: []string{"fader12"}, params: map[string]string{}},
+		{name: "question mark", pattern: "/ch?", address: "/ch1", matches: true, captures: []string{"ch1"}, params: map[string]string{}},
+		{name: "question mark needs a character", pattern: "/ch?", address: "/ch"},
+		{name: "class range", pattern: "/ch[1-3]", address: "/ch2", matches: true, captures: []string{"ch2"}, params: map[string]string{}},
+		{name: "class range miss", pattern: "/ch[1-3]", address: "/ch4"},
+		{name: "negated class", pattern: "/ch[!1-3]", address: "/ch4", matches: true, captures: []string{"ch4"}, params: map[string]string{}},
+		{name: "literal dash in class", pattern: "/a[-x]", address: "/a-", matches: true, captures: []string{"a-"}, params: map[string]string{}},
+		{name: "alternation", pattern: "/{go,stop}/cue", address: "/stop/cue", matches: true, captures: []string{"stop"}, params: map[string]string{}},
+		{name: "alternation miss", pattern: "/{go,stop}/cue", address: "/pause/cue"},
+		{name: "named segment", pattern: "/eos/out/active/chan/:channel", address: "/eos/out/active/chan/5", matches: true, captures: []string{"5"}, params: map[string]string{"channel": "5"}},
+		{name: "named segment not empty", pattern: "/chan/:channel", address: "/chan/"},
+		{name: "mixed", pattern: "/:console/fader[0-9]*/level", address: "/x32/fader10/level", matches: true, captures: []string{"x32", "fader10"}, params: map[string]string{"console": "x32"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pattern, err := oscaddress.Compile(test.pattern)
+			if err != nil {
+				t.Fatalf("Compile failed: %s", err)
+			}
+
+			match, ok := pattern.Match(test.address)
+			if ok != test.matches {
+				t.Fatalf("Match got %t, expected %t", ok, test.matches)
+			}
+
+			if !ok {
+				return
+			}
+
+			if !reflect.DeepEqual(match.Captures, test.captures) {
+				t.Fatalf("Match captures got %+v, expected %+v", match.Captures, test.captures)
+			}
+
+			if !reflect.DeepEqual(match.Params, test.params) {
+				t.Fatalf("Match params got %+v, expected %+v", match.Params, test.params)
+			}
+		})
+	}
+}
+
+func TestBadCompile(t *testing.T) {
+	tests := []struct {
+		name        string
+		pattern     string
+		errorString string
+	}{
+		{name: "no slash", pattern: "eos", errorString: "OSC address pattern must start with '/'"},
+		{name: "unclosed bracket", pattern: "/ch[1-3", errorString: "OSC address pattern segment 0: unclosed '['"},
+		{name: "unclosed brace", pattern: "/a/{go,stop", errorString: "OSC address pattern segment 1: unclosed '{'"},
+		{name: "stray brace", pattern: "/a}", errorString: "OSC address pattern segment 0: unexpected '}'"},
+		{name: "empty name", pattern: "/a/:", errorString: "OSC address pattern segment 1 has an empty name"},
+		{name: "duplicate name", pattern: "/:a/:a", errorString: "OSC address pattern uses the name a more than once"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := oscaddress.Compile(test.pattern)
+			if err == nil {
+				t.Fatalf("Compile expected to fail")
+			}
+			if err.Error() != test.errorString {
+				t.Fatalf("Compile got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
+
+func TestMatchTypes(t *testing.T) {
+	if !oscaddress.MatchTypes("if", "if") || !oscaddress.MatchTypes("?s", "fs") {
+		t.Fatalf("MatchTypes should match")
+	}
+	if oscaddress.MatchTypes("i", "f") || oscaddress.MatchTypes("i", "ii") {
+		t.Fatalf("MatchTypes should not match")
+	}
+}
@@ -0,0 +1,124 @@
+// Package oscbundle handles OSC bundles with readable and settable timetags, which osc-go keeps unexported
+package oscbundle
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jwetzell/osc-go"
+)
+
+// TimeTag is an NTP timestamp, seconds since 1900 in the upper 32 bits and the fraction in the lower 32
+type TimeTag uint64
+
+// Immediately is the special timetag meaning the bundle should be acted on as soon as it arrives
+const Immediately TimeTag = 1
+
+const ntpEpochOffset = 2208988800
+
+var bundleHeader = []byte{'#', 'b', 'u', 'n', 'd', 'l', 'e', 0x00}
+
+func TimeTagFromTime(t time.Time) TimeTag {
+	seconds := uint64(t.Unix() + ntpEpochOffset)
+	fraction := (uint64(t.Nanosecond()) << 32) / uint64(time.Second)
+	return TimeTag(seconds<<32 | fraction)
+}
+
+func (tt TimeTag) IsImmediate() bool {
+	return tt <= Immediately
+}
+
+func (tt TimeTag) Time() time.Time {
+	seconds := int64(tt>>32) - ntpEpochOffset
+	nanoseconds := (int64(tt&0xffffffff) * int64(time.Second)) >> 32
+	return time.Unix(seconds, nanoseconds)
+}
+
+type Bundle struct {
+	TimeTag  TimeTag         `json:"timeTag"`
+	Contents []osc.OSCPacket `json:"contents"`
+}
+
+// ToBytes makes Bundle an osc.OSCPacket so it can be nested and sent like any other packet
+func (b *Bundle) ToBytes() ([]byte, error) {
+	bytes := make([]byte, 16, 64)
+	copy(bytes, bundleHeader)
+	binary.BigEndian.PutUint64(bytes[8:16], uint64(b.TimeTag))
+
+	for _, packet := range b.Contents {
+		if packet == nil {
+			return nil, errors.New("OSC bundle cannot contain a nil packet")
+		}
+		packetBytes, err := packet.ToBytes()
+		if err != nil {
+			return nil, err
+		}
+		bytes = binary.BigEndian.AppendUint32(bytes, uint32(len(packetBytes)))
+		bytes = append(bytes, packetBytes...)
+	}
+	return bytes, nil
+}
+
+// Messages returns every message in the bundle in order, including ones in nested bundles
+func (b *Bundle) Messages() []*osc.OSCMessage {
+	messages := []*osc.OSCMessage{}
+	for _, packet := range b.Contents {
+		switch typedPacket := packet.(type) {
+		case *osc.OSCMessage:
+			messages = append(messages, typedPacket)
+		case *Bundle:
+			messages = append(messages, typedPacket.Messages()...)
+		}
+	}
+	return messages
+}
+
+func IsBundle(bytes []byte) bool {
+	return len(bytes) >= 16 && string(bytes[:8]) == string(bundleHeader)
+}
+
+func Decode(bytes []byte) (*Bundle, error) {
+	if !IsBundle(bytes) {
+		return nil, errors.New("OSC bundle must start with #bundle and a timetag")
+	}
+
+	bundle := &Bundle{
+		TimeTag:  TimeTag(binary.BigEndian.Uint64(bytes[8:16])),
+		Contents: []osc.OSCPacket{},
+	}
+
+	remaining := bytes[16:]
+	for len(remaining) > 0 {
+		if len(remaining) < 4 {
+			return nil, errors.New("OSC bundle element is missing its size")
+		}
+		size := int(binary.BigEndian.Uint32(remaining[:4]))
+		remaining = remaining[4:]
+		if size == 0 || size > len(remaining) {
+			return nil, fmt.Errorf("OSC bundle element size %d does not fit in the bundle", size)
+		}
+
+		elementBytes := remaining[:size]
+		remaining = remaining[size:]
+
+		switch elementBytes[0] {
+		case '#':
+			nested, err := Decode(elementBytes)
+			if err != nil {
+				return nil, err
+			}
+			bundle.Contents = append(bundle.Contents, nested)
+		case '/':
+			message, err := osc.MessageFromBytes(elementBytes)
+			if err != nil {
+				return nil, err
+			}
+			bundle.Contents = append(bundle.Contents, message)
+		default:
+			return nil, errors.New("OSC bundle element does not look like a bundle or message")
+		}
+	}
+	return bundle, nil
+}
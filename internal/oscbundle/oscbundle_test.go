@@ -0,0 +1,110 @@
+package oscbundle_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/osc-go"
+	"github.com/jwetzell/showbridge-go/internal/oscbundle"
+)
+
+func TestTimeTag(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 30, 15, 250_000_000, time.UTC)
+	timeTag := oscbundle.TimeTagFromTime(now)
+
+	if timeTag>>32 != 4001401815 {
+		t.Fatalf("TimeTagFromTime seconds wrong: %d", timeTag>>32)
+	}
+
+	if timeTag&0xffffffff != 1<<30 {
+		t.Fatalf("TimeTagFromTime fraction wrong: %d", timeTag&0xffffffff)
+	}
+
+	if !timeTag.Time().Equal(now) {
+		t.Fatalf("TimeTag.Time got %s, expected %s", timeTag.Time(), now)
+	}
+
+	if !oscbundle.Immediately.IsImmediate() || timeTag.IsImmediate() {
+		t.Fatalf("IsImmediate wrong")
+	}
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	bundle := &oscbundle.Bundle{
+		TimeTag: oscbundle.Immediately,
+		Contents: []osc.OSCPacket{
+			&osc.OSCMessage{Address: "/eos/out/cmd", Args: []osc.OSCArg{{Type: "s", Value: "Go"}}},
+			&oscbundle.Bundle{
+				TimeTag:  oscbundle.TimeTag(42),
+				Contents: []osc.OSCPacket{&osc.OSCMessage{Address: "/nested", Args: []osc.OSCArg{{Type: "i", Value: int32(1)}}}},
+			},
+		},
+	}
+
+	bundleBytes, err := bundle.ToBytes()
+	if err != nil {
+		t.Fatalf("ToBytes failed: %s", err)
+	}
+
+	//NOTE(jwetzell): the library decoder should agree with the encoded layout
+	_, _, err = osc.BundleFromBytes(bundleBytes)
+	if err != nil {
+		t.Fatalf("osc-go could not decode bundle: %s", err)
+	}
+
+	decoded, err := oscbundle.Decode(bundleBytes)
+	if err != nil {
+		t.Fatalf("Decode failed: %s", err)
+	}
+
+	if !reflect.DeepEqual(decoded, bundle) {
+		t.Fatalf("Decode got %+v, expected %+v", decoded, bundle)
+	}
+
+	messages := decoded.Messages()
+	if len(messages) != 2 || messages[0].Address != "/eos/out/cmd" || messages[1].Address != "/nested" {
+		t.Fatalf("Messages got %+v", messages)
+	}
+}
+
+func TestBadDecode(t *testing.T) {
+	goodBytes, err := (&oscbundle.Bundle{Contents: []osc.OSCPacket{&osc.OSCMessage{Address: "/a"}}}).ToBytes()
+	if err != nil {
+		t.Fatalf("ToBytes failed: %s", err)
+	}
+
+	tests := []struct {
+		name        string
+		data        []byte
+		errorString string
+	}{
+		{
+			name:        "message",
+			data:        []byte("/a\x00\x00,\x00\x00\x00"),
+			errorString: "OSC bundle must start with #bundle and a timetag",
+		},
+		{
+			name:        "truncated element",
+			data:        goodBytes[:len(goodBytes)-1],
+			errorString: "OSC bundle element size 8 does not fit in the bundle",
+		},
+		{
+			name:        "missing size",
+			data:        append(goodBytes, 0x00),
+			errorString: "OSC bundle element is missing its size",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := oscbundle.Decode(test.data)
+			if err == nil {
+				t.Fatalf("Decode expected to fail")
+			}
+			if err.Error() != test.errorString {
+				t.Fatalf("Decode got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
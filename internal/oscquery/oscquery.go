@@ -0,0 +1,209 @@
+// Package oscquery holds an OSCQuery namespace and the JSON shapes OSCQuery clients expect
+package oscquery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	AccessNone      = 0
+	AccessRead      = 1
+	AccessWrite     = 2
+	AccessReadWrite = 3
+)
+
+const (
+	CommandListen = "LISTEN"
+	CommandIgnore = "IGNORE"
+)
+
+type Range struct {
+	Min  *float64 `json:"MIN,omitempty"`
+	Max  *float64 `json:"MAX,omitempty"`
+	Vals []any    `json:"VALS,omitempty"`
+}
+
+type Node struct {
+	FullPath    string           `json:"FULL_PATH"`
+	Contents    map[string]*Node `json:"CONTENTS,omitempty"`
+	Type        string           `json:"TYPE,omitempty"`
+	Access      int              `json:"ACCESS"`
+	Description string           `json:"DESCRIPTION,omitempty"`
+	Value       []any            `json:"VALUE,omitempty"`
+	Range       []Range          `json:"RANGE,omitempty"`
+}
+
+type HostInfo struct {
+	Name         string          `json:"NAME"`
+	OSCPort      int             `json:"OSC_PORT"`
+	OSCTransport string          `json:"OSC_TRANSPORT"`
+	Extensions   map[string]bool `json:"EXTENSIONS"`
+}
+
+// Command is a JSON message sent by clients over the websocket, DATA is the path for LISTEN and IGNORE
+type Command struct {
+	Command string `json:"COMMAND"`
+	Data    any    `json:"DATA"`
+}
+
+func NewHostInfo(name string, oscPort int) HostInfo {
+	return HostInfo{
+		Name:         name,
+		OSCPort:      oscPort,
+		OSCTransport: "UDP",
+		Extensions: map[string]bool{
+			"ACCESS":       true,
+			"VALUE":        true,
+			"RANGE":        true,
+			"DESCRIPTION":  true,
+			"TYPE":         true,
+			"LISTEN":       true,
+			"PATH_CHANGED": false,
+		},
+	}
+}
+
+type Namespace struct {
+	root  *Node
+	nodes map[string]*Node
+	mu    sync.RWMutex
+}
+
+func NewNamespace() *Namespace {
+	root := &Node{FullPath: "/", Contents: map[string]*Node{}, Access: AccessNone}
+	return &Namespace{root: root, nodes: map[string]*Node{"/": root}}
+}
+
+// Add puts a method node into the namespace, creating any containers above it
+func (n *Namespace) Add(node Node) error {
+	if !strings.HasPrefix(node.FullPath, "/") || node.FullPath == "/" || strings.HasSuffix(node.FullPath, "/") {
+		return fmt.Errorf("OSCQuery path %s must start with '/' and not end with '/'", node.FullPath)
+	}
+
+	if strings.ContainsAny(node.FullPath, " #*,?[]{}") {
+		return fmt.Errorf("OSCQuery path %s contains characters not allowed in an OSC address", node.FullPath)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, ok := n.nodes[node.FullPath]; ok {
+		return fmt.Errorf("OSCQuery path %s is already in the namespace", node.FullPath)
+	}
+
+	parent := n.root
+	segments := strings.Split(node.FullPath[1:], "/")
+	for segmentIndex, segment := range segments[:len(segments)-1] {
+		containerPath := "/" + strings.Join(segments[:segmentIndex+1], "/")
+		container, ok := parent.Contents[segment]
+		if !ok {
+			container = &Node{FullPath: containerPath, Contents: map[string]*Node{}, Access: AccessNone}
+			parent.Contents[segment] = container
+			n.nodes[containerPath] = container
+		}
+		if container.Contents == nil {
+			return fmt.Errorf("OSCQuery path %s is below the method %s", node.FullPath, containerPath)
+		}
+		parent = container
+	}
+
+	if existing, ok := parent.Contents[segments[len(segments)-1]]; ok && existing.Contents != nil {
+		return fmt.Errorf("OSCQuery path %s is already a container", node.FullPath)
+	}
+
+	method := node
+	method.Contents = nil
+	method.Value = slices.Clone(node.Value)
+	parent.Contents[segments[len(segments)-1]] = &method
+	n.nodes[node.FullPath] = &method
+	return nil
+}
+
+// Method returns a copy of the method node at path
+func (n *Namespace) Method(path string) (Node, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	node, ok := n.nodes[path]
+	if !ok || node.Contents != nil {
+		return Node{}, false
+	}
+	method := *node
+	method.Value = slices.Clone(node.Value)
+	return method, true
+}
+
+func (n *Namespace) SetValue(path string, value []any) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	node, ok := n.nodes[path]
+	if !ok || node.Contents != nil {
+		return fmt.Errorf("OSCQuery path %s is not a method in the namespace", path)
+	}
+	node.Value = slices.Clone(value)
+	return nil
+}
+
+// MarshalNode returns the JSON for the node at path and whether the node exists
+func (n *Namespace) MarshalNode(path string) ([]byte, bool, error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	node, ok := n.nodes[path]
+	if !ok {
+		return nil, false, nil
+	}
+	nodeBytes, err := json.Marshal(node)
+	return nodeBytes, true, err
+}
+
+// MarshalAttribute returns {"ATTRIBUTE": value} for a single attribute of the node at path, nil bytes mean the node lacks the attribute
+func (n *Namespace) MarshalAttribute(path string, attribute string) ([]byte, bool, error) {
+	nodeBytes, ok, err := n.MarshalNode(path)
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+
+	attributes := map[string]json.RawMessage{}
+	err = json.Unmarshal(nodeBytes, &attributes)
+	if err != nil {
+		return nil, true, err
+	}
+
+	attributeValue, ok := attributes[attribute]
+	if !ok {
+		return nil, true, nil
+	}
+
+	attributeBytes, err := json.Marshal(map[string]json.RawMessage{attribute: attributeValue})
+	return attributeBytes, true, err
+}
+
+func ParseCommand(data []byte) (Command, error) {
+	command := Command{}
+	err := json.Unmarshal(data, &command)
+	if err != nil {
+		return Command{}, err
+	}
+	if command.Command == "" {
+		return Command{}, errors.New("OSCQuery command is missing COMMAND")
+	}
+	return command, nil
+}
+
+// AccessFromString turns r, w and rw into OSCQuery access values
+func AccessFromString(access string) (int, error) {
+	switch access {
+	case "r":
+		return AccessRead, nil
+	case "w":
+		return AccessWrite, nil
+	case "rw":
+		return AccessReadWrite, nil
+	default:
+		return AccessNone, fmt.Errorf("OSCQuery access must be r, w or rw, got %s", access)
+	}
+}
@@ -0,0 +1,134 @@
+package oscquery_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/oscquery"
+)
+
+func TestNamespace(t *testing.T) {
+	namespace := oscquery.NewNamespace()
+
+	minimum := 0.0
+	maximum := 1.0
+	err := namespace.Add(oscquery.Node{
+		FullPath: "/mixer/fader/1",
+		Type:     "f",
+		Access:   oscquery.AccessReadWrite,
+		Value:    []any{0.5},
+		Range:    []oscquery.Range{{Min: &minimum, Max: &maximum}},
+	})
+	if err != nil {
+		t.Fatalf("Add failed: %s", err)
+	}
+
+	err = namespace.Add(oscquery.Node{FullPath: "/mixer/mute", Type: "T", Access: oscquery.AccessRead})
+	if err != nil {
+		t.Fatalf("Add failed: %s", err)
+	}
+
+	rootBytes, ok, err := namespace.MarshalNode("/")
+	if err != nil || !ok {
+		t.Fatalf("MarshalNode failed: %t %v", ok, err)
+	}
+
+	root := map[string]any{}
+	err = json.Unmarshal(rootBytes, &root)
+	if err != nil {
+		t.Fatalf("root JSON did not parse: %s", err)
+	}
+
+	fader := root["CONTENTS"].(map[string]any)["mixer"].(map[string]any)["CONTENTS"].(map[string]any)["fader"].(map[string]any)["CONTENTS"].(map[string]any)["1"].(map[string]any)
+	expected := map[string]any{
+		"FULL_PATH": "/mixer/fader/1",
+		"TYPE":      "f",
+		"ACCESS":    float64(3),
+		"VALUE":     []any{0.5},
+		"RANGE":     []any{map[string]any{"MIN": float64(0), "MAX": float64(1)}},
+	}
+	if !reflect.DeepEqual(fader, expected) {
+		t.Fatalf("fader node got %+v, expected %+v", fader, expected)
+	}
+
+	err = namespace.SetValue("/mixer/fader/1", []any{float32(0.75)})
+	if err != nil {
+		t.Fatalf("SetValue failed: %s", err)
+	}
+
+	valueBytes, ok, err := namespace.MarshalAttribute("/mixer/fader/1", "VALUE")
+	if err != nil || !ok {
+		t.Fatalf("MarshalAttribute failed: %t %v", ok, err)
+	}
+	if string(valueBytes) != `{"VALUE":[0.75]}` {
+		t.Fatalf("MarshalAttribute got %s", valueBytes)
+	}
+
+	valueBytes, ok, err = namespace.MarshalAttribute("/mixer/mute", "VALUE")
+	if err != nil || !ok || valueBytes != nil {
+		t.Fatalf("MarshalAttribute for a missing attribute got %s %t %v", valueBytes, ok, err)
+	}
+
+	_, ok, _ = namespace.MarshalNode("/nope")
+	if ok {
+		t.Fatalf("MarshalNode should not find /nope")
+	}
+
+	if _, ok := namespace.Method("/mixer"); ok {
+		t.Fatalf("Method should not return containers")
+	}
+
+	err = namespace.SetValue("/mixer", []any{1})
+	if err == nil {
+		t.Fatalf("SetValue should fail on a container")
+	}
+}
+
+func TestBadNamespaceAdd(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		errorString string
+	}{
+		{name: "root", path: "/", errorString: "OSCQuery path / must start with '/' and not end with '/'"},
+		{name: "no slash", path: "fader", errorString: "OSCQuery path fader must start with '/' and not end with '/'"},
+		{name: "pattern characters", path: "/fader/*", errorString: "OSCQuery path /fader/* contains characters not allowed in an OSC address"},
+		{name: "duplicate", path: "/a/b", errorString: "OSCQuery path /a/b is already in the namespace"},
+		{name: "container", path: "/a", errorString: "OSCQuery path /a is already in the namespace"},
+		{name: "below method", path: "/a/b/c", errorString: "OSCQuery path /a/b/c is below the method /a/b"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			namespace := oscquery.NewNamespace()
+			err := namespace.Add(oscquery.Node{FullPath: "/a/b", Type: "i"})
+			if err != nil {
+				t.Fatalf("Add failed: %s", err)
+			}
+
+			err = namespace.Add(oscquery.Node{FullPath: test.path, Type: "i"})
+			if err == nil {
+				t.Fatalf("Add expected to fail")
+			}
+			if err.Error() != test.errorString {
+				t.Fatalf("Add got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	command, err := oscquery.ParseCommand([]byte(`{"COMMAND":"LISTEN","DATA":"/a/b"}`))
+	if err != nil {
+		t.Fatalf("ParseCommand failed: %s", err)
+	}
+	if command.Command != oscquery.CommandListen || command.Data != "/a/b" {
+		t.Fatalf("ParseCommand got %+v", command)
+	}
+
+	_, err = oscquery.ParseCommand([]byte(`{"DATA":"/a/b"}`))
+	if err == nil {
+		t.Fatalf("ParseCommand should fail without COMMAND")
+	}
+}
@@ -0,0 +1,133 @@
+package pjlink
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// Client keeps a connection to one projector, reconnecting and authenticating as needed
+type Client struct {
+	Address  string
+	Password string
+	Timeout  time.Duration
+	mu       sync.Mutex
+	conn     net.Conn
+	reader   *bufio.Reader
+	digest   string
+}
+
+func NewClient(address string, password string, timeout time.Duration) *Client {
+	return &Client{Address: address, Password: password, Timeout: timeout}
+}
+
+func (c *Client) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: c.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.Address)
+	if err != nil {
+		return err
+	}
+
+	conn.SetDeadline(time.Now().Add(c.Timeout))
+	reader := bufio.NewReader(conn)
+	greeting, err := reader.ReadString('\r')
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	authenticate, random, err := ParseGreeting(greeting)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	c.digest = ""
+	if authenticate {
+		if c.Password == "" {
+			conn.Close()
+			return errors.New("PJLink projector requires a password")
+		}
+		c.digest = AuthDigest(random, c.Password)
+	}
+
+	c.conn = conn
+	c.reader = reader
+	return nil
+}
+
+// Send sends a command and waits for its response, a dropped connection is reopened once
+func (c *Client) Send(ctx context.Context, command Command) (Response, error) {
+	data, err := command.MarshalBinary()
+	if err != nil {
+		return Response{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var response Response
+	for attempt := range 2 {
+		fresh := false
+		if c.conn == nil {
+			err = c.connect(ctx)
+			if err != nil {
+				return Response{}, err
+			}
+			fresh = true
+		}
+
+		response, err = c.exchange(data)
+		if err == nil {
+			break
+		}
+		c.close()
+		//NOTE(jwetzell): projectors drop idle connections so retry on a new one unless it was already new
+		if fresh || attempt == 1 {
+			return Response{}, err
+		}
+	}
+
+	if response.Error == "ERRA" {
+		c.close()
+	}
+	return response, nil
+}
+
+func (c *Client) exchange(data []byte) (Response, error) {
+	c.conn.SetDeadline(time.Now().Add(c.Timeout))
+
+	//NOTE(jwetzell): the digest only goes in front of the first command on a connection
+	if c.digest != "" {
+		data = append([]byte(c.digest), data...)
+		c.digest = ""
+	}
+
+	_, err := c.conn.Write(data)
+	if err != nil {
+		return Response{}, err
+	}
+
+	line, err := c.reader.ReadString('\r')
+	if err != nil {
+		return Response{}, err
+	}
+	return ParseResponse(line)
+}
+
+func (c *Client) close() {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	c.conn = nil
+	c.reader = nil
+}
+
+func (c *Client) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.close()
+}
@@ -0,0 +1,246 @@
+// Package pjlink encodes PJLink class 1 and class 2 commands, parses their responses and talks to projectors over TCP
+package pjlink
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const DefaultPort = 4352
+
+const (
+	CommandPower        = "POWR"
+	CommandInput        = "INPT"
+	CommandAVMute       = "AVMT"
+	CommandErrorStatus  = "ERST"
+	CommandLamp         = "LAMP"
+	CommandInputList    = "INST"
+	CommandName         = "NAME"
+	CommandManufacturer = "INF1"
+	CommandProduct      = "INF2"
+	CommandInformation  = "INFO"
+	CommandClass        = "CLSS"
+	CommandFreeze       = "FREZ"
+)
+
+const (
+	PowerOff     = "off"
+	PowerOn      = "on"
+	PowerCooling = "cooling"
+	PowerWarming = "warming"
+)
+
+var responseErrors = map[string]string{
+	"ERR1": "undefined command",
+	"ERR2": "out of parameter",
+	"ERR3": "unavailable time",
+	"ERR4": "projector or display failure",
+	"ERRA": "authorization error",
+}
+
+// Command is a single PJLink request, Parameter is ? for queries
+type Command struct {
+	Class     uint8
+	Name      string
+	Parameter string
+}
+
+func Query(class uint8, name string) Command {
+	return Command{Class: class, Name: name, Parameter: "?"}
+}
+
+func (c Command) Validate() error {
+	if c.Class != 1 && c.Class != 2 {
+		return fmt.Errorf("PJLink class must be 1 or 2, got %d", c.Class)
+	}
+	if len(c.Name) != 4 {
+		return fmt.Errorf("PJLink command must be 4 characters, got %s", c.Name)
+	}
+	if len(c.Parameter) > 128 {
+		return errors.New("PJLink parameter must be at most 128 characters")
+	}
+	if strings.ContainsAny(c.Parameter, "\r\n") {
+		return errors.New("PJLink parameter must not contain line breaks")
+	}
+	return nil
+}
+
+func (c Command) String() string {
+	return fmt.Sprintf("%%%d%s %s", c.Class, strings.ToUpper(c.Name), c.Parameter)
+}
+
+func (c Command) MarshalBinary() ([]byte, error) {
+	err := c.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(c.String() + "\r"), nil
+}
+
+// Response is a projector's answer to a command, Error is set when the projector answered ERR1-4 or ERRA
+type Response struct {
+	Class uint8
+	Name  string
+	Value string
+	Error string
+}
+
+// OK reports whether a set command was accepted
+func (r Response) OK() bool {
+	return r.Error == "" && r.Value == "OK"
+}
+
+// Err is the projector error as a Go error, nil when there is none
+func (r Response) Err() error {
+	if r.Error == "" {
+		return nil
+	}
+	description, ok := responseErrors[r.Error]
+	if !ok {
+		description = "unknown error"
+	}
+	return fmt.Errorf("PJLink %s %s (%s)", r.Name, r.Error, description)
+}
+
+// ParseResponse parses a response line like %1POWR=1 with or without the trailing CR
+func ParseResponse(line string) (Response, error) {
+	line = strings.TrimRight(line, "\r\n")
+	if strings.HasPrefix(line, "PJLINK ERRA") {
+		return Response{Error: "ERRA"}, nil
+	}
+	if len(line) < 7 || line[0] != '%' || line[6] != '=' {
+		return Response{}, fmt.Errorf("PJLink response is malformed: %q", line)
+	}
+
+	class, err := strconv.ParseUint(line[1:2], 10, 8)
+	if err != nil || (class != 1 && class != 2) {
+		return Response{}, fmt.Errorf("PJLink response has a bad class: %q", line)
+	}
+
+	response := Response{Class: uint8(class), Name: line[2:6]}
+	value := line[7:]
+	if _, ok := responseErrors[value]; ok {
+		response.Error = value
+		return response, nil
+	}
+	response.Value = value
+	return response, nil
+}
+
+// AuthDigest is the MD5 prefix sent before the first command when the projector asks for authentication
+func AuthDigest(random string, password string) string {
+	sum := md5.Sum([]byte(random + password))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseGreeting parses the PJLINK 0 or PJLINK 1 <random> line a projector sends when a connection opens
+func ParseGreeting(line string) (bool, string, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || fields[0] != "PJLINK" {
+		return false, "", fmt.Errorf("PJLink greeting is malformed: %q", line)
+	}
+	switch fields[1] {
+	case "0":
+		return false, "", nil
+	case "1":
+		if len(fields) != 3 {
+			return false, "", errors.New("PJLink authentication greeting has no random number")
+		}
+		return true, fields[2], nil
+	case "ERRA":
+		return false, "", errors.New("PJLink authorization error")
+	}
+	return false, "", fmt.Errorf("PJLink greeting is malformed: %q", line)
+}
+
+func ParsePower(value string) (string, error) {
+	switch value {
+	case "0":
+		return PowerOff, nil
+	case "1":
+		return PowerOn, nil
+	case "2":
+		return PowerCooling, nil
+	case "3":
+		return PowerWarming, nil
+	}
+	return "", fmt.Errorf("PJLink power status is unknown: %s", value)
+}
+
+type Lamp struct {
+	Hours int
+	On    bool
+}
+
+// ParseLamps parses the pairs of hours and on state in a LAMP response
+func ParseLamps(value string) ([]Lamp, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields)%2 != 0 {
+		return nil, fmt.Errorf("PJLink lamp status is malformed: %s", value)
+	}
+
+	lamps := []Lamp{}
+	for index := 0; index < len(fields); index += 2 {
+		hours, err := strconv.Atoi(fields[index])
+		if err != nil {
+			return nil, fmt.Errorf("PJLink lamp hours are malformed: %s", fields[index])
+		}
+		if fields[index+1] != "0" && fields[index+1] != "1" {
+			return nil, fmt.Errorf("PJLink lamp state is malformed: %s", fields[index+1])
+		}
+		lamps = append(lamps, Lamp{Hours: hours, On: fields[index+1] == "1"})
+	}
+	return lamps, nil
+}
+
+// ErrorStatus is the ERST response, each field is ok, warning or error
+type ErrorStatus struct {
+	Fan         string
+	Lamp        string
+	Temperature string
+	Cover       string
+	Filter      string
+	Other       string
+}
+
+func ParseErrorStatus(value string) (ErrorStatus, error) {
+	if len(value) != 6 {
+		return ErrorStatus{}, fmt.Errorf("PJLink error status must be 6 digits: %s", value)
+	}
+
+	levels := [6]string{}
+	for index, digit := range value {
+		switch digit {
+		case '0':
+			levels[index] = "ok"
+		case '1':
+			levels[index] = "warning"
+		case '2':
+			levels[index] = "error"
+		default:
+			return ErrorStatus{}, fmt.Errorf("PJLink error status is malformed: %s", value)
+		}
+	}
+	return ErrorStatus{Fan: levels[0], Lamp: levels[1], Temperature: levels[2], Cover: levels[3], Filter: levels[4], Other: levels[5]}, nil
+}
+
+// Status is the polled state of one projector
+type Status struct {
+	Projector string
+	Connected bool
+	Power     string
+	Input     string
+	Lamps     []Lamp
+	Errors    *ErrorStatus
+}
+
+// Reply pairs a command sent to a projector with its response
+type Reply struct {
+	Projector string
+	Command   Command
+	Response  Response
+}
@@ -0,0 +1,181 @@
+package pjlink_test
+
+import (
+	"bufio"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/pjlink"
+)
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  pjlink.Command
+		expected string
+	}{
+		{
+			name:     "power on",
+			command:  pjlink.Command{Class: 1, Name: pjlink.CommandPower, Parameter: "1"},
+			expected: "%1POWR 1\r",
+		},
+		{
+			name:     "class 2 input query",
+			command:  pjlink.Query(2, pjlink.CommandInput),
+			expected: "%2INPT ?\r",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.command.MarshalBinary()
+			if err != nil {
+				t.Fatalf("PJLink command failed to marshal: %s", err)
+			}
+			if string(got) != test.expected {
+				t.Fatalf("PJLink command got %q, expected %q", got, test.expected)
+			}
+		})
+	}
+
+	_, err := pjlink.Command{Class: 3, Name: pjlink.CommandPower, Parameter: "1"}.MarshalBinary()
+	if err == nil || err.Error() != "PJLink class must be 1 or 2, got 3" {
+		t.Fatalf("PJLink command should reject class 3, got %v", err)
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected pjlink.Response
+	}{
+		{
+			name:     "ok",
+			line:     "%1POWR=OK\r",
+			expected: pjlink.Response{Class: 1, Name: "POWR", Value: "OK"},
+		},
+		{
+			name:     "lamp",
+			line:     "%1LAMP=1200 1 300 0",
+			expected: pjlink.Response{Class: 1, Name: "LAMP", Value: "1200 1 300 0"},
+		},
+		{
+			name:     "unavailable",
+			line:     "%2INPT=ERR3\r",
+			expected: pjlink.Response{Class: 2, Name: "INPT", Error: "ERR3"},
+		},
+		{
+			name:     "authorization error",
+			line:     "PJLINK ERRA\r",
+			expected: pjlink.Response{Error: "ERRA"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := pjlink.ParseResponse(test.line)
+			if err != nil {
+				t.Fatalf("PJLink response failed to parse: %s", err)
+			}
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Fatalf("PJLink response got %+v, expected %+v", got, test.expected)
+			}
+		})
+	}
+
+	response, _ := pjlink.ParseResponse("%1INPT=ERR2")
+	if response.Err() == nil || response.Err().Error() != "PJLink INPT ERR2 (out of parameter)" {
+		t.Fatalf("PJLink response error got %v", response.Err())
+	}
+
+	_, err := pjlink.ParseResponse("%1POWR 1")
+	if err == nil {
+		t.Fatalf("PJLink response should reject a command echo")
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	lamps, err := pjlink.ParseLamps("1200 1 300 0")
+	if err != nil {
+		t.Fatalf("PJLink lamps failed to parse: %s", err)
+	}
+	if !reflect.DeepEqual(lamps, []pjlink.Lamp{{Hours: 1200, On: true}, {Hours: 300}}) {
+		t.Fatalf("PJLink lamps got %+v", lamps)
+	}
+
+	errorStatus, err := pjlink.ParseErrorStatus("010002")
+	if err != nil {
+		t.Fatalf("PJLink error status failed to parse: %s", err)
+	}
+	expected := pjlink.ErrorStatus{Fan: "ok", Lamp: "warning", Temperature: "ok", Cover: "ok", Filter: "ok", Other: "error"}
+	if errorStatus != expected {
+		t.Fatalf("PJLink error status got %+v, expected %+v", errorStatus, expected)
+	}
+
+	power, err := pjlink.ParsePower("3")
+	if err != nil || power != pjlink.PowerWarming {
+		t.Fatalf("PJLink power got %s %v", power, err)
+	}
+}
+
+func TestAuthDigest(t *testing.T) {
+	//NOTE(jwetzell): example from the PJLink specification
+	digest := pjlink.AuthDigest("498e4a67", "JBMIAProjectorLink")
+	if digest != "5d8409bc1c3fa39749434aa3a5c38682" {
+		t.Fatalf("PJLink digest got %s", digest)
+	}
+}
+
+func TestClient(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer listener.Close()
+
+	received := make(chan string, 4)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte("PJLINK 1 498e4a67\r"))
+			reader := bufio.NewReader(conn)
+			for {
+				line, err := reader.ReadString('\r')
+				if err != nil {
+					break
+				}
+				received <- line
+				name := line[strings.Index(line, "%")+2 : strings.Index(line, "%")+6]
+				conn.Write([]byte("%1" + name + "=OK\r"))
+			}
+			conn.Close()
+		}
+	}()
+
+	client := pjlink.NewClient(listener.Addr().String(), "JBMIAProjectorLink", time.Second)
+	defer client.Close()
+
+	for _, command := range []pjlink.Command{{Class: 1, Name: pjlink.CommandPower, Parameter: "1"}, {Class: 1, Name: pjlink.CommandAVMute, Parameter: "31"}} {
+		response, err := client.Send(t.Context(), command)
+		if err != nil {
+			t.Fatalf("PJLink client send failed: %s", err)
+		}
+		if !response.OK() {
+			t.Fatalf("PJLink client got %+v", response)
+		}
+	}
+
+	if line := <-received; line != "5d8409bc1c3fa39749434aa3a5c38682%1POWR 1\r" {
+		t.Fatalf("PJLink client should authenticate the first command, sent %q", line)
+	}
+	if line := <-received; line != "%1AVMT 31\r" {
+		t.Fatalf("PJLink client should only authenticate once per connection, sent %q", line)
+	}
+}
@@ -0,0 +1,86 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/artnet-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "artnet.timecode.create",
+		Title: "Create ArtTimeCode Packet",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"streamId": {
+					Title:       "Stream ID",
+					Description: "timecode stream id, 0 is the master stream",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](0),
+					Maximum:     jsonschema.Ptr[float64](255),
+					Default:     json.RawMessage(`0`),
+				},
+			},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			streamIdNum, err := params.GetInt("streamId")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					streamIdNum = 0
+				} else {
+					return nil, fmt.Errorf("artnet.timecode.create streamId error: %w", err)
+				}
+			}
+
+			if streamIdNum < 0 || streamIdNum > 255 {
+				return nil, errors.New("artnet.timecode.create streamId must be between 0 and 255")
+			}
+
+			return &ArtNetTimecodeCreate{config: processorConfig, StreamId: uint8(streamIdNum)}, nil
+		},
+	})
+}
+
+type ArtNetTimecodeCreate struct {
+	config   config.ProcessorConfig
+	StreamId uint8
+}
+
+func (atc *ArtNetTimecodeCreate) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	payloadTimecode, ok := getTimecode(wrappedPayload.Payload)
+
+	if !ok {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("artnet.timecode.create processor only accepts a timecode.Timecode")
+	}
+
+	err := payloadTimecode.Validate()
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("artnet.timecode.create %w", err)
+	}
+
+	wrappedPayload.Payload = &artnet.ArtTimeCode{
+		StreamId: atc.StreamId,
+		Frames:   payloadTimecode.Frames,
+		Seconds:  payloadTimecode.Seconds,
+		Minutes:  payloadTimecode.Minutes,
+		Hours:    payloadTimecode.Hours,
+		Type:     uint8(payloadTimecode.Rate),
+	}
+	return wrappedPayload, nil
+}
+
+func (atc *ArtNetTimecodeCreate) Type() string {
+	return atc.config.Type
+}
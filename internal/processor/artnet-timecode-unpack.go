@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jwetzell/artnet-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/timecode"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "artnet.timecode.unpack",
+		Title: "Unpack ArtTimeCode Packet",
+		New: func(config config.ProcessorConfig) (Processor, error) {
+			return &ArtNetTimecodeUnpack{config: config}, nil
+		},
+	})
+}
+
+type ArtNetTimecodeUnpack struct {
+	config config.ProcessorConfig
+}
+
+func (atu *ArtNetTimecodeUnpack) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	payloadPacket, ok := common.GetAnyAs[*artnet.ArtTimeCode](wrappedPayload.Payload)
+
+	if !ok {
+		payloadBytes, ok := common.GetAnyAsByteSlice(wrappedPayload.Payload)
+		if !ok {
+			wrappedPayload.End = true
+			return wrappedPayload, errors.New("artnet.timecode.unpack processor only accepts an ArtTimeCode packet")
+		}
+		payloadPacket = &artnet.ArtTimeCode{}
+		err := payloadPacket.UnmarshalBinary(payloadBytes)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, fmt.Errorf("artnet.timecode.unpack %w", err)
+		}
+	}
+
+	payloadTimecode := timecode.Timecode{
+		Hours:   payloadPacket.Hours,
+		Minutes: payloadPacket.Minutes,
+		Seconds: payloadPacket.Seconds,
+		Frames:  payloadPacket.Frames,
+		Rate:    timecode.Rate(payloadPacket.Type),
+	}
+
+	err := payloadTimecode.Validate()
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("artnet.timecode.unpack %w", err)
+	}
+
+	wrappedPayload.Payload = payloadTimecode
+	return wrappedPayload, nil
+}
+
+func (atu *ArtNetTimecodeUnpack) Type() string {
+	return atu.config.Type
+}
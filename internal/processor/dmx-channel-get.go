@@ -0,0 +1,102 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "dmx.channel.get",
+		Title: "Get DMX Channel",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"channel": {
+					Title:       "Channel",
+					Description: "DMX channel to read starting at 1",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1),
+					Maximum:     jsonschema.Ptr[float64](dmxSlots),
+				},
+				"fine": {
+					Title:       "Fine",
+					Description: "read the next channel as the fine byte of a 16-bit value",
+					Type:        "boolean",
+					Default:     json.RawMessage(`false`),
+				},
+			},
+			Required:             []string{"channel"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			channelInt, err := params.GetInt("channel")
+			if err != nil {
+				return nil, fmt.Errorf("dmx.channel.get channel error: %w", err)
+			}
+
+			fineBool, err := params.GetBool("fine")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					fineBool = false
+				} else {
+					return nil, fmt.Errorf("dmx.channel.get fine error: %w", err)
+				}
+			}
+
+			if channelInt < 1 || channelInt > dmxSlots {
+				return nil, fmt.Errorf("dmx.channel.get channel must be between 1 and %d", dmxSlots)
+			}
+
+			if fineBool && channelInt == dmxSlots {
+				return nil, fmt.Errorf("dmx.channel.get fine channel cannot be past channel %d", dmxSlots)
+			}
+
+			return &DMXChannelGet{config: processorConfig, Channel: channelInt, Fine: fineBool}, nil
+		},
+	})
+}
+
+type DMXChannelGet struct {
+	config  config.ProcessorConfig
+	Channel int
+	Fine    bool
+}
+
+func (dcg *DMXChannelGet) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	data, ok := getDMXData(wrappedPayload.Payload)
+
+	if !ok {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("dmx.channel.get processor only accepts DMX data")
+	}
+
+	//NOTE(jwetzell): channels past the end of a short frame read as 0
+	channelValue := func(channel int) int {
+		if channel > len(data) {
+			return 0
+		}
+		return int(data[channel-1])
+	}
+
+	value := channelValue(dcg.Channel)
+	if dcg.Fine {
+		value = value<<8 | channelValue(dcg.Channel+1)
+	}
+
+	wrappedPayload.Payload = value
+
+	return wrappedPayload, nil
+}
+
+func (dcg *DMXChannelGet) Type() string {
+	return dcg.config.Type
+}
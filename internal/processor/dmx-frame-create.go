@@ -0,0 +1,111 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"text/template"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "dmx.frame.create",
+		Title: "Create DMX Frame",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"channels": {
+					Title:       "Channels",
+					Description: "map of channel number to value template, channels not listed are 0",
+					Type:        "object",
+					PropertyNames: &jsonschema.Schema{
+						Pattern: "^[1-9][0-9]*$",
+					},
+					AdditionalProperties: &jsonschema.Schema{
+						Type: "string",
+					},
+					MinProperties: new(1),
+				},
+			},
+			Required:             []string{"channels"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			channelsMap, err := params.GetStringMap("channels")
+			if err != nil {
+				return nil, fmt.Errorf("dmx.frame.create channels error: %w", err)
+			}
+
+			channels := []dmxChannelTemplate{}
+			for channelString, valueString := range channelsMap {
+				channelInt, err := strconv.Atoi(channelString)
+				if err != nil || channelInt < 1 || channelInt > dmxSlots {
+					return nil, fmt.Errorf("dmx.frame.create channel must be between 1 and %d, got %s", dmxSlots, channelString)
+				}
+
+				valueTemplate, err := template.New(channelString).Parse(valueString)
+				if err != nil {
+					return nil, err
+				}
+
+				channels = append(channels, dmxChannelTemplate{Channel: channelInt, Value: valueTemplate})
+			}
+
+			slices.SortFunc(channels, func(a, b dmxChannelTemplate) int {
+				return a.Channel - b.Channel
+			})
+
+			return &DMXFrameCreate{config: processorConfig, Channels: channels}, nil
+		},
+	})
+}
+
+type dmxChannelTemplate struct {
+	Channel int
+	Value   *template.Template
+}
+
+type DMXFrameCreate struct {
+	config   config.ProcessorConfig
+	Channels []dmxChannelTemplate
+}
+
+func (dfc *DMXFrameCreate) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	templateData := wrappedPayload
+
+	frame := make([]byte, dmxSlots)
+	for _, channel := range dfc.Channels {
+		var valueBuffer bytes.Buffer
+		err := channel.Value.Execute(&valueBuffer, templateData)
+
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, err
+		}
+
+		value, err := strconv.ParseUint(valueBuffer.String(), 10, 8)
+
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, fmt.Errorf("dmx.frame.create channel %d error: %w", channel.Channel, err)
+		}
+
+		frame[channel.Channel-1] = byte(value)
+	}
+
+	wrappedPayload.Payload = frame
+
+	return wrappedPayload, nil
+}
+
+func (dfc *DMXFrameCreate) Type() string {
+	return dfc.config.Type
+}
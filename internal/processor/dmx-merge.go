@@ -0,0 +1,278 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/artnet-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/sacn"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "dmx.merge",
+		Title: "Merge DMX",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"mode": {
+					Title:       "Mode",
+					Description: "htp uses the highest value of each channel, ltp uses the most recently changed value",
+					Type:        "string",
+					Enum:        []any{"htp", "ltp"},
+					Default:     json.RawMessage(`"htp"`),
+				},
+				"timeout": {
+					Title:       "Timeout",
+					Description: "milliseconds without data before a source is dropped from the merge, 0 keeps sources forever",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](0),
+					Default:     json.RawMessage(`2500`),
+				},
+				"group": {
+					Title:       "Group",
+					Description: "dmx.merge processors with the same group share sources, so inputs on different modules and routes merge together",
+					Type:        "string",
+				},
+			},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			modeString, err := params.GetString("mode")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					modeString = "htp"
+				} else {
+					return nil, fmt.Errorf("dmx.merge mode error: %w", err)
+				}
+			}
+
+			if modeString != "htp" && modeString != "ltp" {
+				return nil, fmt.Errorf("dmx.merge mode must be htp or ltp, got %s", modeString)
+			}
+
+			timeoutInt, err := params.GetInt("timeout")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					timeoutInt = 2500
+				} else {
+					return nil, fmt.Errorf("dmx.merge timeout error: %w", err)
+				}
+			}
+
+			if timeoutInt < 0 {
+				return nil, errors.New("dmx.merge timeout cannot be negative")
+			}
+
+			groupString, err := params.GetString("group")
+			if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+				return nil, fmt.Errorf("dmx.merge group error: %w", err)
+			}
+
+			return &DMXMerge{
+				config:  processorConfig,
+				Mode:    modeString,
+				Timeout: time.Duration(timeoutInt) * time.Millisecond,
+				Group:   groupString,
+				state:   newDMXMergeState(),
+			}, nil
+		},
+	})
+}
+
+type dmxMergeSource struct {
+	data     [dmxSlots]byte
+	changed  [dmxSlots]uint64
+	lastSeen time.Time
+}
+
+// dmxMergeState holds the sources of every universe being merged, keyed by universe and then by sender
+type dmxMergeState struct {
+	universes map[string]map[string]*dmxMergeSource
+	//NOTE(jwetzell): counts updates so LTP can tell which source changed a channel last
+	changeCount uint64
+	mu          sync.Mutex
+}
+
+func newDMXMergeState() *dmxMergeState {
+	return &dmxMergeState{universes: make(map[string]map[string]*dmxMergeSource)}
+}
+
+// dmxMergeGroup is the state every dmx.merge in a group shares, along with the settings they must agree on
+type dmxMergeGroup struct {
+	mode    string
+	timeout time.Duration
+	state   *dmxMergeState
+}
+
+type DMXMerge struct {
+	config  config.ProcessorConfig
+	Mode    string
+	Timeout time.Duration
+	Group   string
+	state   *dmxMergeState
+}
+
+// dmxMergeKeys works out which universe a frame belongs to and who sent it, frames without a universe all merge together
+func dmxMergeKeys(ctx context.Context, wrappedPayload common.WrappedPayload) (string, string) {
+	universeKey := ""
+	senderKey := wrappedPayload.Source
+
+	switch typedPayload := wrappedPayload.Payload.(type) {
+	case *sacn.DataPacket:
+		universeKey = strconv.Itoa(int(typedPayload.Universe))
+		return universeKey, fmt.Sprintf("%s/%s", senderKey, typedPayload.CID)
+	case *artnet.ArtDmx:
+		universeKey = strconv.Itoa(int(typedPayload.Net&0x7f)<<8 | int(typedPayload.SubUni))
+	}
+
+	remoteAddr, ok := common.RemoteAddr(ctx)
+	if ok {
+		senderKey = fmt.Sprintf("%s/%s", senderKey, remoteAddr)
+	}
+	return universeKey, senderKey
+}
+
+// JoinGroups swaps the merge state for the one shared by its group, members of a group must use the same mode and timeout
+func (dm *DMXMerge) JoinGroups(groups *Groups) error {
+	if dm.Group == "" {
+		return nil
+	}
+	stored, _ := groups.loadOrStore("dmx.merge/"+dm.Group, &dmxMergeGroup{mode: dm.Mode, timeout: dm.Timeout, state: newDMXMergeState()})
+	group := stored.(*dmxMergeGroup)
+	if group.mode != dm.Mode || group.timeout != dm.Timeout {
+		return fmt.Errorf("dmx.merge group %s already uses mode %s and timeout %s", dm.Group, group.mode, group.timeout)
+	}
+	dm.state = group.state
+	return nil
+}
+
+func (dm *DMXMerge) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	data, ok := getDMXData(wrappedPayload.Payload)
+
+	if !ok {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("dmx.merge processor only accepts DMX data")
+	}
+
+	if len(data) > dmxSlots {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("dmx.merge data cannot be more than %d channels", dmxSlots)
+	}
+
+	state := dm.state
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	now := time.Now()
+	if dm.Timeout > 0 {
+		for universeKey, sources := range state.universes {
+			for senderKey, source := range sources {
+				if now.Sub(source.lastSeen) > dm.Timeout {
+					delete(sources, senderKey)
+				}
+			}
+			if len(sources) == 0 {
+				delete(state.universes, universeKey)
+			}
+		}
+	}
+
+	universeKey, senderKey := dmxMergeKeys(ctx, wrappedPayload)
+	sources, ok := state.universes[universeKey]
+	if !ok {
+		sources = make(map[string]*dmxMergeSource)
+		state.universes[universeKey] = sources
+	}
+
+	source, ok := sources[senderKey]
+	if !ok {
+		source = &dmxMergeSource{}
+		sources[senderKey] = source
+	}
+	source.lastSeen = now
+
+	state.changeCount++
+	for channel := range dmxSlots {
+		value := byte(0)
+		if channel < len(data) {
+			value = data[channel]
+		}
+		if !ok || source.data[channel] != value {
+			source.data[channel] = value
+			source.changed[channel] = state.changeCount
+		}
+	}
+
+	merged := make([]byte, dmxSlots)
+	switch dm.Mode {
+	case "htp":
+		for _, source := range sources {
+			for channel := range dmxSlots {
+				merged[channel] = max(merged[channel], source.data[channel])
+			}
+		}
+	case "ltp":
+		latest := make([]uint64, dmxSlots)
+		for _, source := range sources {
+			for channel := range dmxSlots {
+				if source.changed[channel] >= latest[channel] {
+					latest[channel] = source.changed[channel]
+					merged[channel] = source.data[channel]
+				}
+			}
+		}
+	}
+
+	wrappedPayload.Payload = dmxMergeOutput(wrappedPayload.Payload, merged)
+
+	return wrappedPayload, nil
+}
+
+// dmxMergeOutput wraps the merged frame in the same kind of packet that came in so its universe carries on down the route
+func dmxMergeOutput(payload any, merged []byte) any {
+	switch typedPayload := payload.(type) {
+	case *sacn.DataPacket:
+		packet := *typedPayload
+		packet.Data = merged
+		return &packet
+	case *artnet.ArtDmx:
+		packet := *typedPayload
+		packet.Data = merged
+		return &packet
+	}
+	return merged
+}
+
+// Sources returns the keys of the sources currently in the merge, prefixed with their universe when it is known
+func (dm *DMXMerge) Sources() []string {
+	dm.state.mu.Lock()
+	defer dm.state.mu.Unlock()
+	sourceKeys := []string{}
+	for universeKey, sources := range dm.state.universes {
+		for senderKey := range sources {
+			if universeKey == "" {
+				sourceKeys = append(sourceKeys, senderKey)
+			} else {
+				sourceKeys = append(sourceKeys, fmt.Sprintf("universe %s: %s", universeKey, senderKey))
+			}
+		}
+	}
+	slices.Sort(sourceKeys)
+	return sourceKeys
+}
+
+func (dm *DMXMerge) Type() string {
+	return dm.config.Type
+}
@@ -0,0 +1,20 @@
+package processor
+
+import (
+	"github.com/jwetzell/artnet-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/sacn"
+)
+
+const dmxSlots = 512
+
+// getDMXData pulls the channel data out of the DMX payloads produced by the Art-Net and sACN processors and modules
+func getDMXData(payload any) ([]byte, bool) {
+	switch typedPayload := payload.(type) {
+	case *artnet.ArtDmx:
+		return typedPayload.Data, true
+	case *sacn.DataPacket:
+		return typedPayload.Data, true
+	}
+	return common.GetAnyAsByteSlice(payload)
+}
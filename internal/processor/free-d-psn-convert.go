@@ -0,0 +1,277 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	freeD "github.com/jwetzell/free-d-go"
+	"github.com/jwetzell/psn-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+)
+
+func freeDAxisSchema(title string, description string, sources []string, defaults string) *jsonschema.Schema {
+	sourceEnum := []any{}
+	for _, source := range sources {
+		sourceEnum = append(sourceEnum, source, "-"+source)
+	}
+	return &jsonschema.Schema{
+		Title:       title,
+		Description: description,
+		Type:        "object",
+		Properties: map[string]*jsonschema.Schema{
+			"x": {Type: "string", Enum: sourceEnum},
+			"y": {Type: "string", Enum: sourceEnum},
+			"z": {Type: "string", Enum: sourceEnum},
+		},
+		Required:             []string{"x", "y", "z"},
+		AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		Default:              json.RawMessage(defaults),
+	}
+}
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:        "freed.psn.convert",
+		Title:       "Convert FreeD to PSN",
+		Description: "Turn a FreeD camera position into a PSN tracker",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"trackerId": {
+					Title:       "Tracker ID",
+					Description: "PSN tracker id, the FreeD camera id is used if not set",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](0),
+					Maximum:     jsonschema.Ptr[float64](65535),
+				},
+				"trackerName": {
+					Title:       "Tracker Name",
+					Description: "PSN tracker name, camera and the FreeD camera id if not set",
+					Type:        "string",
+				},
+				"scale": {
+					Title:       "Scale",
+					Description: "PSN meters per FreeD millimeter",
+					Type:        "number",
+					Default:     json.RawMessage(`0.001`),
+				},
+				"offset": {
+					Title:       "Offset",
+					Description: "meters added to the PSN position after scaling",
+					Type:        "object",
+					Properties: map[string]*jsonschema.Schema{
+						"x": {Type: "number"},
+						"y": {Type: "number"},
+						"z": {Type: "number"},
+					},
+					AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+				},
+				"position":    freeDAxisSchema("Position Axes", "FreeD position field for each PSN axis, prefix with - to flip it", []string{"posX", "posY", "posZ"}, `{"x":"posX","y":"posZ","z":"-posY"}`),
+				"orientation": freeDAxisSchema("Orientation Axes", "FreeD rotation field for each PSN axis, prefix with - to flip it", []string{"pan", "tilt", "roll"}, `{"x":"tilt","y":"pan","z":"roll"}`),
+			},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			var trackerId *uint16
+			trackerIdNum, err := params.GetInt("trackerId")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("freed.psn.convert trackerId error: %w", err)
+				}
+			} else {
+				if trackerIdNum < 0 || trackerIdNum > 65535 {
+					return nil, errors.New("freed.psn.convert trackerId must be between 0 and 65535")
+				}
+				id := uint16(trackerIdNum)
+				trackerId = &id
+			}
+
+			trackerNameString, err := params.GetString("trackerName")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("freed.psn.convert trackerName error: %w", err)
+				}
+			}
+
+			scaleFloat, err := params.GetFloat64("scale")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					scaleFloat = 0.001
+				} else {
+					return nil, fmt.Errorf("freed.psn.convert scale error: %w", err)
+				}
+			}
+
+			offset := [3]float64{}
+			offsetValue, ok := params["offset"]
+			if ok {
+				offsetMap, ok := offsetValue.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("freed.psn.convert offset error: %w", config.ErrParamNotMap)
+				}
+				offsetParams := config.Params(offsetMap)
+				for axisIndex, axis := range []string{"x", "y", "z"} {
+					axisFloat, err := offsetParams.GetFloat64(axis)
+					if err != nil {
+						if errors.Is(err, config.ErrParamNotFound) {
+							continue
+						}
+						return nil, fmt.Errorf("freed.psn.convert offset %s error: %w", axis, err)
+					}
+					offset[axisIndex] = axisFloat
+				}
+			}
+
+			position, err := freeDAxesFromParams(params, "position", []string{"posX", "posY", "posZ"}, [3]string{"posX", "posZ", "-posY"})
+			if err != nil {
+				return nil, fmt.Errorf("freed.psn.convert %w", err)
+			}
+
+			orientation, err := freeDAxesFromParams(params, "orientation", []string{"pan", "tilt", "roll"}, [3]string{"tilt", "pan", "roll"})
+			if err != nil {
+				return nil, fmt.Errorf("freed.psn.convert %w", err)
+			}
+
+			return &FreeDPSNConvert{
+				config:      processorConfig,
+				TrackerId:   trackerId,
+				TrackerName: trackerNameString,
+				Scale:       scaleFloat,
+				Offset:      offset,
+				Position:    position,
+				Orientation: orientation,
+			}, nil
+		},
+	})
+}
+
+// freeDAxis picks a FreeD field for a PSN axis, Sign is -1 when the axis is flipped
+type freeDAxis struct {
+	Field string
+	Sign  float64
+}
+
+func freeDAxesFromParams(params config.Params, key string, fields []string, defaults [3]string) ([3]freeDAxis, error) {
+	axisStrings := map[string]string{"x": defaults[0], "y": defaults[1], "z": defaults[2]}
+
+	axesMap, err := params.GetStringMap(key)
+	if err != nil {
+		if !errors.Is(err, config.ErrParamNotFound) {
+			return [3]freeDAxis{}, fmt.Errorf("%s error: %w", key, err)
+		}
+	} else {
+		for axis := range axesMap {
+			if axis != "x" && axis != "y" && axis != "z" {
+				return [3]freeDAxis{}, fmt.Errorf("%s axis must be x, y or z, got %s", key, axis)
+			}
+		}
+		for _, axis := range []string{"x", "y", "z"} {
+			fieldString, ok := axesMap[axis]
+			if !ok {
+				return [3]freeDAxis{}, fmt.Errorf("%s is missing the %s axis", key, axis)
+			}
+			axisStrings[axis] = fieldString
+		}
+	}
+
+	axes := [3]freeDAxis{}
+	for axisIndex, axis := range []string{"x", "y", "z"} {
+		fieldString := axisStrings[axis]
+		sign := 1.0
+		if strings.HasPrefix(fieldString, "-") {
+			sign = -1
+			fieldString = fieldString[1:]
+		}
+		validField := false
+		for _, field := range fields {
+			if field == fieldString {
+				validField = true
+			}
+		}
+		if !validField {
+			return [3]freeDAxis{}, fmt.Errorf("%s %s must be one of %s, got %s", key, axis, strings.Join(fields, ", "), axisStrings[axis])
+		}
+		axes[axisIndex] = freeDAxis{Field: fieldString, Sign: sign}
+	}
+	return axes, nil
+}
+
+func freeDField(position freeD.FreeDPosition, field string) float64 {
+	switch field {
+	case "posX":
+		return float64(position.PosX)
+	case "posY":
+		return float64(position.PosY)
+	case "posZ":
+		return float64(position.PosZ)
+	case "pan":
+		return float64(position.Pan)
+	case "tilt":
+		return float64(position.Tilt)
+	case "roll":
+		return float64(position.Roll)
+	}
+	return 0
+}
+
+type FreeDPSNConvert struct {
+	config      config.ProcessorConfig
+	TrackerId   *uint16
+	TrackerName string
+	Scale       float64
+	Offset      [3]float64
+	Position    [3]freeDAxis
+	Orientation [3]freeDAxis
+}
+
+func (fpc *FreeDPSNConvert) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	payload := wrappedPayload.Payload
+	payloadPosition, ok := common.GetAnyAs[freeD.FreeDPosition](payload)
+
+	if !ok {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("freed.psn.convert processor only accepts a FreeDPosition")
+	}
+
+	tracker := &psn.Tracker{
+		Id:   uint16(payloadPosition.ID),
+		Name: fpc.TrackerName,
+	}
+
+	if fpc.TrackerId != nil {
+		tracker.Id = *fpc.TrackerId
+	}
+
+	if tracker.Name == "" {
+		tracker.Name = fmt.Sprintf("camera %d", payloadPosition.ID)
+	}
+
+	position := [3]float32{}
+	for axisIndex, axis := range fpc.Position {
+		position[axisIndex] = float32(axis.Sign*freeDField(payloadPosition, axis.Field)*fpc.Scale + fpc.Offset[axisIndex])
+	}
+	tracker.SetPos(position[0], position[1], position[2])
+
+	//NOTE(jwetzell): FreeD angles are degrees and PSN orientation is radians
+	orientation := [3]float32{}
+	for axisIndex, axis := range fpc.Orientation {
+		orientation[axisIndex] = float32(axis.Sign * freeDField(payloadPosition, axis.Field) * math.Pi / 180)
+	}
+	tracker.SetOri(orientation[0], orientation[1], orientation[2])
+	tracker.SetStatus(1)
+
+	wrappedPayload.Payload = tracker
+	return wrappedPayload, nil
+}
+
+func (fpc *FreeDPSNConvert) Type() string {
+	return fpc.config.Type
+}
@@ -0,0 +1,30 @@
+package processor
+
+import "sync"
+
+// Groups holds state that processors naming the same group share, a new Groups is made each time a config's routes are built so state never outlives its config
+type Groups struct {
+	mu     sync.Mutex
+	states map[string]any
+}
+
+func NewGroups() *Groups {
+	return &Groups{states: make(map[string]any)}
+}
+
+// GroupedProcessor is implemented by processors that share state with other processors in a group
+type GroupedProcessor interface {
+	JoinGroups(groups *Groups) error
+}
+
+// loadOrStore returns the state already stored for the key or stores and returns the given one
+func (g *Groups) loadOrStore(key string, state any) (any, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	existing, ok := g.states[key]
+	if ok {
+		return existing, true
+	}
+	g.states[key] = state
+	return state, false
+}
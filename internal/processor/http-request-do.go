@@ -3,9 +3,12 @@ package processor
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"text/template"
 	"time"
 
@@ -32,12 +35,90 @@ func init() {
 					Description: "URL to send the HTTP request to",
 					Type:        "string",
 				},
+				"bodyTemplate": {
+					Title:       "Body Template",
+					Description: "template for the request body",
+					Type:        "string",
+				},
+				"json": {
+					Title:       "JSON",
+					Description: "send the payload encoded as JSON as the request body",
+					Type:        "boolean",
+					Default:     json.RawMessage(`false`),
+				},
+				"headers": {
+					Title:       "Headers",
+					Description: "templates for headers to set on the request",
+					Type:        "object",
+					AdditionalProperties: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"auth": {
+					Title:       "Auth",
+					Description: "credentials to send with the request",
+					Type:        "object",
+					Properties: map[string]*jsonschema.Schema{
+						"type": {
+							Title: "Type",
+							Type:  "string",
+							Enum:  []any{"basic", "bearer"},
+						},
+						"username": {
+							Title: "Username",
+							Type:  "string",
+						},
+						"password": {
+							Title: "Password",
+							Type:  "string",
+						},
+						"token": {
+							Title: "Token",
+							Type:  "string",
+						},
+					},
+					Required:             []string{"type"},
+					AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+				},
+				"timeout": {
+					Title:       "Timeout",
+					Description: "milliseconds to wait for each attempt",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1),
+					Default:     json.RawMessage(`10000`),
+				},
+				"retries": {
+					Title:       "Retries",
+					Description: "number of times a request is retried after a connection error or a 5xx status",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](0),
+					Default:     json.RawMessage(`0`),
+				},
+				"backoff": {
+					Title:       "Backoff",
+					Description: "milliseconds to wait before the first retry, doubled for every retry after it",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](0),
+					Default:     json.RawMessage(`500`),
+				},
+				"parseJSON": {
+					Title:       "Parse JSON",
+					Description: "decode the response body as JSON into the JSON field of the response",
+					Type:        "boolean",
+					Default:     json.RawMessage(`false`),
+				},
+				"failOnStatus": {
+					Title:       "Fail On Status",
+					Description: "treat responses without a 2xx status as an error",
+					Type:        "boolean",
+					Default:     json.RawMessage(`false`),
+				},
 			},
 			Required:             []string{"method", "url"},
 			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
 		},
-		New: func(config config.ProcessorConfig) (Processor, error) {
-			params := config.Params
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
 
 			methodString, err := params.GetString("method")
 			if err != nil {
@@ -54,20 +135,170 @@ func init() {
 			if err != nil {
 				return nil, err
 			}
+
+			var bodyTemplate *template.Template
+			bodyString, err := params.GetString("bodyTemplate")
+			if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+				return nil, fmt.Errorf("http.request.do bodyTemplate error: %w", err)
+			}
+			if bodyString != "" {
+				bodyTemplate, err = template.New("bodyTemplate").Parse(bodyString)
+				if err != nil {
+					return nil, fmt.Errorf("http.request.do bodyTemplate error: %w", err)
+				}
+			}
+
+			jsonBool, err := params.GetBool("json")
+			if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+				return nil, fmt.Errorf("http.request.do json error: %w", err)
+			}
+
+			if jsonBool && bodyTemplate != nil {
+				return nil, errors.New("http.request.do json and bodyTemplate can't both be set")
+			}
+
+			headers, err := params.GetStringMap("headers")
+			if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+				return nil, fmt.Errorf("http.request.do headers error: %w", err)
+			}
+
+			headerTemplates := map[string]*template.Template{}
+			for name, headerTemplateString := range headers {
+				headerTemplate, err := template.New(name).Parse(headerTemplateString)
+				if err != nil {
+					return nil, err
+				}
+				headerTemplates[name] = headerTemplate
+			}
+
+			var auth *httpRequestAuth
+			authParams, err := params.GetParams("auth")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("http.request.do auth error: %w", err)
+				}
+			} else {
+				auth, err = newHTTPRequestAuth(authParams)
+				if err != nil {
+					return nil, fmt.Errorf("http.request.do auth %w", err)
+				}
+			}
+
+			timeoutNum, err := params.GetInt("timeout")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					timeoutNum = 10000
+				} else {
+					return nil, fmt.Errorf("http.request.do timeout error: %w", err)
+				}
+			}
+
+			if timeoutNum < 1 {
+				return nil, errors.New("http.request.do timeout must be at least 1")
+			}
+
+			retriesNum, err := params.GetInt("retries")
+			if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+				return nil, fmt.Errorf("http.request.do retries error: %w", err)
+			}
+
+			if retriesNum < 0 {
+				return nil, errors.New("http.request.do retries must not be negative")
+			}
+
+			backoffNum, err := params.GetInt("backoff")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					backoffNum = 500
+				} else {
+					return nil, fmt.Errorf("http.request.do backoff error: %w", err)
+				}
+			}
+
+			if backoffNum < 0 {
+				return nil, errors.New("http.request.do backoff must not be negative")
+			}
+
+			parseJSONBool, err := params.GetBool("parseJSON")
+			if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+				return nil, fmt.Errorf("http.request.do parseJSON error: %w", err)
+			}
+
+			failOnStatusBool, err := params.GetBool("failOnStatus")
+			if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+				return nil, fmt.Errorf("http.request.do failOnStatus error: %w", err)
+			}
+
 			client := &http.Client{
-				Timeout: 10 * time.Second,
+				Timeout: time.Duration(timeoutNum) * time.Millisecond,
 			}
 
-			return &HTTPRequestDo{config: config, URL: urlTemplate, Method: methodString, client: client}, nil
+			return &HTTPRequestDo{
+				config:       processorConfig,
+				URL:          urlTemplate,
+				Method:       methodString,
+				Body:         bodyTemplate,
+				JSON:         jsonBool,
+				Headers:      headerTemplates,
+				Auth:         auth,
+				Retries:      retriesNum,
+				Backoff:      time.Duration(backoffNum) * time.Millisecond,
+				ParseJSON:    parseJSONBool,
+				FailOnStatus: failOnStatusBool,
+				client:       client,
+			}, nil
 		},
 	})
 }
 
+type httpRequestAuth struct {
+	Type     string
+	Username string
+	Password string
+	Token    string
+}
+
+func newHTTPRequestAuth(params config.Params) (*httpRequestAuth, error) {
+	typeString, err := params.GetString("type")
+	if err != nil {
+		return nil, fmt.Errorf("type error: %w", err)
+	}
+
+	auth := &httpRequestAuth{Type: typeString}
+	switch typeString {
+	case "basic":
+		auth.Username, err = params.GetString("username")
+		if err != nil {
+			return nil, fmt.Errorf("username error: %w", err)
+		}
+		auth.Password, err = params.GetString("password")
+		if err != nil {
+			return nil, fmt.Errorf("password error: %w", err)
+		}
+	case "bearer":
+		auth.Token, err = params.GetString("token")
+		if err != nil {
+			return nil, fmt.Errorf("token error: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown type: %s", typeString)
+	}
+	return auth, nil
+}
+
 type HTTPRequestDo struct {
-	config config.ProcessorConfig
-	client *http.Client
-	Method string
-	URL    *template.Template
+	config       config.ProcessorConfig
+	client       *http.Client
+	Method       string
+	URL          *template.Template
+	Body         *template.Template
+	JSON         bool
+	Headers      map[string]*template.Template
+	Auth         *httpRequestAuth
+	Retries      int
+	Backoff      time.Duration
+	ParseJSON    bool
+	FailOnStatus bool
 }
 
 func (hrd *HTTPRequestDo) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
@@ -84,36 +315,111 @@ func (hrd *HTTPRequestDo) Process(ctx context.Context, wrappedPayload common.Wra
 
 	urlString := urlBuffer.String()
 
-	//TODO(jwetzell): support body
-	request, err := http.NewRequest(hrd.Method, urlString, bytes.NewBuffer([]byte{}))
+	var body []byte
+	if hrd.JSON {
+		body, err = json.Marshal(wrappedPayload.Payload)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, fmt.Errorf("http.request.do json error: %w", err)
+		}
+	} else {
+		bodyString, err := executeOptionalTemplate(hrd.Body, templateData)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, err
+		}
+		body = []byte(bodyString)
+	}
 
-	if err != nil {
-		wrappedPayload.End = true
-		return wrappedPayload, err
+	header := http.Header{}
+	if hrd.JSON {
+		header.Set("Content-Type", "application/json")
+	}
+	for name, headerTemplate := range hrd.Headers {
+		value, err := executeOptionalTemplate(headerTemplate, templateData)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, err
+		}
+		header.Set(name, value)
 	}
 
-	response, err := hrd.client.Do(request)
+	response, responseBody, err := hrd.do(ctx, urlString, header, body)
 
 	if err != nil {
 		wrappedPayload.End = true
 		return wrappedPayload, err
 	}
 
-	body, err := io.ReadAll(response.Body)
+	responseHeaders := make(map[string]string, len(response.Header))
+	for name, values := range response.Header {
+		responseHeaders[name] = strings.Join(values, ", ")
+	}
 
-	if err != nil {
+	httpResponse := HTTPResponse{
+		Status:  response.StatusCode,
+		Body:    responseBody,
+		Headers: responseHeaders,
+	}
+
+	if hrd.FailOnStatus && (response.StatusCode < 200 || response.StatusCode > 299) {
 		wrappedPayload.End = true
-		return wrappedPayload, err
+		return wrappedPayload, fmt.Errorf("http.request.do got status %s", response.Status)
 	}
 
-	//TODO(jwetzell): support headers, etc
-	wrappedPayload.Payload = HTTPResponse{
-		Status: response.StatusCode,
-		Body:   body,
+	if hrd.ParseJSON && len(responseBody) > 0 {
+		err := json.Unmarshal(responseBody, &httpResponse.JSON)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, fmt.Errorf("http.request.do parseJSON error: %w", err)
+		}
 	}
+
+	wrappedPayload.Payload = httpResponse
 	return wrappedPayload, nil
 }
 
+// do sends the request, retrying connection errors and 5xx responses with exponential backoff
+func (hrd *HTTPRequestDo) do(ctx context.Context, urlString string, header http.Header, body []byte) (*http.Response, []byte, error) {
+	backoff := hrd.Backoff
+	for attempt := 0; ; attempt++ {
+		request, err := http.NewRequestWithContext(ctx, hrd.Method, urlString, bytes.NewReader(body))
+		if err != nil {
+			return nil, nil, err
+		}
+		request.Header = header.Clone()
+
+		if hrd.Auth != nil {
+			if hrd.Auth.Type == "basic" {
+				request.SetBasicAuth(hrd.Auth.Username, hrd.Auth.Password)
+			} else {
+				request.Header.Set("Authorization", "Bearer "+hrd.Auth.Token)
+			}
+		}
+
+		response, err := hrd.client.Do(request)
+		var responseBody []byte
+		if err == nil {
+			responseBody, err = io.ReadAll(response.Body)
+			response.Body.Close()
+		}
+
+		retry := err != nil || response.StatusCode >= 500
+		if !retry || attempt >= hrd.Retries || ctx.Err() != nil {
+			return response, responseBody, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
 func (hrd *HTTPRequestDo) Type() string {
 	return hrd.config.Type
 }
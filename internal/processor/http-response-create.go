@@ -28,6 +28,14 @@ func init() {
 					Description: "template for the response body",
 					Type:        "string",
 				},
+				"headers": {
+					Title:       "Headers",
+					Description: "templates for headers to set on the response",
+					Type:        "object",
+					AdditionalProperties: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
 			},
 			Required:             []string{"status", "bodyTemplate"},
 			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
@@ -51,21 +59,41 @@ func init() {
 				return nil, err
 			}
 
+			headerTemplates := map[string]*template.Template{}
+			if _, ok := params["headers"]; ok {
+				headers, err := params.GetStringMap("headers")
+				if err != nil {
+					return nil, fmt.Errorf("http.response.create headers error: %w", err)
+				}
+
+				for name, headerTemplateString := range headers {
+					headerTemplate, err := template.New(name).Parse(headerTemplateString)
+					if err != nil {
+						return nil, err
+					}
+					headerTemplates[name] = headerTemplate
+				}
+			}
+
 			// TODO(jwetzell): support other body kind (direct bytes from input, from file?)
-			return &HTTPResponseCreate{config: config, Status: int(statusNum), BodyTmpl: bodyTemplate}, nil
+			return &HTTPResponseCreate{config: config, Status: int(statusNum), BodyTmpl: bodyTemplate, HeaderTmpls: headerTemplates}, nil
 		},
 	})
 }
 
 type HTTPResponseCreate struct {
-	Status   int
-	BodyTmpl *template.Template
-	config   config.ProcessorConfig
+	Status      int
+	BodyTmpl    *template.Template
+	HeaderTmpls map[string]*template.Template
+	config      config.ProcessorConfig
 }
 
 type HTTPResponse struct {
-	Status int
-	Body   []byte
+	Status  int
+	Body    []byte
+	Headers map[string]string
+	// JSON is the decoded body of a response from http.request.do with parseJSON set
+	JSON any
 }
 
 func (hrc *HTTPResponseCreate) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
@@ -78,9 +106,24 @@ func (hrc *HTTPResponseCreate) Process(ctx context.Context, wrappedPayload commo
 		wrappedPayload.End = true
 		return wrappedPayload, err
 	}
+	var headers map[string]string
+	if len(hrc.HeaderTmpls) > 0 {
+		headers = make(map[string]string, len(hrc.HeaderTmpls))
+		for name, headerTemplate := range hrc.HeaderTmpls {
+			var headerBuffer bytes.Buffer
+			err := headerTemplate.Execute(&headerBuffer, templateData)
+			if err != nil {
+				wrappedPayload.End = true
+				return wrappedPayload, err
+			}
+			headers[name] = headerBuffer.String()
+		}
+	}
+
 	wrappedPayload.Payload = HTTPResponse{
-		Status: hrc.Status,
-		Body:   bodyBuffer.Bytes(),
+		Status:  hrc.Status,
+		Body:    bodyBuffer.Bytes(),
+		Headers: headers,
 	}
 	return wrappedPayload, nil
 }
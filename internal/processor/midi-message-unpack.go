@@ -1,5 +1,3 @@
-//go:build cgo
-
 package processor
 
 import (
@@ -55,6 +53,17 @@ type MIDIPitchBend struct {
 	Absolute uint16
 }
 
+type MIDIAfterTouch struct {
+	Channel  uint8
+	Pressure uint8
+}
+
+type MIDIPolyAfterTouch struct {
+	Channel  uint8
+	Note     uint8
+	Pressure uint8
+}
+
 func (mmu *MIDIMessageUnpack) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
 	payload := wrappedPayload.Payload
 	payloadMidi, ok := common.GetAnyAs[midi.Message](payload)
@@ -90,6 +99,24 @@ func (mmu *MIDIMessageUnpack) Process(ctx context.Context, wrappedPayload common
 		payloadMidi.GetPitchBend(&pitchBendMsg.Channel, &pitchBendMsg.Relative, &pitchBendMsg.Absolute)
 		wrappedPayload.Payload = pitchBendMsg
 		return wrappedPayload, nil
+	case midi.AfterTouchMsg:
+		afterTouchMsg := MIDIAfterTouch{}
+		payloadMidi.GetAfterTouch(&afterTouchMsg.Channel, &afterTouchMsg.Pressure)
+		wrappedPayload.Payload = afterTouchMsg
+		return wrappedPayload, nil
+	case midi.PolyAfterTouchMsg:
+		polyAfterTouchMsg := MIDIPolyAfterTouch{}
+		payloadMidi.GetPolyAfterTouch(&polyAfterTouchMsg.Channel, &polyAfterTouchMsg.Note, &polyAfterTouchMsg.Pressure)
+		wrappedPayload.Payload = polyAfterTouchMsg
+		return wrappedPayload, nil
+	case midi.SysExMsg:
+		sysExMsg, err := unpackSysEx(payloadMidi)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, fmt.Errorf("midi.message.unpack %w", err)
+		}
+		wrappedPayload.Payload = sysExMsg
+		return wrappedPayload, nil
 	default:
 		wrappedPayload.End = true
 		return wrappedPayload, fmt.Errorf("midi.message.unpack message type not supported %v", payloadMidi.Type())
@@ -0,0 +1,302 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"text/template"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/msc"
+	"github.com/jwetzell/showbridge-go/internal/timecode"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "midi.msc.create",
+		Title: "Create MIDI Show Control Message",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"command": {
+					Title:       "Command",
+					Description: "MSC command",
+					Type:        "string",
+					Enum:        []any{"go", "stop", "resume", "timed_go", "load", "set", "fire", "all_off", "restore", "reset", "go_off"},
+				},
+				"deviceId": {
+					Title:       "Device ID",
+					Description: "device id to address, 127 is all call",
+					Type:        "string",
+					Default:     json.RawMessage(`"127"`),
+				},
+				"commandFormat": {
+					Title:       "Command Format",
+					Description: "command format name like lighting or sound, or its number",
+					Type:        "string",
+					Default:     json.RawMessage(`"lighting"`),
+				},
+				"cue": {
+					Title:       "Cue",
+					Description: "cue number for go, stop, resume, timed_go, load and go_off",
+					Type:        "string",
+				},
+				"list": {
+					Title:       "List",
+					Description: "cue list, requires a cue",
+					Type:        "string",
+				},
+				"path": {
+					Title:       "Path",
+					Description: "cue path, requires a list",
+					Type:        "string",
+				},
+				"time": {
+					Title:       "Time",
+					Description: "hh:mm:ss:ff[.sf] for timed_go and optionally set",
+					Type:        "string",
+				},
+				"rate": {
+					Title:       "Rate",
+					Description: "frame rate of the time",
+					Type:        "string",
+					Enum:        []any{"24", "25", "29.97df", "30"},
+					Default:     json.RawMessage(`"30"`),
+				},
+				"control": {
+					Title:       "Control",
+					Description: "generic control number for set",
+					Type:        "string",
+				},
+				"value": {
+					Title:       "Value",
+					Description: "generic control value for set",
+					Type:        "string",
+				},
+				"macro": {
+					Title:       "Macro",
+					Description: "macro number for fire",
+					Type:        "string",
+				},
+			},
+			Required:             []string{"command"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			commandString, err := params.GetString("command")
+			if err != nil {
+				return nil, fmt.Errorf("midi.msc.create command error: %w", err)
+			}
+
+			command, err := msc.CommandFromString(commandString)
+			if err != nil {
+				return nil, fmt.Errorf("midi.msc.create command error: %w", err)
+			}
+
+			commandFormatString, err := params.GetString("commandFormat")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					commandFormatString = "lighting"
+				} else {
+					return nil, fmt.Errorf("midi.msc.create commandFormat error: %w", err)
+				}
+			}
+
+			commandFormat, err := msc.CommandFormatFromString(commandFormatString)
+			if err != nil {
+				return nil, fmt.Errorf("midi.msc.create commandFormat error: %w", err)
+			}
+
+			rateString, err := params.GetString("rate")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					rateString = "30"
+				} else {
+					return nil, fmt.Errorf("midi.msc.create rate error: %w", err)
+				}
+			}
+
+			rate, err := timecode.ParseRate(rateString)
+			if err != nil {
+				return nil, fmt.Errorf("midi.msc.create rate error: %w", err)
+			}
+
+			mscCreate := &MIDIMSCCreate{
+				config:        processorConfig,
+				Command:       command,
+				CommandFormat: commandFormat,
+				Rate:          rate,
+			}
+
+			templates := []struct {
+				name     string
+				target   **template.Template
+				fallback string
+			}{
+				{name: "deviceId", target: &mscCreate.DeviceID, fallback: "127"},
+				{name: "cue", target: &mscCreate.Cue},
+				{name: "list", target: &mscCreate.List},
+				{name: "path", target: &mscCreate.Path},
+				{name: "time", target: &mscCreate.Time},
+				{name: "control", target: &mscCreate.Control},
+				{name: "value", target: &mscCreate.Value},
+				{name: "macro", target: &mscCreate.Macro},
+			}
+
+			for _, templateParam := range templates {
+				templateString, err := params.GetString(templateParam.name)
+				if err != nil {
+					if errors.Is(err, config.ErrParamNotFound) {
+						templateString = templateParam.fallback
+					} else {
+						return nil, fmt.Errorf("midi.msc.create %s error: %w", templateParam.name, err)
+					}
+				}
+				if templateString == "" {
+					continue
+				}
+				parsedTemplate, err := template.New(templateParam.name).Parse(templateString)
+				if err != nil {
+					return nil, err
+				}
+				*templateParam.target = parsedTemplate
+			}
+
+			switch command {
+			case msc.CommandTimedGo:
+				if mscCreate.Time == nil {
+					return nil, errors.New("midi.msc.create timed_go requires time")
+				}
+			case msc.CommandLoad:
+				if mscCreate.Cue == nil {
+					return nil, errors.New("midi.msc.create load requires cue")
+				}
+			case msc.CommandSet:
+				if mscCreate.Control == nil || mscCreate.Value == nil {
+					return nil, errors.New("midi.msc.create set requires control and value")
+				}
+			case msc.CommandFire:
+				if mscCreate.Macro == nil {
+					return nil, errors.New("midi.msc.create fire requires macro")
+				}
+			}
+
+			return mscCreate, nil
+		},
+	})
+}
+
+type MIDIMSCCreate struct {
+	config        config.ProcessorConfig
+	Command       uint8
+	CommandFormat uint8
+	Rate          timecode.Rate
+	DeviceID      *template.Template
+	Cue           *template.Template
+	List          *template.Template
+	Path          *template.Template
+	Time          *template.Template
+	Control       *template.Template
+	Value         *template.Template
+	Macro         *template.Template
+}
+
+func executeOptionalTemplate(optionalTemplate *template.Template, templateData any) (string, error) {
+	if optionalTemplate == nil {
+		return "", nil
+	}
+	var buffer bytes.Buffer
+	err := optionalTemplate.Execute(&buffer, templateData)
+	if err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+func (mmc *MIDIMSCCreate) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	templateData := wrappedPayload
+
+	values := map[string]string{}
+	for name, valueTemplate := range map[string]*template.Template{
+		"deviceId": mmc.DeviceID,
+		"cue":      mmc.Cue,
+		"list":     mmc.List,
+		"path":     mmc.Path,
+		"time":     mmc.Time,
+		"control":  mmc.Control,
+		"value":    mmc.Value,
+		"macro":    mmc.Macro,
+	} {
+		value, err := executeOptionalTemplate(valueTemplate, templateData)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, err
+		}
+		values[name] = value
+	}
+
+	message := &msc.Message{
+		CommandFormat: mmc.CommandFormat,
+		Command:       mmc.Command,
+		Cue:           values["cue"],
+		List:          values["list"],
+		Path:          values["path"],
+	}
+
+	deviceId, err := strconv.ParseUint(values["deviceId"], 10, 8)
+	if err != nil || deviceId > 0x7f {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("midi.msc.create deviceId must be between 0 and 127: %s", values["deviceId"])
+	}
+	message.DeviceID = uint8(deviceId)
+
+	if values["time"] != "" {
+		message.Time, err = msc.ParseTime(values["time"], mmc.Rate)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, fmt.Errorf("midi.msc.create time error: %w", err)
+		}
+	}
+
+	for name, target := range map[string]*uint16{"control": &message.Control, "value": &message.Value} {
+		if values[name] == "" {
+			continue
+		}
+		number, err := strconv.ParseUint(values[name], 10, 14)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, fmt.Errorf("midi.msc.create %s must be between 0 and 16383: %s", name, values[name])
+		}
+		*target = uint16(number)
+	}
+
+	if values["macro"] != "" {
+		macro, err := strconv.ParseUint(values["macro"], 10, 7)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, fmt.Errorf("midi.msc.create macro must be between 0 and 127: %s", values["macro"])
+		}
+		message.Macro = uint8(macro)
+	}
+
+	messageBytes, err := message.MarshalBinary()
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("midi.msc.create %w", err)
+	}
+
+	wrappedPayload.Payload = midi.Message(messageBytes)
+	return wrappedPayload, nil
+}
+
+func (mmc *MIDIMSCCreate) Type() string {
+	return mmc.config.Type
+}
@@ -0,0 +1,48 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/msc"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "midi.msc.decode",
+		Title: "Decode MIDI Show Control Message",
+		New: func(config config.ProcessorConfig) (Processor, error) {
+			return &MIDIMSCDecode{config: config}, nil
+		},
+	})
+}
+
+type MIDIMSCDecode struct {
+	config config.ProcessorConfig
+}
+
+func (mmd *MIDIMSCDecode) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	payload := wrappedPayload.Payload
+	payloadBytes, ok := common.GetAnyAsByteSlice(payload)
+
+	if !ok {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("midi.msc.decode processor only accepts a midi.Message")
+	}
+
+	message, err := msc.Decode(payloadBytes)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("midi.msc.decode %w", err)
+	}
+
+	wrappedPayload.Payload = message
+	return wrappedPayload, nil
+}
+
+func (mmd *MIDIMSCDecode) Type() string {
+	return mmd.config.Type
+}
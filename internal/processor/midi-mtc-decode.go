@@ -0,0 +1,75 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/timecode"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "midi.mtc.decode",
+		Title: "Decode MIDI Timecode",
+		New: func(config config.ProcessorConfig) (Processor, error) {
+			return &MIDIMTCDecode{config: config, quarterFrames: &timecode.QuarterFrameDecoder{}}, nil
+		},
+	})
+}
+
+// MIDIMTCDecode outputs a timecode.Timecode for every full frame and for every completed set of quarter frames
+type MIDIMTCDecode struct {
+	config        config.ProcessorConfig
+	quarterFrames *timecode.QuarterFrameDecoder
+	mu            sync.Mutex
+}
+
+func (mmd *MIDIMTCDecode) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	payloadBytes, ok := common.GetAnyAsByteSlice(wrappedPayload.Payload)
+
+	if !ok {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("midi.mtc.decode processor only accepts a midi.Message")
+	}
+
+	if timecode.IsFullFrame(payloadBytes) {
+		payloadTimecode, err := timecode.DecodeFullFrame(payloadBytes)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, fmt.Errorf("midi.mtc.decode %w", err)
+		}
+		wrappedPayload.Payload = payloadTimecode
+		return wrappedPayload, nil
+	}
+
+	if !timecode.IsQuarterFrame(payloadBytes) {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("midi.mtc.decode message is not MIDI timecode")
+	}
+
+	mmd.mu.Lock()
+	payloadTimecode, complete, err := mmd.quarterFrames.Decode(payloadBytes)
+	mmd.mu.Unlock()
+
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("midi.mtc.decode %w", err)
+	}
+
+	//NOTE(jwetzell): quarter frames before the set is complete end the route quietly
+	if !complete {
+		wrappedPayload.End = true
+		return wrappedPayload, nil
+	}
+
+	wrappedPayload.Payload = payloadTimecode
+	return wrappedPayload, nil
+}
+
+func (mmd *MIDIMTCDecode) Type() string {
+	return mmd.config.Type
+}
@@ -0,0 +1,108 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/timecode"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "midi.mtc.encode",
+		Title: "Encode MIDI Timecode",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"format": {
+					Title:       "Format",
+					Description: "full outputs a full frame SysEx, quarter fans out the four quarter frames that belong to the frame",
+					Type:        "string",
+					Enum:        []any{"full", "quarter"},
+					Default:     json.RawMessage(`"full"`),
+				},
+			},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			formatString, err := params.GetString("format")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					formatString = "full"
+				} else {
+					return nil, fmt.Errorf("midi.mtc.encode format error: %w", err)
+				}
+			}
+
+			if formatString != "full" && formatString != "quarter" {
+				return nil, errors.New("midi.mtc.encode format must be full or quarter")
+			}
+
+			return &MIDIMTCEncode{config: processorConfig, Format: formatString}, nil
+		},
+	})
+}
+
+type MIDIMTCEncode struct {
+	config config.ProcessorConfig
+	Format string
+}
+
+// getTimecode accepts a timecode.Timecode or a pointer to one
+func getTimecode(payload any) (timecode.Timecode, bool) {
+	payloadTimecode, ok := common.GetAnyAs[timecode.Timecode](payload)
+	if ok {
+		return payloadTimecode, true
+	}
+	payloadPointer, ok := common.GetAnyAs[*timecode.Timecode](payload)
+	if ok && payloadPointer != nil {
+		return *payloadPointer, true
+	}
+	return timecode.Timecode{}, false
+}
+
+func (mme *MIDIMTCEncode) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	payloadTimecode, ok := getTimecode(wrappedPayload.Payload)
+
+	if !ok {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("midi.mtc.encode processor only accepts a timecode.Timecode")
+	}
+
+	err := payloadTimecode.Validate()
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("midi.mtc.encode %w", err)
+	}
+
+	if mme.Format == "full" {
+		wrappedPayload.Payload = midi.Message(payloadTimecode.FullFrame())
+		return wrappedPayload, nil
+	}
+
+	//NOTE(jwetzell): a quarter frame sequence spans two frames, odd frames send the second half of the previous frame's sequence
+	firstPiece := uint8(0)
+	if payloadTimecode.FrameCount()%2 == 1 {
+		firstPiece = 4
+		payloadTimecode = payloadTimecode.Add(-1)
+	}
+
+	quarterFrames := common.FanOut{}
+	for piece := firstPiece; piece < firstPiece+4; piece++ {
+		quarterFrames = append(quarterFrames, midi.Message(payloadTimecode.QuarterFrame(piece)))
+	}
+	wrappedPayload.Payload = quarterFrames
+	return wrappedPayload, nil
+}
+
+func (mme *MIDIMTCEncode) Type() string {
+	return mme.config.Type
+}
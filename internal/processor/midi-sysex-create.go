@@ -0,0 +1,98 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "midi.sysex.create",
+		Title: "Create MIDI SysEx Message",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"data": {
+					Title:       "Data",
+					Description: "hex bytes between F0 and F7 starting with the manufacturer id, spaces are ignored",
+					Type:        "string",
+					MinLength:   new(1),
+				},
+			},
+			Required:             []string{"data"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(config config.ProcessorConfig) (Processor, error) {
+			params := config.Params
+
+			dataString, err := params.GetString("data")
+			if err != nil {
+				return nil, fmt.Errorf("midi.sysex.create data error: %w", err)
+			}
+
+			dataTemplate, err := template.New("data").Parse(dataString)
+
+			if err != nil {
+				return nil, err
+			}
+
+			return &MIDISysExCreate{config: config, Data: dataTemplate}, nil
+		},
+	})
+}
+
+type MIDISysExCreate struct {
+	config config.ProcessorConfig
+	Data   *template.Template
+}
+
+func (msxc *MIDISysExCreate) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	templateData := wrappedPayload
+
+	var dataBuffer bytes.Buffer
+	err := msxc.Data.Execute(&dataBuffer, templateData)
+
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, err
+	}
+
+	data, err := hex.DecodeString(strings.Join(strings.Fields(dataBuffer.String()), ""))
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("midi.sysex.create data error: %w", err)
+	}
+
+	//NOTE(jwetzell): the framing bytes are optional in the data
+	data = bytes.TrimPrefix(data, []byte{0xf0})
+	data = bytes.TrimSuffix(data, []byte{0xf7})
+
+	if len(data) == 0 {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("midi.sysex.create data must include a manufacturer id")
+	}
+
+	for _, dataByte := range data {
+		if dataByte > 0x7f {
+			wrappedPayload.End = true
+			return wrappedPayload, errors.New("midi.sysex.create data bytes must be between 00 and 7F")
+		}
+	}
+
+	wrappedPayload.Payload = midi.SysEx(data)
+	return wrappedPayload, nil
+}
+
+func (msxc *MIDISysExCreate) Type() string {
+	return msxc.config.Type
+}
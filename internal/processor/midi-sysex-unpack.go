@@ -0,0 +1,83 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "midi.sysex.unpack",
+		Title: "Unpack MIDI SysEx Message",
+		New: func(config config.ProcessorConfig) (Processor, error) {
+			return &MIDISysExUnpack{config: config}, nil
+		},
+	})
+}
+
+type MIDISysExUnpack struct {
+	config config.ProcessorConfig
+}
+
+// MIDISysEx splits a system exclusive message into its manufacturer id (one byte, or three when the first is 00) and the rest of the data
+type MIDISysEx struct {
+	Manufacturer []byte
+	Data         []byte
+}
+
+func (msu *MIDISysExUnpack) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	payload := wrappedPayload.Payload
+	payloadMidi, ok := common.GetAnyAs[midi.Message](payload)
+
+	if !ok {
+		payloadBytes, ok := common.GetAnyAsByteSlice(payload)
+		if !ok {
+			wrappedPayload.End = true
+			return wrappedPayload, errors.New("midi.sysex.unpack processor only accepts a midi.Message")
+		}
+		payloadMidi = midi.Message(payloadBytes)
+	}
+
+	sysEx, err := unpackSysEx(payloadMidi)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("midi.sysex.unpack %w", err)
+	}
+
+	wrappedPayload.Payload = sysEx
+	return wrappedPayload, nil
+}
+
+func unpackSysEx(message midi.Message) (MIDISysEx, error) {
+	var data []byte
+	if !message.GetSysEx(&data) {
+		return MIDISysEx{}, errors.New("message is not a SysEx message")
+	}
+
+	if len(data) == 0 {
+		return MIDISysEx{}, errors.New("message is missing a manufacturer id")
+	}
+
+	manufacturerLength := 1
+	if data[0] == 0x00 {
+		manufacturerLength = 3
+	}
+
+	if len(data) < manufacturerLength {
+		return MIDISysEx{}, errors.New("message has a truncated manufacturer id")
+	}
+
+	return MIDISysEx{
+		Manufacturer: data[:manufacturerLength],
+		Data:         data[manufacturerLength:],
+	}, nil
+}
+
+func (msu *MIDISysExUnpack) Type() string {
+	return msu.config.Type
+}
@@ -0,0 +1,150 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"text/template"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/modbus"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:        "modbus.write_coils.create",
+		Title:       "Create Modbus Coil Write",
+		Description: "Create a modbus.PDU that writes one or more coils, a single value uses write single coil unless multiple is set",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"address": {
+					Title:       "Address",
+					Description: "address of the first coil",
+					Type:        "string",
+				},
+				"values": {
+					Title:       "Values",
+					Description: "coil values, true/false or 1/0",
+					Type:        "array",
+					Items: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"multiple": {
+					Title:       "Multiple",
+					Description: "always use write multiple coils",
+					Type:        "boolean",
+					Default:     json.RawMessage(`false`),
+				},
+			},
+			Required:             []string{"address", "values"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			addressString, err := params.GetString("address")
+			if err != nil {
+				return nil, fmt.Errorf("modbus.write_coils.create address error: %w", err)
+			}
+
+			addressTemplate, err := template.New("address").Parse(addressString)
+			if err != nil {
+				return nil, err
+			}
+
+			valueStrings, err := params.GetStringSlice("values")
+			if err != nil {
+				return nil, fmt.Errorf("modbus.write_coils.create values error: %w", err)
+			}
+
+			if len(valueStrings) < 1 || len(valueStrings) > modbus.MaxWriteBits {
+				return nil, fmt.Errorf("modbus.write_coils.create values must have between 1 and %d entries", modbus.MaxWriteBits)
+			}
+
+			valueTemplates := []*template.Template{}
+			for _, valueString := range valueStrings {
+				valueTemplate, err := template.New("value").Parse(valueString)
+				if err != nil {
+					return nil, err
+				}
+				valueTemplates = append(valueTemplates, valueTemplate)
+			}
+
+			multipleBool, err := params.GetBool("multiple")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					multipleBool = false
+				} else {
+					return nil, fmt.Errorf("modbus.write_coils.create multiple error: %w", err)
+				}
+			}
+
+			return &ModbusWriteCoilsCreate{config: processorConfig, Address: addressTemplate, Values: valueTemplates, Multiple: multipleBool}, nil
+		},
+	})
+}
+
+type ModbusWriteCoilsCreate struct {
+	config   config.ProcessorConfig
+	Address  *template.Template
+	Values   []*template.Template
+	Multiple bool
+}
+
+func (mwcc *ModbusWriteCoilsCreate) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	templateData := wrappedPayload
+
+	var addressBuffer bytes.Buffer
+	err := mwcc.Address.Execute(&addressBuffer, templateData)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, err
+	}
+
+	address, err := strconv.ParseUint(addressBuffer.String(), 10, 16)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("modbus.write_coils.create address error: %w", err)
+	}
+
+	values := []bool{}
+	for _, valueTemplate := range mwcc.Values {
+		var valueBuffer bytes.Buffer
+		err := valueTemplate.Execute(&valueBuffer, templateData)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, err
+		}
+
+		value, err := strconv.ParseBool(valueBuffer.String())
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, fmt.Errorf("modbus.write_coils.create value error: %w", err)
+		}
+		values = append(values, value)
+	}
+
+	if len(values) == 1 && !mwcc.Multiple {
+		wrappedPayload.Payload = modbus.WriteSingleCoil(uint16(address), values[0])
+		return wrappedPayload, nil
+	}
+
+	request, err := modbus.WriteMultipleCoils(uint16(address), values)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("modbus.write_coils.create %w", err)
+	}
+	wrappedPayload.Payload = request
+	return wrappedPayload, nil
+}
+
+func (mwcc *ModbusWriteCoilsCreate) Type() string {
+	return mwcc.config.Type
+}
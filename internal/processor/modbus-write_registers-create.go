@@ -0,0 +1,155 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"text/template"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/modbus"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:        "modbus.write_registers.create",
+		Title:       "Create Modbus Register Write",
+		Description: "Create a modbus.PDU that writes one or more holding registers, a single value uses write single register unless multiple is set",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"address": {
+					Title:       "Address",
+					Description: "address of the first holding register",
+					Type:        "string",
+				},
+				"values": {
+					Title:       "Values",
+					Description: "register values from -32768 to 65535, negative values are written as two's complement",
+					Type:        "array",
+					Items: &jsonschema.Schema{
+						Type: "string",
+					},
+				},
+				"multiple": {
+					Title:       "Multiple",
+					Description: "always use write multiple registers",
+					Type:        "boolean",
+					Default:     json.RawMessage(`false`),
+				},
+			},
+			Required:             []string{"address", "values"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			addressString, err := params.GetString("address")
+			if err != nil {
+				return nil, fmt.Errorf("modbus.write_registers.create address error: %w", err)
+			}
+
+			addressTemplate, err := template.New("address").Parse(addressString)
+			if err != nil {
+				return nil, err
+			}
+
+			valueStrings, err := params.GetStringSlice("values")
+			if err != nil {
+				return nil, fmt.Errorf("modbus.write_registers.create values error: %w", err)
+			}
+
+			if len(valueStrings) < 1 || len(valueStrings) > modbus.MaxWriteRegisters {
+				return nil, fmt.Errorf("modbus.write_registers.create values must have between 1 and %d entries", modbus.MaxWriteRegisters)
+			}
+
+			valueTemplates := []*template.Template{}
+			for _, valueString := range valueStrings {
+				valueTemplate, err := template.New("value").Parse(valueString)
+				if err != nil {
+					return nil, err
+				}
+				valueTemplates = append(valueTemplates, valueTemplate)
+			}
+
+			multipleBool, err := params.GetBool("multiple")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					multipleBool = false
+				} else {
+					return nil, fmt.Errorf("modbus.write_registers.create multiple error: %w", err)
+				}
+			}
+
+			return &ModbusWriteRegistersCreate{config: processorConfig, Address: addressTemplate, Values: valueTemplates, Multiple: multipleBool}, nil
+		},
+	})
+}
+
+type ModbusWriteRegistersCreate struct {
+	config   config.ProcessorConfig
+	Address  *template.Template
+	Values   []*template.Template
+	Multiple bool
+}
+
+func (mwrc *ModbusWriteRegistersCreate) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	templateData := wrappedPayload
+
+	var addressBuffer bytes.Buffer
+	err := mwrc.Address.Execute(&addressBuffer, templateData)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, err
+	}
+
+	address, err := strconv.ParseUint(addressBuffer.String(), 10, 16)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("modbus.write_registers.create address error: %w", err)
+	}
+
+	values := []uint16{}
+	for _, valueTemplate := range mwrc.Values {
+		var valueBuffer bytes.Buffer
+		err := valueTemplate.Execute(&valueBuffer, templateData)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, err
+		}
+
+		value, err := strconv.ParseInt(valueBuffer.String(), 10, 32)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, fmt.Errorf("modbus.write_registers.create value error: %w", err)
+		}
+
+		if value < -32768 || value > 65535 {
+			wrappedPayload.End = true
+			return wrappedPayload, errors.New("modbus.write_registers.create value must be between -32768 and 65535")
+		}
+		values = append(values, uint16(value))
+	}
+
+	if len(values) == 1 && !mwrc.Multiple {
+		wrappedPayload.Payload = modbus.WriteSingleRegister(uint16(address), values[0])
+		return wrappedPayload, nil
+	}
+
+	request, err := modbus.WriteMultipleRegisters(uint16(address), values)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("modbus.write_registers.create %w", err)
+	}
+	wrappedPayload.Payload = request
+	return wrappedPayload, nil
+}
+
+func (mwrc *ModbusWriteRegistersCreate) Type() string {
+	return mwrc.config.Type
+}
@@ -46,36 +46,14 @@ type ModuleOutput struct {
 	config   config.ProcessorConfig
 	ModuleId string
 	logger   *slog.Logger
-	module   common.OutputModule
 }
 
 func (mo *ModuleOutput) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
-
-	if mo.module == nil {
-		if wrappedPayload.Modules == nil {
-			wrappedPayload.End = true
-			return wrappedPayload, errors.New("module.output wrapped payload has no modules")
-		}
-
-		module, ok := wrappedPayload.Modules[mo.ModuleId]
-		if !ok {
-			wrappedPayload.End = true
-			return wrappedPayload, fmt.Errorf("module.output unable to find module with id: %s", mo.ModuleId)
-		}
-
-		outputModule, ok := module.(common.OutputModule)
-		if !ok {
-			wrappedPayload.End = true
-			return wrappedPayload, fmt.Errorf("module.output module with id %s is not an OutputModule", mo.ModuleId)
-		}
-		mo.module = outputModule
-	}
-
-	err := mo.module.Output(ctx, wrappedPayload.Payload)
+	err := outputToModule(ctx, wrappedPayload, mo.ModuleId, wrappedPayload.Payload)
 
 	if err != nil {
 		wrappedPayload.End = true
-		return wrappedPayload, fmt.Errorf("module.output failed to send output: %w", err)
+		return wrappedPayload, fmt.Errorf("module.output %w", err)
 	}
 
 	return wrappedPayload, nil
@@ -84,3 +62,26 @@ func (mo *ModuleOutput) Process(ctx context.Context, wrappedPayload common.Wrapp
 func (mo *ModuleOutput) Type() string {
 	return mo.config.Type
 }
+
+// outputToModule sends payload to a module by id for processors that pick the module while processing
+func outputToModule(ctx context.Context, wrappedPayload common.WrappedPayload, moduleId string, payload any) error {
+	if wrappedPayload.Modules == nil {
+		return errors.New("wrapped payload has no modules")
+	}
+
+	module, ok := wrappedPayload.Modules[moduleId]
+	if !ok {
+		return fmt.Errorf("unable to find module with id: %s", moduleId)
+	}
+
+	outputModule, ok := module.(common.OutputModule)
+	if !ok {
+		return fmt.Errorf("module with id %s is not an OutputModule", moduleId)
+	}
+
+	err := outputModule.Output(ctx, payload)
+	if err != nil {
+		return fmt.Errorf("failed to send output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/osc-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/oscbundle"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "osc.bundle.create",
+		Title: "Create OSC Bundle",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"messages": {
+					Title:       "Messages",
+					Description: "messages to put in the bundle, the payload message(s) are used if not set",
+					Type:        "array",
+					Items: &jsonschema.Schema{
+						Type: "object",
+						Properties: map[string]*jsonschema.Schema{
+							"address": {
+								Title:       "Address",
+								Description: "OSC address for the message",
+								Type:        "string",
+							},
+							"args": {
+								Title:       "Arguments",
+								Description: "arguments for the OSC message",
+								Type:        "array",
+								Items: &jsonschema.Schema{
+									Type: "string",
+								},
+							},
+							"types": {
+								Title:       "Argument Types",
+								Description: "string of OSC types corresponding to the arguments in args",
+								Type:        "string",
+							},
+						},
+						Required:             []string{"address"},
+						AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+					},
+				},
+				"delay": {
+					Title:       "Delay",
+					Description: "milliseconds from now to set the bundle timetag to, the bundle is immediate if not set",
+					Type:        "string",
+				},
+			},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			messageCreates := []Processor{}
+			messagesValue, ok := params["messages"]
+			if ok {
+				messagesSlice, ok := messagesValue.([]any)
+				if !ok {
+					return nil, fmt.Errorf("osc.bundle.create messages error: %w", config.ErrParamNotSlice)
+				}
+
+				//NOTE(jwetzell): each message is built exactly like osc.message.create would
+				messageRegistration, ok := GetProcessorRegistration("osc.message.create")
+				if !ok {
+					return nil, errors.New("osc.bundle.create requires the osc.message.create processor")
+				}
+
+				for messageIndex, messageValue := range messagesSlice {
+					messageParams, ok := messageValue.(map[string]any)
+					if !ok {
+						return nil, fmt.Errorf("osc.bundle.create messages[%d] error: %w", messageIndex, config.ErrParamNotMap)
+					}
+					messageCreate, err := messageRegistration.New(config.ProcessorConfig{
+						Type:   "osc.message.create",
+						Params: messageParams,
+					})
+					if err != nil {
+						return nil, fmt.Errorf("osc.bundle.create messages[%d] error: %w", messageIndex, err)
+					}
+					messageCreates = append(messageCreates, messageCreate)
+				}
+			}
+
+			var delayTemplate *template.Template
+			delayString, err := params.GetString("delay")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("osc.bundle.create delay error: %w", err)
+				}
+			} else {
+				delayTemplate, err = template.New("delay").Parse(delayString)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			return &OSCBundleCreate{config: processorConfig, Messages: messageCreates, Delay: delayTemplate}, nil
+		},
+	})
+}
+
+type OSCBundleCreate struct {
+	config   config.ProcessorConfig
+	Messages []Processor
+	Delay    *template.Template
+}
+
+func oscPacketsFromPayload(payload any) ([]osc.OSCPacket, bool) {
+	switch typedPayload := payload.(type) {
+	case osc.OSCPacket:
+		return []osc.OSCPacket{typedPayload}, true
+	case []*osc.OSCMessage:
+		packets := []osc.OSCPacket{}
+		for _, message := range typedPayload {
+			packets = append(packets, message)
+		}
+		return packets, true
+	case []any:
+		packets := []osc.OSCPacket{}
+		for _, item := range typedPayload {
+			packet, ok := item.(osc.OSCPacket)
+			if !ok {
+				return nil, false
+			}
+			packets = append(packets, packet)
+		}
+		return packets, true
+	}
+	return nil, false
+}
+
+func (obc *OSCBundleCreate) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	bundle := &oscbundle.Bundle{
+		TimeTag:  oscbundle.Immediately,
+		Contents: []osc.OSCPacket{},
+	}
+
+	if len(obc.Messages) > 0 {
+		for _, messageCreate := range obc.Messages {
+			created, err := messageCreate.Process(ctx, wrappedPayload)
+			if err != nil {
+				wrappedPayload.End = true
+				return wrappedPayload, err
+			}
+			bundle.Contents = append(bundle.Contents, created.Payload.(*osc.OSCMessage))
+		}
+	} else {
+		packets, ok := oscPacketsFromPayload(wrappedPayload.Payload)
+		if !ok {
+			wrappedPayload.End = true
+			return wrappedPayload, errors.New("osc.bundle.create processor needs a messages param or an OSC payload")
+		}
+		bundle.Contents = packets
+	}
+
+	if obc.Delay != nil {
+		templateData := wrappedPayload
+
+		var delayBuffer bytes.Buffer
+		err := obc.Delay.Execute(&delayBuffer, templateData)
+
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, err
+		}
+
+		delayValue, err := strconv.ParseFloat(delayBuffer.String(), 64)
+
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, err
+		}
+
+		if delayValue > 0 {
+			bundle.TimeTag = oscbundle.TimeTagFromTime(time.Now().Add(time.Duration(delayValue * float64(time.Millisecond))))
+		}
+	}
+
+	wrappedPayload.Payload = bundle
+	return wrappedPayload, nil
+}
+
+func (obc *OSCBundleCreate) Type() string {
+	return obc.config.Type
+}
@@ -0,0 +1,52 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/oscbundle"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "osc.bundle.decode",
+		Title: "Decode OSC Bundle",
+		New: func(config config.ProcessorConfig) (Processor, error) {
+			return &OSCBundleDecode{config: config}, nil
+		},
+	})
+}
+
+type OSCBundleDecode struct {
+	config config.ProcessorConfig
+}
+
+func (obd *OSCBundleDecode) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	payload := wrappedPayload.Payload
+	payloadBytes, ok := common.GetAnyAsByteSlice(payload)
+
+	if !ok {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("osc.bundle.decode processor only accepts a []byte payload")
+	}
+
+	if !oscbundle.IsBundle(payloadBytes) {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("osc.bundle.decode processor needs an OSC bundle looking []byte")
+	}
+
+	bundle, err := oscbundle.Decode(payloadBytes)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("osc.bundle.decode processor failed to decode OSC bundle: %w", err)
+	}
+	wrappedPayload.Payload = bundle
+	return wrappedPayload, nil
+}
+
+func (obd *OSCBundleDecode) Type() string {
+	return obd.config.Type
+}
@@ -0,0 +1,153 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/oscbundle"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "osc.bundle.encode",
+		Title: "Encode OSC Bundle",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"schedule": {
+					Title:       "Schedule",
+					Description: "send bundles with a future timetag to the module at that time instead of passing them down the route",
+					Type:        "boolean",
+					Default:     json.RawMessage(`false`),
+				},
+				"module": {
+					Title:       "Module",
+					Description: "ID of module scheduled bundles are sent to, required with schedule",
+					Type:        "string",
+				},
+				"maxDelay": {
+					Title:       "Max Delay",
+					Description: "longest time in milliseconds a scheduled bundle is held, bundles timetagged further out are rejected",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1),
+					Default:     json.RawMessage(`50`),
+				},
+			},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			scheduleBool, err := params.GetBool("schedule")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					scheduleBool = false
+				} else {
+					return nil, fmt.Errorf("osc.bundle.encode schedule error: %w", err)
+				}
+			}
+
+			moduleIdString, err := params.GetString("module")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("osc.bundle.encode module error: %w", err)
+				}
+			}
+
+			if scheduleBool && moduleIdString == "" {
+				return nil, errors.New("osc.bundle.encode schedule requires module")
+			}
+
+			maxDelayNum, err := params.GetInt("maxDelay")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					maxDelayNum = 50
+				} else {
+					return nil, fmt.Errorf("osc.bundle.encode maxDelay error: %w", err)
+				}
+			}
+
+			if maxDelayNum < 1 {
+				return nil, errors.New("osc.bundle.encode maxDelay must be at least 1")
+			}
+
+			return &OSCBundleEncode{
+				config:   processorConfig,
+				Schedule: scheduleBool,
+				ModuleId: moduleIdString,
+				MaxDelay: time.Duration(maxDelayNum) * time.Millisecond,
+				logger:   slog.Default().With("component", "processor", "type", processorConfig.Type),
+			}, nil
+		},
+	})
+}
+
+type OSCBundleEncode struct {
+	config   config.ProcessorConfig
+	Schedule bool
+	ModuleId string
+	MaxDelay time.Duration
+	logger   *slog.Logger
+}
+
+func (obe *OSCBundleEncode) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	payload := wrappedPayload.Payload
+	payloadBundle, ok := common.GetAnyAs[*oscbundle.Bundle](payload)
+
+	if !ok {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("osc.bundle.encode processor only accepts an OSC bundle")
+	}
+
+	bytes, err := payloadBundle.ToBytes()
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("osc.bundle.encode processor failed to encode OSC bundle: %w", err)
+	}
+
+	wrappedPayload.Payload = bytes
+
+	if obe.Schedule && !payloadBundle.TimeTag.IsImmediate() {
+		wait := time.Until(payloadBundle.TimeTag.Time())
+		if wait > obe.MaxDelay {
+			wrappedPayload.End = true
+			return wrappedPayload, fmt.Errorf("osc.bundle.encode timetag is %s away, more than the maxDelay of %s", wait.Round(time.Millisecond), obe.MaxDelay)
+		}
+		if wait > 0 {
+			obe.sendLater(ctx, wrappedPayload, bytes, wait)
+			// NOTE(jwetzell): the timer owns the bundle now, the route moves on without waiting for it
+			wrappedPayload.End = true
+			return wrappedPayload, nil
+		}
+	}
+
+	return wrappedPayload, nil
+}
+
+// sendLater outputs the bundle to the module after wait, the context of the input that produced it cancels the send when that module stops or the config reloads
+func (obe *OSCBundleEncode) sendLater(ctx context.Context, wrappedPayload common.WrappedPayload, bytes []byte, wait time.Duration) {
+	go func() {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		err := outputToModule(ctx, wrappedPayload, obe.ModuleId, bytes)
+		if err != nil {
+			obe.logger.Error("failed to send scheduled bundle", "module", obe.ModuleId, "error", err)
+		}
+	}()
+}
+
+func (obe *OSCBundleEncode) Type() string {
+	return obe.config.Type
+}
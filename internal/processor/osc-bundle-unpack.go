@@ -0,0 +1,47 @@
+package processor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/oscbundle"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:        "osc.bundle.unpack",
+		Title:       "Unpack OSC Bundle",
+		Description: "Send each message in a bundle through the rest of the route on its own",
+		New: func(config config.ProcessorConfig) (Processor, error) {
+			return &OSCBundleUnpack{config: config}, nil
+		},
+	})
+}
+
+type OSCBundleUnpack struct {
+	config config.ProcessorConfig
+}
+
+func (obu *OSCBundleUnpack) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	payload := wrappedPayload.Payload
+	payloadBundle, ok := common.GetAnyAs[*oscbundle.Bundle](payload)
+
+	if !ok {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("osc.bundle.unpack processor only accepts an OSC bundle")
+	}
+
+	fanOut := common.FanOut{}
+	for _, message := range payloadBundle.Messages() {
+		fanOut = append(fanOut, message)
+	}
+
+	wrappedPayload.Payload = fanOut
+	return wrappedPayload, nil
+}
+
+func (obu *OSCBundleUnpack) Type() string {
+	return obu.config.Type
+}
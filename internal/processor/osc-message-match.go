@@ -0,0 +1,233 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/osc-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/oscaddress"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:        "osc.message.match",
+		Title:       "Match OSC Message",
+		Description: "Match OSC messages against OSC address patterns, segments written as :name are captured by name",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"pattern": {
+					Title:       "Pattern",
+					Description: "OSC address pattern to match",
+					Type:        "string",
+					MinLength:   new(1),
+				},
+				"types": {
+					Title:       "Argument Types",
+					Description: "OSC types the message arguments must have, ? matches any type",
+					Type:        "string",
+				},
+				"routes": {
+					Title:       "Routes",
+					Description: "patterns to try in order, the first match is used and sent to its module if it has one",
+					Type:        "array",
+					Items: &jsonschema.Schema{
+						Type: "object",
+						Properties: map[string]*jsonschema.Schema{
+							"pattern": {
+								Title:       "Pattern",
+								Description: "OSC address pattern to match",
+								Type:        "string",
+								MinLength:   new(1),
+							},
+							"types": {
+								Title:       "Argument Types",
+								Description: "OSC types the message arguments must have, ? matches any type",
+								Type:        "string",
+							},
+							"module": {
+								Title:       "Module ID",
+								Description: "ID of module to send matching messages to, encoded as OSC bytes",
+								Type:        "string",
+							},
+						},
+						Required:             []string{"pattern"},
+						AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+					},
+				},
+			},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			routes := []OSCMessageMatchRoute{}
+
+			patternString, err := params.GetString("pattern")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("osc.message.match pattern error: %w", err)
+				}
+			} else {
+				route, err := newOSCMessageMatchRoute(params)
+				if err != nil {
+					return nil, fmt.Errorf("osc.message.match %w", err)
+				}
+				routes = append(routes, route)
+			}
+
+			routesValue, ok := params["routes"]
+			if ok {
+				if patternString != "" {
+					return nil, errors.New("osc.message.match cannot have both pattern and routes")
+				}
+
+				routesSlice, ok := routesValue.([]any)
+				if !ok {
+					return nil, fmt.Errorf("osc.message.match routes error: %w", config.ErrParamNotSlice)
+				}
+
+				for routeIndex, routeValue := range routesSlice {
+					routeParams, ok := routeValue.(map[string]any)
+					if !ok {
+						return nil, fmt.Errorf("osc.message.match routes[%d] error: %w", routeIndex, config.ErrParamNotMap)
+					}
+					route, err := newOSCMessageMatchRoute(routeParams)
+					if err != nil {
+						return nil, fmt.Errorf("osc.message.match routes[%d] %w", routeIndex, err)
+					}
+					routes = append(routes, route)
+				}
+			}
+
+			if len(routes) == 0 {
+				return nil, errors.New("osc.message.match needs a pattern or routes")
+			}
+
+			return &OSCMessageMatch{config: processorConfig, Routes: routes}, nil
+		},
+	})
+}
+
+func newOSCMessageMatchRoute(params config.Params) (OSCMessageMatchRoute, error) {
+	patternString, err := params.GetString("pattern")
+	if err != nil {
+		return OSCMessageMatchRoute{}, fmt.Errorf("pattern error: %w", err)
+	}
+
+	pattern, err := oscaddress.Compile(patternString)
+	if err != nil {
+		return OSCMessageMatchRoute{}, fmt.Errorf("pattern error: %w", err)
+	}
+
+	route := OSCMessageMatchRoute{Pattern: pattern}
+
+	typesString, err := params.GetString("types")
+	if err != nil {
+		if !errors.Is(err, config.ErrParamNotFound) {
+			return OSCMessageMatchRoute{}, fmt.Errorf("types error: %w", err)
+		}
+	} else {
+		route.Types = &typesString
+	}
+
+	moduleId, err := params.GetString("module")
+	if err != nil {
+		if !errors.Is(err, config.ErrParamNotFound) {
+			return OSCMessageMatchRoute{}, fmt.Errorf("module error: %w", err)
+		}
+	} else {
+		route.ModuleId = moduleId
+	}
+
+	return route, nil
+}
+
+type OSCMessageMatchRoute struct {
+	Pattern *oscaddress.Pattern
+	//NOTE(jwetzell): nil means any arguments are accepted
+	Types    *string
+	ModuleId string
+}
+
+// OSCMessageMatchResult is the payload passed on by osc.message.match so templates can use the captured pieces
+type OSCMessageMatchResult struct {
+	Message  *osc.OSCMessage   `json:"message"`
+	Pattern  string            `json:"pattern"`
+	Segments []string          `json:"segments"`
+	Captures []string          `json:"captures"`
+	Params   map[string]string `json:"params"`
+	Types    string            `json:"types"`
+	Args     []any             `json:"args"`
+}
+
+type OSCMessageMatch struct {
+	config config.ProcessorConfig
+	Routes []OSCMessageMatchRoute
+}
+
+func (omm *OSCMessageMatch) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	payload := wrappedPayload.Payload
+	payloadMessage, ok := common.GetAnyAs[*osc.OSCMessage](payload)
+
+	if !ok {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("osc.message.match processor only accepts an OSCMessage")
+	}
+
+	var typesBuilder strings.Builder
+	args := []any{}
+	for _, arg := range payloadMessage.Args {
+		typesBuilder.WriteString(arg.Type)
+		args = append(args, arg.Value)
+	}
+	types := typesBuilder.String()
+
+	for _, route := range omm.Routes {
+		match, ok := route.Pattern.Match(payloadMessage.Address)
+		if !ok {
+			continue
+		}
+
+		if route.Types != nil && !oscaddress.MatchTypes(*route.Types, types) {
+			continue
+		}
+
+		if route.ModuleId != "" {
+			//NOTE(jwetzell): modules that send to devices only take bytes so send the encoded message
+			messageBytes, err := payloadMessage.ToBytes()
+			if err != nil {
+				wrappedPayload.End = true
+				return wrappedPayload, fmt.Errorf("osc.message.match encode error: %w", err)
+			}
+			err = outputToModule(ctx, wrappedPayload, route.ModuleId, messageBytes)
+			if err != nil {
+				wrappedPayload.End = true
+				return wrappedPayload, fmt.Errorf("osc.message.match %w", err)
+			}
+		}
+
+		wrappedPayload.Payload = OSCMessageMatchResult{
+			Message:  payloadMessage,
+			Pattern:  route.Pattern.String(),
+			Segments: match.Segments,
+			Captures: match.Captures,
+			Params:   match.Params,
+			Types:    types,
+			Args:     args,
+		}
+		return wrappedPayload, nil
+	}
+
+	wrappedPayload.End = true
+	return wrappedPayload, nil
+}
+
+func (omm *OSCMessageMatch) Type() string {
+	return omm.config.Type
+}
@@ -0,0 +1,107 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/sacn"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "sacn.channel.get",
+		Title: "Get sACN Channel",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"channel": {
+					Title:       "Channel",
+					Description: "DMX channel to read starting at 1",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1),
+					Maximum:     jsonschema.Ptr[float64](sacn.MaxSlots),
+				},
+				"count": {
+					Title:       "Count",
+					Description: "number of channels to read, more than 1 outputs a []byte",
+					Type:        "integer",
+					Minimum:     jsonschema.Ptr[float64](1),
+					Maximum:     jsonschema.Ptr[float64](sacn.MaxSlots),
+					Default:     json.RawMessage(`1`),
+				},
+			},
+			Required:             []string{"channel"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			channelInt, err := params.GetInt("channel")
+			if err != nil {
+				return nil, fmt.Errorf("sacn.channel.get channel error: %w", err)
+			}
+
+			countInt, err := params.GetInt("count")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					countInt = 1
+				} else {
+					return nil, fmt.Errorf("sacn.channel.get count error: %w", err)
+				}
+			}
+
+			if channelInt < 1 || channelInt > sacn.MaxSlots {
+				return nil, fmt.Errorf("sacn.channel.get channel must be between 1 and %d", sacn.MaxSlots)
+			}
+
+			if countInt < 1 || channelInt+countInt-1 > sacn.MaxSlots {
+				return nil, fmt.Errorf("sacn.channel.get count must stay within %d channels", sacn.MaxSlots)
+			}
+
+			return &SACNChannelGet{config: processorConfig, Channel: channelInt, Count: countInt}, nil
+		},
+	})
+}
+
+type SACNChannelGet struct {
+	config  config.ProcessorConfig
+	Channel int
+	Count   int
+}
+
+func (scg *SACNChannelGet) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	payload := wrappedPayload.Payload
+	payloadPacket, ok := common.GetAnyAs[*sacn.DataPacket](payload)
+
+	if !ok {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("sacn.channel.get processor only accepts a sACN data packet")
+	}
+
+	values := make([]byte, scg.Count)
+	for index := range scg.Count {
+		value, err := payloadPacket.Channel(scg.Channel + index)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, err
+		}
+		values[index] = value
+	}
+
+	if scg.Count == 1 {
+		wrappedPayload.Payload = values[0]
+	} else {
+		wrappedPayload.Payload = values
+	}
+
+	return wrappedPayload, nil
+}
+
+func (scg *SACNChannelGet) Type() string {
+	return scg.config.Type
+}
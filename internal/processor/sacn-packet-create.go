@@ -0,0 +1,152 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/sacn"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "sacn.packet.create",
+		Title: "Create sACN Packet",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"universe": {
+					Title:       "Universe",
+					Description: "universe for the data packet",
+					Type:        "string",
+				},
+				"data": {
+					Title:       "Data",
+					Description: "comma separated channel values starting at channel 1, the payload bytes are used if not set",
+					Type:        "string",
+				},
+			},
+			Required:             []string{"universe"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			universeString, err := params.GetString("universe")
+			if err != nil {
+				return nil, fmt.Errorf("sacn.packet.create universe error: %w", err)
+			}
+
+			universeTemplate, err := template.New("universe").Parse(universeString)
+
+			if err != nil {
+				return nil, err
+			}
+
+			var dataTemplate *template.Template
+			dataString, err := params.GetString("data")
+			if err != nil {
+				if !errors.Is(err, config.ErrParamNotFound) {
+					return nil, fmt.Errorf("sacn.packet.create data error: %w", err)
+				}
+			} else {
+				dataTemplate, err = template.New("data").Parse(dataString)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			return &SACNPacketCreate{
+				config:   processorConfig,
+				Universe: universeTemplate,
+				Data:     dataTemplate,
+			}, nil
+		},
+	})
+}
+
+type SACNPacketCreate struct {
+	config   config.ProcessorConfig
+	Universe *template.Template
+	Data     *template.Template
+}
+
+func (spc *SACNPacketCreate) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	templateData := wrappedPayload
+
+	var universeBuffer bytes.Buffer
+	err := spc.Universe.Execute(&universeBuffer, templateData)
+
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, err
+	}
+
+	universeValue, err := strconv.ParseUint(universeBuffer.String(), 10, 16)
+
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, err
+	}
+
+	var data []byte
+	if spc.Data != nil {
+		var dataBuffer bytes.Buffer
+		err = spc.Data.Execute(&dataBuffer, templateData)
+
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, err
+		}
+
+		data = []byte{}
+		for valueString := range strings.SplitSeq(dataBuffer.String(), ",") {
+			valueString = strings.TrimSpace(valueString)
+			if valueString == "" {
+				continue
+			}
+			value, err := strconv.ParseUint(valueString, 10, 8)
+			if err != nil {
+				wrappedPayload.End = true
+				return wrappedPayload, err
+			}
+			data = append(data, byte(value))
+		}
+	} else {
+		payloadBytes, ok := common.GetAnyAsByteSlice(wrappedPayload.Payload)
+		if !ok {
+			wrappedPayload.End = true
+			return wrappedPayload, errors.New("sacn.packet.create processor needs a data param or a []byte payload")
+		}
+		data = payloadBytes
+	}
+
+	if universeValue < sacn.MinUniverse || universeValue > sacn.MaxUniverse {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("sacn.packet.create universe must be between %d and %d", sacn.MinUniverse, sacn.MaxUniverse)
+	}
+
+	if len(data) > sacn.MaxSlots {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("sacn.packet.create data cannot be more than %d slots", sacn.MaxSlots)
+	}
+
+	//NOTE(jwetzell): priority is left unset so the sacn.sender priority applies
+	wrappedPayload.Payload = &sacn.DataPacket{
+		Universe: uint16(universeValue),
+		Data:     data,
+	}
+
+	return wrappedPayload, nil
+}
+
+func (spc *SACNPacketCreate) Type() string {
+	return spc.config.Type
+}
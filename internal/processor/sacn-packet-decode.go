@@ -0,0 +1,49 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/sacn"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "sacn.packet.decode",
+		Title: "Decode sACN Packet",
+		New: func(config config.ProcessorConfig) (Processor, error) {
+			return &SACNPacketDecode{config: config}, nil
+		},
+	})
+}
+
+type SACNPacketDecode struct {
+	config config.ProcessorConfig
+}
+
+func (spd *SACNPacketDecode) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	payload := wrappedPayload.Payload
+	payloadBytes, ok := common.GetAnyAsByteSlice(payload)
+
+	if !ok {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("sacn.packet.decode processor only accepts a []byte")
+	}
+
+	payloadPacket, err := sacn.Decode(payloadBytes)
+
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, err
+	}
+
+	wrappedPayload.Payload = payloadPacket
+
+	return wrappedPayload, nil
+}
+
+func (spd *SACNPacketDecode) Type() string {
+	return spd.config.Type
+}
@@ -0,0 +1,48 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/sacn"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:  "sacn.packet.encode",
+		Title: "Encode sACN Packet",
+		New: func(config config.ProcessorConfig) (Processor, error) {
+			return &SACNPacketEncode{config: config}, nil
+		},
+	})
+}
+
+type SACNPacketEncode struct {
+	config config.ProcessorConfig
+}
+
+func (spe *SACNPacketEncode) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	payload := wrappedPayload.Payload
+	payloadPacket, ok := common.GetAnyAs[sacn.Packet](payload)
+
+	if !ok {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("sacn.packet.encode processor only accepts a sACN packet")
+	}
+
+	payloadBytes, err := payloadPacket.MarshalBinary()
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, err
+	}
+
+	wrappedPayload.Payload = payloadBytes
+
+	return wrappedPayload, nil
+}
+
+func (spe *SACNPacketEncode) Type() string {
+	return spe.config.Type
+}
@@ -0,0 +1,135 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/artnet-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/timecode"
+)
+
+func TestArtNetTimecodeCreateFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("artnet.timecode.create")
+	if !ok {
+		t.Fatalf("artnet.timecode.create processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "artnet.timecode.create",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create artnet.timecode.create processor: %s", err)
+	}
+
+	if processorInstance.Type() != "artnet.timecode.create" {
+		t.Fatalf("artnet.timecode.create processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodArtNetTimecodeCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected any
+	}{
+		{
+			name:     "master stream",
+			payload:  timecode.Timecode{Hours: 1, Minutes: 2, Seconds: 3, Frames: 4, Rate: timecode.Rate2997DF},
+			expected: &artnet.ArtTimeCode{Hours: 1, Minutes: 2, Seconds: 3, Frames: 4, Type: 2},
+		},
+		{
+			name:     "stream id",
+			params:   map[string]any{"streamId": 3},
+			payload:  &timecode.Timecode{Frames: 4, Rate: timecode.Rate24},
+			expected: &artnet.ArtTimeCode{StreamId: 3, Frames: 4},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("artnet.timecode.create")
+			if !ok {
+				t.Fatalf("artnet.timecode.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "artnet.timecode.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("artnet.timecode.create failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("artnet.timecode.create processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("artnet.timecode.create got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadArtNetTimecodeCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "stream id out of range",
+			params:      map[string]any{"streamId": 256},
+			errorString: "artnet.timecode.create streamId must be between 0 and 255",
+		},
+		{
+			name:        "non-number stream id",
+			params:      map[string]any{"streamId": "1"},
+			errorString: "artnet.timecode.create streamId error: not a number",
+		},
+		{
+			name:        "not a timecode",
+			payload:     "01:00:00:00",
+			errorString: "artnet.timecode.create processor only accepts a timecode.Timecode",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("artnet.timecode.create")
+			if !ok {
+				t.Fatalf("artnet.timecode.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "artnet.timecode.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("artnet.timecode.create got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("artnet.timecode.create expected to fail but succeeded, got: %v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("artnet.timecode.create got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
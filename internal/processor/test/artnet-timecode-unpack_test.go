@@ -0,0 +1,134 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/artnet-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/timecode"
+)
+
+func TestArtNetTimecodeUnpackFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("artnet.timecode.unpack")
+	if !ok {
+		t.Fatalf("artnet.timecode.unpack processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "artnet.timecode.unpack",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create artnet.timecode.unpack processor: %s", err)
+	}
+
+	if processorInstance.Type() != "artnet.timecode.unpack" {
+		t.Fatalf("artnet.timecode.unpack processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodArtNetTimecodeUnpack(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected any
+	}{
+		{
+			name:     "packet",
+			payload:  &artnet.ArtTimeCode{Hours: 1, Minutes: 2, Seconds: 3, Frames: 4, Type: 1},
+			expected: timecode.Timecode{Hours: 1, Minutes: 2, Seconds: 3, Frames: 4, Rate: timecode.Rate25},
+		},
+		{
+			name:     "bytes",
+			payload:  []byte{'A', 'r', 't', '-', 'N', 'e', 't', 0x00, 0x00, 0x97, 0x00, 0x0e, 0x00, 0x00, 0x0a, 0x0b, 0x0c, 0x0d, 0x03},
+			expected: timecode.Timecode{Hours: 13, Minutes: 12, Seconds: 11, Frames: 10, Rate: timecode.Rate30},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("artnet.timecode.unpack")
+			if !ok {
+				t.Fatalf("artnet.timecode.unpack processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "artnet.timecode.unpack",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("artnet.timecode.unpack failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("artnet.timecode.unpack processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("artnet.timecode.unpack got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadArtNetTimecodeUnpack(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "not a packet",
+			payload:     1,
+			errorString: "artnet.timecode.unpack processor only accepts an ArtTimeCode packet",
+		},
+		{
+			name:        "bytes not an ArtTimeCode",
+			payload:     []byte{0x01, 0x02},
+			errorString: "artnet.timecode.unpack ArtTimeCode packet must be at least 14 bytes long",
+		},
+		{
+			name:        "invalid timecode",
+			payload:     &artnet.ArtTimeCode{Hours: 30, Type: 3},
+			errorString: "artnet.timecode.unpack timecode is out of range: 30:00:00:00",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("artnet.timecode.unpack")
+			if !ok {
+				t.Fatalf("artnet.timecode.unpack processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "artnet.timecode.unpack",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("artnet.timecode.unpack got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("artnet.timecode.unpack expected to fail but succeeded, got: %v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("artnet.timecode.unpack got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package processor_test
+
+import (
+	"testing"
+
+	"github.com/jwetzell/artnet-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/sacn"
+)
+
+func TestDMXChannelGetFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("dmx.channel.get")
+	if !ok {
+		t.Fatalf("dmx.channel.get processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "dmx.channel.get",
+		Params: map[string]any{
+			"channel": 1,
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create dmx.channel.get processor: %s", err)
+	}
+
+	if processorInstance.Type() != "dmx.channel.get" {
+		t.Fatalf("dmx.channel.get processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodDMXChannelGet(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected int
+	}{
+		{
+			name:     "ArtDmx channel",
+			params:   map[string]any{"channel": 2},
+			payload:  &artnet.ArtDmx{Data: []byte{10, 20, 30}},
+			expected: 20,
+		},
+		{
+			name:     "sACN channel",
+			params:   map[string]any{"channel": 3},
+			payload:  &sacn.DataPacket{Universe: 1, Data: []byte{10, 20, 30}},
+			expected: 30,
+		},
+		{
+			name:     "16-bit channel pair",
+			params:   map[string]any{"channel": 1, "fine": true},
+			payload:  []byte{0x12, 0x34},
+			expected: 0x1234,
+		},
+		{
+			name:     "channel past data",
+			params:   map[string]any{"channel": 100},
+			payload:  []byte{1},
+			expected: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("dmx.channel.get")
+			if !ok {
+				t.Fatalf("dmx.channel.get processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "dmx.channel.get",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("dmx.channel.get failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("dmx.channel.get processing failed: %s", err)
+			}
+
+			gotInt, ok := got.Payload.(int)
+			if !ok {
+				t.Fatalf("dmx.channel.get returned a %T payload: %+v", got.Payload, got.Payload)
+			}
+
+			if gotInt != test.expected {
+				t.Fatalf("dmx.channel.get got %d, expected %d", gotInt, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadDMXChannelGet(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "no channel param",
+			params:      map[string]any{},
+			payload:     []byte{1},
+			errorString: "dmx.channel.get channel error: not found",
+		},
+		{
+			name:        "non-boolean fine param",
+			params:      map[string]any{"channel": 1, "fine": "yes"},
+			payload:     []byte{1},
+			errorString: "dmx.channel.get fine error: not a boolean",
+		},
+		{
+			name:        "channel out of range",
+			params:      map[string]any{"channel": 0},
+			payload:     []byte{1},
+			errorString: "dmx.channel.get channel must be between 1 and 512",
+		},
+		{
+			name:        "fine channel out of range",
+			params:      map[string]any{"channel": 512, "fine": true},
+			payload:     []byte{1},
+			errorString: "dmx.channel.get fine channel cannot be past channel 512",
+		},
+		{
+			name:        "not DMX data",
+			params:      map[string]any{"channel": 1},
+			payload:     "hello",
+			errorString: "dmx.channel.get processor only accepts DMX data",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := processor.GetProcessorRegistration("dmx.channel.get")
+			if !ok {
+				t.Fatalf("dmx.channel.get processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "dmx.channel.get",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("dmx.channel.get got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("dmx.channel.get expected to fail but got payload: %+v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("dmx.channel.get got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package processor_test
+
+import (
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+)
+
+func TestDMXFrameCreateFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("dmx.frame.create")
+	if !ok {
+		t.Fatalf("dmx.frame.create processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "dmx.frame.create",
+		Params: map[string]any{
+			"channels": map[string]any{"1": "255"},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create dmx.frame.create processor: %s", err)
+	}
+
+	if processorInstance.Type() != "dmx.frame.create" {
+		t.Fatalf("dmx.frame.create processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodDMXFrameCreate(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("dmx.frame.create")
+	if !ok {
+		t.Fatalf("dmx.frame.create processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "dmx.frame.create",
+		Params: map[string]any{
+			"channels": map[string]any{
+				"1":   "{{.Payload.dimmer}}",
+				"2":   "128",
+				"512": "{{.Payload.last}}",
+			},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("dmx.frame.create failed to create processor: %s", err)
+	}
+
+	got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: map[string]any{"dimmer": 200, "last": 7}})
+	if err != nil {
+		t.Fatalf("dmx.frame.create processing failed: %s", err)
+	}
+
+	frame, ok := got.Payload.([]byte)
+	if !ok {
+		t.Fatalf("dmx.frame.create returned a %T payload", got.Payload)
+	}
+
+	if len(frame) != 512 {
+		t.Fatalf("dmx.frame.create frame should have 512 slots, got %d", len(frame))
+	}
+
+	if frame[0] != 200 || frame[1] != 128 || frame[2] != 0 || frame[511] != 7 {
+		t.Fatalf("dmx.frame.create frame has wrong values: %v %v %v %v", frame[0], frame[1], frame[2], frame[511])
+	}
+}
+
+func TestBadDMXFrameCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "no channels param",
+			params:      map[string]any{},
+			errorString: "dmx.frame.create channels error: not found",
+		},
+		{
+			name:        "non-string channel value",
+			params:      map[string]any{"channels": map[string]any{"1": 255}},
+			errorString: "dmx.frame.create channels error: not a string map",
+		},
+		{
+			name:        "channel out of range",
+			params:      map[string]any{"channels": map[string]any{"513": "1"}},
+			errorString: "dmx.frame.create channel must be between 1 and 512, got 513",
+		},
+		{
+			name:        "channel not a number",
+			params:      map[string]any{"channels": map[string]any{"dimmer": "1"}},
+			errorString: "dmx.frame.create channel must be between 1 and 512, got dimmer",
+		},
+		{
+			name:        "value out of range",
+			params:      map[string]any{"channels": map[string]any{"1": "{{.Payload}}"}},
+			payload:     256,
+			errorString: "dmx.frame.create channel 1 error: strconv.ParseUint: parsing \"256\": value out of range",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := processor.GetProcessorRegistration("dmx.frame.create")
+			if !ok {
+				t.Fatalf("dmx.frame.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "dmx.frame.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("dmx.frame.create got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("dmx.frame.create expected to fail but got payload: %+v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("dmx.frame.create got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,392 @@
+package processor_test
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/artnet-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/route"
+	"github.com/jwetzell/showbridge-go/internal/sacn"
+)
+
+func TestDMXMergeFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("dmx.merge")
+	if !ok {
+		t.Fatalf("dmx.merge processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "dmx.merge",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create dmx.merge processor: %s", err)
+	}
+
+	if processorInstance.Type() != "dmx.merge" {
+		t.Fatalf("dmx.merge processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+type dmxMergeInput struct {
+	source     string
+	remoteAddr string
+	payload    any
+}
+
+func TestGoodDMXMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		inputs   []dmxMergeInput
+		expected []byte
+	}{
+		{
+			name:   "htp",
+			params: map[string]any{"mode": "htp"},
+			inputs: []dmxMergeInput{
+				{source: "a", payload: &artnet.ArtDmx{Data: []byte{100, 0, 50}}},
+				{source: "b", payload: &artnet.ArtDmx{Data: []byte{50, 200}}},
+			},
+			expected: []byte{100, 200, 50},
+		},
+		{
+			name:   "ltp",
+			params: map[string]any{"mode": "ltp"},
+			inputs: []dmxMergeInput{
+				{source: "a", payload: []byte{100, 0, 50}},
+				{source: "b", payload: []byte{50, 200, 10}},
+				{source: "a", payload: []byte{100, 0, 60}},
+			},
+			expected: []byte{50, 200, 60},
+		},
+		{
+			name:   "sacn sources on one module",
+			params: map[string]any{},
+			inputs: []dmxMergeInput{
+				{source: "sacn", payload: &sacn.DataPacket{CID: sacn.CID{1}, Data: []byte{10}}},
+				{source: "sacn", payload: &sacn.DataPacket{CID: sacn.CID{2}, Data: []byte{0, 20}}},
+			},
+			expected: []byte{10, 20},
+		},
+		{
+			name:   "udp senders on one module",
+			params: map[string]any{},
+			inputs: []dmxMergeInput{
+				{source: "udp", remoteAddr: "10.0.0.1:6454", payload: []byte{10}},
+				{source: "udp", remoteAddr: "10.0.0.2:6454", payload: []byte{0, 20}},
+			},
+			expected: []byte{10, 20},
+		},
+		{
+			name:   "universes on one module stay apart",
+			params: map[string]any{},
+			inputs: []dmxMergeInput{
+				{source: "artnet", remoteAddr: "10.0.0.1:6454", payload: &artnet.ArtDmx{SubUni: 1, Data: []byte{200, 200}}},
+				{source: "artnet", remoteAddr: "10.0.0.1:6454", payload: &artnet.ArtDmx{SubUni: 2, Data: []byte{10}}},
+			},
+			expected: []byte{10, 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("dmx.merge")
+			if !ok {
+				t.Fatalf("dmx.merge processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "dmx.merge",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("dmx.merge failed to create processor: %s", err)
+			}
+
+			var got common.WrappedPayload
+			for _, input := range test.inputs {
+				ctx := t.Context()
+				if input.remoteAddr != "" {
+					ctx = common.WithRemoteAddr(ctx, net.UDPAddrFromAddrPort(netip.MustParseAddrPort(input.remoteAddr)))
+				}
+				got, err = processorInstance.Process(ctx, common.WrappedPayload{Source: input.source, Payload: input.payload})
+				if err != nil {
+					t.Fatalf("dmx.merge processing failed: %s", err)
+				}
+			}
+
+			lastPayload := test.inputs[len(test.inputs)-1].payload
+			if reflect.TypeOf(got.Payload) != reflect.TypeOf(lastPayload) {
+				t.Fatalf("dmx.merge returned a %T payload for a %T input", got.Payload, lastPayload)
+			}
+
+			merged := dmxMergeData(got.Payload)
+
+			if len(merged) != 512 {
+				t.Fatalf("dmx.merge frame should have 512 slots, got %d", len(merged))
+			}
+
+			if !slices.Equal(merged[:len(test.expected)], test.expected) {
+				t.Fatalf("dmx.merge got %v, expected %v", merged[:len(test.expected)], test.expected)
+			}
+		})
+	}
+}
+
+func dmxMergeData(payload any) []byte {
+	switch typedPayload := payload.(type) {
+	case *artnet.ArtDmx:
+		return typedPayload.Data
+	case *sacn.DataPacket:
+		return typedPayload.Data
+	case []byte:
+		return typedPayload
+	}
+	return nil
+}
+
+func TestDMXMergeKeepsUniverse(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("dmx.merge")
+	if !ok {
+		t.Fatalf("dmx.merge processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "dmx.merge",
+	})
+	if err != nil {
+		t.Fatalf("dmx.merge failed to create processor: %s", err)
+	}
+
+	input := &sacn.DataPacket{CID: sacn.CID{1}, Universe: 7, Priority: 150, Data: []byte{42}}
+	got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Source: "sacn", Payload: input})
+	if err != nil {
+		t.Fatalf("dmx.merge processing failed: %s", err)
+	}
+
+	packet, ok := got.Payload.(*sacn.DataPacket)
+	if !ok {
+		t.Fatalf("dmx.merge returned a %T payload for an sACN input", got.Payload)
+	}
+
+	if packet.Universe != 7 || packet.Priority != 150 || len(packet.Data) != 512 || packet.Data[0] != 42 {
+		t.Fatalf("dmx.merge sACN output lost its universe or data: %+v", packet)
+	}
+
+	if packet == input || len(input.Data) != 1 {
+		t.Fatalf("dmx.merge should not modify the incoming packet")
+	}
+
+	got, err = processorInstance.Process(t.Context(), common.WrappedPayload{Source: "artnet", Payload: &artnet.ArtDmx{Net: 1, SubUni: 2, Data: []byte{9}}})
+	if err != nil {
+		t.Fatalf("dmx.merge processing failed: %s", err)
+	}
+
+	artDmx, ok := got.Payload.(*artnet.ArtDmx)
+	if !ok || artDmx.Net != 1 || artDmx.SubUni != 2 || artDmx.Data[0] != 9 {
+		t.Fatalf("dmx.merge ArtDmx output lost its universe or data: %+v", got.Payload)
+	}
+}
+
+func TestDMXMergeTimeout(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("dmx.merge")
+	if !ok {
+		t.Fatalf("dmx.merge processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type:   "dmx.merge",
+		Params: map[string]any{"timeout": 50},
+	})
+
+	if err != nil {
+		t.Fatalf("dmx.merge failed to create processor: %s", err)
+	}
+
+	_, err = processorInstance.Process(t.Context(), common.WrappedPayload{Source: "a", Payload: []byte{255}})
+	if err != nil {
+		t.Fatalf("dmx.merge processing failed: %s", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Source: "b", Payload: []byte{10}})
+	if err != nil {
+		t.Fatalf("dmx.merge processing failed: %s", err)
+	}
+
+	merged := got.Payload.([]byte)
+	if merged[0] != 10 {
+		t.Fatalf("dmx.merge should drop timed out sources, got %d", merged[0])
+	}
+
+	dmxMerge := processorInstance.(*processor.DMXMerge)
+	if !slices.Equal(dmxMerge.Sources(), []string{"b"}) {
+		t.Fatalf("dmx.merge sources wrong: %v", dmxMerge.Sources())
+	}
+}
+
+func newDMXMergeGroupRoutes(t *testing.T, groups *processor.Groups, modes ...string) ([]*route.Route, error) {
+	t.Helper()
+	routes := []*route.Route{}
+	for index, mode := range modes {
+		routeInstance, err := route.NewRoute(config.RouteConfig{
+			Input: fmt.Sprintf("node%d", index),
+			Processors: []config.ProcessorConfig{
+				{Type: "dmx.merge", Params: map[string]any{"group": "lights", "mode": mode}},
+			},
+		})
+		if err != nil {
+			t.Fatalf("route failed to create: %s", err)
+		}
+		err = routeInstance.JoinGroups(groups)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, routeInstance)
+	}
+	return routes, nil
+}
+
+func TestDMXMergeGroup(t *testing.T) {
+	routes, err := newDMXMergeGroupRoutes(t, processor.NewGroups(), "htp", "htp")
+	if err != nil {
+		t.Fatalf("routes failed to join groups: %s", err)
+	}
+
+	frames := []struct {
+		route   int
+		source  string
+		payload *artnet.ArtDmx
+	}{
+		{route: 0, source: "nodeA", payload: &artnet.ArtDmx{SubUni: 1, Data: []byte{100, 0, 50}}},
+		{route: 1, source: "nodeB", payload: &artnet.ArtDmx{SubUni: 1, Data: []byte{50, 200}}},
+		{route: 1, source: "nodeB", payload: &artnet.ArtDmx{SubUni: 2, Data: []byte{255}}},
+		{route: 0, source: "nodeA", payload: &artnet.ArtDmx{SubUni: 1, Data: []byte{100, 0, 60}}},
+	}
+
+	var got any
+	for _, frame := range frames {
+		got, err = routes[frame.route].ProcessPayload(t.Context(), common.WrappedPayload{Source: frame.source, Payload: frame.payload})
+		if err != nil {
+			t.Fatalf("route processing failed: %s", err)
+		}
+	}
+
+	packet, ok := got.(*artnet.ArtDmx)
+	if !ok || packet.SubUni != 1 {
+		t.Fatalf("dmx.merge should return an ArtDmx for universe 1, got %+v", got)
+	}
+
+	merged := packet.Data
+	if !slices.Equal(merged[:3], []byte{100, 200, 60}) {
+		t.Fatalf("dmx.merge should merge both routes on universe 1, got %v", merged[:3])
+	}
+
+	//NOTE(jwetzell): routes built into new groups, like after a config reload, start with no sources
+	reloaded, err := newDMXMergeGroupRoutes(t, processor.NewGroups(), "htp")
+	if err != nil {
+		t.Fatalf("routes failed to join groups: %s", err)
+	}
+
+	got, err = reloaded[0].ProcessPayload(t.Context(), common.WrappedPayload{Source: "nodeA", Payload: &artnet.ArtDmx{SubUni: 1, Data: []byte{10}}})
+	if err != nil {
+		t.Fatalf("route processing failed: %s", err)
+	}
+
+	merged = dmxMergeData(got)
+	if !slices.Equal(merged[:3], []byte{10, 0, 0}) {
+		t.Fatalf("dmx.merge kept sources from groups of an old config, got %v", got)
+	}
+}
+
+func TestDMXMergeGroupConflict(t *testing.T) {
+	_, err := newDMXMergeGroupRoutes(t, processor.NewGroups(), "htp", "ltp")
+	if err == nil || err.Error() != "processor[0] error: dmx.merge group lights already uses mode htp and timeout 2.5s" {
+		t.Fatalf("dmx.merge should reject a group member with a different mode, got: %v", err)
+	}
+}
+
+func TestBadDMXMerge(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "unknown mode",
+			params:      map[string]any{"mode": "max"},
+			errorString: "dmx.merge mode must be htp or ltp, got max",
+		},
+		{
+			name:        "non-number timeout",
+			params:      map[string]any{"timeout": "1"},
+			errorString: "dmx.merge timeout error: not a number",
+		},
+		{
+			name:        "negative timeout",
+			params:      map[string]any{"timeout": -1},
+			errorString: "dmx.merge timeout cannot be negative",
+		},
+		{
+			name:        "non-string group",
+			params:      map[string]any{"group": 1},
+			errorString: "dmx.merge group error: not a string",
+		},
+		{
+			name:        "not DMX data",
+			params:      map[string]any{},
+			payload:     "hello",
+			errorString: "dmx.merge processor only accepts DMX data",
+		},
+		{
+			name:        "too much data",
+			params:      map[string]any{},
+			payload:     make([]byte, 513),
+			errorString: "dmx.merge data cannot be more than 512 channels",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := processor.GetProcessorRegistration("dmx.merge")
+			if !ok {
+				t.Fatalf("dmx.merge processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "dmx.merge",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("dmx.merge got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("dmx.merge expected to fail but got payload: %+v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("dmx.merge got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package processor_test
+
+import (
+	"math"
+	"testing"
+
+	freeD "github.com/jwetzell/free-d-go"
+	"github.com/jwetzell/psn-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+)
+
+func TestFreeDPSNConvertFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("freed.psn.convert")
+	if !ok {
+		t.Fatalf("freed.psn.convert processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "freed.psn.convert",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create freed.psn.convert processor: %s", err)
+	}
+
+	if processorInstance.Type() != "freed.psn.convert" {
+		t.Fatalf("freed.psn.convert processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func closeTo(a float32, b float32) bool {
+	return math.Abs(float64(a-b)) < 0.0001
+}
+
+func TestGoodFreeDPSNConvert(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("freed.psn.convert")
+	if !ok {
+		t.Fatalf("freed.psn.convert processor not registered")
+	}
+
+	payload := freeD.FreeDPosition{
+		ID:   3,
+		Pan:  90,
+		Tilt: -45,
+		Roll: 0,
+		PosX: 1000,
+		PosY: 2000,
+		PosZ: 1500,
+	}
+
+	tests := []struct {
+		name     string
+		params   map[string]any
+		expected psn.Tracker
+	}{
+		{
+			name:   "defaults",
+			params: map[string]any{},
+			expected: psn.Tracker{
+				Id:   3,
+				Name: "camera 3",
+				Pos:  &psn.XYZData{X: 1, Y: 1.5, Z: -2},
+				Ori:  &psn.XYZData{X: -math.Pi / 4, Y: math.Pi / 2, Z: 0},
+			},
+		},
+		{
+			name: "calibrated",
+			params: map[string]any{
+				"trackerId":   10,
+				"trackerName": "jib",
+				"scale":       0.01,
+				"offset":      map[string]any{"x": 1, "z": -1},
+				"position":    map[string]any{"x": "-posY", "y": "posZ", "z": "posX"},
+				"orientation": map[string]any{"x": "roll", "y": "-pan", "z": "tilt"},
+			},
+			expected: psn.Tracker{
+				Id:   10,
+				Name: "jib",
+				Pos:  &psn.XYZData{X: -19, Y: 15, Z: 9},
+				Ori:  &psn.XYZData{X: 0, Y: -math.Pi / 2, Z: -math.Pi / 4},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "freed.psn.convert",
+				Params: test.params,
+			})
+			if err != nil {
+				t.Fatalf("freed.psn.convert failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: payload})
+			if err != nil {
+				t.Fatalf("freed.psn.convert processing failed: %s", err)
+			}
+
+			gotTracker, ok := got.Payload.(*psn.Tracker)
+			if !ok {
+				t.Fatalf("freed.psn.convert returned a %T payload: %+v", got.Payload, got.Payload)
+			}
+
+			if gotTracker.Id != test.expected.Id || gotTracker.Name != test.expected.Name {
+				t.Fatalf("freed.psn.convert got tracker %d %s, expected %d %s", gotTracker.Id, gotTracker.Name, test.expected.Id, test.expected.Name)
+			}
+
+			if !closeTo(gotTracker.Pos.X, test.expected.Pos.X) || !closeTo(gotTracker.Pos.Y, test.expected.Pos.Y) || !closeTo(gotTracker.Pos.Z, test.expected.Pos.Z) {
+				t.Fatalf("freed.psn.convert got position %+v, expected %+v", *gotTracker.Pos, *test.expected.Pos)
+			}
+
+			if !closeTo(gotTracker.Ori.X, test.expected.Ori.X) || !closeTo(gotTracker.Ori.Y, test.expected.Ori.Y) || !closeTo(gotTracker.Ori.Z, test.expected.Ori.Z) {
+				t.Fatalf("freed.psn.convert got orientation %+v, expected %+v", *gotTracker.Ori, *test.expected.Ori)
+			}
+
+			if gotTracker.Validity == nil || *gotTracker.Validity != 1 {
+				t.Fatalf("freed.psn.convert should mark the tracker valid")
+			}
+		})
+	}
+}
+
+func TestBadFreeDPSNConvert(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("freed.psn.convert")
+	if !ok {
+		t.Fatalf("freed.psn.convert processor not registered")
+	}
+
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "trackerId out of range",
+			params:      map[string]any{"trackerId": 70000},
+			errorString: "freed.psn.convert trackerId must be between 0 and 65535",
+		},
+		{
+			name:        "non-number scale",
+			params:      map[string]any{"scale": "1"},
+			errorString: "freed.psn.convert scale error: not a number",
+		},
+		{
+			name:        "non-map offset",
+			params:      map[string]any{"offset": 1},
+			errorString: "freed.psn.convert offset error: not a map",
+		},
+		{
+			name:        "non-number offset axis",
+			params:      map[string]any{"offset": map[string]any{"x": "1"}},
+			errorString: "freed.psn.convert offset x error: not a number",
+		},
+		{
+			name:        "missing position axis",
+			params:      map[string]any{"position": map[string]any{"x": "posX", "y": "posY"}},
+			errorString: "freed.psn.convert position is missing the z axis",
+		},
+		{
+			name:        "unknown position axis",
+			params:      map[string]any{"position": map[string]any{"x": "posX", "y": "posY", "z": "posZ", "w": "posX"}},
+			errorString: "freed.psn.convert position axis must be x, y or z, got w",
+		},
+		{
+			name:        "orientation with a position field",
+			params:      map[string]any{"orientation": map[string]any{"x": "posX", "y": "pan", "z": "roll"}},
+			errorString: "freed.psn.convert orientation x must be one of pan, tilt, roll, got posX",
+		},
+		{
+			name:        "non-FreeD payload",
+			params:      map[string]any{},
+			payload:     []byte{0xd1},
+			errorString: "freed.psn.convert processor only accepts a FreeDPosition",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "freed.psn.convert",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("freed.psn.convert got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err == nil {
+				t.Fatalf("freed.psn.convert expected to fail but got payload: %+v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("freed.psn.convert got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -1,8 +1,16 @@
 package processor_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/jwetzell/showbridge-go/internal/common"
 	"github.com/jwetzell/showbridge-go/internal/config"
@@ -32,14 +40,101 @@ func TestHTTPRequestCreateFromRegistry(t *testing.T) {
 	}
 }
 
+func newHTTPRequestDoServer(t *testing.T) *httptest.Server {
+	var flakyCount atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flaky":
+			//NOTE(jwetzell): fail twice so only requests that retry get through
+			if flakyCount.Add(1) <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		case "/text":
+			w.Write([]byte("not json"))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		json.NewEncoder(w).Encode(map[string]string{
+			"authorization": r.Header.Get("Authorization"),
+			"contentType":   r.Header.Get("Content-Type"),
+			"cue":           r.Header.Get("X-Cue"),
+			"body":          string(body),
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
 func TestGoodHTTPRequestDo(t *testing.T) {
+	server := newHTTPRequestDoServer(t)
 
 	tests := []struct {
 		name     string
 		expected any
 		params   map[string]any
 		payload  any
-	}{}
+	}{
+		{
+			name: "templated body and headers",
+			params: map[string]any{
+				"method":       "POST",
+				"url":          server.URL + "/cue",
+				"bodyTemplate": "go {{.Payload}}",
+				"headers":      map[string]any{"X-Cue": "{{.Payload}}"},
+				"auth":         map[string]any{"type": "bearer", "token": "secret"},
+				"parseJSON":    true,
+			},
+			payload: "5",
+			expected: map[string]any{
+				"authorization": "Bearer secret",
+				"contentType":   "",
+				"cue":           "5",
+				"body":          "go 5",
+			},
+		},
+		{
+			name: "json body with basic auth",
+			params: map[string]any{
+				"method":    "PUT",
+				"url":       server.URL + "/cue",
+				"json":      true,
+				"auth":      map[string]any{"type": "basic", "username": "show", "password": "bridge"},
+				"parseJSON": true,
+			},
+			payload: map[string]any{"level": 50},
+			expected: map[string]any{
+				"authorization": "Basic c2hvdzpicmlkZ2U=",
+				"contentType":   "application/json",
+				"cue":           "",
+				"body":          "{\"level\":50}",
+			},
+		},
+		{
+			name: "retries 5xx",
+			params: map[string]any{
+				"method":       "GET",
+				"url":          server.URL + "/flaky",
+				"retries":      2,
+				"backoff":      1,
+				"parseJSON":    true,
+				"failOnStatus": true,
+			},
+			payload: nil,
+			expected: map[string]any{
+				"authorization": "",
+				"contentType":   "",
+				"cue":           "",
+				"body":          "",
+			},
+		},
+	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -63,8 +158,17 @@ func TestGoodHTTPRequestDo(t *testing.T) {
 				t.Fatalf("http.request.do processing failed: %s", err)
 			}
 
-			if !reflect.DeepEqual(got.Payload, test.expected) {
-				t.Fatalf("http.request.do got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			response, ok := got.Payload.(processor.HTTPResponse)
+			if !ok {
+				t.Fatalf("http.request.do got %T, expected processor.HTTPResponse", got.Payload)
+			}
+
+			if response.Status != http.StatusOK || response.Headers["X-Method"] != test.params["method"] {
+				t.Fatalf("http.request.do got status %d headers %v", response.Status, response.Headers)
+			}
+
+			if !reflect.DeepEqual(response.JSON, test.expected) {
+				t.Fatalf("http.request.do got %+v (%T), expected %+v (%T)", response.JSON, response.JSON, test.expected, test.expected)
 			}
 		})
 	}
@@ -127,6 +231,77 @@ func TestBadHTTPRequestDo(t *testing.T) {
 			payload:     nil,
 			errorString: "template: url:1: unclosed action",
 		},
+		{
+			name: "json and bodyTemplate",
+			params: map[string]any{
+				"method":       "POST",
+				"url":          "http://example.com",
+				"json":         true,
+				"bodyTemplate": "{{.Payload}}",
+			},
+			payload:     nil,
+			errorString: "http.request.do json and bodyTemplate can't both be set",
+		},
+		{
+			name: "headers not strings",
+			params: map[string]any{
+				"method":  "GET",
+				"url":     "http://example.com",
+				"headers": map[string]any{"X-Cue": 5},
+			},
+			payload:     nil,
+			errorString: "http.request.do headers error: not a string map",
+		},
+		{
+			name: "unknown auth type",
+			params: map[string]any{
+				"method": "GET",
+				"url":    "http://example.com",
+				"auth":   map[string]any{"type": "digest"},
+			},
+			payload:     nil,
+			errorString: "http.request.do auth unknown type: digest",
+		},
+		{
+			name: "bearer auth without token",
+			params: map[string]any{
+				"method": "GET",
+				"url":    "http://example.com",
+				"auth":   map[string]any{"type": "bearer"},
+			},
+			payload:     nil,
+			errorString: "http.request.do auth token error: not found",
+		},
+		{
+			name: "negative retries",
+			params: map[string]any{
+				"method":  "GET",
+				"url":     "http://example.com",
+				"retries": -1,
+			},
+			payload:     nil,
+			errorString: "http.request.do retries must not be negative",
+		},
+		{
+			name: "timeout not a number",
+			params: map[string]any{
+				"method":  "GET",
+				"url":     "http://example.com",
+				"timeout": "1s",
+			},
+			payload:     nil,
+			errorString: "http.request.do timeout error: not a number",
+		},
+		{
+			name: "failOnStatus not a boolean",
+			params: map[string]any{
+				"method":       "GET",
+				"url":          "http://example.com",
+				"failOnStatus": "yes",
+			},
+			payload:     nil,
+			errorString: "http.request.do failOnStatus error: not a boolean",
+		},
 	}
 
 	for _, test := range tests {
@@ -161,3 +336,81 @@ func TestBadHTTPRequestDo(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPRequestDoFailures(t *testing.T) {
+	server := newHTTPRequestDoServer(t)
+
+	tests := []struct {
+		name        string
+		params      map[string]any
+		errorString string
+	}{
+		{
+			name:        "non 2xx status",
+			params:      map[string]any{"method": "GET", "url": server.URL + "/missing", "failOnStatus": true},
+			errorString: "http.request.do got status 404 Not Found",
+		},
+		{
+			name:        "retries run out",
+			params:      map[string]any{"method": "GET", "url": server.URL + "/flaky", "backoff": 1, "failOnStatus": true},
+			errorString: "http.request.do got status 503 Service Unavailable",
+		},
+		{
+			name:        "invalid json response",
+			params:      map[string]any{"method": "GET", "url": server.URL + "/text", "parseJSON": true},
+			errorString: "http.request.do parseJSON error: invalid character 'o' in literal null (expecting 'u')",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("http.request.do")
+			if !ok {
+				t.Fatalf("http.request.do processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "http.request.do",
+				Params: test.params,
+			})
+			if err != nil {
+				t.Fatalf("http.request.do failed to create processor: %s", err)
+			}
+
+			_, err = processorInstance.Process(t.Context(), common.WrappedPayload{})
+			if err == nil || err.Error() != test.errorString {
+				t.Fatalf("http.request.do got error '%v', expected '%s'", err, test.errorString)
+			}
+		})
+	}
+
+	registration, _ := processor.GetProcessorRegistration("http.request.do")
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type:   "http.request.do",
+		Params: map[string]any{"method": "GET", "url": server.URL + "/slow", "timeout": 50},
+	})
+	if err != nil {
+		t.Fatalf("http.request.do failed to create processor: %s", err)
+	}
+
+	_, err = processorInstance.Process(t.Context(), common.WrappedPayload{})
+	if err == nil {
+		t.Fatalf("http.request.do should time out")
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	processorInstance, err = registration.New(config.ProcessorConfig{
+		Type:   "http.request.do",
+		Params: map[string]any{"method": "GET", "url": server.URL + "/cue"},
+	})
+	if err != nil {
+		t.Fatalf("http.request.do failed to create processor: %s", err)
+	}
+
+	_, err = processorInstance.Process(ctx, common.WrappedPayload{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("http.request.do should honor a canceled context, got %v", err)
+	}
+}
@@ -49,6 +49,23 @@ func TestGoodHTTPResponseCreate(t *testing.T) {
 			params:  map[string]any{"status": 200, "bodyTemplate": "Hello, World!"},
 			payload: nil,
 		},
+		{
+			name: "headers",
+			expected: processor.HTTPResponse{
+				Status:  201,
+				Body:    []byte(`{"cue":"5"}`),
+				Headers: map[string]string{"Content-Type": "application/json", "X-Cue": "5"},
+			},
+			params: map[string]any{
+				"status":       201,
+				"bodyTemplate": `{"cue":"{{.Payload}}"}`,
+				"headers": map[string]any{
+					"Content-Type": "application/json",
+					"X-Cue":        "{{.Payload}}",
+				},
+			},
+			payload: "5",
+		},
 	}
 
 	for _, test := range tests {
@@ -111,6 +128,12 @@ func TestBadHTTPResponseCreate(t *testing.T) {
 			payload:     nil,
 			errorString: "http.response.create bodyTemplate error: not a string",
 		},
+		{
+			name:        "non-string headers",
+			params:      map[string]any{"status": 200, "bodyTemplate": "Hello, World!", "headers": map[string]any{"X-Cue": 5}},
+			payload:     nil,
+			errorString: "http.response.create headers error: not a string map",
+		},
 		{
 			name:        "bodyTemplate template error",
 			params:      map[string]any{"status": 200, "bodyTemplate": "{{.MissingField}}"},
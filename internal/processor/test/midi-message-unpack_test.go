@@ -81,6 +81,31 @@ func TestGoodMIDIMessageUnpack(t *testing.T) {
 				Absolute: 8192,
 			},
 		},
+		{
+			name:    "aftertouch",
+			payload: midi.AfterTouch(2, 50),
+			expected: processor.MIDIAfterTouch{
+				Channel:  2,
+				Pressure: 50,
+			},
+		},
+		{
+			name:    "poly aftertouch",
+			payload: midi.PolyAfterTouch(3, 60, 70),
+			expected: processor.MIDIPolyAfterTouch{
+				Channel:  3,
+				Note:     60,
+				Pressure: 70,
+			},
+		},
+		{
+			name:    "sysex",
+			payload: midi.SysEx([]byte{0x7d, 0x01}),
+			expected: processor.MIDISysEx{
+				Manufacturer: []byte{0x7d},
+				Data:         []byte{0x01},
+			},
+		},
 	}
 
 	for _, test := range tests {
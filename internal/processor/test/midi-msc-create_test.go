@@ -0,0 +1,230 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func TestMIDIMSCCreateFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("midi.msc.create")
+	if !ok {
+		t.Fatalf("midi.msc.create processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "midi.msc.create",
+		Params: map[string]any{
+			"command": "go",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create midi.msc.create processor: %s", err)
+	}
+
+	if processorInstance.Type() != "midi.msc.create" {
+		t.Fatalf("midi.msc.create processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodMIDIMSCCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected any
+	}{
+		{
+			name:     "go all call",
+			params:   map[string]any{"command": "go"},
+			payload:  "test",
+			expected: midi.Message{0xf0, 0x7f, 0x7f, 0x02, 0x01, 0x01, 0xf7},
+		},
+		{
+			name:     "go with templated cue, list and path",
+			params:   map[string]any{"command": "go", "deviceId": "1", "commandFormat": "sound", "cue": "{{.Payload}}", "list": "2", "path": "3"},
+			payload:  "10.5",
+			expected: midi.Message{0xf0, 0x7f, 0x01, 0x02, 0x10, 0x01, '1', '0', '.', '5', 0x00, '2', 0x00, '3', 0xf7},
+		},
+		{
+			name:     "stop",
+			params:   map[string]any{"command": "stop", "cue": "4"},
+			payload:  "test",
+			expected: midi.Message{0xf0, 0x7f, 0x7f, 0x02, 0x01, 0x02, '4', 0xf7},
+		},
+		{
+			name:     "resume",
+			params:   map[string]any{"command": "resume", "commandFormat": "0x7f"},
+			payload:  "test",
+			expected: midi.Message{0xf0, 0x7f, 0x7f, 0x02, 0x7f, 0x03, 0xf7},
+		},
+		{
+			name:     "timed go",
+			params:   map[string]any{"command": "timed_go", "time": "01:02:03:04.05", "cue": "1"},
+			payload:  "test",
+			expected: midi.Message{0xf0, 0x7f, 0x7f, 0x02, 0x01, 0x04, 0x61, 0x02, 0x03, 0x04, 0x05, '1', 0xf7},
+		},
+		{
+			name:     "timed go at 25 fps",
+			params:   map[string]any{"command": "timed_go", "time": "01:02:03:24", "rate": "25"},
+			payload:  "test",
+			expected: midi.Message{0xf0, 0x7f, 0x7f, 0x02, 0x01, 0x04, 0x21, 0x02, 0x03, 0x18, 0x00, 0xf7},
+		},
+		{
+			name:     "set",
+			params:   map[string]any{"command": "set", "control": "1", "value": "{{.Payload}}"},
+			payload:  200,
+			expected: midi.Message{0xf0, 0x7f, 0x7f, 0x02, 0x01, 0x06, 0x01, 0x00, 0x48, 0x01, 0xf7},
+		},
+		{
+			name:     "fire",
+			params:   map[string]any{"command": "fire", "macro": "5"},
+			payload:  "test",
+			expected: midi.Message{0xf0, 0x7f, 0x7f, 0x02, 0x01, 0x07, 0x05, 0xf7},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("midi.msc.create")
+			if !ok {
+				t.Fatalf("midi.msc.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "midi.msc.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("midi.msc.create failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("midi.msc.create processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("midi.msc.create got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadMIDIMSCCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "no command",
+			params:      map[string]any{},
+			errorString: "midi.msc.create command error: not found",
+		},
+		{
+			name:        "unknown command",
+			params:      map[string]any{"command": "jump"},
+			errorString: "midi.msc.create command error: unknown MSC command: jump",
+		},
+		{
+			name:        "unknown command format",
+			params:      map[string]any{"command": "go", "commandFormat": "toasters"},
+			errorString: "midi.msc.create commandFormat error: unknown MSC command format: toasters",
+		},
+		{
+			name:        "timed go without time",
+			params:      map[string]any{"command": "timed_go"},
+			errorString: "midi.msc.create timed_go requires time",
+		},
+		{
+			name:        "set without value",
+			params:      map[string]any{"command": "set", "control": "1"},
+			errorString: "midi.msc.create set requires control and value",
+		},
+		{
+			name:        "fire without macro",
+			params:      map[string]any{"command": "fire"},
+			errorString: "midi.msc.create fire requires macro",
+		},
+		{
+			name:        "device id out of range",
+			params:      map[string]any{"command": "go", "deviceId": "{{.Payload}}"},
+			payload:     128,
+			errorString: "midi.msc.create deviceId must be between 0 and 127: 128",
+		},
+		{
+			name:        "bad time",
+			params:      map[string]any{"command": "timed_go", "time": "1:2:3"},
+			payload:     "test",
+			errorString: "midi.msc.create time error: timecode must be hh:mm:ss:ff: 1:2:3",
+		},
+		{
+			name:        "unknown rate",
+			params:      map[string]any{"command": "timed_go", "time": "01:02:03:04", "rate": "60"},
+			errorString: "midi.msc.create rate error: unsupported timecode rate: 60",
+		},
+		{
+			name:        "frames past the rate",
+			params:      map[string]any{"command": "timed_go", "time": "01:02:03:24", "rate": "24"},
+			payload:     "test",
+			errorString: "midi.msc.create time error: timecode is out of range: 01:02:03:24",
+		},
+		{
+			name:        "dropped frame label",
+			params:      map[string]any{"command": "timed_go", "time": "00:01:00;01", "rate": "29.97df"},
+			payload:     "test",
+			errorString: "midi.msc.create time error: timecode 00:01:00;01 does not exist in drop frame",
+		},
+		{
+			name:        "bad cue",
+			params:      map[string]any{"command": "go", "cue": "{{.Payload}}"},
+			payload:     "one",
+			errorString: "midi.msc.create MSC cue numbers may only contain digits and '.': one",
+		},
+		{
+			name:        "macro out of range",
+			params:      map[string]any{"command": "fire", "macro": "128"},
+			payload:     "test",
+			errorString: "midi.msc.create macro must be between 0 and 127: 128",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("midi.msc.create")
+			if !ok {
+				t.Fatalf("midi.msc.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "midi.msc.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("midi.msc.create got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("midi.msc.create expected to fail but succeeded, got: %v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("midi.msc.create got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/msc"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func TestMIDIMSCDecodeFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("midi.msc.decode")
+	if !ok {
+		t.Fatalf("midi.msc.decode processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "midi.msc.decode",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create midi.msc.decode processor: %s", err)
+	}
+
+	if processorInstance.Type() != "midi.msc.decode" {
+		t.Fatalf("midi.msc.decode processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodMIDIMSCDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected any
+	}{
+		{
+			name:     "go with cue and list",
+			payload:  midi.Message{0xf0, 0x7f, 0x01, 0x02, 0x01, 0x01, '1', '.', '5', 0x00, '2', 0xf7},
+			expected: &msc.Message{DeviceID: 1, CommandFormat: msc.CommandFormatLighting, Command: msc.CommandGo, Cue: "1.5", List: "2"},
+		},
+		{
+			name:     "fire from bytes",
+			payload:  []byte{0xf0, 0x7f, 0x7f, 0x02, 0x10, 0x07, 0x09, 0xf7},
+			expected: &msc.Message{DeviceID: msc.AllCall, CommandFormat: msc.CommandFormatSound, Command: msc.CommandFire, Macro: 9},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("midi.msc.decode")
+			if !ok {
+				t.Fatalf("midi.msc.decode processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "midi.msc.decode",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("midi.msc.decode failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("midi.msc.decode processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("midi.msc.decode got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadMIDIMSCDecode(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "not bytes",
+			payload:     "test",
+			errorString: "midi.msc.decode processor only accepts a midi.Message",
+		},
+		{
+			name:        "not MSC",
+			payload:     midi.SysEx([]byte{0x7d, 0x01}),
+			errorString: "midi.msc.decode not a MIDI Show Control message",
+		},
+		{
+			name:        "set missing value",
+			payload:     midi.Message{0xf0, 0x7f, 0x01, 0x02, 0x01, 0x06, 0x01, 0xf7},
+			errorString: "midi.msc.decode MSC set is missing its control and value",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("midi.msc.decode")
+			if !ok {
+				t.Fatalf("midi.msc.decode processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "midi.msc.decode",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("midi.msc.decode got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("midi.msc.decode expected to fail but succeeded, got: %v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("midi.msc.decode got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/timecode"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func TestMIDIMTCDecodeFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("midi.mtc.decode")
+	if !ok {
+		t.Fatalf("midi.mtc.decode processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "midi.mtc.decode",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create midi.mtc.decode processor: %s", err)
+	}
+
+	if processorInstance.Type() != "midi.mtc.decode" {
+		t.Fatalf("midi.mtc.decode processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodMIDIMTCDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected any
+	}{
+		{
+			name:     "full frame",
+			payload:  midi.Message{0xf0, 0x7f, 0x7f, 0x01, 0x01, 0x61, 0x02, 0x03, 0x04, 0xf7},
+			expected: timecode.Timecode{Hours: 1, Minutes: 2, Seconds: 3, Frames: 4, Rate: timecode.Rate30},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("midi.mtc.decode")
+			if !ok {
+				t.Fatalf("midi.mtc.decode processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "midi.mtc.decode",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("midi.mtc.decode failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("midi.mtc.decode processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("midi.mtc.decode got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadMIDIMTCDecode(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "not bytes",
+			payload:     1,
+			errorString: "midi.mtc.decode processor only accepts a midi.Message",
+		},
+		{
+			name:        "not timecode",
+			payload:     midi.NoteOn(1, 2, 3),
+			errorString: "midi.mtc.decode message is not MIDI timecode",
+		},
+		{
+			name:        "invalid full frame",
+			payload:     []byte{0xf0, 0x7f, 0x7f, 0x01, 0x01, 0x20, 0x02, 0x03, 0x1f, 0xf7},
+			errorString: "midi.mtc.decode timecode is out of range: 00:02:03:31",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("midi.mtc.decode")
+			if !ok {
+				t.Fatalf("midi.mtc.decode processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "midi.mtc.decode",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("midi.mtc.decode got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("midi.mtc.decode expected to fail but succeeded, got: %v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("midi.mtc.decode got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/timecode"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func TestMIDIMTCEncodeFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("midi.mtc.encode")
+	if !ok {
+		t.Fatalf("midi.mtc.encode processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "midi.mtc.encode",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create midi.mtc.encode processor: %s", err)
+	}
+
+	if processorInstance.Type() != "midi.mtc.encode" {
+		t.Fatalf("midi.mtc.encode processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodMIDIMTCEncode(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected any
+	}{
+		{
+			name:     "full frame",
+			payload:  timecode.Timecode{Hours: 1, Minutes: 2, Seconds: 3, Frames: 4, Rate: timecode.Rate25},
+			expected: midi.Message{0xf0, 0x7f, 0x7f, 0x01, 0x01, 0x21, 0x02, 0x03, 0x04, 0xf7},
+		},
+		{
+			name:    "quarter frames even frame",
+			params:  map[string]any{"format": "quarter"},
+			payload: &timecode.Timecode{Frames: 18, Rate: timecode.Rate30},
+			expected: common.FanOut{
+				midi.Message{0xf1, 0x02},
+				midi.Message{0xf1, 0x11},
+				midi.Message{0xf1, 0x20},
+				midi.Message{0xf1, 0x30},
+			},
+		},
+		{
+			name:    "quarter frames odd frame",
+			params:  map[string]any{"format": "quarter"},
+			payload: timecode.Timecode{Hours: 1, Frames: 19, Rate: timecode.Rate30},
+			expected: common.FanOut{
+				midi.Message{0xf1, 0x40},
+				midi.Message{0xf1, 0x50},
+				midi.Message{0xf1, 0x61},
+				midi.Message{0xf1, 0x76},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("midi.mtc.encode")
+			if !ok {
+				t.Fatalf("midi.mtc.encode processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "midi.mtc.encode",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("midi.mtc.encode failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("midi.mtc.encode processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("midi.mtc.encode got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadMIDIMTCEncode(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "bad format",
+			params:      map[string]any{"format": "half"},
+			errorString: "midi.mtc.encode format must be full or quarter",
+		},
+		{
+			name:        "not a timecode",
+			payload:     "01:00:00:00",
+			errorString: "midi.mtc.encode processor only accepts a timecode.Timecode",
+		},
+		{
+			name:        "invalid timecode",
+			payload:     timecode.Timecode{Frames: 30, Rate: timecode.Rate30},
+			errorString: "midi.mtc.encode timecode is out of range: 00:00:00:30",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("midi.mtc.encode")
+			if !ok {
+				t.Fatalf("midi.mtc.encode processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "midi.mtc.encode",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("midi.mtc.encode got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("midi.mtc.encode expected to fail but succeeded, got: %v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("midi.mtc.encode got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
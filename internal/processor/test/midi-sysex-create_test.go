@@ -0,0 +1,146 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func TestMIDISysExCreateFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("midi.sysex.create")
+	if !ok {
+		t.Fatalf("midi.sysex.create processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "midi.sysex.create",
+		Params: map[string]any{
+			"data": "7D 01",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create midi.sysex.create processor: %s", err)
+	}
+
+	if processorInstance.Type() != "midi.sysex.create" {
+		t.Fatalf("midi.sysex.create processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodMIDISysExCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected any
+	}{
+		{
+			name:     "sysex with spaces",
+			params:   map[string]any{"data": "7D 01 02"},
+			payload:  "test",
+			expected: midi.Message{0xf0, 0x7d, 0x01, 0x02, 0xf7},
+		},
+		{
+			name:     "sysex with framing and template",
+			params:   map[string]any{"data": "F07D{{.Payload}}F7"},
+			payload:  "0a",
+			expected: midi.Message{0xf0, 0x7d, 0x0a, 0xf7},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("midi.sysex.create")
+			if !ok {
+				t.Fatalf("midi.sysex.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "midi.sysex.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("midi.sysex.create failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("midi.sysex.create processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("midi.sysex.create got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadMIDISysExCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "no data",
+			params:      map[string]any{},
+			errorString: "midi.sysex.create data error: not found",
+		},
+		{
+			name:        "invalid hex",
+			params:      map[string]any{"data": "7G"},
+			payload:     "test",
+			errorString: "midi.sysex.create data error: encoding/hex: invalid byte: U+0047 'G'",
+		},
+		{
+			name:        "data byte out of range",
+			params:      map[string]any{"data": "7D 80"},
+			payload:     "test",
+			errorString: "midi.sysex.create data bytes must be between 00 and 7F",
+		},
+		{
+			name:        "only framing",
+			params:      map[string]any{"data": "F0 F7"},
+			payload:     "test",
+			errorString: "midi.sysex.create data must include a manufacturer id",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("midi.sysex.create")
+			if !ok {
+				t.Fatalf("midi.sysex.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "midi.sysex.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("midi.sysex.create got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("midi.sysex.create expected to fail but succeeded, got: %v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("midi.sysex.create got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func TestMIDISysExUnpackFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("midi.sysex.unpack")
+	if !ok {
+		t.Fatalf("midi.sysex.unpack processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "midi.sysex.unpack",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create midi.sysex.unpack processor: %s", err)
+	}
+
+	if processorInstance.Type() != "midi.sysex.unpack" {
+		t.Fatalf("midi.sysex.unpack processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodMIDISysExUnpack(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected any
+	}{
+		{
+			name:     "single byte manufacturer",
+			payload:  midi.SysEx([]byte{0x7d, 0x01, 0x02}),
+			expected: processor.MIDISysEx{Manufacturer: []byte{0x7d}, Data: []byte{0x01, 0x02}},
+		},
+		{
+			name:     "three byte manufacturer from bytes",
+			payload:  []byte{0xf0, 0x00, 0x20, 0x32, 0x05, 0xf7},
+			expected: processor.MIDISysEx{Manufacturer: []byte{0x00, 0x20, 0x32}, Data: []byte{0x05}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("midi.sysex.unpack")
+			if !ok {
+				t.Fatalf("midi.sysex.unpack processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "midi.sysex.unpack",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("midi.sysex.unpack failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("midi.sysex.unpack processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("midi.sysex.unpack got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadMIDISysExUnpack(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "not a MIDI message",
+			payload:     "test",
+			errorString: "midi.sysex.unpack processor only accepts a midi.Message",
+		},
+		{
+			name:        "not a sysex message",
+			payload:     midi.NoteOn(1, 60, 100),
+			errorString: "midi.sysex.unpack message is not a SysEx message",
+		},
+		{
+			name:        "truncated manufacturer",
+			payload:     midi.Message{0xf0, 0x00, 0x20, 0xf7},
+			errorString: "midi.sysex.unpack message has a truncated manufacturer id",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("midi.sysex.unpack")
+			if !ok {
+				t.Fatalf("midi.sysex.unpack processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "midi.sysex.unpack",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("midi.sysex.unpack got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("midi.sysex.unpack expected to fail but succeeded, got: %v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("midi.sysex.unpack got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/modbus"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+)
+
+func TestModbusWriteCoilsCreateFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("modbus.write_coils.create")
+	if !ok {
+		t.Fatalf("modbus.write_coils.create processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "modbus.write_coils.create",
+		Params: map[string]any{
+			"address": "0",
+			"values":  []any{"true"},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create modbus.write_coils.create processor: %s", err)
+	}
+
+	if processorInstance.Type() != "modbus.write_coils.create" {
+		t.Fatalf("modbus.write_coils.create processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodModbusWriteCoilsCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected any
+	}{
+		{
+			name:     "single coil",
+			params:   map[string]any{"address": "{{.Payload}}", "values": []any{"1"}},
+			payload:  7,
+			expected: modbus.PDU{Function: modbus.FunctionWriteSingleCoil, Data: []byte{0x00, 0x07, 0xff, 0x00}},
+		},
+		{
+			name:     "single coil as multiple",
+			params:   map[string]any{"address": "7", "values": []any{"false"}, "multiple": true},
+			expected: modbus.PDU{Function: modbus.FunctionWriteMultipleCoils, Data: []byte{0x00, 0x07, 0x00, 0x01, 0x01, 0x00}},
+		},
+		{
+			name:     "multiple coils",
+			params:   map[string]any{"address": "19", "values": []any{"1", "0", "1", "1", "0", "0", "1", "1", "1", "0"}},
+			expected: modbus.PDU{Function: modbus.FunctionWriteMultipleCoils, Data: []byte{0x00, 0x13, 0x00, 0x0a, 0x02, 0xcd, 0x01}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("modbus.write_coils.create")
+			if !ok {
+				t.Fatalf("modbus.write_coils.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "modbus.write_coils.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("modbus.write_coils.create failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("modbus.write_coils.create processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("modbus.write_coils.create got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadModbusWriteCoilsCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "no address",
+			params:      map[string]any{"values": []any{"1"}},
+			errorString: "modbus.write_coils.create address error: not found",
+		},
+		{
+			name:        "no values",
+			params:      map[string]any{"address": "1", "values": []any{}},
+			errorString: "modbus.write_coils.create values must have between 1 and 1968 entries",
+		},
+		{
+			name:        "non-bool multiple",
+			params:      map[string]any{"address": "1", "values": []any{"1"}, "multiple": "yes"},
+			errorString: "modbus.write_coils.create multiple error: not a boolean",
+		},
+		{
+			name:        "address out of range",
+			params:      map[string]any{"address": "65536", "values": []any{"1"}},
+			errorString: "modbus.write_coils.create address error: strconv.ParseUint: parsing \"65536\": value out of range",
+		},
+		{
+			name:        "bad value",
+			params:      map[string]any{"address": "1", "values": []any{"on"}},
+			errorString: "modbus.write_coils.create value error: strconv.ParseBool: parsing \"on\": invalid syntax",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("modbus.write_coils.create")
+			if !ok {
+				t.Fatalf("modbus.write_coils.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "modbus.write_coils.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("modbus.write_coils.create got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("modbus.write_coils.create expected to fail but succeeded, got: %v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("modbus.write_coils.create got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/modbus"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+)
+
+func TestModbusWriteRegistersCreateFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("modbus.write_registers.create")
+	if !ok {
+		t.Fatalf("modbus.write_registers.create processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "modbus.write_registers.create",
+		Params: map[string]any{
+			"address": "0",
+			"values":  []any{"1"},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create modbus.write_registers.create processor: %s", err)
+	}
+
+	if processorInstance.Type() != "modbus.write_registers.create" {
+		t.Fatalf("modbus.write_registers.create processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodModbusWriteRegistersCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected any
+	}{
+		{
+			name:     "single register",
+			params:   map[string]any{"address": "1", "values": []any{"{{.Payload}}"}},
+			payload:  3,
+			expected: modbus.PDU{Function: modbus.FunctionWriteSingleRegister, Data: []byte{0x00, 0x01, 0x00, 0x03}},
+		},
+		{
+			name:     "negative register",
+			params:   map[string]any{"address": "1", "values": []any{"-2"}},
+			expected: modbus.PDU{Function: modbus.FunctionWriteSingleRegister, Data: []byte{0x00, 0x01, 0xff, 0xfe}},
+		},
+		{
+			name:     "multiple registers",
+			params:   map[string]any{"address": "1", "values": []any{"10", "258"}},
+			expected: modbus.PDU{Function: modbus.FunctionWriteMultipleRegisters, Data: []byte{0x00, 0x01, 0x00, 0x02, 0x04, 0x00, 0x0a, 0x01, 0x02}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("modbus.write_registers.create")
+			if !ok {
+				t.Fatalf("modbus.write_registers.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "modbus.write_registers.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("modbus.write_registers.create failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("modbus.write_registers.create processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("modbus.write_registers.create got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadModbusWriteRegistersCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "no values",
+			params:      map[string]any{"address": "1"},
+			errorString: "modbus.write_registers.create values error: not found",
+		},
+		{
+			name:        "value out of range",
+			params:      map[string]any{"address": "1", "values": []any{"65536"}},
+			errorString: "modbus.write_registers.create value must be between -32768 and 65535",
+		},
+		{
+			name:        "multiple past the end of the address space",
+			params:      map[string]any{"address": "65535", "values": []any{"1", "2"}},
+			errorString: "modbus.write_registers.create modbus write runs past the end of the address space",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("modbus.write_registers.create")
+			if !ok {
+				t.Fatalf("modbus.write_registers.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "modbus.write_registers.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("modbus.write_registers.create got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("modbus.write_registers.create expected to fail but succeeded, got: %v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("modbus.write_registers.create got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
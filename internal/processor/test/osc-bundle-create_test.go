@@ -0,0 +1,195 @@
+package processor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jwetzell/osc-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/oscbundle"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+)
+
+func TestOSCBundleCreateFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("osc.bundle.create")
+	if !ok {
+		t.Fatalf("osc.bundle.create processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "osc.bundle.create",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create osc.bundle.create processor: %s", err)
+	}
+
+	if processorInstance.Type() != "osc.bundle.create" {
+		t.Fatalf("osc.bundle.create processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodOSCBundleCreate(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("osc.bundle.create")
+	if !ok {
+		t.Fatalf("osc.bundle.create processor not registered")
+	}
+
+	tests := []struct {
+		name      string
+		params    map[string]any
+		payload   any
+		addresses []string
+		delayed   bool
+	}{
+		{
+			name:      "payload message",
+			payload:   &osc.OSCMessage{Address: "/test"},
+			addresses: []string{"/test"},
+		},
+		{
+			name:      "payload message slice",
+			payload:   []any{&osc.OSCMessage{Address: "/one"}, &osc.OSCMessage{Address: "/two"}},
+			addresses: []string{"/one", "/two"},
+		},
+		{
+			name: "messages param",
+			params: map[string]any{
+				"messages": []any{
+					map[string]any{"address": "/eos/chan/{{.Payload}}"},
+					map[string]any{"address": "/eos/at", "args": []any{"50"}, "types": "i"},
+				},
+			},
+			payload:   "1",
+			addresses: []string{"/eos/chan/1", "/eos/at"},
+		},
+		{
+			name: "delay",
+			params: map[string]any{
+				"delay": "{{.Payload}}",
+				"messages": []any{
+					map[string]any{"address": "/go"},
+				},
+			},
+			payload:   "1000",
+			addresses: []string{"/go"},
+			delayed:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "osc.bundle.create",
+				Params: test.params,
+			})
+			if err != nil {
+				t.Fatalf("osc.bundle.create failed to create processor: %s", err)
+			}
+
+			before := time.Now()
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("osc.bundle.create processing failed: %s", err)
+			}
+
+			gotBundle, ok := got.Payload.(*oscbundle.Bundle)
+			if !ok {
+				t.Fatalf("osc.bundle.create returned a %T payload: %+v", got.Payload, got.Payload)
+			}
+
+			messages := gotBundle.Messages()
+			if len(messages) != len(test.addresses) {
+				t.Fatalf("osc.bundle.create got %d messages, expected %d", len(messages), len(test.addresses))
+			}
+
+			for index, message := range messages {
+				if message.Address != test.addresses[index] {
+					t.Fatalf("osc.bundle.create got address %s, expected %s", message.Address, test.addresses[index])
+				}
+			}
+
+			if test.delayed {
+				if gotBundle.TimeTag.Time().Before(before.Add(time.Second - time.Millisecond)) {
+					t.Fatalf("osc.bundle.create timetag %s is not a second out", gotBundle.TimeTag.Time())
+				}
+			} else if !gotBundle.TimeTag.IsImmediate() {
+				t.Fatalf("osc.bundle.create timetag should be immediate")
+			}
+		})
+	}
+}
+
+func TestBadOSCBundleCreate(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("osc.bundle.create")
+	if !ok {
+		t.Fatalf("osc.bundle.create processor not registered")
+	}
+
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "messages not a slice",
+			params:      map[string]any{"messages": "/test"},
+			errorString: "osc.bundle.create messages error: not a slice",
+		},
+		{
+			name:        "message not a map",
+			params:      map[string]any{"messages": []any{"/test"}},
+			errorString: "osc.bundle.create messages[0] error: not a map",
+		},
+		{
+			name:        "message missing address",
+			params:      map[string]any{"messages": []any{map[string]any{}}},
+			errorString: "osc.bundle.create messages[0] error: osc.message.create address error: not found",
+		},
+		{
+			name:        "delay not a string",
+			params:      map[string]any{"delay": 100},
+			errorString: "osc.bundle.create delay error: not a string",
+		},
+		{
+			name:        "non OSC payload",
+			payload:     "hello",
+			errorString: "osc.bundle.create processor needs a messages param or an OSC payload",
+		},
+		{
+			name: "delay not a number",
+			params: map[string]any{
+				"delay":    "soon",
+				"messages": []any{map[string]any{"address": "/go"}},
+			},
+			errorString: "strconv.ParseFloat: parsing \"soon\": invalid syntax",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "osc.bundle.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("osc.bundle.create got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err == nil {
+				t.Fatalf("osc.bundle.create expected to fail but got payload: %+v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("osc.bundle.create got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package processor_test
+
+import (
+	"testing"
+
+	"github.com/jwetzell/osc-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/oscbundle"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+)
+
+func TestOSCBundleDecodeFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("osc.bundle.decode")
+	if !ok {
+		t.Fatalf("osc.bundle.decode processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "osc.bundle.decode",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create osc.bundle.decode processor: %s", err)
+	}
+
+	if processorInstance.Type() != "osc.bundle.decode" {
+		t.Fatalf("osc.bundle.decode processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodOSCBundleDecode(t *testing.T) {
+	processorInstance := processor.OSCBundleDecode{}
+
+	payload := []byte{
+		'#', 'b', 'u', 'n', 'd', 'l', 'e', 0, 0, 0, 0, 0, 0, 0, 0, 1,
+		0, 0, 0, 12, 47, 116, 101, 115, 116, 0, 0, 0, 44, 0, 0, 0,
+	}
+
+	got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: payload})
+	if err != nil {
+		t.Fatalf("osc.bundle.decode processing failed: %s", err)
+	}
+
+	gotBundle, ok := got.Payload.(*oscbundle.Bundle)
+	if !ok {
+		t.Fatalf("osc.bundle.decode returned a %T payload: %+v", got.Payload, got.Payload)
+	}
+
+	if !gotBundle.TimeTag.IsImmediate() {
+		t.Fatalf("osc.bundle.decode timetag should be immediate, got %d", gotBundle.TimeTag)
+	}
+
+	if len(gotBundle.Contents) != 1 {
+		t.Fatalf("osc.bundle.decode got %d elements, expected 1", len(gotBundle.Contents))
+	}
+
+	message, ok := gotBundle.Contents[0].(*osc.OSCMessage)
+	if !ok || message.Address != "/test" {
+		t.Fatalf("osc.bundle.decode got wrong element: %+v", gotBundle.Contents[0])
+	}
+}
+
+func TestBadOSCBundleDecode(t *testing.T) {
+	processorInstance := processor.OSCBundleDecode{}
+	tests := []struct {
+		name        string
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "non-byte payload",
+			payload:     "hello",
+			errorString: "osc.bundle.decode processor only accepts a []byte payload",
+		},
+		{
+			name:        "OSC message bytes",
+			payload:     []byte{47, 116, 101, 115, 116, 0, 0, 0, 44, 0, 0, 0},
+			errorString: "osc.bundle.decode processor needs an OSC bundle looking []byte",
+		},
+		{
+			name:        "truncated element",
+			payload:     []byte{'#', 'b', 'u', 'n', 'd', 'l', 'e', 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 12, 47},
+			errorString: "osc.bundle.decode processor failed to decode OSC bundle: OSC bundle element size 12 does not fit in the bundle",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err == nil {
+				t.Fatalf("osc.bundle.decode expected to fail but got payload: %+v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("osc.bundle.decode got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package processor_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/osc-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/oscbundle"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+)
+
+func TestOSCBundleEncodeFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("osc.bundle.encode")
+	if !ok {
+		t.Fatalf("osc.bundle.encode processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "osc.bundle.encode",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create osc.bundle.encode processor: %s", err)
+	}
+
+	if processorInstance.Type() != "osc.bundle.encode" {
+		t.Fatalf("osc.bundle.encode processor has wrong type: %s", processorInstance.Type())
+	}
+
+	if processorInstance.(*processor.OSCBundleEncode).MaxDelay != 50*time.Millisecond {
+		t.Fatalf("osc.bundle.encode maxDelay should default to 50ms, got %s", processorInstance.(*processor.OSCBundleEncode).MaxDelay)
+	}
+
+	_, err = registration.New(config.ProcessorConfig{
+		Type: "osc.bundle.encode",
+		Params: map[string]any{
+			"maxDelay": 0,
+		},
+	})
+	if err == nil || err.Error() != "osc.bundle.encode maxDelay must be at least 1" {
+		t.Fatalf("osc.bundle.encode should reject a maxDelay of 0, got: %v", err)
+	}
+
+	_, err = registration.New(config.ProcessorConfig{
+		Type: "osc.bundle.encode",
+		Params: map[string]any{
+			"schedule": true,
+		},
+	})
+	if err == nil || err.Error() != "osc.bundle.encode schedule requires module" {
+		t.Fatalf("osc.bundle.encode should require a module to schedule, got: %v", err)
+	}
+}
+
+func TestGoodOSCBundleEncode(t *testing.T) {
+	processorInstance := processor.OSCBundleEncode{}
+
+	payload := &oscbundle.Bundle{
+		TimeTag:  oscbundle.Immediately,
+		Contents: []osc.OSCPacket{&osc.OSCMessage{Address: "/test"}},
+	}
+
+	expected := []byte{
+		'#', 'b', 'u', 'n', 'd', 'l', 'e', 0, 0, 0, 0, 0, 0, 0, 0, 1,
+		0, 0, 0, 12, 47, 116, 101, 115, 116, 0, 0, 0, 44, 0, 0, 0,
+	}
+
+	got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: payload})
+	if err != nil {
+		t.Fatalf("osc.bundle.encode processing failed: %s", err)
+	}
+
+	gotBytes, ok := got.Payload.([]byte)
+	if !ok {
+		t.Fatalf("osc.bundle.encode returned a %T payload: %+v", got.Payload, got.Payload)
+	}
+
+	if !slices.Equal(gotBytes, expected) {
+		t.Fatalf("osc.bundle.encode got %+v, expected %+v", gotBytes, expected)
+	}
+}
+
+type scheduleOutputModule struct {
+	outputs chan []byte
+}
+
+func (m *scheduleOutputModule) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	return nil
+}
+
+func (m *scheduleOutputModule) Output(ctx context.Context, payload any) error {
+	payloadBytes, ok := common.GetAnyAsByteSlice(payload)
+	if !ok {
+		return errors.New("test.output can only output bytes")
+	}
+	m.outputs <- payloadBytes
+	return nil
+}
+
+func (m *scheduleOutputModule) Stop() {}
+
+func (m *scheduleOutputModule) Type() string {
+	return "test.output"
+}
+
+func (m *scheduleOutputModule) Id() string {
+	return "output"
+}
+
+func TestOSCBundleEncodeSchedule(t *testing.T) {
+	processorInstance := processor.OSCBundleEncode{Schedule: true, ModuleId: "output", MaxDelay: time.Second}
+	outputModule := &scheduleOutputModule{outputs: make(chan []byte, 1)}
+	modules := map[string]common.Module{"output": outputModule}
+
+	payload := &oscbundle.Bundle{
+		TimeTag:  oscbundle.TimeTagFromTime(time.Now().Add(50 * time.Millisecond)),
+		Contents: []osc.OSCPacket{&osc.OSCMessage{Address: "/test"}},
+	}
+
+	start := time.Now()
+	got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: payload, Modules: modules})
+	if err != nil {
+		t.Fatalf("osc.bundle.encode processing failed: %s", err)
+	}
+
+	if time.Since(start) > 20*time.Millisecond {
+		t.Fatalf("osc.bundle.encode held up the route while a bundle was scheduled")
+	}
+
+	if !got.End {
+		t.Fatalf("osc.bundle.encode should end the route for a scheduled bundle")
+	}
+
+	select {
+	case <-outputModule.outputs:
+		if time.Since(start) < 40*time.Millisecond {
+			t.Fatalf("osc.bundle.encode sent the bundle before its timetag")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("osc.bundle.encode never sent the scheduled bundle")
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	payload.TimeTag = oscbundle.TimeTagFromTime(time.Now().Add(50 * time.Millisecond))
+	_, err = processorInstance.Process(ctx, common.WrappedPayload{Payload: payload, Modules: modules})
+	if err != nil {
+		t.Fatalf("osc.bundle.encode processing failed: %s", err)
+	}
+	cancel()
+
+	select {
+	case <-outputModule.outputs:
+		t.Fatalf("osc.bundle.encode sent a scheduled bundle after its context was canceled")
+	case <-time.After(150 * time.Millisecond):
+	}
+
+	payload.TimeTag = oscbundle.TimeTagFromTime(time.Now().Add(time.Hour))
+	_, err = processorInstance.Process(t.Context(), common.WrappedPayload{Payload: payload, Modules: modules})
+	if err == nil || !strings.Contains(err.Error(), "more than the maxDelay of 1s") {
+		t.Fatalf("osc.bundle.encode expected to reject a timetag past maxDelay, got: %v", err)
+	}
+}
+
+func TestBadOSCBundleEncode(t *testing.T) {
+	processorInstance := processor.OSCBundleEncode{}
+	tests := []struct {
+		name        string
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "OSC message",
+			payload:     &osc.OSCMessage{Address: "/test"},
+			errorString: "osc.bundle.encode processor only accepts an OSC bundle",
+		},
+		{
+			name:        "nil packet",
+			payload:     &oscbundle.Bundle{Contents: []osc.OSCPacket{nil}},
+			errorString: "osc.bundle.encode processor failed to encode OSC bundle: OSC bundle cannot contain a nil packet",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err == nil {
+				t.Fatalf("osc.bundle.encode expected to fail but got payload: %+v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("osc.bundle.encode got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
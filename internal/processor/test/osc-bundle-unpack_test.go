@@ -0,0 +1,79 @@
+package processor_test
+
+import (
+	"testing"
+
+	"github.com/jwetzell/osc-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/oscbundle"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+)
+
+func TestOSCBundleUnpackFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("osc.bundle.unpack")
+	if !ok {
+		t.Fatalf("osc.bundle.unpack processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "osc.bundle.unpack",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create osc.bundle.unpack processor: %s", err)
+	}
+
+	if processorInstance.Type() != "osc.bundle.unpack" {
+		t.Fatalf("osc.bundle.unpack processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodOSCBundleUnpack(t *testing.T) {
+	processorInstance := processor.OSCBundleUnpack{}
+
+	payload := &oscbundle.Bundle{
+		TimeTag: oscbundle.Immediately,
+		Contents: []osc.OSCPacket{
+			&osc.OSCMessage{Address: "/one"},
+			&oscbundle.Bundle{
+				TimeTag:  oscbundle.Immediately,
+				Contents: []osc.OSCPacket{&osc.OSCMessage{Address: "/two"}},
+			},
+		},
+	}
+
+	got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: payload})
+	if err != nil {
+		t.Fatalf("osc.bundle.unpack processing failed: %s", err)
+	}
+
+	fanOut, ok := got.Payload.(common.FanOut)
+	if !ok {
+		t.Fatalf("osc.bundle.unpack returned a %T payload: %+v", got.Payload, got.Payload)
+	}
+
+	if len(fanOut) != 2 {
+		t.Fatalf("osc.bundle.unpack got %d items, expected 2", len(fanOut))
+	}
+
+	for index, address := range []string{"/one", "/two"} {
+		message, ok := fanOut[index].(*osc.OSCMessage)
+		if !ok || message.Address != address {
+			t.Fatalf("osc.bundle.unpack item %d got %+v, expected address %s", index, fanOut[index], address)
+		}
+	}
+}
+
+func TestBadOSCBundleUnpack(t *testing.T) {
+	processorInstance := processor.OSCBundleUnpack{}
+
+	got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: &osc.OSCMessage{Address: "/test"}})
+	if err == nil {
+		t.Fatalf("osc.bundle.unpack expected to fail but got payload: %+v", got)
+	}
+
+	if err.Error() != "osc.bundle.unpack processor only accepts an OSC bundle" {
+		t.Fatalf("osc.bundle.unpack got error '%s'", err.Error())
+	}
+}
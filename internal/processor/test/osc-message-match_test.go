@@ -0,0 +1,277 @@
+package processor_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/osc-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+)
+
+type matchOutputModule struct {
+	outputs []any
+}
+
+func (m *matchOutputModule) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	return nil
+}
+
+// NOTE(jwetzell): only takes bytes like the modules that send to devices
+func (m *matchOutputModule) Output(ctx context.Context, payload any) error {
+	payloadBytes, ok := common.GetAnyAsByteSlice(payload)
+	if !ok {
+		return errors.New("test.output can only output bytes")
+	}
+	m.outputs = append(m.outputs, payloadBytes)
+	return nil
+}
+
+func (m *matchOutputModule) Stop() {}
+
+func (m *matchOutputModule) Type() string {
+	return "test.output"
+}
+
+func (m *matchOutputModule) Id() string {
+	return "output"
+}
+
+func TestOSCMessageMatchFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("osc.message.match")
+	if !ok {
+		t.Fatalf("osc.message.match processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "osc.message.match",
+		Params: map[string]any{
+			"pattern": "/test",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create osc.message.match processor: %s", err)
+	}
+
+	if processorInstance.Type() != "osc.message.match" {
+		t.Fatalf("osc.message.match processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodOSCMessageMatch(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("osc.message.match")
+	if !ok {
+		t.Fatalf("osc.message.match processor not registered")
+	}
+
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  *osc.OSCMessage
+		expected *processor.OSCMessageMatchResult
+	}{
+		{
+			name:   "named segment with args",
+			params: map[string]any{"pattern": "/eos/out/active/chan/:channel"},
+			payload: &osc.OSCMessage{
+				Address: "/eos/out/active/chan/12",
+				Args:    []osc.OSCArg{{Type: "s", Value: "12 Front"}, {Type: "f", Value: float32(0.5)}},
+			},
+			expected: &processor.OSCMessageMatchResult{
+				Pattern:  "/eos/out/active/chan/:channel",
+				Segments: []string{"eos", "out", "active", "chan", "12"},
+				Captures: []string{"12"},
+				Params:   map[string]string{"channel": "12"},
+				Types:    "sf",
+				Args:     []any{"12 Front", float32(0.5)},
+			},
+		},
+		{
+			name:    "no match",
+			params:  map[string]any{"pattern": "/eos/out/active/chan/*"},
+			payload: &osc.OSCMessage{Address: "/eos/out/cmd"},
+		},
+		{
+			name:    "types mismatch",
+			params:  map[string]any{"pattern": "/fader/*", "types": "f"},
+			payload: &osc.OSCMessage{Address: "/fader/1", Args: []osc.OSCArg{{Type: "i", Value: int32(1)}}},
+		},
+		{
+			name: "first matching route",
+			params: map[string]any{"routes": []any{
+				map[string]any{"pattern": "/fader/*", "types": "f"},
+				map[string]any{"pattern": "/{fader,knob}/[0-9]", "types": "?"},
+			}},
+			payload: &osc.OSCMessage{Address: "/fader/1", Args: []osc.OSCArg{{Type: "i", Value: int32(1)}}},
+			expected: &processor.OSCMessageMatchResult{
+				Pattern:  "/{fader,knob}/[0-9]",
+				Segments: []string{"fader", "1"},
+				Captures: []string{"fader", "1"},
+				Params:   map[string]string{},
+				Types:    "i",
+				Args:     []any{int32(1)},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "osc.message.match",
+				Params: test.params,
+			})
+			if err != nil {
+				t.Fatalf("osc.message.match failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("osc.message.match processing failed: %s", err)
+			}
+
+			if test.expected == nil {
+				if !got.End {
+					t.Fatalf("osc.message.match should have ended the route, got %+v", got.Payload)
+				}
+				return
+			}
+
+			gotResult, ok := got.Payload.(processor.OSCMessageMatchResult)
+			if !ok {
+				t.Fatalf("osc.message.match returned a %T payload: %+v", got.Payload, got.Payload)
+			}
+
+			test.expected.Message = test.payload
+			if !reflect.DeepEqual(gotResult, *test.expected) {
+				t.Fatalf("osc.message.match got %+v, expected %+v", gotResult, *test.expected)
+			}
+		})
+	}
+}
+
+func TestOSCMessageMatchRouteOutput(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("osc.message.match")
+	if !ok {
+		t.Fatalf("osc.message.match processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "osc.message.match",
+		Params: map[string]any{"routes": []any{
+			map[string]any{"pattern": "/eos/*", "module": "eos"},
+			map[string]any{"pattern": "/x32/*", "module": "x32"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("osc.message.match failed to create processor: %s", err)
+	}
+
+	eosModule := &matchOutputModule{}
+	x32Module := &matchOutputModule{}
+	modules := map[string]common.Module{"eos": eosModule, "x32": x32Module}
+
+	message := &osc.OSCMessage{Address: "/x32/mute"}
+	_, err = processorInstance.Process(t.Context(), common.WrappedPayload{Payload: message, Modules: modules})
+	if err != nil {
+		t.Fatalf("osc.message.match processing failed: %s", err)
+	}
+
+	if len(eosModule.outputs) != 0 {
+		t.Fatalf("osc.message.match sent to the wrong module")
+	}
+
+	messageBytes, err := message.ToBytes()
+	if err != nil {
+		t.Fatalf("failed to encode message: %s", err)
+	}
+
+	if len(x32Module.outputs) != 1 || !reflect.DeepEqual(x32Module.outputs[0], messageBytes) {
+		t.Fatalf("osc.message.match did not send the message to its route module: %+v", x32Module.outputs)
+	}
+}
+
+func TestBadOSCMessageMatch(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("osc.message.match")
+	if !ok {
+		t.Fatalf("osc.message.match processor not registered")
+	}
+
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "no pattern or routes",
+			params:      map[string]any{},
+			errorString: "osc.message.match needs a pattern or routes",
+		},
+		{
+			name:        "pattern and routes",
+			params:      map[string]any{"pattern": "/a", "routes": []any{}},
+			errorString: "osc.message.match cannot have both pattern and routes",
+		},
+		{
+			name:        "bad pattern",
+			params:      map[string]any{"pattern": "/ch[1"},
+			errorString: "osc.message.match pattern error: OSC address pattern segment 0: unclosed '['",
+		},
+		{
+			name:        "routes not a slice",
+			params:      map[string]any{"routes": "/a"},
+			errorString: "osc.message.match routes error: not a slice",
+		},
+		{
+			name:        "route not a map",
+			params:      map[string]any{"routes": []any{"/a"}},
+			errorString: "osc.message.match routes[0] error: not a map",
+		},
+		{
+			name:        "route types not a string",
+			params:      map[string]any{"routes": []any{map[string]any{"pattern": "/a", "types": 1}}},
+			errorString: "osc.message.match routes[0] types error: not a string",
+		},
+		{
+			name:        "non OSC payload",
+			params:      map[string]any{"pattern": "/a"},
+			payload:     "/a",
+			errorString: "osc.message.match processor only accepts an OSCMessage",
+		},
+		{
+			name:        "missing route module",
+			params:      map[string]any{"routes": []any{map[string]any{"pattern": "/a", "module": "missing"}}},
+			payload:     &osc.OSCMessage{Address: "/a"},
+			errorString: "osc.message.match wrapped payload has no modules",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "osc.message.match",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("osc.message.match got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err == nil {
+				t.Fatalf("osc.message.match expected to fail but got payload: %+v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("osc.message.match got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
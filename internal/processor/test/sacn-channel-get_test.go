@@ -0,0 +1,152 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/sacn"
+)
+
+func TestSACNChannelGetFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("sacn.channel.get")
+	if !ok {
+		t.Fatalf("sacn.channel.get processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "sacn.channel.get",
+		Params: map[string]any{
+			"channel": 1,
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create sacn.channel.get processor: %s", err)
+	}
+
+	if processorInstance.Type() != "sacn.channel.get" {
+		t.Fatalf("sacn.channel.get processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodSACNChannelGet(t *testing.T) {
+	packet := &sacn.DataPacket{Universe: 1, Data: []byte{10, 20, 30}}
+
+	tests := []struct {
+		name     string
+		params   map[string]any
+		expected any
+	}{
+		{
+			name:     "single channel",
+			params:   map[string]any{"channel": 2},
+			expected: byte(20),
+		},
+		{
+			name:     "multiple channels",
+			params:   map[string]any{"channel": 2, "count": 3},
+			expected: []byte{20, 30, 0},
+		},
+		{
+			name:     "channel past data",
+			params:   map[string]any{"channel": 512},
+			expected: byte(0),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("sacn.channel.get")
+			if !ok {
+				t.Fatalf("sacn.channel.get processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "sacn.channel.get",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("sacn.channel.get failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: packet})
+			if err != nil {
+				t.Fatalf("sacn.channel.get processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("sacn.channel.get got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadSACNChannelGet(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "no channel param",
+			params:      map[string]any{},
+			payload:     &sacn.DataPacket{},
+			errorString: "sacn.channel.get channel error: not found",
+		},
+		{
+			name:        "channel out of range",
+			params:      map[string]any{"channel": 513},
+			payload:     &sacn.DataPacket{},
+			errorString: "sacn.channel.get channel must be between 1 and 512",
+		},
+		{
+			name:        "count past last channel",
+			params:      map[string]any{"channel": 510, "count": 4},
+			payload:     &sacn.DataPacket{},
+			errorString: "sacn.channel.get count must stay within 512 channels",
+		},
+		{
+			name:        "not a data packet",
+			params:      map[string]any{"channel": 1},
+			payload:     []byte{1, 2, 3},
+			errorString: "sacn.channel.get processor only accepts a sACN data packet",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := processor.GetProcessorRegistration("sacn.channel.get")
+			if !ok {
+				t.Fatalf("sacn.channel.get processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "sacn.channel.get",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("sacn.channel.get got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("sacn.channel.get expected to fail but got payload: %+v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("sacn.channel.get got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
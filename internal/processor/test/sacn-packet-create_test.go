@@ -0,0 +1,166 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/sacn"
+)
+
+func TestSACNPacketCreateFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("sacn.packet.create")
+	if !ok {
+		t.Fatalf("sacn.packet.create processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "sacn.packet.create",
+		Params: map[string]any{
+			"universe": "1",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create sacn.packet.create processor: %s", err)
+	}
+
+	if processorInstance.Type() != "sacn.packet.create" {
+		t.Fatalf("sacn.packet.create processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodSACNPacketCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected *sacn.DataPacket
+	}{
+		{
+			name:     "data param",
+			params:   map[string]any{"universe": "2", "data": "0, 127,{{.Payload}}"},
+			payload:  255,
+			expected: &sacn.DataPacket{Universe: 2, Data: []byte{0, 127, 255}},
+		},
+		{
+			name:     "universe template",
+			params:   map[string]any{"universe": "{{.Payload.universe}}", "data": "{{.Payload.level}}"},
+			payload:  map[string]any{"universe": 7, "level": 50},
+			expected: &sacn.DataPacket{Universe: 7, Data: []byte{50}},
+		},
+		{
+			name:     "byte payload",
+			params:   map[string]any{"universe": "1"},
+			payload:  []byte{1, 2, 3},
+			expected: &sacn.DataPacket{Universe: 1, Data: []byte{1, 2, 3}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("sacn.packet.create")
+			if !ok {
+				t.Fatalf("sacn.packet.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "sacn.packet.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("sacn.packet.create failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("sacn.packet.create processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("sacn.packet.create got %+v, expected %+v", got.Payload, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadSACNPacketCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "no universe param",
+			params:      map[string]any{},
+			payload:     []byte{1},
+			errorString: "sacn.packet.create universe error: not found",
+		},
+		{
+			name:        "non-string data param",
+			params:      map[string]any{"universe": "1", "data": 1},
+			payload:     []byte{1},
+			errorString: "sacn.packet.create data error: not a string",
+		},
+		{
+			name:        "universe out of range",
+			params:      map[string]any{"universe": "64000"},
+			payload:     []byte{1},
+			errorString: "sacn.packet.create universe must be between 1 and 63999",
+		},
+		{
+			name:        "no data",
+			params:      map[string]any{"universe": "1"},
+			payload:     "hello",
+			errorString: "sacn.packet.create processor needs a data param or a []byte payload",
+		},
+		{
+			name:        "too much data",
+			params:      map[string]any{"universe": "1"},
+			payload:     make([]byte, 513),
+			errorString: "sacn.packet.create data cannot be more than 512 slots",
+		},
+		{
+			name:        "data value too large",
+			params:      map[string]any{"universe": "1", "data": "256"},
+			payload:     nil,
+			errorString: "strconv.ParseUint: parsing \"256\": value out of range",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			registration, ok := processor.GetProcessorRegistration("sacn.packet.create")
+			if !ok {
+				t.Fatalf("sacn.packet.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "sacn.packet.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("sacn.packet.create got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("sacn.packet.create expected to fail but got payload: %+v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("sacn.packet.create got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/sacn"
+)
+
+func TestSACNPacketDecodeFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("sacn.packet.decode")
+	if !ok {
+		t.Fatalf("sacn.packet.decode processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "sacn.packet.decode",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create sacn.packet.decode processor: %s", err)
+	}
+
+	if processorInstance.Type() != "sacn.packet.decode" {
+		t.Fatalf("sacn.packet.decode processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodSACNPacketDecode(t *testing.T) {
+	packetDecoder := processor.SACNPacketDecode{}
+
+	expected := &sacn.DataPacket{
+		CID:        sacn.CID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SourceName: "console",
+		Priority:   100,
+		Sequence:   3,
+		Universe:   1,
+		Data:       []byte{255, 0, 128},
+	}
+	payload, err := expected.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to create sACN packet: %s", err)
+	}
+
+	got, err := packetDecoder.Process(t.Context(), common.WrappedPayload{Payload: payload})
+	if err != nil {
+		t.Fatalf("sacn.packet.decode processing failed: %s", err)
+	}
+
+	if !reflect.DeepEqual(got.Payload, expected) {
+		t.Fatalf("sacn.packet.decode got %+v, expected %+v", got.Payload, expected)
+	}
+}
+
+func TestBadSACNPacketDecode(t *testing.T) {
+	packetDecoder := processor.SACNPacketDecode{}
+	tests := []struct {
+		name        string
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "not bytes",
+			payload:     1,
+			errorString: "sacn.packet.decode processor only accepts a []byte",
+		},
+		{
+			name:        "not a sACN packet",
+			payload:     []byte{0x00, 0x10, 0x00, 0x00},
+			errorString: "sacn packet too short",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			got, err := packetDecoder.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("sacn.packet.decode expected to fail but succeeded, got: %v", got)
+			}
+			if err.Error() != test.errorString {
+				t.Fatalf("sacn.packet.decode got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/sacn"
+)
+
+func TestSACNPacketEncodeFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("sacn.packet.encode")
+	if !ok {
+		t.Fatalf("sacn.packet.encode processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "sacn.packet.encode",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create sacn.packet.encode processor: %s", err)
+	}
+
+	if processorInstance.Type() != "sacn.packet.encode" {
+		t.Fatalf("sacn.packet.encode processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodSACNPacketEncode(t *testing.T) {
+	packetEncoder := processor.SACNPacketEncode{}
+
+	tests := []struct {
+		name     string
+		expected []byte
+		payload  any
+	}{
+		{
+			name: "sync packet",
+			expected: []byte{
+				0x00, 0x10, 0x00, 0x00, 0x41, 0x53, 0x43, 0x2d, 0x45, 0x31, 0x2e, 0x31, 0x37, 0x00, 0x00, 0x00,
+				0x70, 0x21, 0x00, 0x00, 0x00, 0x08, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16,
+				0x70, 0x0b, 0x00, 0x00, 0x00, 0x01, 5, 0x00, 0x01, 0x00, 0x00,
+			},
+			payload: &sacn.SyncPacket{
+				CID:         sacn.CID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+				Sequence:    5,
+				SyncAddress: 1,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			got, err := packetEncoder.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err != nil {
+				t.Fatalf("sacn.packet.encode processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("sacn.packet.encode got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadSACNPacketEncode(t *testing.T) {
+	packetEncoder := processor.SACNPacketEncode{}
+	tests := []struct {
+		name        string
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "not a sACN packet",
+			payload:     "test",
+			errorString: "sacn.packet.encode processor only accepts a sACN packet",
+		},
+		{
+			name:        "invalid universe",
+			payload:     &sacn.DataPacket{Universe: 0},
+			errorString: "universe must be between 1 and 63999",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			got, err := packetEncoder.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("sacn.packet.encode expected to fail but succeeded, got: %v", got)
+			}
+			if err.Error() != test.errorString {
+				t.Fatalf("sacn.packet.encode got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
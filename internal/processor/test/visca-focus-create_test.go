@@ -0,0 +1,146 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/visca"
+)
+
+func TestVISCAFocusCreateFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("visca.focus.create")
+	if !ok {
+		t.Fatalf("visca.focus.create processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "visca.focus.create",
+		Params: map[string]any{
+			"mode": "auto",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create visca.focus.create processor: %s", err)
+	}
+
+	if processorInstance.Type() != "visca.focus.create" {
+		t.Fatalf("visca.focus.create processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodVISCAFocusCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected any
+	}{
+		{
+			name:     "near",
+			params:   map[string]any{"speed": "-3"},
+			expected: visca.Message{Data: []byte{0x81, 0x01, 0x04, 0x08, 0x33, 0xff}},
+		},
+		{
+			name:     "direct",
+			params:   map[string]any{"position": "4096"},
+			expected: visca.Message{Data: []byte{0x81, 0x01, 0x04, 0x48, 0x01, 0x00, 0x00, 0x00, 0xff}},
+		},
+		{
+			name:     "manual",
+			params:   map[string]any{"mode": "manual"},
+			expected: visca.Message{Data: []byte{0x81, 0x01, 0x04, 0x38, 0x03, 0xff}},
+		},
+		{
+			name:     "one push",
+			params:   map[string]any{"mode": "onePush"},
+			expected: visca.Message{Data: []byte{0x81, 0x01, 0x04, 0x18, 0x01, 0xff}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("visca.focus.create")
+			if !ok {
+				t.Fatalf("visca.focus.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "visca.focus.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("visca.focus.create failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("visca.focus.create processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("visca.focus.create got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadVISCAFocusCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "no params",
+			params:      map[string]any{},
+			errorString: "visca.focus.create requires one of speed, position or mode",
+		},
+		{
+			name:        "speed and mode",
+			params:      map[string]any{"speed": "1", "mode": "auto"},
+			errorString: "visca.focus.create requires one of speed, position or mode",
+		},
+		{
+			name:        "unknown mode",
+			params:      map[string]any{"mode": "tracking"},
+			errorString: "visca.focus.create VISCA focus mode must be auto, manual or onePush, got tracking",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("visca.focus.create")
+			if !ok {
+				t.Fatalf("visca.focus.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "visca.focus.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("visca.focus.create got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("visca.focus.create expected to fail but succeeded, got: %v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("visca.focus.create got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/visca"
+)
+
+func TestVISCAInquiryCreateFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("visca.inquiry.create")
+	if !ok {
+		t.Fatalf("visca.inquiry.create processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "visca.inquiry.create",
+		Params: map[string]any{
+			"inquiry": "power",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create visca.inquiry.create processor: %s", err)
+	}
+
+	if processorInstance.Type() != "visca.inquiry.create" {
+		t.Fatalf("visca.inquiry.create processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodVISCAInquiryCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected any
+	}{
+		{
+			name:     "power",
+			params:   map[string]any{"inquiry": "power"},
+			expected: visca.Message{Data: []byte{0x81, 0x09, 0x04, 0x00, 0xff}},
+		},
+		{
+			name:     "pan tilt position",
+			params:   map[string]any{"inquiry": "panTiltPosition"},
+			expected: visca.Message{Data: []byte{0x81, 0x09, 0x06, 0x12, 0xff}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("visca.inquiry.create")
+			if !ok {
+				t.Fatalf("visca.inquiry.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "visca.inquiry.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("visca.inquiry.create failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("visca.inquiry.create processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("visca.inquiry.create got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadVISCAInquiryCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "no inquiry param",
+			params:      map[string]any{},
+			errorString: "visca.inquiry.create inquiry error: not found",
+		},
+		{
+			name:        "unknown inquiry",
+			params:      map[string]any{"inquiry": "iris"},
+			errorString: "visca.inquiry.create VISCA inquiry is unknown: iris",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("visca.inquiry.create")
+			if !ok {
+				t.Fatalf("visca.inquiry.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "visca.inquiry.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("visca.inquiry.create got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("visca.inquiry.create expected to fail but succeeded, got: %v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("visca.inquiry.create got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/visca"
+)
+
+func TestVISCAInquiryDecodeFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("visca.inquiry.decode")
+	if !ok {
+		t.Fatalf("visca.inquiry.decode processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "visca.inquiry.decode",
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create visca.inquiry.decode processor: %s", err)
+	}
+
+	if processorInstance.Type() != "visca.inquiry.decode" {
+		t.Fatalf("visca.inquiry.decode processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodVISCAInquiryDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected any
+	}{
+		{
+			name:     "zoom position response",
+			params:   map[string]any{},
+			payload:  visca.Response{Message: visca.Message{Data: []byte{0x81, 0x09, 0x04, 0x47, 0xff}}, Reply: visca.Reply{Kind: visca.ReplyCompletion, Data: []byte{0x01, 0x02, 0x03, 0x04}}},
+			expected: visca.ZoomPosition{Zoom: 0x1234},
+		},
+		{
+			name:     "power bytes",
+			params:   map[string]any{"inquiry": "power"},
+			payload:  []byte{0x90, 0x50, 0x02, 0xff},
+			expected: visca.PowerStatus{On: true},
+		},
+		{
+			name:     "pan tilt position bytes",
+			params:   map[string]any{"inquiry": "panTiltPosition"},
+			payload:  []byte{0x90, 0x50, 0x00, 0x00, 0x01, 0x00, 0x0f, 0x0f, 0x0f, 0x00, 0xff},
+			expected: visca.PanTiltPosition{Pan: 16, Tilt: -16},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("visca.inquiry.decode")
+			if !ok {
+				t.Fatalf("visca.inquiry.decode processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "visca.inquiry.decode",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("visca.inquiry.decode failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("visca.inquiry.decode processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("visca.inquiry.decode got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadVISCAInquiryDecode(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "bytes without inquiry",
+			params:      map[string]any{},
+			payload:     []byte{0x90, 0x50, 0x02, 0xff},
+			errorString: "visca.inquiry.decode does not know which inquiry the reply answers",
+		},
+		{
+			name:        "error reply",
+			params:      map[string]any{"inquiry": "power"},
+			payload:     []byte{0x90, 0x60, 0x02, 0xff},
+			errorString: "visca.inquiry.decode reply must be a completion, got error",
+		},
+		{
+			name:        "wrong length",
+			params:      map[string]any{"inquiry": "zoomPosition"},
+			payload:     []byte{0x90, 0x50, 0x02, 0xff},
+			errorString: "visca.inquiry.decode VISCA zoom position reply must be 4 bytes",
+		},
+		{
+			name:        "not bytes",
+			params:      map[string]any{},
+			payload:     1.5,
+			errorString: "visca.inquiry.decode can only decode a visca.Response or bytes",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("visca.inquiry.decode")
+			if !ok {
+				t.Fatalf("visca.inquiry.decode processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "visca.inquiry.decode",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("visca.inquiry.decode got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("visca.inquiry.decode expected to fail but succeeded, got: %v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("visca.inquiry.decode got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
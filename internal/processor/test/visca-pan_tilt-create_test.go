@@ -0,0 +1,143 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/visca"
+)
+
+func TestVISCAPanTiltCreateFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("visca.pan_tilt.create")
+	if !ok {
+		t.Fatalf("visca.pan_tilt.create processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "visca.pan_tilt.create",
+		Params: map[string]any{
+			"pan":  "0",
+			"tilt": "0",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create visca.pan_tilt.create processor: %s", err)
+	}
+
+	if processorInstance.Type() != "visca.pan_tilt.create" {
+		t.Fatalf("visca.pan_tilt.create processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodVISCAPanTiltCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected any
+	}{
+		{
+			name:     "stop",
+			params:   map[string]any{"pan": "0", "tilt": "0"},
+			expected: visca.Message{Data: []byte{0x81, 0x01, 0x06, 0x01, 0x01, 0x01, 0x03, 0x03, 0xff}},
+		},
+		{
+			name:     "pan right from payload",
+			params:   map[string]any{"pan": "{{.Payload}}", "tilt": "0"},
+			payload:  24,
+			expected: visca.Message{Data: []byte{0x81, 0x01, 0x06, 0x01, 0x18, 0x01, 0x02, 0x03, 0xff}},
+		},
+		{
+			name:     "tilt down",
+			params:   map[string]any{"pan": "0", "tilt": "-20"},
+			expected: visca.Message{Data: []byte{0x81, 0x01, 0x06, 0x01, 0x01, 0x14, 0x03, 0x02, 0xff}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("visca.pan_tilt.create")
+			if !ok {
+				t.Fatalf("visca.pan_tilt.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "visca.pan_tilt.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("visca.pan_tilt.create failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("visca.pan_tilt.create processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("visca.pan_tilt.create got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadVISCAPanTiltCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "no tilt param",
+			params:      map[string]any{"pan": "0"},
+			errorString: "visca.pan_tilt.create tilt error: not found",
+		},
+		{
+			name:        "pan not a number",
+			params:      map[string]any{"pan": "left", "tilt": "0"},
+			errorString: "visca.pan_tilt.create pan error: strconv.Atoi: parsing \"left\": invalid syntax",
+		},
+		{
+			name:        "tilt too fast",
+			params:      map[string]any{"pan": "0", "tilt": "21"},
+			errorString: "visca.pan_tilt.create VISCA tilt speed must be between -20 and 20",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("visca.pan_tilt.create")
+			if !ok {
+				t.Fatalf("visca.pan_tilt.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "visca.pan_tilt.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("visca.pan_tilt.create got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("visca.pan_tilt.create expected to fail but succeeded, got: %v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("visca.pan_tilt.create got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
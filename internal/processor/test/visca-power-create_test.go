@@ -0,0 +1,132 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/visca"
+)
+
+func TestVISCAPowerCreateFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("visca.power.create")
+	if !ok {
+		t.Fatalf("visca.power.create processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "visca.power.create",
+		Params: map[string]any{
+			"state": "on",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create visca.power.create processor: %s", err)
+	}
+
+	if processorInstance.Type() != "visca.power.create" {
+		t.Fatalf("visca.power.create processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodVISCAPowerCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected any
+	}{
+		{
+			name:     "on",
+			params:   map[string]any{"state": "on"},
+			expected: visca.Message{Data: []byte{0x81, 0x01, 0x04, 0x00, 0x02, 0xff}},
+		},
+		{
+			name:     "off from payload",
+			params:   map[string]any{"state": "{{.Payload}}"},
+			payload:  "OFF",
+			expected: visca.Message{Data: []byte{0x81, 0x01, 0x04, 0x00, 0x03, 0xff}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("visca.power.create")
+			if !ok {
+				t.Fatalf("visca.power.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "visca.power.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("visca.power.create failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("visca.power.create processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("visca.power.create got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadVISCAPowerCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "no state param",
+			params:      map[string]any{},
+			errorString: "visca.power.create state error: not found",
+		},
+		{
+			name:        "unknown state",
+			params:      map[string]any{"state": "standby"},
+			errorString: "visca.power.create state must be on or off, got standby",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("visca.power.create")
+			if !ok {
+				t.Fatalf("visca.power.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "visca.power.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("visca.power.create got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("visca.power.create expected to fail but succeeded, got: %v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("visca.power.create got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
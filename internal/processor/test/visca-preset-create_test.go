@@ -0,0 +1,142 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/visca"
+)
+
+func TestVISCAPresetCreateFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("visca.preset.create")
+	if !ok {
+		t.Fatalf("visca.preset.create processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "visca.preset.create",
+		Params: map[string]any{
+			"preset": "1",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create visca.preset.create processor: %s", err)
+	}
+
+	if processorInstance.Type() != "visca.preset.create" {
+		t.Fatalf("visca.preset.create processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodVISCAPresetCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected any
+	}{
+		{
+			name:     "recall",
+			params:   map[string]any{"preset": "{{.Payload}}"},
+			payload:  5,
+			expected: visca.Message{Data: []byte{0x81, 0x01, 0x04, 0x3f, 0x02, 0x05, 0xff}},
+		},
+		{
+			name:     "set",
+			params:   map[string]any{"action": "set", "preset": "127"},
+			expected: visca.Message{Data: []byte{0x81, 0x01, 0x04, 0x3f, 0x01, 0x7f, 0xff}},
+		},
+		{
+			name:     "reset",
+			params:   map[string]any{"action": "reset", "preset": "0"},
+			expected: visca.Message{Data: []byte{0x81, 0x01, 0x04, 0x3f, 0x00, 0x00, 0xff}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("visca.preset.create")
+			if !ok {
+				t.Fatalf("visca.preset.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "visca.preset.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("visca.preset.create failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("visca.preset.create processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("visca.preset.create got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadVISCAPresetCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "no preset param",
+			params:      map[string]any{},
+			errorString: "visca.preset.create preset error: not found",
+		},
+		{
+			name:        "unknown action",
+			params:      map[string]any{"action": "save", "preset": "1"},
+			errorString: "visca.preset.create VISCA preset action must be recall, set or reset, got save",
+		},
+		{
+			name:        "preset out of range",
+			params:      map[string]any{"preset": "128"},
+			errorString: "visca.preset.create VISCA preset must be between 0 and 127",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("visca.preset.create")
+			if !ok {
+				t.Fatalf("visca.preset.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "visca.preset.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("visca.preset.create got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("visca.preset.create expected to fail but succeeded, got: %v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("visca.preset.create got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
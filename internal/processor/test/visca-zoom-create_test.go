@@ -0,0 +1,147 @@
+package processor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/visca"
+)
+
+func TestVISCAZoomCreateFromRegistry(t *testing.T) {
+	registration, ok := processor.GetProcessorRegistration("visca.zoom.create")
+	if !ok {
+		t.Fatalf("visca.zoom.create processor not registered")
+	}
+
+	processorInstance, err := registration.New(config.ProcessorConfig{
+		Type: "visca.zoom.create",
+		Params: map[string]any{
+			"speed": "0",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("failed to create visca.zoom.create processor: %s", err)
+	}
+
+	if processorInstance.Type() != "visca.zoom.create" {
+		t.Fatalf("visca.zoom.create processor has wrong type: %s", processorInstance.Type())
+	}
+}
+
+func TestGoodVISCAZoomCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]any
+		payload  any
+		expected any
+	}{
+		{
+			name:     "stop",
+			params:   map[string]any{"speed": "0"},
+			expected: visca.Message{Data: []byte{0x81, 0x01, 0x04, 0x07, 0x00, 0xff}},
+		},
+		{
+			name:     "tele",
+			params:   map[string]any{"speed": "7"},
+			expected: visca.Message{Data: []byte{0x81, 0x01, 0x04, 0x07, 0x27, 0xff}},
+		},
+		{
+			name:     "direct",
+			params:   map[string]any{"position": "{{.Payload}}"},
+			payload:  "4660",
+			expected: visca.Message{Data: []byte{0x81, 0x01, 0x04, 0x47, 0x01, 0x02, 0x03, 0x04, 0xff}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("visca.zoom.create")
+			if !ok {
+				t.Fatalf("visca.zoom.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "visca.zoom.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				t.Fatalf("visca.zoom.create failed to create processor: %s", err)
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+			if err != nil {
+				t.Fatalf("visca.zoom.create processing failed: %s", err)
+			}
+
+			if !reflect.DeepEqual(got.Payload, test.expected) {
+				t.Fatalf("visca.zoom.create got %+v (%T), expected %+v (%T)", got.Payload, got.Payload, test.expected, test.expected)
+			}
+		})
+	}
+}
+
+func TestBadVISCAZoomCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]any
+		payload     any
+		errorString string
+	}{
+		{
+			name:        "no params",
+			params:      map[string]any{},
+			errorString: "visca.zoom.create requires either speed or position",
+		},
+		{
+			name:        "both params",
+			params:      map[string]any{"speed": "1", "position": "1"},
+			errorString: "visca.zoom.create requires either speed or position",
+		},
+		{
+			name:        "speed too fast",
+			params:      map[string]any{"speed": "-8"},
+			errorString: "visca.zoom.create VISCA zoom speed must be between -7 and 7",
+		},
+		{
+			name:        "position out of range",
+			params:      map[string]any{"position": "65536"},
+			errorString: "visca.zoom.create position must be between 0 and 65535",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registration, ok := processor.GetProcessorRegistration("visca.zoom.create")
+			if !ok {
+				t.Fatalf("visca.zoom.create processor not registered")
+			}
+
+			processorInstance, err := registration.New(config.ProcessorConfig{
+				Type:   "visca.zoom.create",
+				Params: test.params,
+			})
+
+			if err != nil {
+				if test.errorString != err.Error() {
+					t.Fatalf("visca.zoom.create got error '%s', expected '%s'", err.Error(), test.errorString)
+				}
+				return
+			}
+
+			got, err := processorInstance.Process(t.Context(), common.WrappedPayload{Payload: test.payload})
+
+			if err == nil {
+				t.Fatalf("visca.zoom.create expected to fail but succeeded, got: %v", got)
+			}
+
+			if err.Error() != test.errorString {
+				t.Fatalf("visca.zoom.create got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/template"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/visca"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:        "visca.focus.create",
+		Title:       "Create VISCA Focus",
+		Description: "Create a visca.Message that drives the focus at a speed, moves it to a position or changes the focus mode",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"speed": {
+					Title:       "Speed",
+					Description: "focus speed from -7 (near) to 7 (far), 0 stops the focus",
+					Type:        "string",
+				},
+				"position": {
+					Title:       "Position",
+					Description: "focus position from 0 to 65535, cameras only use part of the range",
+					Type:        "string",
+				},
+				"mode": {
+					Title:       "Mode",
+					Description: "focus mode, onePush triggers a single auto focus",
+					Type:        "string",
+					Enum:        []any{"auto", "manual", "onePush"},
+				},
+			},
+			OneOf: []*jsonschema.Schema{
+				{Required: []string{"speed"}},
+				{Required: []string{"position"}},
+				{Required: []string{"mode"}},
+			},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			var speedTemplate *template.Template
+			speedString, err := params.GetString("speed")
+			if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+				return nil, fmt.Errorf("visca.focus.create speed error: %w", err)
+			}
+			if speedString != "" {
+				speedTemplate, err = template.New("speed").Parse(speedString)
+				if err != nil {
+					return nil, fmt.Errorf("visca.focus.create speed error: %w", err)
+				}
+			}
+
+			var positionTemplate *template.Template
+			positionString, err := params.GetString("position")
+			if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+				return nil, fmt.Errorf("visca.focus.create position error: %w", err)
+			}
+			if positionString != "" {
+				positionTemplate, err = template.New("position").Parse(positionString)
+				if err != nil {
+					return nil, fmt.Errorf("visca.focus.create position error: %w", err)
+				}
+			}
+
+			modeString, err := params.GetString("mode")
+			if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+				return nil, fmt.Errorf("visca.focus.create mode error: %w", err)
+			}
+
+			set := 0
+			for _, isSet := range []bool{speedTemplate != nil, positionTemplate != nil, modeString != ""} {
+				if isSet {
+					set++
+				}
+			}
+
+			if set != 1 {
+				return nil, errors.New("visca.focus.create requires one of speed, position or mode")
+			}
+
+			var modeMessage *visca.Message
+			if modeString != "" {
+				message, err := visca.FocusMode(modeString)
+				if err != nil {
+					return nil, fmt.Errorf("visca.focus.create %w", err)
+				}
+				modeMessage = &message
+			}
+
+			return &VISCAFocusCreate{config: processorConfig, Speed: speedTemplate, Position: positionTemplate, mode: modeMessage}, nil
+		},
+	})
+}
+
+type VISCAFocusCreate struct {
+	config   config.ProcessorConfig
+	Speed    *template.Template
+	Position *template.Template
+	mode     *visca.Message
+}
+
+func (vfc *VISCAFocusCreate) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	templateData := wrappedPayload
+
+	if vfc.mode != nil {
+		wrappedPayload.Payload = *vfc.mode
+		return wrappedPayload, nil
+	}
+
+	if vfc.Speed != nil {
+		speed, err := executeVISCAInt(vfc.Speed, templateData)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, fmt.Errorf("visca.focus.create speed error: %w", err)
+		}
+
+		message, err := visca.FocusDrive(speed)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, fmt.Errorf("visca.focus.create %w", err)
+		}
+		wrappedPayload.Payload = message
+		return wrappedPayload, nil
+	}
+
+	position, err := executeVISCAInt(vfc.Position, templateData)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("visca.focus.create position error: %w", err)
+	}
+
+	if position < 0 || position > 0xffff {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("visca.focus.create position must be between 0 and 65535")
+	}
+	wrappedPayload.Payload = visca.FocusDirect(uint16(position))
+	return wrappedPayload, nil
+}
+
+func (vfc *VISCAFocusCreate) Type() string {
+	return vfc.config.Type
+}
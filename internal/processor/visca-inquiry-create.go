@@ -0,0 +1,60 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/visca"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:        "visca.inquiry.create",
+		Title:       "Create VISCA Inquiry",
+		Description: "Create a visca.Message that asks the camera for its power, pan tilt, zoom or focus position",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"inquiry": {
+					Title: "Inquiry",
+					Type:  "string",
+					Enum:  []any{visca.InquiryPower, visca.InquiryPanTiltPosition, visca.InquiryZoomPosition, visca.InquiryFocusPosition},
+				},
+			},
+			Required:             []string{"inquiry"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			inquiryString, err := params.GetString("inquiry")
+			if err != nil {
+				return nil, fmt.Errorf("visca.inquiry.create inquiry error: %w", err)
+			}
+
+			message, err := visca.Inquiry(inquiryString)
+			if err != nil {
+				return nil, fmt.Errorf("visca.inquiry.create %w", err)
+			}
+
+			return &VISCAInquiryCreate{config: processorConfig, Message: message}, nil
+		},
+	})
+}
+
+type VISCAInquiryCreate struct {
+	config  config.ProcessorConfig
+	Message visca.Message
+}
+
+func (vic *VISCAInquiryCreate) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	wrappedPayload.Payload = visca.Message{Data: append([]byte{}, vic.Message.Data...)}
+	return wrappedPayload, nil
+}
+
+func (vic *VISCAInquiryCreate) Type() string {
+	return vic.config.Type
+}
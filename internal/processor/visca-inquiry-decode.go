@@ -0,0 +1,102 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/visca"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:        "visca.inquiry.decode",
+		Title:       "Decode VISCA Inquiry",
+		Description: "Decode an inquiry reply into a visca.PowerStatus, visca.PanTiltPosition, visca.ZoomPosition or visca.FocusPosition, replies that aren't a visca.Response need the inquiry param",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"inquiry": {
+					Title:       "Inquiry",
+					Description: "inquiry the reply answers, defaults to the inquiry in the visca.Response",
+					Type:        "string",
+					Enum:        []any{visca.InquiryPower, visca.InquiryPanTiltPosition, visca.InquiryZoomPosition, visca.InquiryFocusPosition},
+				},
+			},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			inquiryString, err := params.GetString("inquiry")
+			if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+				return nil, fmt.Errorf("visca.inquiry.decode inquiry error: %w", err)
+			}
+
+			if inquiryString != "" {
+				_, err := visca.Inquiry(inquiryString)
+				if err != nil {
+					return nil, fmt.Errorf("visca.inquiry.decode %w", err)
+				}
+			}
+
+			return &VISCAInquiryDecode{config: processorConfig, Inquiry: inquiryString}, nil
+		},
+	})
+}
+
+type VISCAInquiryDecode struct {
+	config  config.ProcessorConfig
+	Inquiry string
+}
+
+func (vid *VISCAInquiryDecode) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	inquiry := vid.Inquiry
+	var reply visca.Reply
+
+	response, ok := common.GetAnyAs[visca.Response](wrappedPayload.Payload)
+	if ok {
+		if inquiry == "" {
+			inquiry = response.Message.InquiryKind()
+		}
+		reply = response.Reply
+	} else {
+		payloadBytes, ok := common.GetAnyAsByteSlice(wrappedPayload.Payload)
+		if !ok {
+			wrappedPayload.End = true
+			return wrappedPayload, errors.New("visca.inquiry.decode can only decode a visca.Response or bytes")
+		}
+
+		parsed, err := visca.ParseReply(payloadBytes)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, fmt.Errorf("visca.inquiry.decode %w", err)
+		}
+		reply = parsed
+	}
+
+	if inquiry == "" {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("visca.inquiry.decode does not know which inquiry the reply answers")
+	}
+
+	if reply.Kind != visca.ReplyCompletion {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("visca.inquiry.decode reply must be a completion, got %s", reply.Kind)
+	}
+
+	decoded, err := visca.DecodeInquiry(inquiry, reply.Data)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("visca.inquiry.decode %w", err)
+	}
+	wrappedPayload.Payload = decoded
+	return wrappedPayload, nil
+}
+
+func (vid *VISCAInquiryDecode) Type() string {
+	return vid.config.Type
+}
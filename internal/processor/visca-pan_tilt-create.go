@@ -0,0 +1,96 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"text/template"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/visca"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:        "visca.pan_tilt.create",
+		Title:       "Create VISCA Pan Tilt Drive",
+		Description: "Create a visca.Message that drives pan and tilt, the sign of each speed is the direction and 0 stops that axis",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"pan": {
+					Title:       "Pan",
+					Description: "pan speed from -24 (left) to 24 (right)",
+					Type:        "string",
+				},
+				"tilt": {
+					Title:       "Tilt",
+					Description: "tilt speed from -20 (down) to 20 (up)",
+					Type:        "string",
+				},
+			},
+			Required:             []string{"pan", "tilt"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			panString, err := params.GetString("pan")
+			if err != nil {
+				return nil, fmt.Errorf("visca.pan_tilt.create pan error: %w", err)
+			}
+
+			panTemplate, err := template.New("pan").Parse(panString)
+			if err != nil {
+				return nil, err
+			}
+
+			tiltString, err := params.GetString("tilt")
+			if err != nil {
+				return nil, fmt.Errorf("visca.pan_tilt.create tilt error: %w", err)
+			}
+
+			tiltTemplate, err := template.New("tilt").Parse(tiltString)
+			if err != nil {
+				return nil, err
+			}
+
+			return &VISCAPanTiltCreate{config: processorConfig, Pan: panTemplate, Tilt: tiltTemplate}, nil
+		},
+	})
+}
+
+type VISCAPanTiltCreate struct {
+	config config.ProcessorConfig
+	Pan    *template.Template
+	Tilt   *template.Template
+}
+
+func (vptc *VISCAPanTiltCreate) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	templateData := wrappedPayload
+
+	pan, err := executeVISCAInt(vptc.Pan, templateData)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("visca.pan_tilt.create pan error: %w", err)
+	}
+
+	tilt, err := executeVISCAInt(vptc.Tilt, templateData)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("visca.pan_tilt.create tilt error: %w", err)
+	}
+
+	message, err := visca.PanTiltDrive(pan, tilt)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("visca.pan_tilt.create %w", err)
+	}
+	wrappedPayload.Payload = message
+	return wrappedPayload, nil
+}
+
+func (vptc *VISCAPanTiltCreate) Type() string {
+	return vptc.config.Type
+}
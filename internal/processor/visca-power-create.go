@@ -0,0 +1,80 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/visca"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:        "visca.power.create",
+		Title:       "Create VISCA Power",
+		Description: "Create a visca.Message that turns the camera on or off",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"state": {
+					Title:       "State",
+					Description: "on or off",
+					Type:        "string",
+				},
+			},
+			Required:             []string{"state"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			stateString, err := params.GetString("state")
+			if err != nil {
+				return nil, fmt.Errorf("visca.power.create state error: %w", err)
+			}
+
+			stateTemplate, err := template.New("state").Parse(stateString)
+			if err != nil {
+				return nil, err
+			}
+
+			return &VISCAPowerCreate{config: processorConfig, State: stateTemplate}, nil
+		},
+	})
+}
+
+type VISCAPowerCreate struct {
+	config config.ProcessorConfig
+	State  *template.Template
+}
+
+func (vpc *VISCAPowerCreate) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	templateData := wrappedPayload
+
+	var stateBuffer bytes.Buffer
+	err := vpc.State.Execute(&stateBuffer, templateData)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, err
+	}
+
+	switch strings.ToLower(stateBuffer.String()) {
+	case "on":
+		wrappedPayload.Payload = visca.Power(true)
+	case "off":
+		wrappedPayload.Payload = visca.Power(false)
+	default:
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("visca.power.create state must be on or off, got %s", stateBuffer.String())
+	}
+	return wrappedPayload, nil
+}
+
+func (vpc *VISCAPowerCreate) Type() string {
+	return vpc.config.Type
+}
@@ -0,0 +1,98 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"text/template"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/visca"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:        "visca.preset.create",
+		Title:       "Create VISCA Preset",
+		Description: "Create a visca.Message that recalls, sets or resets a camera memory preset",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"action": {
+					Title:   "Action",
+					Type:    "string",
+					Enum:    []any{"recall", "set", "reset"},
+					Default: json.RawMessage(`"recall"`),
+				},
+				"preset": {
+					Title:       "Preset",
+					Description: "preset number from 0 to 127",
+					Type:        "string",
+				},
+			},
+			Required:             []string{"preset"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			actionString, err := params.GetString("action")
+			if err != nil {
+				if errors.Is(err, config.ErrParamNotFound) {
+					actionString = "recall"
+				} else {
+					return nil, fmt.Errorf("visca.preset.create action error: %w", err)
+				}
+			}
+
+			//NOTE(jwetzell): build a throwaway preset so a bad action fails at startup
+			_, err = visca.Preset(actionString, 0)
+			if err != nil {
+				return nil, fmt.Errorf("visca.preset.create %w", err)
+			}
+
+			presetString, err := params.GetString("preset")
+			if err != nil {
+				return nil, fmt.Errorf("visca.preset.create preset error: %w", err)
+			}
+
+			presetTemplate, err := template.New("preset").Parse(presetString)
+			if err != nil {
+				return nil, err
+			}
+
+			return &VISCAPresetCreate{config: processorConfig, Action: actionString, Preset: presetTemplate}, nil
+		},
+	})
+}
+
+type VISCAPresetCreate struct {
+	config config.ProcessorConfig
+	Action string
+	Preset *template.Template
+}
+
+func (vpc *VISCAPresetCreate) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	templateData := wrappedPayload
+
+	preset, err := executeVISCAInt(vpc.Preset, templateData)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("visca.preset.create preset error: %w", err)
+	}
+
+	message, err := visca.Preset(vpc.Action, preset)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("visca.preset.create %w", err)
+	}
+	wrappedPayload.Payload = message
+	return wrappedPayload, nil
+}
+
+func (vpc *VISCAPresetCreate) Type() string {
+	return vpc.config.Type
+}
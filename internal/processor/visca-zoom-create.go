@@ -0,0 +1,117 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/template"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/visca"
+)
+
+func init() {
+	RegisterProcessor(ProcessorRegistration{
+		Type:        "visca.zoom.create",
+		Title:       "Create VISCA Zoom",
+		Description: "Create a visca.Message that drives the zoom at a speed or moves it to a position",
+		ParamsSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"speed": {
+					Title:       "Speed",
+					Description: "zoom speed from -7 (wide) to 7 (tele), 0 stops the zoom",
+					Type:        "string",
+				},
+				"position": {
+					Title:       "Position",
+					Description: "zoom position from 0 to 65535, cameras only use part of the range",
+					Type:        "string",
+				},
+			},
+			OneOf: []*jsonschema.Schema{
+				{Required: []string{"speed"}},
+				{Required: []string{"position"}},
+			},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+		New: func(processorConfig config.ProcessorConfig) (Processor, error) {
+			params := processorConfig.Params
+
+			var speedTemplate *template.Template
+			speedString, err := params.GetString("speed")
+			if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+				return nil, fmt.Errorf("visca.zoom.create speed error: %w", err)
+			}
+			if speedString != "" {
+				speedTemplate, err = template.New("speed").Parse(speedString)
+				if err != nil {
+					return nil, fmt.Errorf("visca.zoom.create speed error: %w", err)
+				}
+			}
+
+			var positionTemplate *template.Template
+			positionString, err := params.GetString("position")
+			if err != nil && !errors.Is(err, config.ErrParamNotFound) {
+				return nil, fmt.Errorf("visca.zoom.create position error: %w", err)
+			}
+			if positionString != "" {
+				positionTemplate, err = template.New("position").Parse(positionString)
+				if err != nil {
+					return nil, fmt.Errorf("visca.zoom.create position error: %w", err)
+				}
+			}
+
+			if (speedTemplate == nil) == (positionTemplate == nil) {
+				return nil, errors.New("visca.zoom.create requires either speed or position")
+			}
+
+			return &VISCAZoomCreate{config: processorConfig, Speed: speedTemplate, Position: positionTemplate}, nil
+		},
+	})
+}
+
+type VISCAZoomCreate struct {
+	config   config.ProcessorConfig
+	Speed    *template.Template
+	Position *template.Template
+}
+
+func (vzc *VISCAZoomCreate) Process(ctx context.Context, wrappedPayload common.WrappedPayload) (common.WrappedPayload, error) {
+	templateData := wrappedPayload
+
+	if vzc.Speed != nil {
+		speed, err := executeVISCAInt(vzc.Speed, templateData)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, fmt.Errorf("visca.zoom.create speed error: %w", err)
+		}
+
+		message, err := visca.ZoomDrive(speed)
+		if err != nil {
+			wrappedPayload.End = true
+			return wrappedPayload, fmt.Errorf("visca.zoom.create %w", err)
+		}
+		wrappedPayload.Payload = message
+		return wrappedPayload, nil
+	}
+
+	position, err := executeVISCAInt(vzc.Position, templateData)
+	if err != nil {
+		wrappedPayload.End = true
+		return wrappedPayload, fmt.Errorf("visca.zoom.create position error: %w", err)
+	}
+
+	if position < 0 || position > 0xffff {
+		wrappedPayload.End = true
+		return wrappedPayload, errors.New("visca.zoom.create position must be between 0 and 65535")
+	}
+	wrappedPayload.Payload = visca.ZoomDirect(uint16(position))
+	return wrappedPayload, nil
+}
+
+func (vzc *VISCAZoomCreate) Type() string {
+	return vzc.config.Type
+}
@@ -0,0 +1,17 @@
+package processor
+
+import (
+	"bytes"
+	"strconv"
+	"text/template"
+)
+
+// executeVISCAInt runs a template from one of the VISCA create processors and parses the result as an integer
+func executeVISCAInt(valueTemplate *template.Template, templateData any) (int, error) {
+	var buffer bytes.Buffer
+	err := valueTemplate.Execute(&buffer, templateData)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(buffer.String())
+}
@@ -0,0 +1,204 @@
+package recording
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/codec"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+)
+
+// Entry is a single line of a recording file
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Source   string    `json:"source"`
+	Encoding string    `json:"encoding"`
+	Payload  any       `json:"payload"`
+}
+
+type Recorder struct {
+	config  config.RecordingConfig
+	file    *os.File
+	encoder *json.Encoder
+	mu      sync.Mutex
+	logger  *slog.Logger
+}
+
+func NewRecorder(recordingConfig config.RecordingConfig) (*Recorder, error) {
+	if recordingConfig.Path == "" {
+		return nil, errors.New("recording path cannot be empty")
+	}
+
+	file, err := os.OpenFile(recordingConfig.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("recording error opening file: %w", err)
+	}
+
+	return &Recorder{
+		config:  recordingConfig,
+		file:    file,
+		encoder: json.NewEncoder(file),
+		logger:  slog.Default().With("component", "recording"),
+	}, nil
+}
+
+// Records reports whether inputs from the source should be recorded
+func (r *Recorder) Records(sourceId string) bool {
+	return len(r.config.Modules) == 0 || slices.Contains(r.config.Modules, sourceId)
+}
+
+func (r *Recorder) Record(sourceId string, payload any) error {
+	encoding, encodedPayload, err := codec.EncodePayload(payload)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return errors.New("recording is closed")
+	}
+	return r.encoder.Encode(Entry{
+		Time:     time.Now(),
+		Source:   sourceId,
+		Encoding: encoding,
+		Payload:  encodedPayload,
+	})
+}
+
+// InputHandler records inputs from the configured modules before handing them to next
+func (r *Recorder) InputHandler(next common.InputHandler) common.InputHandler {
+	return func(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+		if r.Records(sourceId) {
+			err := r.Record(sourceId, payload)
+			if err != nil {
+				r.logger.Warn("unable to record input", "source", sourceId, "error", err)
+			}
+		}
+		return next(ctx, sourceId, payload)
+	}
+}
+
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// Frame is a decoded entry ready to be replayed
+type Frame struct {
+	Offset  time.Duration
+	Source  string
+	Payload any
+}
+
+func Load(path string) ([]Frame, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	frames := []Frame{}
+	var start time.Time
+
+	scanner := bufio.NewScanner(file)
+	//NOTE(jwetzell): allow for large payloads on a single line
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		entry := Entry{}
+		err := json.Unmarshal(line, &entry)
+		if err != nil {
+			return nil, fmt.Errorf("recording line %d error: %w", lineNumber, err)
+		}
+
+		payload, err := codec.DecodePayload(entry.Encoding, entry.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("recording line %d error: %w", lineNumber, err)
+		}
+
+		if len(frames) == 0 {
+			start = entry.Time
+		}
+
+		frames = append(frames, Frame{
+			Offset:  max(entry.Time.Sub(start), 0),
+			Source:  entry.Source,
+			Payload: payload,
+		})
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("recording read error: %w", err)
+	}
+	return frames, nil
+}
+
+type ReplayOptions struct {
+	// Speed scales the time between frames, 1 is real time and 0 sends frames as fast as possible
+	Speed float64
+	Loop  bool
+	// Source overrides the recorded source of every frame when set
+	Source string
+}
+
+// Replay feeds frames into the input handler until the recording ends or the context is done
+func Replay(ctx context.Context, frames []Frame, inputHandler common.InputHandler, options ReplayOptions) error {
+	if len(frames) == 0 {
+		return errors.New("recording has no frames")
+	}
+	if options.Speed < 0 {
+		return errors.New("replay speed cannot be negative")
+	}
+
+	for {
+		start := time.Now()
+		for _, frame := range frames {
+			if options.Speed > 0 {
+				wait := time.Until(start.Add(time.Duration(float64(frame.Offset) / options.Speed)))
+				if wait > 0 {
+					timer := time.NewTimer(wait)
+					select {
+					case <-ctx.Done():
+						timer.Stop()
+						return nil
+					case <-timer.C:
+					}
+				}
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			source := frame.Source
+			if options.Source != "" {
+				source = options.Source
+			}
+			inputHandler(ctx, source, frame.Payload)
+		}
+		if !options.Loop {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package recording_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/osc-go"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/recording"
+)
+
+type replayedInput struct {
+	source  string
+	payload any
+}
+
+type inputCollector struct {
+	inputs []replayedInput
+	mu     sync.Mutex
+}
+
+func (ic *inputCollector) handleInput(ctx context.Context, sourceId string, payload any) (bool, []common.RouteIOError) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	ic.inputs = append(ic.inputs, replayedInput{source: sourceId, payload: payload})
+	return true, nil
+}
+
+func (ic *inputCollector) Inputs() []replayedInput {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	inputs := make([]replayedInput, len(ic.inputs))
+	copy(inputs, ic.inputs)
+	return inputs
+}
+
+func TestRecordAndLoad(t *testing.T) {
+	recordingPath := filepath.Join(t.TempDir(), "recording.jsonl")
+
+	recorder, err := recording.NewRecorder(config.RecordingConfig{
+		Path:    recordingPath,
+		Modules: []string{"udp", "osc"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create recorder: %s", err)
+	}
+
+	collector := &inputCollector{}
+	inputHandler := recorder.InputHandler(collector.handleInput)
+
+	oscMessage := &osc.OSCMessage{
+		Address: "/test",
+		Args: []osc.OSCArg{
+			{Type: "i", Value: int32(1)},
+			{Type: "s", Value: "hi"},
+		},
+	}
+
+	inputHandler(t.Context(), "udp", []byte{0x01, 0x02})
+	inputHandler(t.Context(), "ignored", "not recorded")
+	inputHandler(t.Context(), "osc", oscMessage)
+	inputHandler(t.Context(), "udp", map[string]any{"a": 1})
+
+	if len(collector.Inputs()) != 4 {
+		t.Fatalf("recorder should pass every input through, got %d", len(collector.Inputs()))
+	}
+
+	err = recorder.Close()
+	if err != nil {
+		t.Fatalf("failed to close recorder: %s", err)
+	}
+
+	err = recorder.Record("udp", []byte{0x01})
+	if err == nil {
+		t.Fatalf("closed recorder should not record")
+	}
+
+	frames, err := recording.Load(recordingPath)
+	if err != nil {
+		t.Fatalf("failed to load recording: %s", err)
+	}
+
+	expected := []replayedInput{
+		{source: "udp", payload: []byte{0x01, 0x02}},
+		{source: "osc", payload: oscMessage},
+		{source: "udp", payload: map[string]any{"a": 1.0}},
+	}
+
+	if len(frames) != len(expected) {
+		t.Fatalf("recording should have %d frames, got %d", len(expected), len(frames))
+	}
+
+	for i, frame := range frames {
+		if frame.Source != expected[i].source {
+			t.Fatalf("frame %d source got %s, expected %s", i, frame.Source, expected[i].source)
+		}
+		if !reflect.DeepEqual(frame.Payload, expected[i].payload) {
+			t.Fatalf("frame %d payload got %+v, expected %+v", i, frame.Payload, expected[i].payload)
+		}
+		if i > 0 && frame.Offset < frames[i-1].Offset {
+			t.Fatalf("frame %d offset should not go backwards", i)
+		}
+	}
+}
+
+func TestLoadBadRecording(t *testing.T) {
+	recordingPath := filepath.Join(t.TempDir(), "recording.jsonl")
+	err := os.WriteFile(recordingPath, []byte(`{"time":"2026-01-01T00:00:00Z","source":"udp","encoding":"hex","payload":"zz"}`+"\n"), 0644)
+	if err != nil {
+		t.Fatalf("failed to write recording: %s", err)
+	}
+
+	_, err = recording.Load(recordingPath)
+	if err == nil || !strings.HasPrefix(err.Error(), "recording line 1 error: hex payload error") {
+		t.Fatalf("loading a bad recording should fail on the bad line, got: %v", err)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	frames := []recording.Frame{
+		{Offset: 0, Source: "a", Payload: "1"},
+		{Offset: 100 * time.Millisecond, Source: "b", Payload: "2"},
+	}
+
+	tests := []struct {
+		name        string
+		options     recording.ReplayOptions
+		minDuration time.Duration
+		maxDuration time.Duration
+		sources     []string
+	}{
+		{
+			name:        "real time",
+			options:     recording.ReplayOptions{Speed: 1},
+			minDuration: 100 * time.Millisecond,
+			maxDuration: time.Second,
+			sources:     []string{"a", "b"},
+		},
+		{
+			name:        "double speed",
+			options:     recording.ReplayOptions{Speed: 2},
+			minDuration: 50 * time.Millisecond,
+			maxDuration: 500 * time.Millisecond,
+			sources:     []string{"a", "b"},
+		},
+		{
+			name:        "as fast as possible with source",
+			options:     recording.ReplayOptions{Speed: 0, Source: "replay"},
+			minDuration: 0,
+			maxDuration: 100 * time.Millisecond,
+			sources:     []string{"replay", "replay"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collector := &inputCollector{}
+			start := time.Now()
+			err := recording.Replay(t.Context(), frames, collector.handleInput, test.options)
+			duration := time.Since(start)
+			if err != nil {
+				t.Fatalf("replay failed: %s", err)
+			}
+			if duration < test.minDuration || duration > test.maxDuration {
+				t.Fatalf("replay took %s, expected between %s and %s", duration, test.minDuration, test.maxDuration)
+			}
+			inputs := collector.Inputs()
+			if len(inputs) != len(test.sources) {
+				t.Fatalf("replay sent %d inputs, expected %d", len(inputs), len(test.sources))
+			}
+			for i, input := range inputs {
+				if input.source != test.sources[i] {
+					t.Fatalf("replay input %d source got %s, expected %s", i, input.source, test.sources[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReplayLoop(t *testing.T) {
+	frames := []recording.Frame{
+		{Offset: 0, Source: "a", Payload: "1"},
+		{Offset: 10 * time.Millisecond, Source: "a", Payload: "2"},
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+
+	collector := &inputCollector{}
+	err := recording.Replay(ctx, frames, collector.handleInput, recording.ReplayOptions{Speed: 1, Loop: true})
+	if err != nil {
+		t.Fatalf("replay failed: %s", err)
+	}
+
+	if len(collector.Inputs()) <= len(frames) {
+		t.Fatalf("looped replay should send more than one pass of frames, got %d", len(collector.Inputs()))
+	}
+}
+
+func TestBadReplay(t *testing.T) {
+	collector := &inputCollector{}
+
+	err := recording.Replay(t.Context(), []recording.Frame{}, collector.handleInput, recording.ReplayOptions{Speed: 1})
+	if err == nil || err.Error() != "recording has no frames" {
+		t.Fatalf("replaying an empty recording should fail, got: %v", err)
+	}
+
+	err = recording.Replay(t.Context(), []recording.Frame{{Source: "a"}}, collector.handleInput, recording.ReplayOptions{Speed: -1})
+	if err == nil || err.Error() != "replay speed cannot be negative" {
+		t.Fatalf("replaying with a negative speed should fail, got: %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package route
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jwetzell/showbridge-go/internal/common"
@@ -36,6 +37,21 @@ func NewRoute(config config.RouteConfig) (*Route, error) {
 	return &Route{id: config.Id, input: config.Input, processors: processors}, nil
 }
 
+// JoinGroups hands the groups to every processor that shares state with others
+func (r *Route) JoinGroups(groups *processor.Groups) error {
+	for processorIndex, processorInstance := range r.processors {
+		groupedProcessor, ok := processorInstance.(processor.GroupedProcessor)
+		if !ok {
+			continue
+		}
+		err := groupedProcessor.JoinGroups(groups)
+		if err != nil {
+			return fmt.Errorf("processor[%d] error: %w", processorIndex, err)
+		}
+	}
+	return nil
+}
+
 func (r *Route) Id() string {
 	return r.id
 }
@@ -45,8 +61,12 @@ func (r *Route) Input() string {
 }
 
 func (r *Route) ProcessPayload(ctx context.Context, wrappedPayload common.WrappedPayload) (any, error) {
-	for processorIndex, processor := range r.processors {
-		processedPayload, err := processor.Process(ctx, wrappedPayload)
+	return r.processFrom(ctx, wrappedPayload, 0)
+}
+
+func (r *Route) processFrom(ctx context.Context, wrappedPayload common.WrappedPayload, start int) (any, error) {
+	for processorIndex := start; processorIndex < len(r.processors); processorIndex++ {
+		processedPayload, err := r.processors[processorIndex].Process(ctx, wrappedPayload)
 		if err != nil {
 			return nil, fmt.Errorf("processor[%d] error: %w", processorIndex, err)
 		}
@@ -54,6 +74,23 @@ func (r *Route) ProcessPayload(ctx context.Context, wrappedPayload common.Wrappe
 		if processedPayload.End {
 			return processedPayload.Payload, nil
 		}
+
+		fanOut, ok := processedPayload.Payload.(common.FanOut)
+		if ok {
+			results := []any{}
+			errs := []error{}
+			for _, item := range fanOut {
+				itemPayload := processedPayload
+				itemPayload.Payload = item
+				result, err := r.processFrom(ctx, itemPayload, processorIndex+1)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				results = append(results, result)
+			}
+			return results, errors.Join(errs...)
+		}
 		wrappedPayload = processedPayload
 	}
 
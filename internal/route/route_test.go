@@ -5,8 +5,10 @@ import (
 	"slices"
 	"testing"
 
+	"github.com/jwetzell/osc-go"
 	"github.com/jwetzell/showbridge-go/internal/common"
 	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/oscbundle"
 	"github.com/jwetzell/showbridge-go/internal/route"
 	"github.com/jwetzell/showbridge-go/internal/test"
 )
@@ -193,3 +195,48 @@ func TestRouteBadProcessorConfig(t *testing.T) {
 		t.Fatalf("route error expected creating route with bad processor, got nil")
 	}
 }
+
+func TestRouteFanOut(t *testing.T) {
+	routeConfig := config.RouteConfig{
+		Input: "input",
+		Processors: []config.ProcessorConfig{
+			{Type: "osc.bundle.unpack"},
+			{Type: "osc.message.encode"},
+		},
+	}
+
+	testRoute, err := route.NewRoute(routeConfig)
+	if err != nil {
+		t.Fatalf("route failed to create: %v", err)
+	}
+
+	bundle := &oscbundle.Bundle{
+		TimeTag: oscbundle.Immediately,
+		Contents: []osc.OSCPacket{
+			&osc.OSCMessage{Address: "/one"},
+			&osc.OSCMessage{Address: "/two"},
+		},
+	}
+
+	payload, err := testRoute.ProcessPayload(t.Context(), common.WrappedPayload{Payload: bundle})
+	if err != nil {
+		t.Fatalf("route ProcessPayload returned error: %v", err)
+	}
+
+	payloads, ok := payload.([]any)
+	if !ok {
+		t.Fatalf("fan out payload should be []any got %T", payload)
+	}
+
+	if len(payloads) != 2 {
+		t.Fatalf("fan out should produce 2 payloads got %d", len(payloads))
+	}
+
+	for index, address := range []string{"/one", "/two"} {
+		expected, _ := (&osc.OSCMessage{Address: address}).ToBytes()
+		payloadBytes, ok := common.GetAnyAsByteSlice(payloads[index])
+		if !ok || !slices.Equal(payloadBytes, expected) {
+			t.Fatalf("fan out payload %d wrong. expected: %+v got %+v", index, expected, payloads[index])
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package routetest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+)
+
+type Output struct {
+	Module  string `json:"module"`
+	Topic   string `json:"topic,omitempty"`
+	Payload any    `json:"payload"`
+}
+
+// Recorder stands in for a configured module, it records everything sent to it and keeps key/value data in memory
+// TODO(jwetzell): stand in for database modules
+type Recorder struct {
+	id         string
+	moduleType string
+	outputs    []Output
+	kvData     map[string]any
+	mu         sync.Mutex
+}
+
+func NewRecorder(id string, moduleType string) *Recorder {
+	return &Recorder{
+		id:         id,
+		moduleType: moduleType,
+		kvData:     make(map[string]any),
+	}
+}
+
+func (r *Recorder) Id() string {
+	return r.id
+}
+
+func (r *Recorder) Type() string {
+	return r.moduleType
+}
+
+func (r *Recorder) Start(ctx context.Context, inputHandler common.InputHandler) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (r *Recorder) Stop() {}
+
+func (r *Recorder) Output(ctx context.Context, payload any) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.outputs = append(r.outputs, Output{Module: r.id, Payload: payload})
+	return nil
+}
+
+func (r *Recorder) Publish(ctx context.Context, topic string, payload any) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.outputs = append(r.outputs, Output{Module: r.id, Topic: topic, Payload: payload})
+	return nil
+}
+
+func (r *Recorder) Get(ctx context.Context, key string) (any, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	value, ok := r.kvData[key]
+	if !ok {
+		return nil, nil
+	}
+	return value, nil
+}
+
+func (r *Recorder) Set(ctx context.Context, key string, value any) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.kvData[key] = value
+	return nil
+}
+
+func (r *Recorder) Outputs() []Output {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	outputs := make([]Output, len(r.outputs))
+	copy(outputs, r.outputs)
+	return outputs
+}
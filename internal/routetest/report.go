@@ -0,0 +1,111 @@
+package routetest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func WriteText(w io.Writer, suiteResults []SuiteResult) error {
+	total := 0
+	failed := 0
+	for _, suiteResult := range suiteResults {
+		for _, caseResult := range suiteResult.Cases {
+			total++
+			status := "PASS"
+			if !caseResult.Passed {
+				status = "FAIL"
+				failed++
+			}
+			_, err := fmt.Fprintf(w, "%s %s/%s (%s)\n", status, suiteResult.Name, caseResult.Name, caseResult.Duration)
+			if err != nil {
+				return err
+			}
+			for _, failure := range caseResult.Failures {
+				_, err := fmt.Fprintf(w, "    %s\n", failure)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d passed, %d failed\n", total-failed, failed)
+	return err
+}
+
+func WriteJSON(w io.Writer, suiteResults []SuiteResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(suiteResults)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Tests   int              `xml:"tests,attr"`
+	Failure int              `xml:"failures,attr"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name    string          `xml:"name,attr"`
+	Tests   int             `xml:"tests,attr"`
+	Failure int             `xml:"failures,attr"`
+	Time    string          `xml:"time,attr"`
+	Cases   []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
+
+func WriteJUnit(w io.Writer, suiteResults []SuiteResult) error {
+	testSuites := junitTestSuites{}
+	for _, suiteResult := range suiteResults {
+		testSuite := junitTestSuite{
+			Name:    suiteResult.Name,
+			Tests:   len(suiteResult.Cases),
+			Failure: suiteResult.Failed(),
+			Time:    fmt.Sprintf("%.3f", suiteResult.Duration.Seconds()),
+		}
+		for _, caseResult := range suiteResult.Cases {
+			testCase := junitTestCase{
+				Name:      caseResult.Name,
+				ClassName: suiteResult.Name,
+				Time:      fmt.Sprintf("%.3f", caseResult.Duration.Seconds()),
+			}
+			if !caseResult.Passed {
+				testCase.Failure = &junitFailure{
+					Message:  caseResult.Failures[0],
+					Contents: strings.Join(caseResult.Failures, "\n"),
+				}
+			}
+			testSuite.Cases = append(testSuite.Cases, testCase)
+		}
+		testSuites.Tests += testSuite.Tests
+		testSuites.Failure += testSuite.Failure
+		testSuites.Suites = append(testSuites.Suites, testSuite)
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(testSuites)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
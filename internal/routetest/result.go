@@ -0,0 +1,109 @@
+package routetest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/codec"
+)
+
+type CaseResult struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Failures []string      `json:"failures,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Outputs  []Output      `json:"outputs"`
+	Duration time.Duration `json:"duration"`
+}
+
+type SuiteResult struct {
+	Name     string        `json:"name"`
+	Path     string        `json:"path"`
+	Cases    []CaseResult  `json:"cases"`
+	Duration time.Duration `json:"duration"`
+}
+
+func (sr SuiteResult) Failed() int {
+	failed := 0
+	for _, caseResult := range sr.Cases {
+		if !caseResult.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Check compares what a case actually produced against what it expected and returns a description of each mismatch
+func Check(testCase Case, outputs []Output, err error) []string {
+	failures := []string{}
+
+	if testCase.Error == "" {
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("unexpected error: %s", err))
+		}
+	} else {
+		if err == nil {
+			failures = append(failures, fmt.Sprintf("expected error containing %q but routes succeeded", testCase.Error))
+		} else if !strings.Contains(err.Error(), testCase.Error) {
+			failures = append(failures, fmt.Sprintf("expected error containing %q, got: %s", testCase.Error, err))
+		}
+		//NOTE(jwetzell): outputs are only checked for error cases when they are listed
+		if testCase.Outputs == nil {
+			return failures
+		}
+	}
+
+	return append(failures, checkOutputs(testCase.Outputs, outputs)...)
+}
+
+func checkOutputs(expectedOutputs []ExpectedOutput, outputs []Output) []string {
+	failures := []string{}
+
+	expectedByModule := map[string][]ExpectedOutput{}
+	moduleIds := []string{}
+	for _, expectedOutput := range expectedOutputs {
+		if _, ok := expectedByModule[expectedOutput.Module]; !ok {
+			moduleIds = append(moduleIds, expectedOutput.Module)
+		}
+		expectedByModule[expectedOutput.Module] = append(expectedByModule[expectedOutput.Module], expectedOutput)
+	}
+
+	actualByModule := map[string][]Output{}
+	for _, output := range outputs {
+		if !slices.Contains(moduleIds, output.Module) {
+			moduleIds = append(moduleIds, output.Module)
+		}
+		actualByModule[output.Module] = append(actualByModule[output.Module], output)
+	}
+
+	//NOTE(jwetzell): routes run concurrently so order is only checked per module
+	for _, moduleId := range moduleIds {
+		expected := expectedByModule[moduleId]
+		actual := actualByModule[moduleId]
+
+		if len(expected) != len(actual) {
+			failures = append(failures, fmt.Sprintf("module %s expected %d outputs, got %d", moduleId, len(expected), len(actual)))
+		}
+
+		for outputIndex := range min(len(expected), len(actual)) {
+			expectedPayload, err := codec.DecodePayload(expected[outputIndex].Encoding, expected[outputIndex].Payload)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("module %s output[%d] expected payload error: %s", moduleId, outputIndex, err))
+				continue
+			}
+			if expected[outputIndex].Topic != actual[outputIndex].Topic {
+				failures = append(failures, fmt.Sprintf("module %s output[%d] expected topic %q, got %q", moduleId, outputIndex, expected[outputIndex].Topic, actual[outputIndex].Topic))
+			}
+			if !codec.PayloadsEqual(expectedPayload, actual[outputIndex].Payload) {
+				failures = append(failures, fmt.Sprintf("module %s output[%d] expected %s, got %s", moduleId, outputIndex, codec.FormatPayload(expectedPayload), codec.FormatPayload(actual[outputIndex].Payload)))
+			}
+		}
+
+		for outputIndex := len(expected); outputIndex < len(actual); outputIndex++ {
+			failures = append(failures, fmt.Sprintf("module %s unexpected output[%d]: %s", moduleId, outputIndex, codec.FormatPayload(actual[outputIndex].Payload)))
+		}
+	}
+	return failures
+}
@@ -0,0 +1,163 @@
+package routetest_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/routetest"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		testCase routetest.Case
+		outputs  []routetest.Output
+		err      error
+		failures int
+	}{
+		{
+			name: "matching outputs",
+			testCase: routetest.Case{
+				Outputs: []routetest.ExpectedOutput{
+					{Module: "a", Encoding: "string", Payload: "1"},
+					{Module: "b", Encoding: "string", Payload: "2"},
+					{Module: "a", Encoding: "string", Payload: "3"},
+				},
+			},
+			outputs: []routetest.Output{
+				{Module: "b", Payload: "2"},
+				{Module: "a", Payload: "1"},
+				{Module: "a", Payload: "3"},
+			},
+			failures: 0,
+		},
+		{
+			name:     "unexpected output",
+			testCase: routetest.Case{},
+			outputs:  []routetest.Output{{Module: "a", Payload: "1"}},
+			failures: 2,
+		},
+		{
+			name: "missing output",
+			testCase: routetest.Case{
+				Outputs: []routetest.ExpectedOutput{{Module: "a", Encoding: "string", Payload: "1"}},
+			},
+			failures: 1,
+		},
+		{
+			name: "wrong topic",
+			testCase: routetest.Case{
+				Outputs: []routetest.ExpectedOutput{{Module: "a", Topic: "x", Encoding: "string", Payload: "1"}},
+			},
+			outputs:  []routetest.Output{{Module: "a", Topic: "y", Payload: "1"}},
+			failures: 1,
+		},
+		{
+			name:     "unexpected error",
+			testCase: routetest.Case{},
+			err:      errors.New("boom"),
+			failures: 1,
+		},
+		{
+			name:     "expected error",
+			testCase: routetest.Case{Error: "boom"},
+			outputs:  []routetest.Output{{Module: "a", Payload: "1"}},
+			err:      errors.New("route[0]: boom"),
+			failures: 0,
+		},
+		{
+			name:     "expected error missing",
+			testCase: routetest.Case{Error: "boom"},
+			failures: 1,
+		},
+		{
+			name:     "expected error with outputs listed",
+			testCase: routetest.Case{Error: "boom", Outputs: []routetest.ExpectedOutput{}},
+			outputs:  []routetest.Output{{Module: "a", Payload: "1"}},
+			err:      errors.New("boom"),
+			failures: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			failures := routetest.Check(test.testCase, test.outputs, test.err)
+			if len(failures) != test.failures {
+				t.Fatalf("Check got %d failures %v, expected %d", len(failures), failures, test.failures)
+			}
+		})
+	}
+}
+
+func TestReadSuite(t *testing.T) {
+	suitePath := filepath.Join(t.TempDir(), "osc.test.yaml")
+	err := os.WriteFile(suitePath, []byte(`
+tests:
+  - input: udp
+    encoding: hex
+    payload: "0102"
+    outputs:
+      - module: out
+        encoding: string
+        payload: hello
+  - name: by route
+    route: a-route
+    payload: {}
+`), 0644)
+	if err != nil {
+		t.Fatalf("failed to write suite: %s", err)
+	}
+
+	suite, err := routetest.ReadSuite(suitePath)
+	if err != nil {
+		t.Fatalf("ReadSuite failed: %s", err)
+	}
+
+	if suite.Name != "osc.test" {
+		t.Fatalf("suite name should default to the file name, got %s", suite.Name)
+	}
+	if len(suite.Tests) != 2 {
+		t.Fatalf("suite should have 2 tests, got %d", len(suite.Tests))
+	}
+	if suite.Tests[0].Name != "tests[0]" || suite.Tests[1].Name != "by route" {
+		t.Fatalf("suite test names did not match, got %s and %s", suite.Tests[0].Name, suite.Tests[1].Name)
+	}
+
+	err = os.WriteFile(suitePath, []byte("tests:\n  - payload: 1\n"), 0644)
+	if err != nil {
+		t.Fatalf("failed to write suite: %s", err)
+	}
+
+	_, err = routetest.ReadSuite(suitePath)
+	if err == nil || !strings.Contains(err.Error(), "test must name an input or a route") {
+		t.Fatalf("ReadSuite should reject a test without an input or route, got: %v", err)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buffer bytes.Buffer
+	err := routetest.WriteJUnit(&buffer, []routetest.SuiteResult{
+		{
+			Name: "suite",
+			Cases: []routetest.CaseResult{
+				{Name: "good", Passed: true},
+				{Name: "bad", Passed: false, Failures: []string{"module a expected 1 outputs, got 0"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("WriteJUnit failed: %s", err)
+	}
+
+	output := buffer.String()
+	if !strings.Contains(output, `<testsuites tests="2" failures="1">`) {
+		t.Fatalf("WriteJUnit output missing testsuites totals: %s", output)
+	}
+	if !strings.Contains(output, `<failure message="module a expected 1 outputs, got 0">`) {
+		t.Fatalf("WriteJUnit output missing failure: %s", output)
+	}
+}
@@ -0,0 +1,76 @@
+package routetest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+type Suite struct {
+	Name  string `json:"name"`
+	Path  string `json:"-"`
+	Tests []Case `json:"tests"`
+}
+
+// Case feeds a single payload into the routes for an input (or a single route) and checks what the routes send to modules
+type Case struct {
+	Name     string           `json:"name"`
+	Input    string           `json:"input,omitempty"`
+	Route    string           `json:"route,omitempty"`
+	Encoding string           `json:"encoding,omitempty"`
+	Payload  any              `json:"payload"`
+	Outputs  []ExpectedOutput `json:"outputs,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+type ExpectedOutput struct {
+	Module   string `json:"module"`
+	Topic    string `json:"topic,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+	Payload  any    `json:"payload"`
+}
+
+func ReadSuite(path string) (Suite, error) {
+	suiteBytes, err := os.ReadFile(path)
+	if err != nil {
+		return Suite{}, err
+	}
+
+	suite := Suite{}
+	err = yaml.Unmarshal(suiteBytes, &suite)
+	if err != nil {
+		return Suite{}, fmt.Errorf("failed to parse test file %s: %w", path, err)
+	}
+
+	suite.Path = path
+	if suite.Name == "" {
+		suite.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	for caseIndex, testCase := range suite.Tests {
+		err := testCase.validate()
+		if err != nil {
+			return Suite{}, fmt.Errorf("test file %s tests[%d] error: %w", path, caseIndex, err)
+		}
+		if testCase.Name == "" {
+			suite.Tests[caseIndex].Name = fmt.Sprintf("tests[%d]", caseIndex)
+		}
+	}
+	return suite, nil
+}
+
+func (c Case) validate() error {
+	if c.Input == "" && c.Route == "" {
+		return errors.New("test must name an input or a route")
+	}
+	for outputIndex, output := range c.Outputs {
+		if output.Module == "" {
+			return fmt.Errorf("outputs[%d] must name a module", outputIndex)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// Package rtpmidi encodes and decodes AppleMIDI session packets and RTP-MIDI (RFC 6295) payloads
+package rtpmidi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	ProtocolVersion = 2
+	// PayloadType is the dynamic RTP payload type AppleMIDI uses for MIDI
+	PayloadType = 0x61
+	// ClockRate is the RTP timestamp rate, AppleMIDI counts in 100 microsecond units
+	ClockRate = 10000
+)
+
+const (
+	CommandInvitation       = "IN"
+	CommandAccept           = "OK"
+	CommandReject           = "NO"
+	CommandBye              = "BY"
+	CommandClockSync        = "CK"
+	CommandReceiverFeedback = "RS"
+)
+
+var controlSignature = []byte{0xff, 0xff}
+
+// Packet is anything that can be sent on an AppleMIDI control or data port
+type Packet interface {
+	MarshalBinary() ([]byte, error)
+}
+
+// Invitation covers IN, OK, NO and BY which share one layout
+type Invitation struct {
+	Command string
+	Version uint32
+	Token   uint32
+	SSRC    uint32
+	Name    string
+}
+
+type ClockSync struct {
+	SSRC       uint32
+	Count      uint8
+	Timestamps [3]uint64
+}
+
+type ReceiverFeedback struct {
+	SSRC     uint32
+	Sequence uint16
+}
+
+func (i *Invitation) MarshalBinary() ([]byte, error) {
+	switch i.Command {
+	case CommandInvitation, CommandAccept, CommandReject, CommandBye:
+	default:
+		return nil, fmt.Errorf("AppleMIDI invitation command must be IN, OK, NO or BY, got %s", i.Command)
+	}
+
+	data := make([]byte, 0, 17+len(i.Name))
+	data = append(data, controlSignature...)
+	data = append(data, i.Command...)
+	data = binary.BigEndian.AppendUint32(data, i.Version)
+	data = binary.BigEndian.AppendUint32(data, i.Token)
+	data = binary.BigEndian.AppendUint32(data, i.SSRC)
+	if i.Command != CommandBye {
+		data = append(data, i.Name...)
+		data = append(data, 0x00)
+	}
+	return data, nil
+}
+
+func (c *ClockSync) MarshalBinary() ([]byte, error) {
+	if c.Count > 2 {
+		return nil, errors.New("AppleMIDI clock sync count must be 0, 1 or 2")
+	}
+	data := make([]byte, 0, 36)
+	data = append(data, controlSignature...)
+	data = append(data, CommandClockSync...)
+	data = binary.BigEndian.AppendUint32(data, c.SSRC)
+	data = append(data, c.Count, 0x00, 0x00, 0x00)
+	for _, timestamp := range c.Timestamps {
+		data = binary.BigEndian.AppendUint64(data, timestamp)
+	}
+	return data, nil
+}
+
+func (rf *ReceiverFeedback) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 12)
+	data = append(data, controlSignature...)
+	data = append(data, CommandReceiverFeedback...)
+	data = binary.BigEndian.AppendUint32(data, rf.SSRC)
+	data = binary.BigEndian.AppendUint16(data, rf.Sequence)
+	data = append(data, 0x00, 0x00)
+	return data, nil
+}
+
+func IsControl(data []byte) bool {
+	return len(data) >= 4 && data[0] == 0xff && data[1] == 0xff
+}
+
+// DecodeControl decodes an AppleMIDI session packet into an *Invitation, *ClockSync or *ReceiverFeedback
+func DecodeControl(data []byte) (Packet, error) {
+	if !IsControl(data) {
+		return nil, errors.New("AppleMIDI packet must start with 0xffff")
+	}
+
+	command := string(data[2:4])
+	switch command {
+	case CommandInvitation, CommandAccept, CommandReject, CommandBye:
+		if len(data) < 16 {
+			return nil, fmt.Errorf("AppleMIDI %s packet is too short", command)
+		}
+		invitation := &Invitation{
+			Command: command,
+			Version: binary.BigEndian.Uint32(data[4:8]),
+			Token:   binary.BigEndian.Uint32(data[8:12]),
+			SSRC:    binary.BigEndian.Uint32(data[12:16]),
+		}
+		name := data[16:]
+		if end := bytes.IndexByte(name, 0x00); end >= 0 {
+			name = name[:end]
+		}
+		invitation.Name = string(name)
+		return invitation, nil
+	case CommandClockSync:
+		if len(data) < 36 {
+			return nil, errors.New("AppleMIDI CK packet is too short")
+		}
+		clockSync := &ClockSync{
+			SSRC:  binary.BigEndian.Uint32(data[4:8]),
+			Count: data[8],
+		}
+		for index := range clockSync.Timestamps {
+			clockSync.Timestamps[index] = binary.BigEndian.Uint64(data[12+index*8 : 20+index*8])
+		}
+		return clockSync, nil
+	case CommandReceiverFeedback:
+		if len(data) < 10 {
+			return nil, errors.New("AppleMIDI RS packet is too short")
+		}
+		return &ReceiverFeedback{
+			SSRC:     binary.BigEndian.Uint32(data[4:8]),
+			Sequence: binary.BigEndian.Uint16(data[8:10]),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported AppleMIDI command %q", command)
+	}
+}
@@ -0,0 +1,558 @@
+package rtpmidi
+
+import (
+	"encoding/binary"
+	"errors"
+	"slices"
+)
+
+// Journal is the RTP-MIDI recovery journal, only the channel chapters P (program), C (controllers) and N (notes) are produced and understood
+type Journal struct {
+	SinglePacketLoss bool
+	Checkpoint       uint16
+	Channels         []ChannelJournal
+}
+
+type ChannelJournal struct {
+	Channel     uint8
+	Program     *ProgramLog
+	Controllers []ControllerLog
+	Notes       []NoteLog
+	NoteOffs    []uint8
+}
+
+type ProgramLog struct {
+	Program uint8
+	Bank    bool
+	BankMSB uint8
+	BankLSB uint8
+}
+
+type ControllerLog struct {
+	Number uint8
+	Value  uint8
+}
+
+type NoteLog struct {
+	Note     uint8
+	Velocity uint8
+}
+
+const (
+	chapterP = 0x80
+	chapterC = 0x40
+	chapterM = 0x20
+	chapterW = 0x10
+	chapterN = 0x08
+)
+
+func (j *Journal) MarshalBinary() ([]byte, error) {
+	if len(j.Channels) > 16 {
+		return nil, errors.New("RTP-MIDI journal can have at most 16 channel journals")
+	}
+
+	var header byte
+	if j.SinglePacketLoss {
+		header |= 0x80
+	}
+	if len(j.Channels) > 0 {
+		header |= 0x20 | byte(len(j.Channels)-1)
+	}
+
+	data := []byte{header}
+	data = binary.BigEndian.AppendUint16(data, j.Checkpoint)
+
+	for _, channelJournal := range j.Channels {
+		channelBytes, err := channelJournal.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, channelBytes...)
+	}
+	return data, nil
+}
+
+func (cj *ChannelJournal) MarshalBinary() ([]byte, error) {
+	if cj.Channel > 15 {
+		return nil, errors.New("RTP-MIDI channel journal channel must be between 0 and 15")
+	}
+
+	var flags byte
+	chapters := []byte{}
+
+	if cj.Program != nil {
+		flags |= chapterP
+		bank := byte(0)
+		if cj.Program.Bank {
+			bank = 0x80
+		}
+		chapters = append(chapters, cj.Program.Program&0x7f, bank|cj.Program.BankMSB&0x7f, cj.Program.BankLSB&0x7f)
+	}
+
+	if len(cj.Controllers) > 0 {
+		if len(cj.Controllers) > 128 {
+			return nil, errors.New("RTP-MIDI chapter C can have at most 128 controllers")
+		}
+		flags |= chapterC
+		chapters = append(chapters, byte(len(cj.Controllers)-1))
+		for _, controller := range cj.Controllers {
+			chapters = append(chapters, controller.Number&0x7f, controller.Value&0x7f)
+		}
+	}
+
+	if len(cj.Notes) > 0 || len(cj.NoteOffs) > 0 {
+		if len(cj.Notes) > 127 {
+			return nil, errors.New("RTP-MIDI chapter N can have at most 127 note logs")
+		}
+		flags |= chapterN
+
+		//NOTE(jwetzell): LOW=15 HIGH=0 signals that no offbit octets follow
+		low, high := byte(15), byte(0)
+		if len(cj.NoteOffs) > 0 {
+			for _, note := range cj.NoteOffs {
+				octet := (note & 0x7f) / 8
+				low = min(low, octet)
+				high = max(high, octet)
+			}
+		}
+
+		chapters = append(chapters, byte(len(cj.Notes)), low<<4|high)
+		for _, note := range cj.Notes {
+			chapters = append(chapters, note.Note&0x7f, 0x80|note.Velocity&0x7f)
+		}
+		if low <= high {
+			offbits := make([]byte, high-low+1)
+			for _, note := range cj.NoteOffs {
+				note &= 0x7f
+				offbits[note/8-low] |= 0x80 >> (note % 8)
+			}
+			chapters = append(chapters, offbits...)
+		}
+	}
+
+	length := 3 + len(chapters)
+	if length > 0x3ff {
+		return nil, errors.New("RTP-MIDI channel journal is too long")
+	}
+
+	data := []byte{cj.Channel<<3 | byte(length>>8), byte(length), flags}
+	return append(data, chapters...), nil
+}
+
+func DecodeJournal(data []byte) (*Journal, error) {
+	if len(data) < 3 {
+		return nil, errors.New("RTP-MIDI journal is too short")
+	}
+
+	journal := &Journal{
+		SinglePacketLoss: data[0]&0x80 != 0,
+		Checkpoint:       binary.BigEndian.Uint16(data[1:3]),
+	}
+	hasSystem := data[0]&0x40 != 0
+	hasChannels := data[0]&0x20 != 0
+	totalChannels := int(data[0]&0x0f) + 1
+	offset := 3
+
+	//NOTE(jwetzell): the system journal is skipped, its header carries its own length
+	if hasSystem {
+		if offset+2 > len(data) {
+			return nil, errors.New("RTP-MIDI system journal is truncated")
+		}
+		length := int(data[offset]&0x03)<<8 | int(data[offset+1])
+		if length < 2 || offset+length > len(data) {
+			return nil, errors.New("RTP-MIDI system journal has an invalid length")
+		}
+		offset += length
+	}
+
+	if !hasChannels {
+		return journal, nil
+	}
+
+	for range totalChannels {
+		if offset+3 > len(data) {
+			return nil, errors.New("RTP-MIDI channel journal is truncated")
+		}
+		length := int(data[offset]&0x03)<<8 | int(data[offset+1])
+		if length < 3 || offset+length > len(data) {
+			return nil, errors.New("RTP-MIDI channel journal has an invalid length")
+		}
+		channelJournal, err := decodeChannelJournal(data[offset : offset+length])
+		if err != nil {
+			return nil, err
+		}
+		journal.Channels = append(journal.Channels, channelJournal)
+		offset += length
+	}
+	return journal, nil
+}
+
+func decodeChannelJournal(data []byte) (ChannelJournal, error) {
+	channelJournal := ChannelJournal{
+		Channel: (data[0] >> 3) & 0x0f,
+	}
+	flags := data[2]
+	chapters := data[3:]
+	offset := 0
+
+	truncated := errors.New("RTP-MIDI channel journal chapter is truncated")
+
+	if flags&chapterP != 0 {
+		if offset+3 > len(chapters) {
+			return channelJournal, truncated
+		}
+		channelJournal.Program = &ProgramLog{
+			Program: chapters[offset] & 0x7f,
+			Bank:    chapters[offset+1]&0x80 != 0,
+			BankMSB: chapters[offset+1] & 0x7f,
+			BankLSB: chapters[offset+2] & 0x7f,
+		}
+		offset += 3
+	}
+
+	if flags&chapterC != 0 {
+		if offset+1 > len(chapters) {
+			return channelJournal, truncated
+		}
+		count := int(chapters[offset]&0x7f) + 1
+		offset++
+		if offset+count*2 > len(chapters) {
+			return channelJournal, truncated
+		}
+		for range count {
+			//NOTE(jwetzell): entries using the alternate (toggle/count) encoding are skipped
+			if chapters[offset+1]&0x80 == 0 {
+				channelJournal.Controllers = append(channelJournal.Controllers, ControllerLog{
+					Number: chapters[offset] & 0x7f,
+					Value:  chapters[offset+1] & 0x7f,
+				})
+			}
+			offset += 2
+		}
+	}
+
+	if flags&chapterM != 0 {
+		if offset+2 > len(chapters) {
+			return channelJournal, truncated
+		}
+		length := int(chapters[offset]&0x03)<<8 | int(chapters[offset+1])
+		if length < 2 || offset+length > len(chapters) {
+			return channelJournal, truncated
+		}
+		offset += length
+	}
+
+	if flags&chapterW != 0 {
+		offset += 2
+	}
+
+	if flags&chapterN != 0 {
+		if offset+2 > len(chapters) {
+			return channelJournal, truncated
+		}
+		count := int(chapters[offset] & 0x7f)
+		low := chapters[offset+1] >> 4
+		high := chapters[offset+1] & 0x0f
+		offset += 2
+		if count == 127 && low == 15 && high == 1 {
+			count = 128
+		}
+		if offset+count*2 > len(chapters) {
+			return channelJournal, truncated
+		}
+		for range count {
+			channelJournal.Notes = append(channelJournal.Notes, NoteLog{
+				Note:     chapters[offset] & 0x7f,
+				Velocity: chapters[offset+1] & 0x7f,
+			})
+			offset += 2
+		}
+		if low <= high {
+			if offset+int(high-low)+1 > len(chapters) {
+				return channelJournal, truncated
+			}
+			for octet := low; octet <= high; octet++ {
+				bits := chapters[offset]
+				for bit := range uint8(8) {
+					if bits&(0x80>>bit) != 0 {
+						channelJournal.NoteOffs = append(channelJournal.NoteOffs, octet*8+bit)
+					}
+				}
+				offset++
+			}
+		}
+	}
+
+	return channelJournal, nil
+}
+
+type channelHistory struct {
+	program     *ProgramLog
+	controllers [128]int16
+	notes       [128]int16
+}
+
+func newChannelHistory() *channelHistory {
+	history := &channelHistory{}
+	for index := range 128 {
+		history.controllers[index] = -1
+		history.notes[index] = -1
+	}
+	return history
+}
+
+// apply records a channel message, notes hold -1 when untouched, 0 when off and the velocity when on
+func (ch *channelHistory) apply(message []byte) {
+	switch message[0] & 0xf0 {
+	case 0x80:
+		if len(message) >= 3 {
+			ch.notes[message[1]&0x7f] = 0
+		}
+	case 0x90:
+		if len(message) >= 3 {
+			ch.notes[message[1]&0x7f] = int16(message[2] & 0x7f)
+		}
+	case 0xb0:
+		if len(message) >= 3 {
+			ch.controllers[message[1]&0x7f] = int16(message[2] & 0x7f)
+		}
+	case 0xc0:
+		if len(message) >= 2 {
+			program := &ProgramLog{Program: message[1] & 0x7f}
+			if ch.controllers[0] >= 0 || ch.controllers[32] >= 0 {
+				program.Bank = true
+				program.BankMSB = uint8(max(ch.controllers[0], 0))
+				program.BankLSB = uint8(max(ch.controllers[32], 0))
+			}
+			ch.program = program
+		}
+	}
+}
+
+func (ch *channelHistory) journal(channel uint8) (ChannelJournal, bool) {
+	channelJournal := ChannelJournal{Channel: channel, Program: ch.program}
+	for number, value := range ch.controllers {
+		if value >= 0 {
+			channelJournal.Controllers = append(channelJournal.Controllers, ControllerLog{Number: uint8(number), Value: uint8(value)})
+		}
+	}
+	for note, velocity := range ch.notes {
+		if velocity > 0 && len(channelJournal.Notes) < 127 {
+			channelJournal.Notes = append(channelJournal.Notes, NoteLog{Note: uint8(note), Velocity: uint8(velocity)})
+		} else if velocity == 0 {
+			channelJournal.NoteOffs = append(channelJournal.NoteOffs, uint8(note))
+		}
+	}
+	empty := channelJournal.Program == nil && len(channelJournal.Controllers) == 0 && len(channelJournal.Notes) == 0 && len(channelJournal.NoteOffs) == 0
+	return channelJournal, !empty
+}
+
+type sentPacket struct {
+	sequence uint16
+	messages [][]byte
+}
+
+// Sender numbers outgoing packets and keeps the history needed to build a recovery journal until the receiver acknowledges it
+type Sender struct {
+	SSRC     uint32
+	sequence uint16
+	sent     []sentPacket
+}
+
+func NewSender(ssrc uint32, sequence uint16) *Sender {
+	return &Sender{SSRC: ssrc, sequence: sequence}
+}
+
+// Packet builds the next packet for the given messages with a journal covering every unacknowledged packet
+func (s *Sender) Packet(messages [][]byte, timestamp uint32) *MIDIPacket {
+	packet := &MIDIPacket{
+		Sequence:  s.sequence,
+		Timestamp: timestamp,
+		SSRC:      s.SSRC,
+		Journal:   s.Journal(),
+	}
+	for _, message := range messages {
+		packet.Commands = append(packet.Commands, Command{Message: message})
+	}
+	s.sent = append(s.sent, sentPacket{sequence: s.sequence, messages: messages})
+	s.sequence++
+	return packet
+}
+
+// Ack drops history up to and including the sequence number from a receiver feedback packet
+func (s *Sender) Ack(sequence uint16) {
+	s.sent = slices.DeleteFunc(s.sent, func(sent sentPacket) bool {
+		return int16(sequence-sent.sequence) >= 0
+	})
+}
+
+func (s *Sender) Journal() *Journal {
+	if len(s.sent) == 0 {
+		return nil
+	}
+
+	channels := map[uint8]*channelHistory{}
+	for _, sent := range s.sent {
+		for _, message := range sent.messages {
+			if len(message) == 0 || message[0] < 0x80 || message[0] >= 0xf0 {
+				continue
+			}
+			channel := message[0] & 0x0f
+			history, ok := channels[channel]
+			if !ok {
+				history = newChannelHistory()
+				channels[channel] = history
+			}
+			history.apply(message)
+		}
+	}
+
+	journal := &Journal{
+		SinglePacketLoss: len(s.sent) == 1,
+		Checkpoint:       s.sent[0].sequence,
+	}
+	for channel := range uint8(16) {
+		history, ok := channels[channel]
+		if !ok {
+			continue
+		}
+		channelJournal, ok := history.journal(channel)
+		if ok {
+			journal.Channels = append(journal.Channels, channelJournal)
+		}
+	}
+	return journal
+}
+
+type channelState struct {
+	program     int16
+	bankMSB     int16
+	bankLSB     int16
+	controllers [128]int16
+	notes       [128]bool
+}
+
+// Receiver tracks incoming sequence numbers and channel state so a recovery journal can repair lost packets
+type Receiver struct {
+	started  bool
+	expected uint16
+	channels [16]*channelState
+}
+
+func NewReceiver() *Receiver {
+	receiver := &Receiver{}
+	for index := range receiver.channels {
+		state := &channelState{program: -1, bankMSB: -1, bankLSB: -1}
+		for controller := range state.controllers {
+			state.controllers[controller] = -1
+		}
+		receiver.channels[index] = state
+	}
+	return receiver
+}
+
+// Receive returns the messages to play for a packet, messages recovered from the journal come first when packets were lost
+func (r *Receiver) Receive(packet *MIDIPacket) (messages [][]byte, lost bool) {
+	if r.started {
+		gap := int16(packet.Sequence - r.expected)
+		if gap < 0 {
+			//NOTE(jwetzell): late or duplicate packet, everything in it has already been recovered or played
+			return nil, false
+		}
+		lost = gap > 0
+	}
+	r.started = true
+	r.expected = packet.Sequence + 1
+
+	if lost && packet.Journal != nil {
+		messages = append(messages, r.Recover(packet.Journal)...)
+	}
+
+	for _, command := range packet.Commands {
+		r.Apply(command.Message)
+		messages = append(messages, command.Message)
+	}
+	return messages, lost
+}
+
+func (r *Receiver) Sequence() uint16 {
+	return r.expected - 1
+}
+
+func (r *Receiver) Apply(message []byte) {
+	if len(message) < 2 || message[0] < 0x80 || message[0] >= 0xf0 {
+		return
+	}
+	state := r.channels[message[0]&0x0f]
+	switch message[0] & 0xf0 {
+	case 0x80:
+		if len(message) >= 3 {
+			state.notes[message[1]&0x7f] = false
+		}
+	case 0x90:
+		if len(message) >= 3 {
+			state.notes[message[1]&0x7f] = message[2] > 0
+		}
+	case 0xb0:
+		if len(message) >= 3 {
+			number := message[1] & 0x7f
+			state.controllers[number] = int16(message[2] & 0x7f)
+			switch number {
+			case 0:
+				state.bankMSB = int16(message[2] & 0x7f)
+			case 32:
+				state.bankLSB = int16(message[2] & 0x7f)
+			}
+		}
+	case 0xc0:
+		state.program = int16(message[1] & 0x7f)
+	}
+}
+
+// Recover compares a journal against the current state and returns the messages needed to catch up
+func (r *Receiver) Recover(journal *Journal) [][]byte {
+	messages := [][]byte{}
+	emit := func(message []byte) {
+		r.Apply(message)
+		messages = append(messages, message)
+	}
+
+	for _, channelJournal := range journal.Channels {
+		channel := channelJournal.Channel & 0x0f
+		state := r.channels[channel]
+
+		if channelJournal.Program != nil {
+			program := channelJournal.Program
+			if program.Bank {
+				if state.bankMSB != int16(program.BankMSB) {
+					emit([]byte{0xb0 | channel, 0, program.BankMSB})
+				}
+				if state.bankLSB != int16(program.BankLSB) {
+					emit([]byte{0xb0 | channel, 32, program.BankLSB})
+				}
+			}
+			if state.program != int16(program.Program) {
+				emit([]byte{0xc0 | channel, program.Program})
+			}
+		}
+
+		for _, controller := range channelJournal.Controllers {
+			if state.controllers[controller.Number&0x7f] != int16(controller.Value) {
+				emit([]byte{0xb0 | channel, controller.Number & 0x7f, controller.Value & 0x7f})
+			}
+		}
+
+		for _, note := range channelJournal.NoteOffs {
+			if state.notes[note&0x7f] {
+				emit([]byte{0x80 | channel, note & 0x7f, 0})
+			}
+		}
+
+		for _, note := range channelJournal.Notes {
+			if note.Velocity > 0 && !state.notes[note.Note&0x7f] {
+				emit([]byte{0x90 | channel, note.Note & 0x7f, note.Velocity & 0x7f})
+			}
+		}
+	}
+	return messages
+}
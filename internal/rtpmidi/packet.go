@@ -0,0 +1,226 @@
+package rtpmidi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Command is a single MIDI message from the command section along with its delta time in RTP clock ticks
+type Command struct {
+	Delta   uint32
+	Message []byte
+}
+
+type MIDIPacket struct {
+	Sequence  uint16
+	Timestamp uint32
+	SSRC      uint32
+	Commands  []Command
+	Journal   *Journal
+}
+
+func (mp *MIDIPacket) MarshalBinary() ([]byte, error) {
+	list := []byte{}
+	for index, command := range mp.Commands {
+		if len(command.Message) == 0 {
+			return nil, errors.New("RTP-MIDI command must not be empty")
+		}
+		if command.Message[0] < 0x80 {
+			return nil, errors.New("RTP-MIDI command must start with a status byte")
+		}
+		if index > 0 || command.Delta > 0 {
+			list = appendDelta(list, command.Delta)
+		}
+		list = append(list, command.Message...)
+	}
+
+	if len(list) > 0x0fff {
+		return nil, errors.New("RTP-MIDI command section is too long")
+	}
+
+	data := make([]byte, 0, 14+len(list))
+	data = append(data, 0x80, PayloadType)
+	data = binary.BigEndian.AppendUint16(data, mp.Sequence)
+	data = binary.BigEndian.AppendUint32(data, mp.Timestamp)
+	data = binary.BigEndian.AppendUint32(data, mp.SSRC)
+
+	var flags byte
+	if mp.Journal != nil {
+		flags |= 0x40
+	}
+	if len(mp.Commands) > 0 && mp.Commands[0].Delta > 0 {
+		flags |= 0x20
+	}
+
+	if len(list) > 0x0f {
+		data = append(data, 0x80|flags|byte(len(list)>>8), byte(len(list)))
+	} else {
+		data = append(data, flags|byte(len(list)))
+	}
+	data = append(data, list...)
+
+	if mp.Journal != nil {
+		journalBytes, err := mp.Journal.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, journalBytes...)
+	}
+	return data, nil
+}
+
+func DecodeMIDIPacket(data []byte) (*MIDIPacket, error) {
+	if len(data) < 13 {
+		return nil, errors.New("RTP-MIDI packet is too short")
+	}
+	if data[0]>>6 != 2 {
+		return nil, errors.New("RTP-MIDI packet must be RTP version 2")
+	}
+	if data[1]&0x7f != PayloadType {
+		return nil, fmt.Errorf("RTP-MIDI packet has unexpected payload type %d", data[1]&0x7f)
+	}
+
+	packet := &MIDIPacket{
+		Sequence:  binary.BigEndian.Uint16(data[2:4]),
+		Timestamp: binary.BigEndian.Uint32(data[4:8]),
+		SSRC:      binary.BigEndian.Uint32(data[8:12]),
+	}
+
+	offset := 12 + int(data[0]&0x0f)*4
+	if offset >= len(data) {
+		return nil, errors.New("RTP-MIDI packet is missing the command section")
+	}
+
+	header := data[offset]
+	hasJournal := header&0x40 != 0
+	firstDelta := header&0x20 != 0
+	listLength := int(header & 0x0f)
+	offset++
+	if header&0x80 != 0 {
+		if offset >= len(data) {
+			return nil, errors.New("RTP-MIDI packet is missing the long command section header")
+		}
+		listLength = listLength<<8 | int(data[offset])
+		offset++
+	}
+
+	if offset+listLength > len(data) {
+		return nil, errors.New("RTP-MIDI command section is truncated")
+	}
+
+	commands, err := decodeCommands(data[offset:offset+listLength], firstDelta)
+	if err != nil {
+		return nil, err
+	}
+	packet.Commands = commands
+	offset += listLength
+
+	if hasJournal {
+		journal, err := DecodeJournal(data[offset:])
+		if err != nil {
+			return nil, err
+		}
+		packet.Journal = journal
+	}
+	return packet, nil
+}
+
+func decodeCommands(list []byte, firstDelta bool) ([]Command, error) {
+	commands := []Command{}
+	var runningStatus byte
+	offset := 0
+	for offset < len(list) {
+		var delta uint32
+		if len(commands) > 0 || firstDelta {
+			value, size, err := readDelta(list[offset:])
+			if err != nil {
+				return nil, err
+			}
+			delta = value
+			offset += size
+			if offset >= len(list) {
+				return nil, errors.New("RTP-MIDI delta time is missing its command")
+			}
+		}
+
+		status := list[offset]
+		if status < 0x80 {
+			//NOTE(jwetzell): running status carried over from a previous packet (phantom flag) is not tracked
+			if runningStatus == 0 {
+				return nil, errors.New("RTP-MIDI command uses running status without a status byte")
+			}
+			status = runningStatus
+		} else {
+			offset++
+		}
+
+		message := []byte{status}
+		if status == 0xf0 {
+			end := offset
+			for end < len(list) && list[end] != 0xf7 {
+				end++
+			}
+			if end >= len(list) {
+				return nil, errors.New("RTP-MIDI sysex command is not terminated")
+			}
+			message = append(message, list[offset:end+1]...)
+			offset = end + 1
+		} else {
+			dataLength := messageDataLength(status)
+			if offset+dataLength > len(list) {
+				return nil, errors.New("RTP-MIDI command is truncated")
+			}
+			message = append(message, list[offset:offset+dataLength]...)
+			offset += dataLength
+		}
+
+		if status < 0xf0 {
+			runningStatus = status
+		} else if status < 0xf8 {
+			runningStatus = 0
+		}
+		commands = append(commands, Command{Delta: delta, Message: message})
+	}
+	return commands, nil
+}
+
+// messageDataLength returns the number of data bytes following a status byte
+func messageDataLength(status byte) int {
+	switch {
+	case status >= 0xc0 && status < 0xe0:
+		return 1
+	case status < 0xf0:
+		return 2
+	case status == 0xf1 || status == 0xf3:
+		return 1
+	case status == 0xf2:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func appendDelta(data []byte, delta uint32) []byte {
+	delta &= 0x0fffffff
+	switch {
+	case delta >= 1<<21:
+		data = append(data, byte(delta>>21)|0x80, byte(delta>>14)|0x80, byte(delta>>7)|0x80)
+	case delta >= 1<<14:
+		data = append(data, byte(delta>>14)|0x80, byte(delta>>7)|0x80)
+	case delta >= 1<<7:
+		data = append(data, byte(delta>>7)|0x80)
+	}
+	return append(data, byte(delta&0x7f))
+}
+
+func readDelta(data []byte) (uint32, int, error) {
+	var delta uint32
+	for index := 0; index < 4 && index < len(data); index++ {
+		delta = delta<<7 | uint32(data[index]&0x7f)
+		if data[index]&0x80 == 0 {
+			return delta, index + 1, nil
+		}
+	}
+	return 0, 0, errors.New("RTP-MIDI delta time is invalid")
+}
@@ -0,0 +1,218 @@
+package rtpmidi_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/rtpmidi"
+)
+
+func TestControlRoundTrip(t *testing.T) {
+	packets := []rtpmidi.Packet{
+		&rtpmidi.Invitation{Command: rtpmidi.CommandInvitation, Version: rtpmidi.ProtocolVersion, Token: 1234, SSRC: 5678, Name: "showbridge"},
+		&rtpmidi.Invitation{Command: rtpmidi.CommandBye, Version: rtpmidi.ProtocolVersion, Token: 1, SSRC: 2},
+		&rtpmidi.ClockSync{SSRC: 9, Count: 2, Timestamps: [3]uint64{1, 2, 3}},
+		&rtpmidi.ReceiverFeedback{SSRC: 9, Sequence: 65535},
+	}
+
+	for _, packet := range packets {
+		data, err := packet.MarshalBinary()
+		if err != nil {
+			t.Fatalf("failed to marshal %T: %s", packet, err)
+		}
+		if !rtpmidi.IsControl(data) {
+			t.Fatalf("%T should be a control packet", packet)
+		}
+		decoded, err := rtpmidi.DecodeControl(data)
+		if err != nil {
+			t.Fatalf("failed to decode %T: %s", packet, err)
+		}
+		if !reflect.DeepEqual(packet, decoded) {
+			t.Fatalf("control round trip wrong. expected: %+v got %+v", packet, decoded)
+		}
+	}
+}
+
+func TestBadControl(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		errorString string
+	}{
+		{name: "no signature", data: []byte{0x80, 0x61, 0x00, 0x00}, errorString: "AppleMIDI packet must start with 0xffff"},
+		{name: "unknown command", data: []byte{0xff, 0xff, 'Z', 'Z'}, errorString: "unsupported AppleMIDI command \"ZZ\""},
+		{name: "short invitation", data: []byte{0xff, 0xff, 'I', 'N', 0x00}, errorString: "AppleMIDI IN packet is too short"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := rtpmidi.DecodeControl(test.data)
+			if err == nil || err.Error() != test.errorString {
+				t.Fatalf("got error '%v', expected '%s'", err, test.errorString)
+			}
+		})
+	}
+}
+
+func TestMIDIPacketRunningStatus(t *testing.T) {
+	//NOTE(jwetzell): note on, then a running status note on with a delta time, then a sysex
+	data := []byte{
+		0x80, 0x61, 0x00, 0x07, 0x00, 0x00, 0x00, 0x10, 0x00, 0x00, 0x00, 0x01,
+		0x0c,
+		0x90, 0x3c, 0x40,
+		0x81, 0x00, 0x3e, 0x40,
+		0x00, 0xf0, 0x7e, 0x01, 0xf7,
+	}
+
+	packet, err := rtpmidi.DecodeMIDIPacket(data)
+	if err != nil {
+		t.Fatalf("failed to decode packet: %s", err)
+	}
+
+	if packet.Sequence != 7 || packet.Timestamp != 16 || packet.SSRC != 1 || packet.Journal != nil {
+		t.Fatalf("packet header wrong: %+v", packet)
+	}
+
+	expected := []rtpmidi.Command{
+		{Message: []byte{0x90, 0x3c, 0x40}},
+		{Delta: 128, Message: []byte{0x90, 0x3e, 0x40}},
+		{Message: []byte{0xf0, 0x7e, 0x01, 0xf7}},
+	}
+	if !reflect.DeepEqual(packet.Commands, expected) {
+		t.Fatalf("packet commands wrong. expected: %+v got %+v", expected, packet.Commands)
+	}
+}
+
+func TestMIDIPacketRoundTrip(t *testing.T) {
+	commands := []rtpmidi.Command{}
+	for note := range byte(10) {
+		commands = append(commands, rtpmidi.Command{Delta: uint32(note) * 300, Message: []byte{0x91, note, 0x7f}})
+	}
+
+	packet := &rtpmidi.MIDIPacket{
+		Sequence:  65535,
+		Timestamp: 12345,
+		SSRC:      42,
+		Commands:  commands,
+		Journal: &rtpmidi.Journal{
+			Checkpoint: 65530,
+			Channels: []rtpmidi.ChannelJournal{
+				{
+					Channel:     1,
+					Program:     &rtpmidi.ProgramLog{Program: 5, Bank: true, BankMSB: 1, BankLSB: 2},
+					Controllers: []rtpmidi.ControllerLog{{Number: 7, Value: 100}},
+					Notes:       []rtpmidi.NoteLog{{Note: 60, Velocity: 90}},
+					NoteOffs:    []uint8{20, 61, 127},
+				},
+				{
+					Channel:  9,
+					NoteOffs: []uint8{36},
+				},
+			},
+		},
+	}
+
+	data, err := packet.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal packet: %s", err)
+	}
+
+	decoded, err := rtpmidi.DecodeMIDIPacket(data)
+	if err != nil {
+		t.Fatalf("failed to decode packet: %s", err)
+	}
+
+	if !reflect.DeepEqual(packet, decoded) {
+		t.Fatalf("packet round trip wrong. expected: %+v got %+v", packet, decoded)
+	}
+}
+
+func TestBadMIDIPacket(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		errorString string
+	}{
+		{name: "too short", data: []byte{0x80, 0x61}, errorString: "RTP-MIDI packet is too short"},
+		{name: "wrong version", data: []byte{0x40, 0x61, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, errorString: "RTP-MIDI packet must be RTP version 2"},
+		{name: "truncated command section", data: []byte{0x80, 0x61, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x03, 0x90}, errorString: "RTP-MIDI command section is truncated"},
+		{name: "running status without status", data: []byte{0x80, 0x61, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x02, 0x3c, 0x40}, errorString: "RTP-MIDI command uses running status without a status byte"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := rtpmidi.DecodeMIDIPacket(test.data)
+			if err == nil || err.Error() != test.errorString {
+				t.Fatalf("got error '%v', expected '%s'", err, test.errorString)
+			}
+		})
+	}
+}
+
+func TestJournalRecovery(t *testing.T) {
+	sender := rtpmidi.NewSender(1, 100)
+	receiver := rtpmidi.NewReceiver()
+
+	send := func(messages ...[]byte) *rtpmidi.MIDIPacket {
+		packet := sender.Packet(messages, 0)
+		data, err := packet.MarshalBinary()
+		if err != nil {
+			t.Fatalf("failed to marshal packet: %s", err)
+		}
+		decoded, err := rtpmidi.DecodeMIDIPacket(data)
+		if err != nil {
+			t.Fatalf("failed to decode packet: %s", err)
+		}
+		return decoded
+	}
+
+	messages, lost := receiver.Receive(send([]byte{0x90, 60, 100}))
+	if lost || len(messages) != 1 {
+		t.Fatalf("first packet should play without loss: %v %v", messages, lost)
+	}
+	sender.Ack(receiver.Sequence())
+
+	//NOTE(jwetzell): these two packets never arrive
+	send([]byte{0x80, 60, 0}, []byte{0x90, 62, 80})
+	send([]byte{0xb0, 7, 90}, []byte{0xc0, 3})
+
+	messages, lost = receiver.Receive(send([]byte{0x90, 64, 70}))
+	if !lost {
+		t.Fatalf("receiver should detect the lost packets")
+	}
+
+	expected := [][]byte{
+		{0xc0, 3},
+		{0xb0, 7, 90},
+		{0x80, 60, 0},
+		{0x90, 62, 80},
+		{0x90, 64, 70},
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Fatalf("recovered messages wrong. expected: %v got %v", expected, messages)
+	}
+
+	sender.Ack(receiver.Sequence())
+	if sender.Journal() != nil {
+		t.Fatalf("acknowledged sender should not have a journal")
+	}
+
+	//NOTE(jwetzell): a late duplicate is ignored
+	messages, _ = receiver.Receive(&rtpmidi.MIDIPacket{Sequence: 101, Commands: []rtpmidi.Command{{Message: []byte{0x90, 1, 1}}}})
+	if len(messages) != 0 {
+		t.Fatalf("late packet should be dropped, got %v", messages)
+	}
+}
+
+func TestJournalNoRecoveryWithoutLoss(t *testing.T) {
+	sender := rtpmidi.NewSender(1, 0)
+	receiver := rtpmidi.NewReceiver()
+
+	for _, message := range [][]byte{{0x90, 60, 100}, {0x80, 60, 0}, {0xb0, 1, 2}} {
+		messages, lost := receiver.Receive(sender.Packet([][]byte{message}, 0))
+		if lost || !slices.EqualFunc(messages, [][]byte{message}, slices.Equal) {
+			t.Fatalf("receiver should only play the packet commands, got %v", messages)
+		}
+	}
+}
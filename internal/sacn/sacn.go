@@ -0,0 +1,369 @@
+// Package sacn implements the parts of ANSI E1.31 (streaming ACN) needed to send and receive DMX
+package sacn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	Port               = 5568
+	DiscoveryUniverse  = 64214
+	MinUniverse        = 1
+	MaxUniverse        = 63999
+	DefaultPriority    = 100
+	MaxPriority        = 200
+	MaxSlots           = 512
+	sourceNameLength   = 64
+	maxDiscoveryPerPkt = 512
+
+	vectorRootData           = 0x00000004
+	vectorRootExtended       = 0x00000008
+	vectorFramingData        = 0x00000002
+	vectorFramingSync        = 0x00000001
+	vectorFramingDiscovery   = 0x00000002
+	vectorDMPSetProperty     = 0x02
+	vectorDiscoveryUniverses = 0x00000001
+
+	optionPreview          = 0x80
+	optionStreamTerminated = 0x40
+	optionForceSync        = 0x20
+
+	rootLayerLength       = 38
+	dataFramingLength     = 77
+	syncFramingLength     = 11
+	discoveryFramingLen   = 74
+	dmpHeaderLength       = 10
+	discoveryHeaderLength = 8
+)
+
+var acnPacketIdentifier = []byte{0x41, 0x53, 0x43, 0x2d, 0x45, 0x31, 0x2e, 0x31, 0x37, 0x00, 0x00, 0x00}
+
+// CID is the 16 byte component identifier (a UUID) every sACN source sends
+type CID [16]byte
+
+func NewCID() CID {
+	cid := CID{}
+	rand.Read(cid[:])
+	//NOTE(jwetzell): mark as a version 4 UUID
+	cid[6] = (cid[6] & 0x0f) | 0x40
+	cid[8] = (cid[8] & 0x3f) | 0x80
+	return cid
+}
+
+func ParseCID(value string) (CID, error) {
+	cid := CID{}
+	cidBytes, err := hex.DecodeString(strings.ReplaceAll(value, "-", ""))
+	if err != nil {
+		return cid, fmt.Errorf("invalid cid: %w", err)
+	}
+	if len(cidBytes) != len(cid) {
+		return cid, errors.New("cid must be 16 bytes")
+	}
+	copy(cid[:], cidBytes)
+	return cid, nil
+}
+
+func (c CID) String() string {
+	encoded := hex.EncodeToString(c[:])
+	return fmt.Sprintf("%s-%s-%s-%s-%s", encoded[0:8], encoded[8:12], encoded[12:16], encoded[16:20], encoded[20:32])
+}
+
+func (c CID) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *CID) UnmarshalText(text []byte) error {
+	cid, err := ParseCID(string(text))
+	if err != nil {
+		return err
+	}
+	*c = cid
+	return nil
+}
+
+type Packet interface {
+	MarshalBinary() ([]byte, error)
+}
+
+type DataPacket struct {
+	CID              CID    `json:"cid"`
+	SourceName       string `json:"sourceName"`
+	Priority         uint8  `json:"priority"`
+	SyncAddress      uint16 `json:"syncAddress"`
+	Sequence         uint8  `json:"sequence"`
+	Preview          bool   `json:"preview"`
+	StreamTerminated bool   `json:"streamTerminated"`
+	ForceSync        bool   `json:"forceSync"`
+	Universe         uint16 `json:"universe"`
+	StartCode        uint8  `json:"startCode"`
+	Data             []byte `json:"data"`
+}
+
+type SyncPacket struct {
+	CID         CID    `json:"cid"`
+	Sequence    uint8  `json:"sequence"`
+	SyncAddress uint16 `json:"syncAddress"`
+}
+
+type DiscoveryPacket struct {
+	CID        CID      `json:"cid"`
+	SourceName string   `json:"sourceName"`
+	Page       uint8    `json:"page"`
+	LastPage   uint8    `json:"lastPage"`
+	Universes  []uint16 `json:"universes"`
+}
+
+// Channel returns the value of a 1-indexed DMX channel, channels past the end of the data are 0
+func (dp *DataPacket) Channel(channel int) (uint8, error) {
+	if channel < 1 || channel > MaxSlots {
+		return 0, fmt.Errorf("channel must be between 1 and %d", MaxSlots)
+	}
+	if channel > len(dp.Data) {
+		return 0, nil
+	}
+	return dp.Data[channel-1], nil
+}
+
+func flagsAndLength(length int) uint16 {
+	return 0x7000 | uint16(length&0x0fff)
+}
+
+func writeRootLayer(buffer *bytes.Buffer, totalLength int, vector uint32, cid CID) {
+	binary.Write(buffer, binary.BigEndian, uint16(0x0010))
+	binary.Write(buffer, binary.BigEndian, uint16(0x0000))
+	buffer.Write(acnPacketIdentifier)
+	binary.Write(buffer, binary.BigEndian, flagsAndLength(totalLength-16))
+	binary.Write(buffer, binary.BigEndian, vector)
+	buffer.Write(cid[:])
+}
+
+func writeSourceName(buffer *bytes.Buffer, sourceName string) {
+	nameBytes := make([]byte, sourceNameLength)
+	//NOTE(jwetzell): source name must be null terminated
+	copy(nameBytes[:sourceNameLength-1], sourceName)
+	buffer.Write(nameBytes)
+}
+
+func (dp DataPacket) MarshalBinary() ([]byte, error) {
+	if dp.Universe < MinUniverse || dp.Universe > MaxUniverse {
+		return nil, fmt.Errorf("universe must be between %d and %d", MinUniverse, MaxUniverse)
+	}
+	if dp.Priority > MaxPriority {
+		return nil, fmt.Errorf("priority must be between 0 and %d", MaxPriority)
+	}
+	if len(dp.Data) > MaxSlots {
+		return nil, fmt.Errorf("data cannot be more than %d slots", MaxSlots)
+	}
+
+	totalLength := rootLayerLength + dataFramingLength + dmpHeaderLength + 1 + len(dp.Data)
+	buffer := bytes.NewBuffer(make([]byte, 0, totalLength))
+
+	writeRootLayer(buffer, totalLength, vectorRootData, dp.CID)
+
+	binary.Write(buffer, binary.BigEndian, flagsAndLength(totalLength-rootLayerLength))
+	binary.Write(buffer, binary.BigEndian, uint32(vectorFramingData))
+	writeSourceName(buffer, dp.SourceName)
+	buffer.WriteByte(dp.Priority)
+	binary.Write(buffer, binary.BigEndian, dp.SyncAddress)
+	buffer.WriteByte(dp.Sequence)
+	options := byte(0)
+	if dp.Preview {
+		options |= optionPreview
+	}
+	if dp.StreamTerminated {
+		options |= optionStreamTerminated
+	}
+	if dp.ForceSync {
+		options |= optionForceSync
+	}
+	buffer.WriteByte(options)
+	binary.Write(buffer, binary.BigEndian, dp.Universe)
+
+	binary.Write(buffer, binary.BigEndian, flagsAndLength(totalLength-rootLayerLength-dataFramingLength))
+	buffer.WriteByte(vectorDMPSetProperty)
+	buffer.WriteByte(0xa1)
+	binary.Write(buffer, binary.BigEndian, uint16(0x0000))
+	binary.Write(buffer, binary.BigEndian, uint16(0x0001))
+	binary.Write(buffer, binary.BigEndian, uint16(len(dp.Data)+1))
+	buffer.WriteByte(dp.StartCode)
+	buffer.Write(dp.Data)
+
+	return buffer.Bytes(), nil
+}
+
+func (sp SyncPacket) MarshalBinary() ([]byte, error) {
+	totalLength := rootLayerLength + syncFramingLength
+	buffer := bytes.NewBuffer(make([]byte, 0, totalLength))
+
+	writeRootLayer(buffer, totalLength, vectorRootExtended, sp.CID)
+
+	binary.Write(buffer, binary.BigEndian, flagsAndLength(syncFramingLength))
+	binary.Write(buffer, binary.BigEndian, uint32(vectorFramingSync))
+	buffer.WriteByte(sp.Sequence)
+	binary.Write(buffer, binary.BigEndian, sp.SyncAddress)
+	binary.Write(buffer, binary.BigEndian, uint16(0x0000))
+
+	return buffer.Bytes(), nil
+}
+
+func (dp DiscoveryPacket) MarshalBinary() ([]byte, error) {
+	if len(dp.Universes) > maxDiscoveryPerPkt {
+		return nil, fmt.Errorf("discovery packet cannot list more than %d universes", maxDiscoveryPerPkt)
+	}
+
+	totalLength := rootLayerLength + discoveryFramingLen + discoveryHeaderLength + len(dp.Universes)*2
+	buffer := bytes.NewBuffer(make([]byte, 0, totalLength))
+
+	writeRootLayer(buffer, totalLength, vectorRootExtended, dp.CID)
+
+	binary.Write(buffer, binary.BigEndian, flagsAndLength(totalLength-rootLayerLength))
+	binary.Write(buffer, binary.BigEndian, uint32(vectorFramingDiscovery))
+	writeSourceName(buffer, dp.SourceName)
+	binary.Write(buffer, binary.BigEndian, uint32(0x00000000))
+
+	binary.Write(buffer, binary.BigEndian, flagsAndLength(totalLength-rootLayerLength-discoveryFramingLen))
+	binary.Write(buffer, binary.BigEndian, uint32(vectorDiscoveryUniverses))
+	buffer.WriteByte(dp.Page)
+	buffer.WriteByte(dp.LastPage)
+	for _, universe := range dp.Universes {
+		binary.Write(buffer, binary.BigEndian, universe)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func readSourceName(data []byte) string {
+	end := bytes.IndexByte(data, 0x00)
+	if end < 0 {
+		end = len(data)
+	}
+	return string(data[:end])
+}
+
+func Decode(data []byte) (Packet, error) {
+	if len(data) < rootLayerLength {
+		return nil, errors.New("sacn packet too short")
+	}
+	if !bytes.Equal(data[4:16], acnPacketIdentifier) {
+		return nil, errors.New("sacn packet has wrong ACN packet identifier")
+	}
+
+	cid := CID{}
+	copy(cid[:], data[22:38])
+
+	rootVector := binary.BigEndian.Uint32(data[18:22])
+	switch rootVector {
+	case vectorRootData:
+		return decodeDataPacket(data, cid)
+	case vectorRootExtended:
+		if len(data) < rootLayerLength+6 {
+			return nil, errors.New("sacn extended packet too short")
+		}
+		framingVector := binary.BigEndian.Uint32(data[40:44])
+		switch framingVector {
+		case vectorFramingSync:
+			return decodeSyncPacket(data, cid)
+		case vectorFramingDiscovery:
+			return decodeDiscoveryPacket(data, cid)
+		default:
+			return nil, fmt.Errorf("sacn unknown extended framing vector: %d", framingVector)
+		}
+	default:
+		return nil, fmt.Errorf("sacn unknown root vector: %d", rootVector)
+	}
+}
+
+func decodeDataPacket(data []byte, cid CID) (*DataPacket, error) {
+	headerLength := rootLayerLength + dataFramingLength + dmpHeaderLength
+	if len(data) < headerLength+1 {
+		return nil, errors.New("sacn data packet too short")
+	}
+
+	framingVector := binary.BigEndian.Uint32(data[40:44])
+	if framingVector != vectorFramingData {
+		return nil, fmt.Errorf("sacn unknown data framing vector: %d", framingVector)
+	}
+
+	if data[117] != vectorDMPSetProperty {
+		return nil, fmt.Errorf("sacn unknown dmp vector: %d", data[117])
+	}
+
+	propertyCount := int(binary.BigEndian.Uint16(data[123:125]))
+	if propertyCount < 1 || headerLength+propertyCount > len(data) {
+		return nil, errors.New("sacn data packet property count does not match length")
+	}
+	if propertyCount-1 > MaxSlots {
+		return nil, fmt.Errorf("sacn data packet cannot have more than %d slots", MaxSlots)
+	}
+
+	options := data[112]
+	packet := &DataPacket{
+		CID:              cid,
+		SourceName:       readSourceName(data[44:108]),
+		Priority:         data[108],
+		SyncAddress:      binary.BigEndian.Uint16(data[109:111]),
+		Sequence:         data[111],
+		Preview:          options&optionPreview != 0,
+		StreamTerminated: options&optionStreamTerminated != 0,
+		ForceSync:        options&optionForceSync != 0,
+		Universe:         binary.BigEndian.Uint16(data[113:115]),
+		StartCode:        data[125],
+		Data:             make([]byte, propertyCount-1),
+	}
+	copy(packet.Data, data[126:headerLength+propertyCount])
+	return packet, nil
+}
+
+func decodeSyncPacket(data []byte, cid CID) (*SyncPacket, error) {
+	if len(data) < rootLayerLength+syncFramingLength {
+		return nil, errors.New("sacn sync packet too short")
+	}
+	return &SyncPacket{
+		CID:         cid,
+		Sequence:    data[44],
+		SyncAddress: binary.BigEndian.Uint16(data[45:47]),
+	}, nil
+}
+
+func decodeDiscoveryPacket(data []byte, cid CID) (*DiscoveryPacket, error) {
+	headerLength := rootLayerLength + discoveryFramingLen + discoveryHeaderLength
+	if len(data) < headerLength {
+		return nil, errors.New("sacn discovery packet too short")
+	}
+	if (len(data)-headerLength)%2 != 0 {
+		return nil, errors.New("sacn discovery packet has a partial universe")
+	}
+
+	packet := &DiscoveryPacket{
+		CID:        cid,
+		SourceName: readSourceName(data[44:108]),
+		Page:       data[118],
+		LastPage:   data[119],
+		Universes:  []uint16{},
+	}
+	for offset := headerLength; offset < len(data); offset += 2 {
+		packet.Universes = append(packet.Universes, binary.BigEndian.Uint16(data[offset:offset+2]))
+	}
+	return packet, nil
+}
+
+// MulticastAddr is the multicast group a universe is sent to
+func MulticastAddr(universe uint16) *net.UDPAddr {
+	return &net.UDPAddr{
+		IP:   net.IPv4(239, 255, byte(universe>>8), byte(universe&0xff)),
+		Port: Port,
+	}
+}
+
+// SequenceIsNewer applies the E1.31 rule for discarding out of order packets
+func SequenceIsNewer(last uint8, next uint8) bool {
+	diff := int8(next - last)
+	return diff > 0 || diff <= -20
+}
@@ -0,0 +1,261 @@
+package sacn_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/sacn"
+)
+
+var testCID = sacn.CID{0x5b, 0x7a, 0x1c, 0x2e, 0x3d, 0x4f, 0x40, 0x11, 0x92, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99}
+
+func TestDataPacketRoundTrip(t *testing.T) {
+	packet := sacn.DataPacket{
+		CID:              testCID,
+		SourceName:       "showbridge",
+		Priority:         150,
+		Sequence:         42,
+		StreamTerminated: true,
+		Universe:         513,
+		Data:             []byte{0, 127, 255},
+	}
+
+	packetBytes, err := packet.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %s", err)
+	}
+
+	if len(packetBytes) != 129 {
+		t.Fatalf("data packet has wrong length: %d", len(packetBytes))
+	}
+
+	//NOTE(jwetzell): root, framing, and dmp flags and length
+	if packetBytes[16] != 0x70 || packetBytes[17] != 113 {
+		t.Fatalf("root layer flags and length wrong: %x %x", packetBytes[16], packetBytes[17])
+	}
+	if packetBytes[38] != 0x70 || packetBytes[39] != 91 {
+		t.Fatalf("framing layer flags and length wrong: %x %x", packetBytes[38], packetBytes[39])
+	}
+	if packetBytes[115] != 0x70 || packetBytes[116] != 14 {
+		t.Fatalf("dmp layer flags and length wrong: %x %x", packetBytes[115], packetBytes[116])
+	}
+
+	decoded, err := sacn.Decode(packetBytes)
+	if err != nil {
+		t.Fatalf("Decode failed: %s", err)
+	}
+
+	if !reflect.DeepEqual(decoded, &packet) {
+		t.Fatalf("Decode got %+v, expected %+v", decoded, &packet)
+	}
+}
+
+func TestSyncPacketRoundTrip(t *testing.T) {
+	packet := sacn.SyncPacket{CID: testCID, Sequence: 7, SyncAddress: 12}
+
+	packetBytes, err := packet.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %s", err)
+	}
+
+	decoded, err := sacn.Decode(packetBytes)
+	if err != nil {
+		t.Fatalf("Decode failed: %s", err)
+	}
+
+	if !reflect.DeepEqual(decoded, &packet) {
+		t.Fatalf("Decode got %+v, expected %+v", decoded, &packet)
+	}
+}
+
+func TestDiscoveryPacketRoundTrip(t *testing.T) {
+	packet := sacn.DiscoveryPacket{CID: testCID, SourceName: "showbridge", Universes: []uint16{1, 2, 300}}
+
+	packetBytes, err := packet.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %s", err)
+	}
+
+	decoded, err := sacn.Decode(packetBytes)
+	if err != nil {
+		t.Fatalf("Decode failed: %s", err)
+	}
+
+	if !reflect.DeepEqual(decoded, &packet) {
+		t.Fatalf("Decode got %+v, expected %+v", decoded, &packet)
+	}
+}
+
+func TestBadDataPacket(t *testing.T) {
+	tests := []struct {
+		name        string
+		packet      sacn.DataPacket
+		errorString string
+	}{
+		{
+			name:        "universe zero",
+			packet:      sacn.DataPacket{Universe: 0},
+			errorString: "universe must be between 1 and 63999",
+		},
+		{
+			name:        "priority too high",
+			packet:      sacn.DataPacket{Universe: 1, Priority: 201},
+			errorString: "priority must be between 0 and 200",
+		},
+		{
+			name:        "too much data",
+			packet:      sacn.DataPacket{Universe: 1, Data: make([]byte, 513)},
+			errorString: "data cannot be more than 512 slots",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.packet.MarshalBinary()
+			if err == nil {
+				t.Fatalf("MarshalBinary expected to fail")
+			}
+			if err.Error() != test.errorString {
+				t.Fatalf("MarshalBinary got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
+
+func TestBadDecode(t *testing.T) {
+	goodPacket, err := sacn.DataPacket{CID: testCID, Universe: 1, Data: []byte{1, 2}}.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %s", err)
+	}
+
+	wrongIdentifier := append([]byte{}, goodPacket...)
+	wrongIdentifier[4] = 0x00
+
+	truncated := goodPacket[:len(goodPacket)-1]
+
+	tests := []struct {
+		name        string
+		data        []byte
+		errorString string
+	}{
+		{
+			name:        "too short",
+			data:        []byte{0x00, 0x10},
+			errorString: "sacn packet too short",
+		},
+		{
+			name:        "wrong identifier",
+			data:        wrongIdentifier,
+			errorString: "sacn packet has wrong ACN packet identifier",
+		},
+		{
+			name:        "truncated data",
+			data:        truncated,
+			errorString: "sacn data packet property count does not match length",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := sacn.Decode(test.data)
+			if err == nil {
+				t.Fatalf("Decode expected to fail")
+			}
+			if err.Error() != test.errorString {
+				t.Fatalf("Decode got error '%s', expected '%s'", err.Error(), test.errorString)
+			}
+		})
+	}
+}
+
+func TestCID(t *testing.T) {
+	cidString := testCID.String()
+	if cidString != "5b7a1c2e-3d4f-4011-9233-445566778899" {
+		t.Fatalf("CID string wrong: %s", cidString)
+	}
+
+	parsed, err := sacn.ParseCID(cidString)
+	if err != nil {
+		t.Fatalf("ParseCID failed: %s", err)
+	}
+	if parsed != testCID {
+		t.Fatalf("ParseCID got %s, expected %s", parsed, testCID)
+	}
+
+	_, err = sacn.ParseCID("1234")
+	if err == nil {
+		t.Fatalf("ParseCID should reject a short cid")
+	}
+}
+
+func TestSequenceIsNewer(t *testing.T) {
+	tests := []struct {
+		last     uint8
+		next     uint8
+		expected bool
+	}{
+		{last: 1, next: 2, expected: true},
+		{last: 255, next: 0, expected: true},
+		{last: 2, next: 2, expected: false},
+		{last: 10, next: 5, expected: false},
+		{last: 30, next: 10, expected: true},
+	}
+
+	for _, test := range tests {
+		got := sacn.SequenceIsNewer(test.last, test.next)
+		if got != test.expected {
+			t.Fatalf("SequenceIsNewer(%d, %d) got %t, expected %t", test.last, test.next, got, test.expected)
+		}
+	}
+}
+
+func TestMulticastAddr(t *testing.T) {
+	addr := sacn.MulticastAddr(513)
+	if addr.String() != "239.255.2.1:5568" {
+		t.Fatalf("MulticastAddr got %s", addr.String())
+	}
+}
+
+func TestTracker(t *testing.T) {
+	tracker := sacn.NewTracker()
+	now := time.Now()
+	lowCID := sacn.CID{1}
+	highCID := sacn.CID{2}
+
+	if !tracker.Accept(&sacn.DataPacket{CID: lowCID, Universe: 1, Priority: 100, Sequence: 1}, now) {
+		t.Fatalf("first packet from a source should be accepted")
+	}
+
+	if tracker.Accept(&sacn.DataPacket{CID: lowCID, Universe: 1, Priority: 100, Sequence: 1}, now) {
+		t.Fatalf("repeated sequence should be discarded")
+	}
+
+	if !tracker.Accept(&sacn.DataPacket{CID: highCID, Universe: 1, Priority: 150, Sequence: 9}, now) {
+		t.Fatalf("higher priority source should be accepted")
+	}
+
+	if tracker.Accept(&sacn.DataPacket{CID: lowCID, Universe: 1, Priority: 100, Sequence: 2}, now) {
+		t.Fatalf("lower priority source should be discarded")
+	}
+
+	if tracker.Sources(1, now) != 2 {
+		t.Fatalf("tracker should have 2 sources, got %d", tracker.Sources(1, now))
+	}
+
+	if tracker.Accept(&sacn.DataPacket{CID: highCID, Universe: 1, Priority: 150, Sequence: 10, StreamTerminated: true}, now) {
+		t.Fatalf("stream terminated packet should not be accepted")
+	}
+
+	if !tracker.Accept(&sacn.DataPacket{CID: lowCID, Universe: 1, Priority: 100, Sequence: 3}, now) {
+		t.Fatalf("lower priority source should be accepted once the higher priority source terminates")
+	}
+
+	later := now.Add(sacn.SourceTimeout + time.Second)
+	if !tracker.Accept(&sacn.DataPacket{CID: highCID, Universe: 2, Priority: 50, Sequence: 1}, later) {
+		t.Fatalf("packet on another universe should be accepted")
+	}
+	if tracker.Sources(1, later) != 0 {
+		t.Fatalf("sources should time out, got %d", tracker.Sources(1, later))
+	}
+}
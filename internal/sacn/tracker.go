@@ -0,0 +1,84 @@
+package sacn
+
+import (
+	"sync"
+	"time"
+)
+
+// SourceTimeout is how long a source can go without sending before it is considered lost
+const SourceTimeout = 2500 * time.Millisecond
+
+type sourceState struct {
+	priority uint8
+	sequence uint8
+	lastSeen time.Time
+}
+
+// Tracker keeps track of the sources sending to each universe and decides which data packets should be used
+type Tracker struct {
+	universes map[uint16]map[CID]*sourceState
+	mu        sync.Mutex
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		universes: make(map[uint16]map[CID]*sourceState),
+	}
+}
+
+// Accept records the packet and reports whether it is in order and from a highest priority source for its universe
+func (t *Tracker) Accept(packet *DataPacket, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sources, ok := t.universes[packet.Universe]
+	if !ok {
+		sources = make(map[CID]*sourceState)
+		t.universes[packet.Universe] = sources
+	}
+
+	for cid, source := range sources {
+		if now.Sub(source.lastSeen) > SourceTimeout {
+			delete(sources, cid)
+		}
+	}
+
+	source, known := sources[packet.CID]
+	if known && !SequenceIsNewer(source.sequence, packet.Sequence) {
+		return false
+	}
+
+	if packet.StreamTerminated {
+		delete(sources, packet.CID)
+		return false
+	}
+
+	if !known {
+		source = &sourceState{}
+		sources[packet.CID] = source
+	}
+	source.priority = packet.Priority
+	source.sequence = packet.Sequence
+	source.lastSeen = now
+
+	for _, otherSource := range sources {
+		if otherSource.priority > packet.Priority {
+			return false
+		}
+	}
+	return true
+}
+
+// Sources returns the number of active sources for a universe
+func (t *Tracker) Sources(universe uint16, now time.Time) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	count := 0
+	for _, source := range t.universes[universe] {
+		if now.Sub(source.lastSeen) <= SourceTimeout {
+			count++
+		}
+	}
+	return count
+}
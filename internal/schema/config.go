@@ -11,7 +11,9 @@ var ConfigSchema = jsonschema.Schema{
 	Description: "showbridge configuration",
 	Type:        "object",
 	Properties: map[string]*jsonschema.Schema{
-		"api": &ApiConfigSchema,
+		"api":       &ApiConfigSchema,
+		"eventLog":  &EventLogConfigSchema,
+		"recording": &RecordingConfigSchema,
 		"modules": {
 			Ref: "https://showbridge.io/modules.schema.json",
 		},
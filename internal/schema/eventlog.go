@@ -0,0 +1,32 @@
+package schema
+
+import (
+	"encoding/json"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+var EventLogConfigSchema = jsonschema.Schema{
+	ID:   "https://showbridge.io/eventlog.schema.json",
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"size": {
+			Type:        "integer",
+			Description: "Number of recent events to keep in memory",
+			Minimum:     jsonschema.Ptr[float64](1),
+			Default:     json.RawMessage(`1000`),
+		},
+		"path": {
+			Type:        "string",
+			Description: "Path of a SQLite file to persist events to, events are only kept in memory when empty",
+		},
+		"maxRows": {
+			Type:        "integer",
+			Description: "Maximum number of events to keep in the SQLite file, older events are removed first (0 keeps everything)",
+			Minimum:     jsonschema.Ptr[float64](0),
+			Default:     json.RawMessage(`100000`),
+		},
+	},
+	Default:              json.RawMessage(`{"size": 1000, "maxRows": 100000}`),
+	AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+}
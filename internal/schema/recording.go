@@ -0,0 +1,26 @@
+package schema
+
+import (
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+var RecordingConfigSchema = jsonschema.Schema{
+	ID:   "https://showbridge.io/recording.schema.json",
+	Type: "object",
+	Properties: map[string]*jsonschema.Schema{
+		"path": {
+			Type:        "string",
+			Description: "Path of the file module inputs are appended to",
+			MinLength:   new(1),
+		},
+		"modules": {
+			Type:        "array",
+			Description: "Ids of the modules to record inputs from, every module is recorded when empty",
+			Items: &jsonschema.Schema{
+				Type: "string",
+			},
+		},
+	},
+	Required:             []string{"path"},
+	AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+}
@@ -0,0 +1,87 @@
+// Package sse parses Server-Sent Events streams
+package sse
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is a dispatched event, Event is "message" when the stream didn't name one
+type Event struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// Reader reads events from a stream, LastEventID and Retry carry over between events as the spec requires
+type Reader struct {
+	LastEventID string
+	Retry       time.Duration
+	scanner     *bufio.Scanner
+}
+
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), 1<<20)
+	return &Reader{scanner: scanner}
+}
+
+// Next blocks until a complete event has been read, returning io.EOF when the stream ends
+func (r *Reader) Next() (Event, error) {
+	eventType := ""
+	var data strings.Builder
+	hasData := false
+	//NOTE(jwetzell): an id only counts once its event is dispatched, a stream that drops mid event keeps the previous id
+	pendingID := r.LastEventID
+
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+
+		if line == "" {
+			r.LastEventID = pendingID
+			if !hasData {
+				eventType = ""
+				continue
+			}
+			if eventType == "" {
+				eventType = "message"
+			}
+			return Event{ID: r.LastEventID, Event: eventType, Data: strings.TrimSuffix(data.String(), "\n")}, nil
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			data.WriteString(value)
+			data.WriteString("\n")
+			hasData = true
+		case "id":
+			//NOTE(jwetzell): the spec ignores ids containing NULL
+			if !strings.ContainsRune(value, 0) {
+				pendingID = value
+			}
+		case "retry":
+			milliseconds, err := strconv.Atoi(value)
+			if err == nil && milliseconds >= 0 {
+				r.Retry = time.Duration(milliseconds) * time.Millisecond
+			}
+		}
+	}
+
+	err := r.scanner.Err()
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{}, io.EOF
+}
@@ -0,0 +1,86 @@
+package sse_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/sse"
+)
+
+func TestReader(t *testing.T) {
+	stream := strings.Join([]string{
+		": keepalive",
+		"retry: 1500",
+		"",
+		"data: first",
+		"",
+		"id: 7",
+		"event: cue",
+		"data: line one",
+		"data:line two",
+		"",
+		"event: ignored",
+		"",
+		"data: {\"level\":50}",
+		"",
+		"data: unterminated",
+	}, "\n")
+
+	reader := sse.NewReader(strings.NewReader(stream))
+
+	expected := []sse.Event{
+		{ID: "", Event: "message", Data: "first"},
+		{ID: "7", Event: "cue", Data: "line one\nline two"},
+		{ID: "7", Event: "message", Data: "{\"level\":50}"},
+	}
+
+	for _, want := range expected {
+		event, err := reader.Next()
+		if err != nil {
+			t.Fatalf("sse reader failed: %s", err)
+		}
+		if event != want {
+			t.Fatalf("sse reader got %+v, expected %+v", event, want)
+		}
+	}
+
+	//NOTE(jwetzell): an event without the closing blank line is never dispatched
+	_, err := reader.Next()
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("sse reader should end with EOF, got %v", err)
+	}
+
+	if reader.Retry != 1500*time.Millisecond || reader.LastEventID != "7" {
+		t.Fatalf("sse reader got retry %s last event id %q", reader.Retry, reader.LastEventID)
+	}
+}
+
+func TestReaderTruncated(t *testing.T) {
+	stream := io.MultiReader(
+		strings.NewReader("id: 1\ndata: first\n\nid: 2\ndata: cut"),
+		iotest.ErrReader(io.ErrUnexpectedEOF),
+	)
+
+	reader := sse.NewReader(stream)
+
+	event, err := reader.Next()
+	if err != nil {
+		t.Fatalf("sse reader failed: %s", err)
+	}
+	if event.ID != "1" || event.Data != "first" {
+		t.Fatalf("sse reader got %+v", event)
+	}
+
+	_, err = reader.Next()
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("sse reader should return the stream error, got %v", err)
+	}
+
+	if reader.LastEventID != "1" {
+		t.Fatalf("sse reader should keep the last dispatched id when the stream drops mid event, got %q", reader.LastEventID)
+	}
+}
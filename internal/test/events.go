@@ -0,0 +1,39 @@
+package test
+
+import (
+	"sync"
+
+	"github.com/jwetzell/showbridge-go/internal/common"
+)
+
+func NewTestEventDestination() *TestEventDestination {
+	return &TestEventDestination{}
+}
+
+type TestEventDestination struct {
+	events   []common.Event
+	eventsMu sync.Mutex
+}
+
+func (d *TestEventDestination) Send(event common.Event) error {
+	d.eventsMu.Lock()
+	defer d.eventsMu.Unlock()
+	d.events = append(d.events, event)
+	return nil
+}
+
+func (d *TestEventDestination) Is(dest common.EventDestination) bool {
+	other, ok := dest.(*TestEventDestination)
+	if !ok {
+		return false
+	}
+	return d == other
+}
+
+func (d *TestEventDestination) Events() []common.Event {
+	d.eventsMu.Lock()
+	defer d.eventsMu.Unlock()
+	events := make([]common.Event, len(d.events))
+	copy(events, d.events)
+	return events
+}
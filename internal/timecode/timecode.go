@@ -0,0 +1,274 @@
+// Package timecode does SMPTE timecode math and MIDI Timecode (MTC) encoding
+package timecode
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rate values match the rate codes used by MTC and ArtTimeCode
+type Rate uint8
+
+const (
+	Rate24 Rate = iota
+	Rate25
+	Rate2997DF
+	Rate30
+)
+
+func ParseRate(value string) (Rate, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "24":
+		return Rate24, nil
+	case "25":
+		return Rate25, nil
+	case "29.97df", "29.97", "2997df", "df":
+		return Rate2997DF, nil
+	case "30":
+		return Rate30, nil
+	default:
+		return 0, fmt.Errorf("unsupported timecode rate: %s", value)
+	}
+}
+
+func (r Rate) String() string {
+	switch r {
+	case Rate24:
+		return "24"
+	case Rate25:
+		return "25"
+	case Rate2997DF:
+		return "29.97df"
+	case Rate30:
+		return "30"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(r))
+	}
+}
+
+// Nominal is the number of frame labels per second
+func (r Rate) Nominal() int {
+	switch r {
+	case Rate24:
+		return 24
+	case Rate25:
+		return 25
+	default:
+		return 30
+	}
+}
+
+// FPS is the real number of frames per second
+func (r Rate) FPS() float64 {
+	if r == Rate2997DF {
+		return 30000.0 / 1001.0
+	}
+	return float64(r.Nominal())
+}
+
+func (r Rate) DropFrame() bool {
+	return r == Rate2997DF
+}
+
+// FramesPerDay is the number of frames before timecode wraps at 24 hours
+func (r Rate) FramesPerDay() int {
+	if r.DropFrame() {
+		return 86400*30 - 2*(1440-144)
+	}
+	return 86400 * r.Nominal()
+}
+
+type Timecode struct {
+	Hours   uint8
+	Minutes uint8
+	Seconds uint8
+	Frames  uint8
+	Rate    Rate
+}
+
+// Parse reads hh:mm:ss:ff, drop frame timecode may use ; before the frames
+func Parse(value string, rate Rate) (Timecode, error) {
+	normalized := strings.ReplaceAll(strings.TrimSpace(value), ";", ":")
+	normalized = strings.ReplaceAll(normalized, ".", ":")
+	parts := strings.Split(normalized, ":")
+	if len(parts) != 4 {
+		return Timecode{}, fmt.Errorf("timecode must be hh:mm:ss:ff: %s", value)
+	}
+
+	values := [4]uint8{}
+	for index, part := range parts {
+		number, err := strconv.ParseUint(part, 10, 8)
+		if err != nil {
+			return Timecode{}, fmt.Errorf("timecode must be hh:mm:ss:ff: %s", value)
+		}
+		values[index] = uint8(number)
+	}
+
+	timecode := Timecode{Hours: values[0], Minutes: values[1], Seconds: values[2], Frames: values[3], Rate: rate}
+	err := timecode.Validate()
+	if err != nil {
+		return Timecode{}, err
+	}
+	return timecode, nil
+}
+
+func (t Timecode) Validate() error {
+	if t.Rate > Rate30 {
+		return fmt.Errorf("unsupported timecode rate: %d", t.Rate)
+	}
+	if t.Hours > 23 || t.Minutes > 59 || t.Seconds > 59 || int(t.Frames) >= t.Rate.Nominal() {
+		return fmt.Errorf("timecode is out of range: %s", t)
+	}
+	if t.Rate.DropFrame() && t.Seconds == 0 && t.Frames < 2 && t.Minutes%10 != 0 {
+		return fmt.Errorf("timecode %s does not exist in drop frame", t)
+	}
+	return nil
+}
+
+func (t Timecode) String() string {
+	separator := ":"
+	if t.Rate.DropFrame() {
+		separator = ";"
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%02d", t.Hours, t.Minutes, t.Seconds, separator, t.Frames)
+}
+
+// FrameCount is the number of real frames since 00:00:00:00
+func (t Timecode) FrameCount() int {
+	nominal := t.Rate.Nominal()
+	frames := ((int(t.Hours)*60+int(t.Minutes))*60+int(t.Seconds))*nominal + int(t.Frames)
+	if t.Rate.DropFrame() {
+		totalMinutes := int(t.Hours)*60 + int(t.Minutes)
+		frames -= 2 * (totalMinutes - totalMinutes/10)
+	}
+	return frames
+}
+
+// FromFrameCount converts a real frame count into timecode, wrapping at 24 hours
+func FromFrameCount(frames int, rate Rate) Timecode {
+	perDay := rate.FramesPerDay()
+	frames %= perDay
+	if frames < 0 {
+		frames += perDay
+	}
+
+	if rate.DropFrame() {
+		//NOTE(jwetzell): put back the two labels skipped every minute except every tenth
+		tenMinutes := frames / 17982
+		remainder := frames % 17982
+		frames += 18 * tenMinutes
+		if remainder >= 2 {
+			frames += 2 * ((remainder - 2) / 1798)
+		}
+	}
+
+	nominal := rate.Nominal()
+	return Timecode{
+		Hours:   uint8(frames / (nominal * 3600)),
+		Minutes: uint8(frames / (nominal * 60) % 60),
+		Seconds: uint8(frames / nominal % 60),
+		Frames:  uint8(frames % nominal),
+		Rate:    rate,
+	}
+}
+
+func (t Timecode) Add(frames int) Timecode {
+	return FromFrameCount(t.FrameCount()+frames, t.Rate)
+}
+
+func (t Timecode) Duration() time.Duration {
+	return time.Duration(float64(t.FrameCount()) / t.Rate.FPS() * float64(time.Second))
+}
+
+// FullFrame encodes the timecode as an MTC full frame SysEx message
+func (t Timecode) FullFrame() []byte {
+	return []byte{0xf0, 0x7f, 0x7f, 0x01, 0x01, byte(t.Rate&0x03)<<5 | t.Hours&0x1f, t.Minutes & 0x3f, t.Seconds & 0x3f, t.Frames & 0x1f, 0xf7}
+}
+
+// QuarterFrame encodes one of the eight MTC quarter frame pieces
+func (t Timecode) QuarterFrame(piece uint8) []byte {
+	var value uint8
+	switch piece & 0x07 {
+	case 0:
+		value = t.Frames & 0x0f
+	case 1:
+		value = t.Frames >> 4 & 0x01
+	case 2:
+		value = t.Seconds & 0x0f
+	case 3:
+		value = t.Seconds >> 4 & 0x03
+	case 4:
+		value = t.Minutes & 0x0f
+	case 5:
+		value = t.Minutes >> 4 & 0x03
+	case 6:
+		value = t.Hours & 0x0f
+	case 7:
+		value = t.Hours>>4&0x01 | uint8(t.Rate&0x03)<<1
+	}
+	return []byte{0xf1, (piece&0x07)<<4 | value}
+}
+
+func IsFullFrame(data []byte) bool {
+	return len(data) == 10 && data[0] == 0xf0 && data[1] == 0x7f && data[3] == 0x01 && data[4] == 0x01 && data[9] == 0xf7
+}
+
+func IsQuarterFrame(data []byte) bool {
+	return len(data) == 2 && data[0] == 0xf1
+}
+
+func DecodeFullFrame(data []byte) (Timecode, error) {
+	if !IsFullFrame(data) {
+		return Timecode{}, errors.New("not an MTC full frame message")
+	}
+	timecode := Timecode{
+		Rate:    Rate(data[5] >> 5 & 0x03),
+		Hours:   data[5] & 0x1f,
+		Minutes: data[6] & 0x3f,
+		Seconds: data[7] & 0x3f,
+		Frames:  data[8] & 0x1f,
+	}
+	return timecode, timecode.Validate()
+}
+
+// QuarterFrameDecoder collects MTC quarter frames, a timecode is complete once all eight pieces arrive in order
+type QuarterFrameDecoder struct {
+	pieces   [8]uint8
+	received uint8
+}
+
+// Decode returns true with a timecode when the message completed a set of eight pieces
+func (qfd *QuarterFrameDecoder) Decode(data []byte) (Timecode, bool, error) {
+	if !IsQuarterFrame(data) {
+		return Timecode{}, false, errors.New("not an MTC quarter frame message")
+	}
+
+	piece := data[1] >> 4 & 0x07
+	if piece == 0 {
+		qfd.received = 0
+	}
+	qfd.pieces[piece] = data[1] & 0x0f
+	qfd.received |= 1 << piece
+
+	if piece != 7 || qfd.received != 0xff {
+		return Timecode{}, false, nil
+	}
+	qfd.received = 0
+
+	timecode := Timecode{
+		Frames:  qfd.pieces[0] | (qfd.pieces[1]&0x01)<<4,
+		Seconds: qfd.pieces[2] | (qfd.pieces[3]&0x03)<<4,
+		Minutes: qfd.pieces[4] | (qfd.pieces[5]&0x03)<<4,
+		Hours:   qfd.pieces[6] | (qfd.pieces[7]&0x01)<<4,
+		Rate:    Rate(qfd.pieces[7] >> 1 & 0x03),
+	}
+	err := timecode.Validate()
+	if err != nil {
+		return Timecode{}, false, err
+	}
+	//NOTE(jwetzell): the eight pieces take two frames to send so the timecode they carry is two frames old
+	return timecode.Add(2), true, nil
+}
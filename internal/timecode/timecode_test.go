@@ -0,0 +1,99 @@
+package timecode_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/timecode"
+)
+
+func TestDropFrame(t *testing.T) {
+	tests := []struct {
+		frames   int
+		expected string
+	}{
+		{frames: 0, expected: "00:00:00;00"},
+		{frames: 1799, expected: "00:00:59;29"},
+		{frames: 1800, expected: "00:01:00;02"},
+		{frames: 17981, expected: "00:09:59;29"},
+		{frames: 17982, expected: "00:10:00;00"},
+		{frames: 107892, expected: "01:00:00;00"},
+	}
+
+	for _, test := range tests {
+		got := timecode.FromFrameCount(test.frames, timecode.Rate2997DF)
+		if got.String() != test.expected {
+			t.Fatalf("FromFrameCount(%d) got %s, expected %s", test.frames, got, test.expected)
+		}
+		if got.FrameCount() != test.frames {
+			t.Fatalf("FrameCount(%s) got %d, expected %d", got, got.FrameCount(), test.frames)
+		}
+	}
+
+	_, err := timecode.Parse("00:01:00;00", timecode.Rate2997DF)
+	if err == nil {
+		t.Fatalf("Parse should reject dropped labels")
+	}
+}
+
+func TestParse(t *testing.T) {
+	parsed, err := timecode.Parse("01:02:03:04", timecode.Rate25)
+	if err != nil {
+		t.Fatalf("Parse failed: %s", err)
+	}
+	if parsed != (timecode.Timecode{Hours: 1, Minutes: 2, Seconds: 3, Frames: 4, Rate: timecode.Rate25}) {
+		t.Fatalf("Parse got %+v", parsed)
+	}
+
+	if parsed.Add(21).String() != "01:02:04:00" {
+		t.Fatalf("Add got %s", parsed.Add(21))
+	}
+
+	if timecode.FromFrameCount(-1, timecode.Rate24).String() != "23:59:59:23" {
+		t.Fatalf("FromFrameCount should wrap below zero, got %s", timecode.FromFrameCount(-1, timecode.Rate24))
+	}
+
+	for _, value := range []string{"1:2:3", "01:02:03:25", "24:00:00:00", "aa:00:00:00"} {
+		_, err := timecode.Parse(value, timecode.Rate25)
+		if err == nil {
+			t.Fatalf("Parse should reject %s", value)
+		}
+	}
+}
+
+func TestMTC(t *testing.T) {
+	original := timecode.Timecode{Hours: 17, Minutes: 42, Seconds: 31, Frames: 28, Rate: timecode.Rate30}
+
+	fullFrame := original.FullFrame()
+	if !slices.Equal(fullFrame, []byte{0xf0, 0x7f, 0x7f, 0x01, 0x01, 0x71, 42, 31, 28, 0xf7}) {
+		t.Fatalf("FullFrame got %x", fullFrame)
+	}
+
+	decoded, err := timecode.DecodeFullFrame(fullFrame)
+	if err != nil || decoded != original {
+		t.Fatalf("DecodeFullFrame got %+v %v", decoded, err)
+	}
+
+	decoder := &timecode.QuarterFrameDecoder{}
+	for piece := range uint8(8) {
+		got, complete, err := decoder.Decode(original.QuarterFrame(piece))
+		if err != nil {
+			t.Fatalf("quarter frame %d failed: %s", piece, err)
+		}
+		if complete != (piece == 7) {
+			t.Fatalf("quarter frame %d complete should be %v", piece, piece == 7)
+		}
+		if complete && got != original.Add(2) {
+			t.Fatalf("quarter frames got %s, expected %s", got, original.Add(2))
+		}
+	}
+
+	//NOTE(jwetzell): starting mid sequence never completes
+	decoder = &timecode.QuarterFrameDecoder{}
+	for piece := uint8(4); piece < 8; piece++ {
+		_, complete, _ := decoder.Decode(original.QuarterFrame(piece))
+		if complete {
+			t.Fatalf("partial quarter frames should not complete")
+		}
+	}
+}
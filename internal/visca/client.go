@@ -0,0 +1,298 @@
+package visca
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	PayloadCommand        = 0x0100
+	PayloadInquiry        = 0x0110
+	PayloadReply          = 0x0111
+	PayloadControl        = 0x0200
+	PayloadControlReply   = 0x0201
+	controlReset          = 0x01
+	controlErrorSequence  = 0x01
+	controlErrorMessage   = 0x02
+	controlErrorIndicator = 0x0f
+)
+
+var ErrTimeout = errors.New("VISCA camera did not reply")
+
+// EncodeIP wraps a payload in the 8 byte VISCA over IP header
+func EncodeIP(payloadType uint16, sequence uint32, payload []byte) []byte {
+	packet := make([]byte, 8, 8+len(payload))
+	binary.BigEndian.PutUint16(packet[0:2], payloadType)
+	binary.BigEndian.PutUint16(packet[2:4], uint16(len(payload)))
+	binary.BigEndian.PutUint32(packet[4:8], sequence)
+	return append(packet, payload...)
+}
+
+func DecodeIP(packet []byte) (uint16, uint32, []byte, error) {
+	if len(packet) < 8 {
+		return 0, 0, nil, errors.New("VISCA over IP packet is too short")
+	}
+	length := int(binary.BigEndian.Uint16(packet[2:4]))
+	if len(packet) != 8+length {
+		return 0, 0, nil, fmt.Errorf("VISCA over IP packet length is %d but header says %d", len(packet)-8, length)
+	}
+	return binary.BigEndian.Uint16(packet[0:2]), binary.BigEndian.Uint32(packet[4:8]), packet[8:], nil
+}
+
+// Response pairs a message sent to a camera with a reply to it
+type Response struct {
+	Message Message
+	Reply   Reply
+}
+
+type received struct {
+	control  bool
+	sequence uint32
+	reply    Reply
+	payload  []byte
+}
+
+// Client sends one message at a time to a camera over UDP, retrying until the camera acknowledges it
+type Client struct {
+	Timeout time.Duration
+	Retries int
+	// Header wraps messages in the VISCA over IP header, cameras that take raw VISCA over UDP need it off
+	Header bool
+	// OnCompletion is called with completions and errors that arrive after a command was acknowledged
+	OnCompletion func(Response)
+	conn         net.Conn
+	sequence     uint32
+	sendMu       sync.Mutex
+	pendingMu    sync.Mutex
+	pending      *Message
+	sockets      map[uint8]Message
+	replies      chan received
+	done         chan struct{}
+}
+
+func NewClient(conn net.Conn, header bool, timeout time.Duration, retries int) *Client {
+	client := &Client{
+		Timeout: timeout,
+		Retries: retries,
+		Header:  header,
+		conn:    conn,
+		sockets: map[uint8]Message{},
+		replies: make(chan received, 8),
+		done:    make(chan struct{}),
+	}
+	go client.read()
+	return client
+}
+
+func (c *Client) read() {
+	defer close(c.done)
+	buffer := make([]byte, 1024)
+	for {
+		n, err := c.conn.Read(buffer)
+		if err != nil {
+			return
+		}
+
+		packet := received{payload: append([]byte{}, buffer[:n]...)}
+		if c.Header {
+			payloadType, sequence, payload, err := DecodeIP(packet.payload)
+			if err != nil {
+				continue
+			}
+			packet.sequence = sequence
+			packet.payload = payload
+			switch payloadType {
+			case PayloadControlReply:
+				packet.control = true
+			case PayloadReply:
+			default:
+				continue
+			}
+		}
+
+		if !packet.control {
+			reply, err := ParseReply(packet.payload)
+			if err != nil {
+				continue
+			}
+			packet.reply = reply
+			if c.route(reply) {
+				continue
+			}
+		}
+
+		select {
+		case c.replies <- packet:
+		default:
+		}
+	}
+}
+
+// route tracks the socket a command was acknowledged on and hands later replies on that socket to OnCompletion
+func (c *Client) route(reply Reply) bool {
+	c.pendingMu.Lock()
+	if reply.Kind == ReplyAck {
+		if c.pending != nil {
+			c.sockets[reply.Socket] = *c.pending
+		}
+		c.pendingMu.Unlock()
+		return false
+	}
+
+	message, ok := c.sockets[reply.Socket]
+	if !ok || reply.Socket == 0 {
+		c.pendingMu.Unlock()
+		return false
+	}
+	delete(c.sockets, reply.Socket)
+	c.pendingMu.Unlock()
+
+	if c.OnCompletion != nil {
+		c.OnCompletion(Response{Message: message, Reply: reply})
+	}
+	return true
+}
+
+func (c *Client) write(payloadType uint16, payload []byte) error {
+	if c.Header {
+		payload = EncodeIP(payloadType, c.sequence, payload)
+	}
+	_, err := c.conn.Write(payload)
+	return err
+}
+
+// wait waits for a reply to the message with the current sequence number
+func (c *Client) wait(timer *time.Timer) (received, error) {
+	for {
+		select {
+		case <-c.done:
+			return received{}, net.ErrClosed
+		case <-timer.C:
+			return received{}, ErrTimeout
+		case packet := <-c.replies:
+			if c.Header && packet.sequence != c.sequence {
+				continue
+			}
+			return packet, nil
+		}
+	}
+}
+
+func (c *Client) drain() {
+	for {
+		select {
+		case <-c.replies:
+		default:
+			return
+		}
+	}
+}
+
+// Reset resets the sequence number the camera expects, only used with the VISCA over IP header
+func (c *Client) Reset() error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	return c.reset()
+}
+
+func (c *Client) reset() error {
+	c.drain()
+	timer := time.NewTimer(c.Timeout)
+	defer timer.Stop()
+
+	for attempt := 0; attempt <= c.Retries; attempt++ {
+		err := c.write(PayloadControl, []byte{controlReset})
+		if err != nil {
+			return err
+		}
+		timer.Reset(c.Timeout)
+		packet, err := c.wait(timer)
+		if errors.Is(err, ErrTimeout) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !packet.control || len(packet.payload) != 1 || packet.payload[0] != controlReset {
+			return fmt.Errorf("VISCA reset got an unexpected reply: % X", packet.payload)
+		}
+		c.sequence = 0
+		return nil
+	}
+	return ErrTimeout
+}
+
+// Send sends a message and waits for the camera to acknowledge a command or answer an inquiry
+func (c *Client) Send(message Message) (Reply, error) {
+	err := message.Validate()
+	if err != nil {
+		return Reply{}, err
+	}
+
+	payloadType := uint16(PayloadCommand)
+	if message.Inquiry() {
+		payloadType = PayloadInquiry
+	}
+
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	c.drain()
+	c.pendingMu.Lock()
+	c.pending = &message
+	c.pendingMu.Unlock()
+	defer func() {
+		c.pendingMu.Lock()
+		c.pending = nil
+		c.pendingMu.Unlock()
+	}()
+
+	c.sequence++
+	timer := time.NewTimer(c.Timeout)
+	defer timer.Stop()
+
+	//NOTE(jwetzell): UDP can drop either way so resend with the same sequence number until something comes back
+	for attempt := 0; attempt <= c.Retries; attempt++ {
+		err := c.write(payloadType, message.Data)
+		if err != nil {
+			return Reply{}, err
+		}
+		timer.Reset(c.Timeout)
+		packet, err := c.wait(timer)
+		if errors.Is(err, ErrTimeout) {
+			continue
+		}
+		if err != nil {
+			return Reply{}, err
+		}
+
+		if packet.control {
+			if len(packet.payload) == 2 && packet.payload[0] == controlErrorIndicator && packet.payload[1] == controlErrorSequence {
+				err := c.reset()
+				if err != nil {
+					return Reply{}, err
+				}
+				c.sequence++
+				continue
+			}
+			if len(packet.payload) == 2 && packet.payload[0] == controlErrorIndicator && packet.payload[1] == controlErrorMessage {
+				return Reply{}, errors.New("VISCA camera could not parse the message")
+			}
+			return Reply{}, fmt.Errorf("VISCA camera rejected the message: % X", packet.payload)
+		}
+
+		if packet.reply.Kind == ReplyError {
+			return packet.reply, packet.reply.Error
+		}
+		return packet.reply, nil
+	}
+	return Reply{}, ErrTimeout
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
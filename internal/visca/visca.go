@@ -0,0 +1,305 @@
+// Package visca builds VISCA camera commands and inquiries, parses camera replies and frames them for VISCA over IP
+package visca
+
+import (
+	"errors"
+	"fmt"
+)
+
+const DefaultPort = 52381
+
+const (
+	MaxPanSpeed   = 0x18
+	MaxTiltSpeed  = 0x14
+	MaxZoomSpeed  = 7
+	MaxFocusSpeed = 7
+	MaxPreset     = 0x7f
+)
+
+const (
+	InquiryPower           = "power"
+	InquiryPanTiltPosition = "panTiltPosition"
+	InquiryZoomPosition    = "zoomPosition"
+	InquiryFocusPosition   = "focusPosition"
+)
+
+var inquiries = map[string][]byte{
+	InquiryPower:           {0x81, 0x09, 0x04, 0x00, 0xff},
+	InquiryPanTiltPosition: {0x81, 0x09, 0x06, 0x12, 0xff},
+	InquiryZoomPosition:    {0x81, 0x09, 0x04, 0x47, 0xff},
+	InquiryFocusPosition:   {0x81, 0x09, 0x04, 0x48, 0xff},
+}
+
+var replyErrors = map[byte]string{
+	0x01: "message length error",
+	0x02: "syntax error",
+	0x03: "command buffer full",
+	0x04: "command canceled",
+	0x05: "no socket",
+	0x41: "command not executable",
+}
+
+// Message is a single VISCA command or inquiry starting with the 8x address byte and ending with FF
+type Message struct {
+	Data []byte
+}
+
+func (m Message) Inquiry() bool {
+	return len(m.Data) > 1 && m.Data[1] == 0x09
+}
+
+func (m Message) Validate() error {
+	if len(m.Data) < 3 || len(m.Data) > 16 {
+		return errors.New("VISCA message must be between 3 and 16 bytes")
+	}
+	if m.Data[0]&0xf0 != 0x80 {
+		return errors.New("VISCA message must start with a camera address")
+	}
+	if m.Data[len(m.Data)-1] != 0xff {
+		return errors.New("VISCA message must end with FF")
+	}
+	return nil
+}
+
+// InquiryKind returns the name of the inquiry the message is, or an empty string when it isn't one this package decodes
+func (m Message) InquiryKind() string {
+	for kind, data := range inquiries {
+		if len(m.Data) == len(data) && string(m.Data[1:]) == string(data[1:]) {
+			return kind
+		}
+	}
+	return ""
+}
+
+func command(data ...byte) Message {
+	return Message{Data: append(append([]byte{0x81, 0x01}, data...), 0xff)}
+}
+
+func nibbles(value uint16) []byte {
+	return []byte{byte(value>>12) & 0x0f, byte(value>>8) & 0x0f, byte(value>>4) & 0x0f, byte(value) & 0x0f}
+}
+
+func fromNibbles(data []byte) uint16 {
+	value := uint16(0)
+	for _, nibble := range data {
+		value = value<<4 | uint16(nibble&0x0f)
+	}
+	return value
+}
+
+func Power(on bool) Message {
+	if on {
+		return command(0x04, 0x00, 0x02)
+	}
+	return command(0x04, 0x00, 0x03)
+}
+
+// PanTiltDrive moves the camera, the sign of each speed is the direction and 0 stops that axis
+func PanTiltDrive(panSpeed int, tiltSpeed int) (Message, error) {
+	if panSpeed < -MaxPanSpeed || panSpeed > MaxPanSpeed {
+		return Message{}, fmt.Errorf("VISCA pan speed must be between %d and %d", -MaxPanSpeed, MaxPanSpeed)
+	}
+	if tiltSpeed < -MaxTiltSpeed || tiltSpeed > MaxTiltSpeed {
+		return Message{}, fmt.Errorf("VISCA tilt speed must be between %d and %d", -MaxTiltSpeed, MaxTiltSpeed)
+	}
+
+	panDirection := byte(0x03)
+	if panSpeed < 0 {
+		panDirection = 0x01
+	} else if panSpeed > 0 {
+		panDirection = 0x02
+	}
+
+	tiltDirection := byte(0x03)
+	if tiltSpeed > 0 {
+		tiltDirection = 0x01
+	} else if tiltSpeed < 0 {
+		tiltDirection = 0x02
+	}
+
+	//NOTE(jwetzell): stopped axes still need a valid speed byte
+	return command(0x06, 0x01, byte(max(abs(panSpeed), 1)), byte(max(abs(tiltSpeed), 1)), panDirection, tiltDirection), nil
+}
+
+func PanTiltHome() Message {
+	return command(0x06, 0x04)
+}
+
+// ZoomDrive zooms in for positive speeds, out for negative speeds and stops at 0
+func ZoomDrive(speed int) (Message, error) {
+	if speed < -MaxZoomSpeed || speed > MaxZoomSpeed {
+		return Message{}, fmt.Errorf("VISCA zoom speed must be between %d and %d", -MaxZoomSpeed, MaxZoomSpeed)
+	}
+	if speed == 0 {
+		return command(0x04, 0x07, 0x00), nil
+	}
+	if speed > 0 {
+		return command(0x04, 0x07, 0x20|byte(speed)), nil
+	}
+	return command(0x04, 0x07, 0x30|byte(-speed)), nil
+}
+
+func ZoomDirect(position uint16) Message {
+	return command(append([]byte{0x04, 0x47}, nibbles(position)...)...)
+}
+
+// FocusDrive focuses far for positive speeds, near for negative speeds and stops at 0
+func FocusDrive(speed int) (Message, error) {
+	if speed < -MaxFocusSpeed || speed > MaxFocusSpeed {
+		return Message{}, fmt.Errorf("VISCA focus speed must be between %d and %d", -MaxFocusSpeed, MaxFocusSpeed)
+	}
+	if speed == 0 {
+		return command(0x04, 0x08, 0x00), nil
+	}
+	if speed > 0 {
+		return command(0x04, 0x08, 0x20|byte(speed)), nil
+	}
+	return command(0x04, 0x08, 0x30|byte(-speed)), nil
+}
+
+func FocusDirect(position uint16) Message {
+	return command(append([]byte{0x04, 0x48}, nibbles(position)...)...)
+}
+
+func FocusMode(mode string) (Message, error) {
+	switch mode {
+	case "auto":
+		return command(0x04, 0x38, 0x02), nil
+	case "manual":
+		return command(0x04, 0x38, 0x03), nil
+	case "onePush":
+		return command(0x04, 0x18, 0x01), nil
+	}
+	return Message{}, fmt.Errorf("VISCA focus mode must be auto, manual or onePush, got %s", mode)
+}
+
+// Preset recalls, sets or resets a memory preset
+func Preset(action string, preset int) (Message, error) {
+	if preset < 0 || preset > MaxPreset {
+		return Message{}, fmt.Errorf("VISCA preset must be between 0 and %d", MaxPreset)
+	}
+	switch action {
+	case "reset":
+		return command(0x04, 0x3f, 0x00, byte(preset)), nil
+	case "set":
+		return command(0x04, 0x3f, 0x01, byte(preset)), nil
+	case "recall":
+		return command(0x04, 0x3f, 0x02, byte(preset)), nil
+	}
+	return Message{}, fmt.Errorf("VISCA preset action must be recall, set or reset, got %s", action)
+}
+
+func Inquiry(kind string) (Message, error) {
+	data, ok := inquiries[kind]
+	if !ok {
+		return Message{}, fmt.Errorf("VISCA inquiry is unknown: %s", kind)
+	}
+	return Message{Data: append([]byte{}, data...)}, nil
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+const (
+	ReplyAck        = "ack"
+	ReplyCompletion = "completion"
+	ReplyError      = "error"
+)
+
+// ReplyErrorCode is the error code a camera answered a message with
+type ReplyErrorCode byte
+
+func (e ReplyErrorCode) Error() string {
+	description, ok := replyErrors[byte(e)]
+	if !ok {
+		description = "unknown error"
+	}
+	return fmt.Sprintf("VISCA error %02X (%s)", byte(e), description)
+}
+
+// Reply is a parsed camera reply, Data holds inquiry values between the 50 and the FF
+type Reply struct {
+	Kind   string
+	Socket uint8
+	Data   []byte
+	Error  error
+}
+
+func ParseReply(data []byte) (Reply, error) {
+	if len(data) < 3 || data[0]&0x8f != 0x80 || data[len(data)-1] != 0xff {
+		return Reply{}, fmt.Errorf("VISCA reply is malformed: % X", data)
+	}
+
+	reply := Reply{Socket: data[1] & 0x0f}
+	switch data[1] & 0xf0 {
+	case 0x40:
+		reply.Kind = ReplyAck
+	case 0x50:
+		reply.Kind = ReplyCompletion
+		reply.Data = append([]byte{}, data[2:len(data)-1]...)
+	case 0x60:
+		if len(data) != 4 {
+			return Reply{}, fmt.Errorf("VISCA error reply is malformed: % X", data)
+		}
+		reply.Kind = ReplyError
+		reply.Error = ReplyErrorCode(data[2])
+	default:
+		return Reply{}, fmt.Errorf("VISCA reply type is unknown: % X", data)
+	}
+	return reply, nil
+}
+
+type PowerStatus struct {
+	On bool
+}
+
+// PanTiltPosition holds the signed pan and tilt motor positions
+type PanTiltPosition struct {
+	Pan  int16
+	Tilt int16
+}
+
+type ZoomPosition struct {
+	Zoom uint16
+}
+
+type FocusPosition struct {
+	Focus uint16
+}
+
+// DecodeInquiry decodes the data of an inquiry completion into the struct for that inquiry
+func DecodeInquiry(kind string, data []byte) (any, error) {
+	switch kind {
+	case InquiryPower:
+		if len(data) != 1 {
+			return nil, errors.New("VISCA power reply must be 1 byte")
+		}
+		switch data[0] {
+		case 0x02:
+			return PowerStatus{On: true}, nil
+		case 0x03, 0x04:
+			return PowerStatus{On: false}, nil
+		}
+		return nil, fmt.Errorf("VISCA power reply is unknown: %02X", data[0])
+	case InquiryPanTiltPosition:
+		if len(data) != 8 {
+			return nil, errors.New("VISCA pan tilt position reply must be 8 bytes")
+		}
+		return PanTiltPosition{Pan: int16(fromNibbles(data[:4])), Tilt: int16(fromNibbles(data[4:]))}, nil
+	case InquiryZoomPosition:
+		if len(data) != 4 {
+			return nil, errors.New("VISCA zoom position reply must be 4 bytes")
+		}
+		return ZoomPosition{Zoom: fromNibbles(data)}, nil
+	case InquiryFocusPosition:
+		if len(data) != 4 {
+			return nil, errors.New("VISCA focus position reply must be 4 bytes")
+		}
+		return FocusPosition{Focus: fromNibbles(data)}, nil
+	}
+	return nil, fmt.Errorf("VISCA inquiry is unknown: %s", kind)
+}
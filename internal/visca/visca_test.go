@@ -0,0 +1,187 @@
+package visca_test
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/visca"
+)
+
+func TestCommands(t *testing.T) {
+	panTilt, err := visca.PanTiltDrive(-5, 3)
+	if err != nil {
+		t.Fatalf("VISCA pan tilt drive failed: %s", err)
+	}
+
+	zoomOut, err := visca.ZoomDrive(-2)
+	if err != nil {
+		t.Fatalf("VISCA zoom drive failed: %s", err)
+	}
+
+	recall, err := visca.Preset("recall", 12)
+	if err != nil {
+		t.Fatalf("VISCA preset failed: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		message  visca.Message
+		expected []byte
+	}{
+		{name: "power on", message: visca.Power(true), expected: []byte{0x81, 0x01, 0x04, 0x00, 0x02, 0xff}},
+		{name: "pan left tilt up", message: panTilt, expected: []byte{0x81, 0x01, 0x06, 0x01, 0x05, 0x03, 0x01, 0x01, 0xff}},
+		{name: "zoom out", message: zoomOut, expected: []byte{0x81, 0x01, 0x04, 0x07, 0x32, 0xff}},
+		{name: "zoom direct", message: visca.ZoomDirect(0x4000), expected: []byte{0x81, 0x01, 0x04, 0x47, 0x04, 0x00, 0x00, 0x00, 0xff}},
+		{name: "preset recall", message: recall, expected: []byte{0x81, 0x01, 0x04, 0x3f, 0x02, 0x0c, 0xff}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if !reflect.DeepEqual(test.message.Data, test.expected) {
+				t.Fatalf("VISCA message got % X, expected % X", test.message.Data, test.expected)
+			}
+			if test.message.Validate() != nil {
+				t.Fatalf("VISCA message should be valid")
+			}
+		})
+	}
+
+	_, err = visca.PanTiltDrive(25, 0)
+	if err == nil || err.Error() != "VISCA pan speed must be between -24 and 24" {
+		t.Fatalf("VISCA pan tilt drive should reject a fast pan, got %v", err)
+	}
+}
+
+func TestDecodeInquiry(t *testing.T) {
+	inquiry, err := visca.Inquiry(visca.InquiryPanTiltPosition)
+	if err != nil {
+		t.Fatalf("VISCA inquiry failed: %s", err)
+	}
+	if inquiry.InquiryKind() != visca.InquiryPanTiltPosition || !inquiry.Inquiry() {
+		t.Fatalf("VISCA inquiry kind got %s", inquiry.InquiryKind())
+	}
+
+	reply, err := visca.ParseReply([]byte{0x90, 0x50, 0x0f, 0x0f, 0x0f, 0x0e, 0x00, 0x01, 0x02, 0x03, 0xff})
+	if err != nil {
+		t.Fatalf("VISCA reply failed to parse: %s", err)
+	}
+
+	position, err := visca.DecodeInquiry(inquiry.InquiryKind(), reply.Data)
+	if err != nil {
+		t.Fatalf("VISCA position failed to decode: %s", err)
+	}
+	if position != (visca.PanTiltPosition{Pan: -2, Tilt: 0x0123}) {
+		t.Fatalf("VISCA position got %+v", position)
+	}
+
+	reply, err = visca.ParseReply([]byte{0x90, 0x61, 0x41, 0xff})
+	if err != nil {
+		t.Fatalf("VISCA error reply failed to parse: %s", err)
+	}
+	if reply.Kind != visca.ReplyError || reply.Socket != 1 || reply.Error.Error() != "VISCA error 41 (command not executable)" {
+		t.Fatalf("VISCA error reply got %+v", reply)
+	}
+}
+
+func TestIPHeader(t *testing.T) {
+	packet := visca.EncodeIP(visca.PayloadCommand, 0x01020304, []byte{0x81, 0x01, 0x04, 0x00, 0x02, 0xff})
+	expected := []byte{0x01, 0x00, 0x00, 0x06, 0x01, 0x02, 0x03, 0x04, 0x81, 0x01, 0x04, 0x00, 0x02, 0xff}
+	if !reflect.DeepEqual(packet, expected) {
+		t.Fatalf("VISCA over IP packet got % X", packet)
+	}
+
+	payloadType, sequence, payload, err := visca.DecodeIP(packet)
+	if err != nil || payloadType != visca.PayloadCommand || sequence != 0x01020304 || len(payload) != 6 {
+		t.Fatalf("VISCA over IP packet decoded to %04X %d % X %v", payloadType, sequence, payload, err)
+	}
+}
+
+func TestClient(t *testing.T) {
+	camera, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer camera.Close()
+
+	//NOTE(jwetzell): the fake camera drops the first copy of every command to exercise retries
+	go func() {
+		seen := map[uint32]bool{}
+		buffer := make([]byte, 1024)
+		for {
+			n, addr, err := camera.ReadFromUDP(buffer)
+			if err != nil {
+				return
+			}
+			payloadType, sequence, _, err := visca.DecodeIP(buffer[:n])
+			if err != nil {
+				continue
+			}
+			switch payloadType {
+			case visca.PayloadControl:
+				camera.WriteToUDP(visca.EncodeIP(visca.PayloadControlReply, sequence, []byte{0x01}), addr)
+			case visca.PayloadCommand:
+				if !seen[sequence] {
+					seen[sequence] = true
+					continue
+				}
+				camera.WriteToUDP(visca.EncodeIP(visca.PayloadReply, sequence, []byte{0x90, 0x41, 0xff}), addr)
+				camera.WriteToUDP(visca.EncodeIP(visca.PayloadReply, sequence, []byte{0x90, 0x51, 0xff}), addr)
+			case visca.PayloadInquiry:
+				camera.WriteToUDP(visca.EncodeIP(visca.PayloadReply, sequence, []byte{0x90, 0x50, 0x02, 0xff}), addr)
+			}
+		}
+	}()
+
+	conn, err := net.Dial("udp", camera.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+
+	client := visca.NewClient(conn, true, 50*time.Millisecond, 2)
+	defer client.Close()
+
+	completions := make(chan visca.Response, 1)
+	client.OnCompletion = func(response visca.Response) {
+		completions <- response
+	}
+
+	err = client.Reset()
+	if err != nil {
+		t.Fatalf("VISCA client reset failed: %s", err)
+	}
+
+	reply, err := client.Send(visca.Power(true))
+	if err != nil {
+		t.Fatalf("VISCA client send failed: %s", err)
+	}
+	if reply.Kind != visca.ReplyAck {
+		t.Fatalf("VISCA client should return the ack, got %+v", reply)
+	}
+
+	select {
+	case completion := <-completions:
+		if completion.Reply.Kind != visca.ReplyCompletion || !reflect.DeepEqual(completion.Message, visca.Power(true)) {
+			t.Fatalf("VISCA client completion got %+v", completion)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("VISCA client never saw the completion")
+	}
+
+	inquiry, _ := visca.Inquiry(visca.InquiryPower)
+	reply, err = client.Send(inquiry)
+	if err != nil {
+		t.Fatalf("VISCA client inquiry failed: %s", err)
+	}
+	if reply.Kind != visca.ReplyCompletion || !reflect.DeepEqual(reply.Data, []byte{0x02}) {
+		t.Fatalf("VISCA client inquiry got %+v", reply)
+	}
+
+	client.Retries = 0
+	_, err = client.Send(visca.Power(false))
+	if !errors.Is(err, visca.ErrTimeout) {
+		t.Fatalf("VISCA client should time out without retries, got %v", err)
+	}
+}
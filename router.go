@@ -4,13 +4,18 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 
 	"github.com/jwetzell/showbridge-go/internal/api"
 	"github.com/jwetzell/showbridge-go/internal/common"
 	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/eventlog"
 	"github.com/jwetzell/showbridge-go/internal/module"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/recording"
 	"github.com/jwetzell/showbridge-go/internal/route"
 )
 
@@ -20,6 +25,8 @@ type Router struct {
 	Context       context.Context
 	// TODO(jwetzell): do these need to be guarded against concurrency?
 	ModuleInstances map[string]common.Module
+	// moduleIds holds the id of every module instance so duplicates are caught without walking ModuleInstances
+	moduleIds map[string]bool
 	// TODO(jwetzell): change to something easier to lookup
 	RouteInstances      []*route.Route
 	ConfigChange        chan config.Config
@@ -30,28 +37,40 @@ type Router struct {
 	apiServer           *api.ApiServer
 	eventDestinations   []common.EventDestination
 	eventDestinationsMu sync.Mutex
+	moduleStatuses      map[string]common.ModuleStatus
+	moduleStatusesMu    sync.RWMutex
+	eventLog            *eventlog.EventLog
+	eventLogMu          sync.RWMutex
+	recorder            *recording.Recorder
+	processorGroups     *processor.Groups
 }
 
-func (r *Router) addModule(moduleDecl config.ModuleConfig) error {
+// newModule constructs a module after checking its id is not already in moduleIds, callers add the id once the module is kept
+func newModule(moduleDecl config.ModuleConfig, moduleIds map[string]bool) (common.Module, error) {
 	if moduleDecl.Id == "" {
-		return errors.New("module id cannot be empty")
+		return nil, errors.New("module id cannot be empty")
 	}
 	moduleRegistration, ok := module.GetModuleRegistration(moduleDecl.Type)
 	if !ok {
-		return errors.New("module type not defined")
+		return nil, errors.New("module type not defined")
 	}
 
-	_, ok = r.ModuleInstances[moduleDecl.Id]
-	if ok {
-		return errors.New("module id already exists")
+	if moduleIds[moduleDecl.Id] {
+		return nil, errors.New("module id already exists")
 	}
 
-	moduleInstance, err := moduleRegistration.New(moduleDecl)
+	return moduleRegistration.New(moduleDecl)
+}
+
+func (r *Router) addModule(moduleDecl config.ModuleConfig) error {
+	moduleInstance, err := newModule(moduleDecl, r.moduleIds)
 	if err != nil {
 		return err
 	}
 
 	r.ModuleInstances[moduleDecl.Id] = moduleInstance
+	r.moduleIds[moduleDecl.Id] = true
+	r.setModuleStatus(moduleInstance, "stopped", nil)
 	return nil
 }
 
@@ -61,6 +80,7 @@ func (r *Router) removeModule(moduleId string) error {
 		return err
 	}
 	delete(r.ModuleInstances, moduleId)
+	delete(r.moduleIds, moduleId)
 	return nil
 }
 
@@ -69,12 +89,23 @@ func (r *Router) startModule(ctx context.Context, moduleId string) error {
 	if moduleInstance == nil {
 		return errors.New("module id not found")
 	}
+	inputHandler := r.HandleInput
+	if r.recorder != nil {
+		inputHandler = r.recorder.InputHandler(inputHandler)
+	}
+	moduleContext := common.WithModuleStatusReporter(ctx, func(status string, err error) {
+		r.setModuleStatus(moduleInstance, status, err)
+	})
 	r.moduleWait.Go(func() {
-		err := moduleInstance.Start(ctx, r.HandleInput)
+		r.setModuleStatus(moduleInstance, "running", nil)
+		err := moduleInstance.Start(moduleContext, inputHandler)
 		if err != nil {
 			// TODO(jwetzell): propagate module run errors better
 			r.logger.Error("error encountered running module", "moduleId", moduleId, "error", err)
+			r.setModuleStatus(moduleInstance, "error", err)
+			return
 		}
+		r.setModuleStatus(moduleInstance, "stopped", nil)
 	})
 	return nil
 }
@@ -94,6 +125,10 @@ func (r *Router) addRoute(routeDecl config.RouteConfig) error {
 	if err != nil {
 		return err
 	}
+	err = routeInstance.JoinGroups(r.processorGroups)
+	if err != nil {
+		return err
+	}
 	r.RouteInstances = append(r.RouteInstances, routeInstance)
 	return nil
 }
@@ -110,12 +145,17 @@ func NewRouter(routerConfig config.Config) (*Router, []config.ModuleError, []con
 
 	router := Router{
 		ModuleInstances: make(map[string]common.Module),
+		moduleIds:       make(map[string]bool),
 		RouteInstances:  []*route.Route{},
+		processorGroups: processor.NewGroups(),
 		ConfigChange:    make(chan config.Config, 1),
+		moduleStatuses:  make(map[string]common.ModuleStatus),
 		logger:          slog.Default().With("component", "router"),
 		runningConfig:   routerConfig,
 	}
 	router.logger.Debug("creating")
+	router.eventLog = router.newEventLog(routerConfig.EventLog)
+	router.recorder = router.newRecorder(routerConfig.Recording)
 
 	var moduleErrors []config.ModuleError
 
@@ -152,7 +192,7 @@ func NewRouter(routerConfig config.Config) (*Router, []config.ModuleError, []con
 		}
 	}
 
-	apiServer := api.NewApiServer(&router, &router)
+	apiServer := api.NewApiServer(&router)
 
 	router.apiServer = apiServer
 
@@ -176,8 +216,16 @@ func (r *Router) Stop() {
 	r.stopModules()
 	r.logger.Debug("waiting for modules to exit")
 	r.moduleWait.Wait()
+	if r.recorder != nil {
+		r.logger.Debug("closing recording")
+		r.recorder.Close()
+	}
 	r.logger.Debug("canceling router context")
 	r.contextCancel()
+	r.logger.Debug("closing event log")
+	r.eventLogMu.Lock()
+	r.eventLog.Close()
+	r.eventLogMu.Unlock()
 	r.logger.Info("done")
 }
 
@@ -226,7 +274,9 @@ func (r *Router) HandleInput(ctx context.Context, sourceId string, payload any)
 					r.broadcastEvent(common.Event{
 						Type: "route",
 						Data: map[string]any{
-							"index": routeIndex,
+							"index":  routeIndex,
+							"id":     routeInstance.Id(),
+							"source": sourceId,
 						},
 						Error: err.Error(),
 					})
@@ -235,7 +285,9 @@ func (r *Router) HandleInput(ctx context.Context, sourceId string, payload any)
 				r.broadcastEvent(common.Event{
 					Type: "route",
 					Data: map[string]any{
-						"index": routeIndex,
+						"index":  routeIndex,
+						"id":     routeInstance.Id(),
+						"source": sourceId,
 					},
 				})
 			})
@@ -264,3 +316,54 @@ func (r *Router) stopModules() {
 		}
 	}
 }
+
+func (r *Router) setModuleStatus(moduleInstance common.Module, status string, err error) {
+	moduleStatus := common.ModuleStatus{
+		Id:     moduleInstance.Id(),
+		Type:   moduleInstance.Type(),
+		Status: status,
+	}
+	if err != nil {
+		moduleStatus.Error = err.Error()
+	}
+
+	r.moduleStatusesMu.Lock()
+	r.moduleStatuses[moduleStatus.Id] = moduleStatus
+	r.moduleStatusesMu.Unlock()
+
+	r.broadcastEvent(common.Event{
+		Type: "module",
+		Data: map[string]any{
+			"id":     moduleStatus.Id,
+			"type":   moduleStatus.Type,
+			"status": moduleStatus.Status,
+		},
+		Error: moduleStatus.Error,
+	})
+}
+
+func (r *Router) GetModuleStatuses() []common.ModuleStatus {
+	r.moduleStatusesMu.RLock()
+	defer r.moduleStatusesMu.RUnlock()
+
+	moduleStatuses := make([]common.ModuleStatus, 0, len(r.moduleStatuses))
+	for _, moduleStatus := range r.moduleStatuses {
+		moduleStatuses = append(moduleStatuses, moduleStatus)
+	}
+	slices.SortFunc(moduleStatuses, func(a, b common.ModuleStatus) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+	return moduleStatuses
+}
+
+func (r *Router) newRecorder(recordingConfig *config.RecordingConfig) *recording.Recorder {
+	if recordingConfig == nil {
+		return nil
+	}
+	recorder, err := recording.NewRecorder(*recordingConfig)
+	if err != nil {
+		r.logger.Error("unable to start recording", "error", err)
+		return nil
+	}
+	return recorder
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/jwetzell/showbridge-go/internal/common"
 	"github.com/jwetzell/showbridge-go/internal/config"
 	"github.com/jwetzell/showbridge-go/internal/module"
+	"github.com/jwetzell/showbridge-go/internal/routetest"
+	"github.com/jwetzell/showbridge-go/internal/test"
 )
 
 func init() {
@@ -46,6 +48,10 @@ func (mcm *MockCounterModule) Start(ctx context.Context, inputHandler common.Inp
 	moduleContext, cancel := context.WithCancel(ctx)
 	mcm.ctx = moduleContext
 	mcm.cancel = cancel
+	status, err := mcm.config.Params.GetString("status")
+	if err == nil {
+		common.ReportModuleStatus(ctx, status, nil)
+	}
 	<-mcm.ctx.Done()
 	return nil
 }
@@ -549,3 +555,385 @@ func TestRouterInputMultipleModules(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name               string
+		config             config.Config
+		moduleErrorStrings []string
+		routeErrorStrings  []string
+	}{
+		{
+			name: "valid config",
+			config: config.Config{
+				Modules: []config.ModuleConfig{
+					{
+						Id:   "mock",
+						Type: "mock.counter",
+					},
+				},
+				Routes: []config.RouteConfig{
+					{
+						Input: "mock",
+						Processors: []config.ProcessorConfig{
+							{
+								Type: "module.output",
+								Params: config.Params{
+									"module": "mock",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "duplicate module id",
+			config: config.Config{
+				Modules: []config.ModuleConfig{
+					{
+						Id:   "mock",
+						Type: "mock.counter",
+					},
+					{
+						Id:   "mock",
+						Type: "mock.counter",
+					},
+				},
+			},
+			moduleErrorStrings: []string{"module id already exists"},
+		},
+		{
+			name: "unknown module type and processor",
+			config: config.Config{
+				Modules: []config.ModuleConfig{
+					{
+						Id:   "mock",
+						Type: "asd.fjlkj23oiu4ksldj",
+					},
+				},
+				Routes: []config.RouteConfig{
+					{
+						Input: "mock",
+						Processors: []config.ProcessorConfig{
+							{
+								Type: "asdfasdf",
+							},
+						},
+					},
+				},
+			},
+			moduleErrorStrings: []string{"module type not defined"},
+			routeErrorStrings:  []string{"problem loading processor registration for processor type: asdfasdf"},
+		},
+		{
+			name: "conflicting processor group",
+			config: config.Config{
+				Routes: []config.RouteConfig{
+					{
+						Input: "a",
+						Processors: []config.ProcessorConfig{
+							{
+								Type: "dmx.merge",
+								Params: config.Params{
+									"group": "lights",
+								},
+							},
+						},
+					},
+					{
+						Input: "b",
+						Processors: []config.ProcessorConfig{
+							{
+								Type: "dmx.merge",
+								Params: config.Params{
+									"group": "lights",
+									"mode":  "ltp",
+								},
+							},
+						},
+					},
+				},
+			},
+			routeErrorStrings: []string{"processor[0] error: dmx.merge group lights already uses mode htp and timeout 2.5s"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			moduleErrors, routeErrors := showbridge.ValidateConfig(test.config)
+
+			if len(moduleErrors) != len(test.moduleErrorStrings) {
+				t.Fatalf("ValidateConfig got %d module errors, expected %d: %v", len(moduleErrors), len(test.moduleErrorStrings), moduleErrors)
+			}
+			for i, moduleError := range moduleErrors {
+				if moduleError.Error != test.moduleErrorStrings[i] {
+					t.Fatalf("ValidateConfig got module error '%s', expected '%s'", moduleError.Error, test.moduleErrorStrings[i])
+				}
+			}
+
+			if len(routeErrors) != len(test.routeErrorStrings) {
+				t.Fatalf("ValidateConfig got %d route errors, expected %d: %v", len(routeErrors), len(test.routeErrorStrings), routeErrors)
+			}
+			for i, routeError := range routeErrors {
+				if routeError.Error != test.routeErrorStrings[i] {
+					t.Fatalf("ValidateConfig got route error '%s', expected '%s'", routeError.Error, test.routeErrorStrings[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRouterModuleReportedStatus(t *testing.T) {
+	router, moduleErrors, _ := showbridge.NewRouter(config.Config{
+		Modules: []config.ModuleConfig{
+			{
+				Id:     "mock",
+				Type:   "mock.counter",
+				Params: config.Params{"status": "connected"},
+			},
+		},
+	})
+
+	if moduleErrors != nil {
+		t.Fatalf("router should not have returned any module errors: %v", moduleErrors)
+	}
+
+	router.Start(t.Context())
+	defer router.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		moduleStatuses := router.GetModuleStatuses()
+		if len(moduleStatuses) == 1 && moduleStatuses[0].Status == "connected" {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("module status should be the one it reported, got: %+v", router.GetModuleStatuses())
+}
+
+func TestRouterModuleStatuses(t *testing.T) {
+	routerConfig := config.Config{
+		Modules: []config.ModuleConfig{
+			{
+				Id:   "mock",
+				Type: "mock.counter",
+			},
+		},
+	}
+
+	router, moduleErrors, routeErrors := showbridge.NewRouter(routerConfig)
+
+	if moduleErrors != nil {
+		t.Fatalf("router should not have returned any module errors: %v", moduleErrors)
+	}
+
+	if routeErrors != nil {
+		t.Fatalf("router should not have returned any route errors: %v", routeErrors)
+	}
+
+	moduleStatuses := router.GetModuleStatuses()
+	if len(moduleStatuses) != 1 || moduleStatuses[0].Status != "stopped" {
+		t.Fatalf("module should be stopped before router start, got: %+v", moduleStatuses)
+	}
+
+	router.Start(t.Context())
+
+	time.Sleep(time.Second * 1)
+
+	moduleStatuses = router.GetModuleStatuses()
+	if len(moduleStatuses) != 1 || moduleStatuses[0].Status != "running" {
+		t.Fatalf("module should be running after router start, got: %+v", moduleStatuses)
+	}
+
+	router.Stop()
+
+	moduleStatuses = router.GetModuleStatuses()
+	if len(moduleStatuses) != 1 || moduleStatuses[0].Status != "stopped" {
+		t.Fatalf("module should be stopped after router stop, got: %+v", moduleStatuses)
+	}
+}
+
+func TestRouterInjectEvent(t *testing.T) {
+	routerConfig := config.Config{
+		Modules: []config.ModuleConfig{
+			{
+				Id:   "mock",
+				Type: "mock.counter",
+			},
+		},
+		Routes: []config.RouteConfig{
+			{
+				Input: "mock",
+				Processors: []config.ProcessorConfig{
+					{
+						Type: "module.output",
+						Params: config.Params{
+							"module": "mock",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	router, moduleErrors, routeErrors := showbridge.NewRouter(routerConfig)
+
+	if moduleErrors != nil {
+		t.Fatalf("router should not have returned any module errors: %v", moduleErrors)
+	}
+
+	if routeErrors != nil {
+		t.Fatalf("router should not have returned any route errors: %v", routeErrors)
+	}
+
+	router.Start(t.Context())
+
+	time.Sleep(time.Second * 1)
+
+	defer router.Stop()
+
+	eventDestination := test.NewTestEventDestination()
+
+	router.HandleEvent(common.Event{
+		Type: "inject",
+		Data: map[string]any{
+			"source":   "mock",
+			"encoding": "hex",
+			"payload":  "01 02 03",
+		},
+	}, eventDestination)
+
+	events := eventDestination.Events()
+	if len(events) != 1 {
+		t.Fatalf("inject should have sent exactly 1 event back, got: %+v", events)
+	}
+
+	if events[0].Type != "inject" || events[0].Error != "" {
+		t.Fatalf("inject result did not match expected, got: %+v", events[0])
+	}
+
+	if events[0].Data.(map[string]any)["routeFound"] != true {
+		t.Fatalf("inject should have found a route, got: %+v", events[0])
+	}
+
+	mockModuleInstance, ok := router.ModuleInstances["mock"].(*MockCounterModule)
+	if !ok {
+		t.Fatalf("couldn't get mock module")
+	}
+
+	if mockModuleInstance.outputCount != 1 {
+		t.Fatalf("mock module output count did not matched expected: 1 got: %d", mockModuleInstance.outputCount)
+	}
+
+	router.HandleEvent(common.Event{
+		Type: "inject",
+		Data: map[string]any{
+			"source":   "mock",
+			"encoding": "hex",
+			"payload":  "zz",
+		},
+	}, eventDestination)
+
+	events = eventDestination.Events()
+	if len(events) != 2 || events[1].Error == "" {
+		t.Fatalf("inject with bad hex payload should have returned an error, got: %+v", events)
+	}
+}
+
+func TestRunRouteTestSuite(t *testing.T) {
+	cfg := config.Config{
+		Modules: []config.ModuleConfig{
+			{
+				Id:   "in",
+				Type: "mock.counter",
+			},
+			{
+				Id:   "out",
+				Type: "mock.counter",
+			},
+		},
+		Routes: []config.RouteConfig{
+			{
+				Id:    "in-to-out",
+				Input: "in",
+				Processors: []config.ProcessorConfig{
+					{
+						Type: "string.create",
+						Params: map[string]any{
+							"template": "{{.Payload.name}}",
+						},
+					},
+					{
+						Type: "module.output",
+						Params: map[string]any{
+							"module": "out",
+						},
+					},
+				},
+			},
+			{
+				Id:    "in-to-missing",
+				Input: "missing",
+				Processors: []config.ProcessorConfig{
+					{
+						Type: "module.output",
+						Params: map[string]any{
+							"module": "nope",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	suiteResult := showbridge.RunRouteTestSuite(cfg, routetest.Suite{
+		Name: "suite",
+		Tests: []routetest.Case{
+			{
+				Name:    "input",
+				Input:   "in",
+				Payload: map[string]any{"name": "hello"},
+				Outputs: []routetest.ExpectedOutput{{Module: "out", Encoding: "string", Payload: "hello"}},
+			},
+			{
+				Name:    "route",
+				Route:   "in-to-out",
+				Payload: map[string]any{"name": "world"},
+				Outputs: []routetest.ExpectedOutput{{Module: "out", Encoding: "string", Payload: "world"}},
+			},
+			{
+				Name:    "expected error",
+				Input:   "missing",
+				Payload: "",
+				Error:   "module.output unable to find module with id: nope",
+			},
+			{
+				Name:    "wrong output",
+				Input:   "in",
+				Payload: map[string]any{"name": "hello"},
+				Outputs: []routetest.ExpectedOutput{{Module: "out", Encoding: "string", Payload: "goodbye"}},
+			},
+			{
+				Name:    "no route",
+				Input:   "other",
+				Payload: "",
+			},
+		},
+	})
+
+	expectedPassed := []bool{true, true, true, false, false}
+	if len(suiteResult.Cases) != len(expectedPassed) {
+		t.Fatalf("route test suite should have %d results, got %d", len(expectedPassed), len(suiteResult.Cases))
+	}
+	for caseIndex, caseResult := range suiteResult.Cases {
+		if caseResult.Passed != expectedPassed[caseIndex] {
+			t.Fatalf("route test %s passed should be %t, got failures: %v", caseResult.Name, expectedPassed[caseIndex], caseResult.Failures)
+		}
+	}
+
+	if suiteResult.Failed() != 2 {
+		t.Fatalf("route test suite should have 2 failures, got %d", suiteResult.Failed())
+	}
+}
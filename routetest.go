@@ -0,0 +1,166 @@
+package showbridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jwetzell/showbridge-go/internal/codec"
+	"github.com/jwetzell/showbridge-go/internal/common"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/processor"
+	"github.com/jwetzell/showbridge-go/internal/route"
+	"github.com/jwetzell/showbridge-go/internal/routetest"
+)
+
+const routeTestTimeout = 10 * time.Second
+
+// newRouteTestRouter builds a router that is never started with every module swapped for a recording stand-in
+func newRouteTestRouter(cfg config.Config) (*Router, []*routetest.Recorder, error) {
+	router := &Router{
+		ModuleInstances: make(map[string]common.Module),
+		moduleIds:       make(map[string]bool),
+		RouteInstances:  []*route.Route{},
+		processorGroups: processor.NewGroups(),
+		moduleStatuses:  make(map[string]common.ModuleStatus),
+		logger:          slog.Default().With("component", "router"),
+		runningConfig:   cfg,
+	}
+	router.eventLog = router.newEventLog(config.EventLogConfig{Size: defaultEventLogSize})
+
+	recorders := []*routetest.Recorder{}
+	for _, moduleDecl := range cfg.Modules {
+		recorder := routetest.NewRecorder(moduleDecl.Id, moduleDecl.Type)
+		router.ModuleInstances[moduleDecl.Id] = recorder
+		router.moduleIds[moduleDecl.Id] = true
+		recorders = append(recorders, recorder)
+	}
+
+	for routeIndex, routeDecl := range cfg.Routes {
+		err := router.addRoute(routeDecl)
+		if err != nil {
+			router.eventLog.Close()
+			return nil, nil, fmt.Errorf("route[%d] error: %w", routeIndex, err)
+		}
+	}
+	return router, recorders, nil
+}
+
+func (r *Router) runRouteTestCase(ctx context.Context, testCase routetest.Case, payload any) error {
+	if testCase.Route == "" {
+		routeFound, routeIOErrors := r.HandleInput(ctx, testCase.Input, payload)
+		if !routeFound {
+			return fmt.Errorf("no routes found for input: %s", testCase.Input)
+		}
+		return joinRouteIOErrors(routeIOErrors)
+	}
+
+	routeFound := false
+	var routeIOErrors []common.RouteIOError
+	var routeIOErrorsMu sync.Mutex
+	var routeWaitGroup sync.WaitGroup
+	for routeIndex, routeInstance := range r.RouteInstances {
+		if routeInstance.Id() != testCase.Route {
+			continue
+		}
+		routeFound = true
+		source := testCase.Input
+		if source == "" {
+			source = routeInstance.Input()
+		}
+		routeWaitGroup.Go(func() {
+			_, err := routeInstance.ProcessPayload(ctx, common.WrappedPayload{
+				Payload:      payload,
+				Source:       source,
+				Modules:      r.ModuleInstances,
+				InputHandler: r.HandleInput,
+				End:          false,
+			})
+			if err != nil {
+				routeIOErrorsMu.Lock()
+				routeIOErrors = append(routeIOErrors, common.RouteIOError{
+					Index:        routeIndex,
+					ProcessError: err,
+				})
+				routeIOErrorsMu.Unlock()
+			}
+		})
+	}
+	routeWaitGroup.Wait()
+
+	if !routeFound {
+		return fmt.Errorf("route not found: %s", testCase.Route)
+	}
+	return joinRouteIOErrors(routeIOErrors)
+}
+
+func joinRouteIOErrors(routeIOErrors []common.RouteIOError) error {
+	if len(routeIOErrors) == 0 {
+		return nil
+	}
+	errorMessages := []string{}
+	for _, routeIOError := range routeIOErrors {
+		errorMessages = append(errorMessages, fmt.Sprintf("route[%d]: %s", routeIOError.Index, routeIOError.ProcessError))
+	}
+	return errors.New(strings.Join(errorMessages, "; "))
+}
+
+// RunRouteTestCase runs a single case against a fresh set of recording stand-ins for the modules in the config
+func RunRouteTestCase(cfg config.Config, testCase routetest.Case) routetest.CaseResult {
+	start := time.Now()
+	result := routetest.CaseResult{
+		Name:    testCase.Name,
+		Outputs: []routetest.Output{},
+	}
+
+	finish := func(failures []string) routetest.CaseResult {
+		result.Failures = failures
+		result.Passed = len(failures) == 0
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	payload, err := codec.DecodePayload(testCase.Encoding, testCase.Payload)
+	if err != nil {
+		return finish([]string{fmt.Sprintf("input payload error: %s", err)})
+	}
+
+	router, recorders, err := newRouteTestRouter(cfg)
+	if err != nil {
+		return finish([]string{err.Error()})
+	}
+	defer router.eventLog.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), routeTestTimeout)
+	defer cancel()
+	router.Context = ctx
+
+	runErr := router.runRouteTestCase(ctx, testCase, payload)
+	if runErr != nil {
+		result.Error = runErr.Error()
+	}
+
+	for _, recorder := range recorders {
+		result.Outputs = append(result.Outputs, recorder.Outputs()...)
+	}
+
+	return finish(routetest.Check(testCase, result.Outputs, runErr))
+}
+
+func RunRouteTestSuite(cfg config.Config, suite routetest.Suite) routetest.SuiteResult {
+	start := time.Now()
+	suiteResult := routetest.SuiteResult{
+		Name:  suite.Name,
+		Path:  suite.Path,
+		Cases: []routetest.CaseResult{},
+	}
+	for _, testCase := range suite.Tests {
+		suiteResult.Cases = append(suiteResult.Cases, RunRouteTestCase(cfg, testCase))
+	}
+	suiteResult.Duration = time.Since(start)
+	return suiteResult
+}
@@ -0,0 +1,56 @@
+// Package showbridgetest runs route test files against a showbridge config, from the CLI or from Go tests.
+package showbridgetest
+
+import (
+	"testing"
+
+	"github.com/jwetzell/showbridge-go"
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/internal/routetest"
+)
+
+// RunFiles reads each route test file and runs its cases against the config
+func RunFiles(cfg config.Config, testPaths ...string) ([]routetest.SuiteResult, error) {
+	suiteResults := []routetest.SuiteResult{}
+	for _, testPath := range testPaths {
+		suite, err := routetest.ReadSuite(testPath)
+		if err != nil {
+			return suiteResults, err
+		}
+		suiteResults = append(suiteResults, showbridge.RunRouteTestSuite(cfg, suite))
+	}
+	return suiteResults, nil
+}
+
+// TestRoutes reads route test files and runs each case as a subtest against the config
+func TestRoutes(t *testing.T, cfg config.Config, testPaths ...string) {
+	t.Helper()
+
+	moduleErrors, routeErrors := showbridge.ValidateConfig(cfg)
+	for _, moduleError := range moduleErrors {
+		t.Errorf("modules[%d] error: %s", moduleError.Index, moduleError.Error)
+	}
+	for _, routeError := range routeErrors {
+		t.Errorf("routes[%d] error: %s", routeError.Index, routeError.Error)
+	}
+	if t.Failed() {
+		t.FailNow()
+	}
+
+	for _, testPath := range testPaths {
+		suite, err := routetest.ReadSuite(testPath)
+		if err != nil {
+			t.Fatalf("failed to read route tests: %s", err)
+		}
+		t.Run(suite.Name, func(t *testing.T) {
+			for _, testCase := range suite.Tests {
+				t.Run(testCase.Name, func(t *testing.T) {
+					result := showbridge.RunRouteTestCase(cfg, testCase)
+					for _, failure := range result.Failures {
+						t.Error(failure)
+					}
+				})
+			}
+		})
+	}
+}
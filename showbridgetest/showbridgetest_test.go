@@ -0,0 +1,108 @@
+package showbridgetest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jwetzell/showbridge-go/internal/config"
+	"github.com/jwetzell/showbridge-go/showbridgetest"
+)
+
+var testConfig = config.Config{
+	Modules: []config.ModuleConfig{
+		{
+			Id:   "in",
+			Type: "net.udp.server",
+			Params: map[string]any{
+				"port": 14360,
+			},
+		},
+		{
+			Id:   "out",
+			Type: "net.udp.client",
+			Params: map[string]any{
+				"host": "127.0.0.1",
+				"port": 14361,
+			},
+		},
+	},
+	Routes: []config.RouteConfig{
+		{
+			Id:    "in-to-out",
+			Input: "in",
+			Processors: []config.ProcessorConfig{
+				{
+					Type: "string.create",
+					Params: map[string]any{
+						"template": "{{.Payload.name}}",
+					},
+				},
+				{
+					Type: "module.output",
+					Params: map[string]any{
+						"module": "out",
+					},
+				},
+			},
+		},
+	},
+}
+
+func writeSuite(t *testing.T, contents string) string {
+	t.Helper()
+	suitePath := filepath.Join(t.TempDir(), "routes.test.yaml")
+	err := os.WriteFile(suitePath, []byte(contents), 0644)
+	if err != nil {
+		t.Fatalf("failed to write suite: %s", err)
+	}
+	return suitePath
+}
+
+func TestTestRoutes(t *testing.T) {
+	suitePath := writeSuite(t, `
+tests:
+  - name: input
+    input: in
+    payload:
+      name: hello
+    outputs:
+      - module: out
+        encoding: string
+        payload: hello
+`)
+
+	showbridgetest.TestRoutes(t, testConfig, suitePath)
+}
+
+func TestRunFiles(t *testing.T) {
+	suitePath := writeSuite(t, `
+tests:
+  - name: wrong output
+    input: in
+    payload:
+      name: hello
+    outputs:
+      - module: out
+        encoding: string
+        payload: goodbye
+`)
+
+	suiteResults, err := showbridgetest.RunFiles(testConfig, suitePath)
+	if err != nil {
+		t.Fatalf("RunFiles failed: %s", err)
+	}
+
+	if len(suiteResults) != 1 {
+		t.Fatalf("RunFiles should return 1 suite result, got %d", len(suiteResults))
+	}
+
+	if suiteResults[0].Failed() != 1 {
+		t.Fatalf("wrong output case should have failed, got %+v", suiteResults[0])
+	}
+
+	_, err = showbridgetest.RunFiles(testConfig, filepath.Join(t.TempDir(), "missing.yaml"))
+	if err == nil {
+		t.Fatalf("RunFiles should fail for a missing file")
+	}
+}